@@ -25,37 +25,162 @@ var File_service_proto protoreflect.FileDescriptor
 
 const file_service_proto_rawDesc = "" +
 	"\n" +
-	"\rservice.proto\x12\x02pb\x1a\vtasks.proto\x1a\x1bgoogle/protobuf/empty.proto2\xdd\x01\n" +
+	"\rservice.proto\x12\x02pb\x1a\vtasks.proto\x1a\x1bgoogle/protobuf/empty.proto2\xc6\r\n" +
 	"\fTasksService\x121\n" +
 	"\aAddTask\x12\x12.pb.TaskImportData\x1a\x12.pb.TaskExportData\x120\n" +
 	"\n" +
 	"RemoveTask\x12\n" +
-	".pb.TaskId\x1a\x16.google.protobuf.Empty\x124\n" +
-	"\fListAllTasks\x12\x16.google.protobuf.Empty\x1a\f.pb.TaskList\x122\n" +
+	".pb.TaskId\x1a\x16.google.protobuf.Empty\x121\n" +
+	"\tListTrash\x12\x16.google.protobuf.Empty\x1a\f.pb.TaskList\x12-\n" +
+	"\vRestoreTask\x12\n" +
+	".pb.TaskId\x1a\x12.pb.TaskExportData\x12/\n" +
+	"\tPurgeTask\x12\n" +
+	".pb.TaskId\x1a\x16.google.protobuf.Empty\x12'\n" +
+	"\vGetTaskTree\x12\n" +
+	".pb.TaskId\x1a\f.pb.TaskTree\x12=\n" +
+	"\x0eGetTaskHistory\x12\x16.pb.TaskHistoryRequest\x1a\x13.pb.TaskHistoryPage\x124\n" +
+	"\fListAllTasks\x12\x16.google.protobuf.Empty\x1a\f.pb.TaskList\x12)\n" +
+	"\tListTasks\x12\x0e.pb.TaskFilter\x1a\f.pb.TaskList\x122\n" +
+	"\vSearchTasks\x12\x11.pb.SearchRequest\x1a\x10.pb.SearchResult\x124\n" +
+	"\n" +
+	"WatchTasks\x12\x15.pb.WatchTasksRequest\x1a\r.pb.TaskEvent0\x01\x122\n" +
+	"\x0fGetChangesSince\x12\x12.pb.ChangesRequest\x1a\v.pb.Changes\x122\n" +
 	"\x10MarkTaskFinished\x12\n" +
-	".pb.TaskId\x1a\x12.pb.TaskExportDataB1Z/github.com/dodocheck/go-pet-project-1/pkg/pb;pbb\x06proto3"
+	".pb.TaskId\x1a\x12.pb.TaskExportData\x12,\n" +
+	"\n" +
+	"ReopenTask\x12\n" +
+	".pb.TaskId\x1a\x12.pb.TaskExportData\x127\n" +
+	"\x0fSetTaskPriority\x12\x10.pb.TaskPriority\x1a\x12.pb.TaskExportData\x123\n" +
+	"\bMoveTask\x12\x13.pb.MoveTaskRequest\x1a\x12.pb.TaskExportData\x12;\n" +
+	"\x11SetTaskRecurrence\x12\x12.pb.TaskRecurrence\x1a\x12.pb.TaskExportData\x120\n" +
+	"\x0eSkipOccurrence\x12\n" +
+	".pb.TaskId\x1a\x12.pb.TaskExportData\x12/\n" +
+	"\vAddTaskTags\x12\f.pb.TaskTags\x1a\x12.pb.TaskExportData\x122\n" +
+	"\x0eRemoveTaskTags\x12\f.pb.TaskTags\x1a\x12.pb.TaskExportData\x12/\n" +
+	"\bListTags\x12\x16.google.protobuf.Empty\x1a\v.pb.TagList\x120\n" +
+	"\tRenameTag\x12\x14.pb.RenameTagRequest\x1a\r.pb.TagChange\x120\n" +
+	"\tMergeTags\x12\x14.pb.MergeTagsRequest\x1a\r.pb.TagChange\x126\n" +
+	"\rCreateProject\x12\x18.pb.CreateProjectRequest\x1a\v.pb.Project\x128\n" +
+	"\fListProjects\x12\x17.pb.ListProjectsRequest\x1a\x0f.pb.ProjectList\x126\n" +
+	"\rRenameProject\x12\x18.pb.RenameProjectRequest\x1a\v.pb.Project\x128\n" +
+	"\x0eArchiveProject\x12\x19.pb.ArchiveProjectRequest\x1a\v.pb.Project\x126\n" +
+	"\rDeleteProject\x12\r.pb.ProjectId\x1a\x16.google.protobuf.Empty\x128\n" +
+	"\x11MoveTaskToProject\x12\x0f.pb.TaskProject\x1a\x12.pb.TaskExportData\x12'\n" +
+	"\bGetStats\x12\x10.pb.StatsRequest\x1a\t.pb.Stats\x12:\n" +
+	"\rBatchAddTasks\x12\x18.pb.BatchAddTasksRequest\x1a\x0f.pb.BatchResult\x121\n" +
+	"\x11BatchMarkFinished\x12\v.pb.TaskIds\x1a\x0f.pb.BatchResult\x120\n" +
+	"\x10BatchRemoveTasks\x12\v.pb.TaskIds\x1a\x0f.pb.BatchResultB1Z/github.com/dodocheck/go-pet-project-1/pkg/pb;pbb\x06proto3"
 
 var file_service_proto_goTypes = []any{
-	(*TaskImportData)(nil), // 0: pb.TaskImportData
-	(*TaskId)(nil),         // 1: pb.TaskId
-	(*emptypb.Empty)(nil),  // 2: google.protobuf.Empty
-	(*TaskExportData)(nil), // 3: pb.TaskExportData
-	(*TaskList)(nil),       // 4: pb.TaskList
+	(*TaskImportData)(nil),        // 0: pb.TaskImportData
+	(*TaskId)(nil),                // 1: pb.TaskId
+	(*emptypb.Empty)(nil),         // 2: google.protobuf.Empty
+	(*TaskHistoryRequest)(nil),    // 3: pb.TaskHistoryRequest
+	(*TaskFilter)(nil),            // 4: pb.TaskFilter
+	(*SearchRequest)(nil),         // 5: pb.SearchRequest
+	(*WatchTasksRequest)(nil),     // 6: pb.WatchTasksRequest
+	(*ChangesRequest)(nil),        // 7: pb.ChangesRequest
+	(*TaskPriority)(nil),          // 8: pb.TaskPriority
+	(*MoveTaskRequest)(nil),       // 9: pb.MoveTaskRequest
+	(*TaskRecurrence)(nil),        // 10: pb.TaskRecurrence
+	(*TaskTags)(nil),              // 11: pb.TaskTags
+	(*RenameTagRequest)(nil),      // 12: pb.RenameTagRequest
+	(*MergeTagsRequest)(nil),      // 13: pb.MergeTagsRequest
+	(*CreateProjectRequest)(nil),  // 14: pb.CreateProjectRequest
+	(*ListProjectsRequest)(nil),   // 15: pb.ListProjectsRequest
+	(*RenameProjectRequest)(nil),  // 16: pb.RenameProjectRequest
+	(*ArchiveProjectRequest)(nil), // 17: pb.ArchiveProjectRequest
+	(*ProjectId)(nil),             // 18: pb.ProjectId
+	(*TaskProject)(nil),           // 19: pb.TaskProject
+	(*StatsRequest)(nil),          // 20: pb.StatsRequest
+	(*BatchAddTasksRequest)(nil),  // 21: pb.BatchAddTasksRequest
+	(*TaskIds)(nil),               // 22: pb.TaskIds
+	(*TaskExportData)(nil),        // 23: pb.TaskExportData
+	(*TaskList)(nil),              // 24: pb.TaskList
+	(*TaskTree)(nil),              // 25: pb.TaskTree
+	(*TaskHistoryPage)(nil),       // 26: pb.TaskHistoryPage
+	(*SearchResult)(nil),          // 27: pb.SearchResult
+	(*TaskEvent)(nil),             // 28: pb.TaskEvent
+	(*Changes)(nil),               // 29: pb.Changes
+	(*TagList)(nil),               // 30: pb.TagList
+	(*TagChange)(nil),             // 31: pb.TagChange
+	(*Project)(nil),               // 32: pb.Project
+	(*ProjectList)(nil),           // 33: pb.ProjectList
+	(*Stats)(nil),                 // 34: pb.Stats
+	(*BatchResult)(nil),           // 35: pb.BatchResult
 }
 var file_service_proto_depIdxs = []int32{
-	0, // 0: pb.TasksService.AddTask:input_type -> pb.TaskImportData
-	1, // 1: pb.TasksService.RemoveTask:input_type -> pb.TaskId
-	2, // 2: pb.TasksService.ListAllTasks:input_type -> google.protobuf.Empty
-	1, // 3: pb.TasksService.MarkTaskFinished:input_type -> pb.TaskId
-	3, // 4: pb.TasksService.AddTask:output_type -> pb.TaskExportData
-	2, // 5: pb.TasksService.RemoveTask:output_type -> google.protobuf.Empty
-	4, // 6: pb.TasksService.ListAllTasks:output_type -> pb.TaskList
-	3, // 7: pb.TasksService.MarkTaskFinished:output_type -> pb.TaskExportData
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: pb.TasksService.AddTask:input_type -> pb.TaskImportData
+	1,  // 1: pb.TasksService.RemoveTask:input_type -> pb.TaskId
+	2,  // 2: pb.TasksService.ListTrash:input_type -> google.protobuf.Empty
+	1,  // 3: pb.TasksService.RestoreTask:input_type -> pb.TaskId
+	1,  // 4: pb.TasksService.PurgeTask:input_type -> pb.TaskId
+	1,  // 5: pb.TasksService.GetTaskTree:input_type -> pb.TaskId
+	3,  // 6: pb.TasksService.GetTaskHistory:input_type -> pb.TaskHistoryRequest
+	2,  // 7: pb.TasksService.ListAllTasks:input_type -> google.protobuf.Empty
+	4,  // 8: pb.TasksService.ListTasks:input_type -> pb.TaskFilter
+	5,  // 9: pb.TasksService.SearchTasks:input_type -> pb.SearchRequest
+	6,  // 10: pb.TasksService.WatchTasks:input_type -> pb.WatchTasksRequest
+	7,  // 11: pb.TasksService.GetChangesSince:input_type -> pb.ChangesRequest
+	1,  // 12: pb.TasksService.MarkTaskFinished:input_type -> pb.TaskId
+	1,  // 13: pb.TasksService.ReopenTask:input_type -> pb.TaskId
+	8,  // 14: pb.TasksService.SetTaskPriority:input_type -> pb.TaskPriority
+	9,  // 15: pb.TasksService.MoveTask:input_type -> pb.MoveTaskRequest
+	10, // 16: pb.TasksService.SetTaskRecurrence:input_type -> pb.TaskRecurrence
+	1,  // 17: pb.TasksService.SkipOccurrence:input_type -> pb.TaskId
+	11, // 18: pb.TasksService.AddTaskTags:input_type -> pb.TaskTags
+	11, // 19: pb.TasksService.RemoveTaskTags:input_type -> pb.TaskTags
+	2,  // 20: pb.TasksService.ListTags:input_type -> google.protobuf.Empty
+	12, // 21: pb.TasksService.RenameTag:input_type -> pb.RenameTagRequest
+	13, // 22: pb.TasksService.MergeTags:input_type -> pb.MergeTagsRequest
+	14, // 23: pb.TasksService.CreateProject:input_type -> pb.CreateProjectRequest
+	15, // 24: pb.TasksService.ListProjects:input_type -> pb.ListProjectsRequest
+	16, // 25: pb.TasksService.RenameProject:input_type -> pb.RenameProjectRequest
+	17, // 26: pb.TasksService.ArchiveProject:input_type -> pb.ArchiveProjectRequest
+	18, // 27: pb.TasksService.DeleteProject:input_type -> pb.ProjectId
+	19, // 28: pb.TasksService.MoveTaskToProject:input_type -> pb.TaskProject
+	20, // 29: pb.TasksService.GetStats:input_type -> pb.StatsRequest
+	21, // 30: pb.TasksService.BatchAddTasks:input_type -> pb.BatchAddTasksRequest
+	22, // 31: pb.TasksService.BatchMarkFinished:input_type -> pb.TaskIds
+	22, // 32: pb.TasksService.BatchRemoveTasks:input_type -> pb.TaskIds
+	23, // 33: pb.TasksService.AddTask:output_type -> pb.TaskExportData
+	2,  // 34: pb.TasksService.RemoveTask:output_type -> google.protobuf.Empty
+	24, // 35: pb.TasksService.ListTrash:output_type -> pb.TaskList
+	23, // 36: pb.TasksService.RestoreTask:output_type -> pb.TaskExportData
+	2,  // 37: pb.TasksService.PurgeTask:output_type -> google.protobuf.Empty
+	25, // 38: pb.TasksService.GetTaskTree:output_type -> pb.TaskTree
+	26, // 39: pb.TasksService.GetTaskHistory:output_type -> pb.TaskHistoryPage
+	24, // 40: pb.TasksService.ListAllTasks:output_type -> pb.TaskList
+	24, // 41: pb.TasksService.ListTasks:output_type -> pb.TaskList
+	27, // 42: pb.TasksService.SearchTasks:output_type -> pb.SearchResult
+	28, // 43: pb.TasksService.WatchTasks:output_type -> pb.TaskEvent
+	29, // 44: pb.TasksService.GetChangesSince:output_type -> pb.Changes
+	23, // 45: pb.TasksService.MarkTaskFinished:output_type -> pb.TaskExportData
+	23, // 46: pb.TasksService.ReopenTask:output_type -> pb.TaskExportData
+	23, // 47: pb.TasksService.SetTaskPriority:output_type -> pb.TaskExportData
+	23, // 48: pb.TasksService.MoveTask:output_type -> pb.TaskExportData
+	23, // 49: pb.TasksService.SetTaskRecurrence:output_type -> pb.TaskExportData
+	23, // 50: pb.TasksService.SkipOccurrence:output_type -> pb.TaskExportData
+	23, // 51: pb.TasksService.AddTaskTags:output_type -> pb.TaskExportData
+	23, // 52: pb.TasksService.RemoveTaskTags:output_type -> pb.TaskExportData
+	30, // 53: pb.TasksService.ListTags:output_type -> pb.TagList
+	31, // 54: pb.TasksService.RenameTag:output_type -> pb.TagChange
+	31, // 55: pb.TasksService.MergeTags:output_type -> pb.TagChange
+	32, // 56: pb.TasksService.CreateProject:output_type -> pb.Project
+	33, // 57: pb.TasksService.ListProjects:output_type -> pb.ProjectList
+	32, // 58: pb.TasksService.RenameProject:output_type -> pb.Project
+	32, // 59: pb.TasksService.ArchiveProject:output_type -> pb.Project
+	2,  // 60: pb.TasksService.DeleteProject:output_type -> google.protobuf.Empty
+	23, // 61: pb.TasksService.MoveTaskToProject:output_type -> pb.TaskExportData
+	34, // 62: pb.TasksService.GetStats:output_type -> pb.Stats
+	35, // 63: pb.TasksService.BatchAddTasks:output_type -> pb.BatchResult
+	35, // 64: pb.TasksService.BatchMarkFinished:output_type -> pb.BatchResult
+	35, // 65: pb.TasksService.BatchRemoveTasks:output_type -> pb.BatchResult
+	33, // [33:66] is the sub-list for method output_type
+	0,  // [0:33] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_service_proto_init() }
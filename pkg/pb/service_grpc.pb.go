@@ -20,10 +20,39 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TasksService_AddTask_FullMethodName          = "/pb.TasksService/AddTask"
-	TasksService_RemoveTask_FullMethodName       = "/pb.TasksService/RemoveTask"
-	TasksService_ListAllTasks_FullMethodName     = "/pb.TasksService/ListAllTasks"
-	TasksService_MarkTaskFinished_FullMethodName = "/pb.TasksService/MarkTaskFinished"
+	TasksService_AddTask_FullMethodName           = "/pb.TasksService/AddTask"
+	TasksService_RemoveTask_FullMethodName        = "/pb.TasksService/RemoveTask"
+	TasksService_ListTrash_FullMethodName         = "/pb.TasksService/ListTrash"
+	TasksService_RestoreTask_FullMethodName       = "/pb.TasksService/RestoreTask"
+	TasksService_PurgeTask_FullMethodName         = "/pb.TasksService/PurgeTask"
+	TasksService_GetTaskTree_FullMethodName       = "/pb.TasksService/GetTaskTree"
+	TasksService_GetTaskHistory_FullMethodName    = "/pb.TasksService/GetTaskHistory"
+	TasksService_ListAllTasks_FullMethodName      = "/pb.TasksService/ListAllTasks"
+	TasksService_ListTasks_FullMethodName         = "/pb.TasksService/ListTasks"
+	TasksService_SearchTasks_FullMethodName       = "/pb.TasksService/SearchTasks"
+	TasksService_WatchTasks_FullMethodName        = "/pb.TasksService/WatchTasks"
+	TasksService_GetChangesSince_FullMethodName   = "/pb.TasksService/GetChangesSince"
+	TasksService_MarkTaskFinished_FullMethodName  = "/pb.TasksService/MarkTaskFinished"
+	TasksService_ReopenTask_FullMethodName        = "/pb.TasksService/ReopenTask"
+	TasksService_SetTaskPriority_FullMethodName   = "/pb.TasksService/SetTaskPriority"
+	TasksService_MoveTask_FullMethodName          = "/pb.TasksService/MoveTask"
+	TasksService_SetTaskRecurrence_FullMethodName = "/pb.TasksService/SetTaskRecurrence"
+	TasksService_SkipOccurrence_FullMethodName    = "/pb.TasksService/SkipOccurrence"
+	TasksService_AddTaskTags_FullMethodName       = "/pb.TasksService/AddTaskTags"
+	TasksService_RemoveTaskTags_FullMethodName    = "/pb.TasksService/RemoveTaskTags"
+	TasksService_ListTags_FullMethodName          = "/pb.TasksService/ListTags"
+	TasksService_RenameTag_FullMethodName         = "/pb.TasksService/RenameTag"
+	TasksService_MergeTags_FullMethodName         = "/pb.TasksService/MergeTags"
+	TasksService_CreateProject_FullMethodName     = "/pb.TasksService/CreateProject"
+	TasksService_ListProjects_FullMethodName      = "/pb.TasksService/ListProjects"
+	TasksService_RenameProject_FullMethodName     = "/pb.TasksService/RenameProject"
+	TasksService_ArchiveProject_FullMethodName    = "/pb.TasksService/ArchiveProject"
+	TasksService_DeleteProject_FullMethodName     = "/pb.TasksService/DeleteProject"
+	TasksService_MoveTaskToProject_FullMethodName = "/pb.TasksService/MoveTaskToProject"
+	TasksService_GetStats_FullMethodName          = "/pb.TasksService/GetStats"
+	TasksService_BatchAddTasks_FullMethodName     = "/pb.TasksService/BatchAddTasks"
+	TasksService_BatchMarkFinished_FullMethodName = "/pb.TasksService/BatchMarkFinished"
+	TasksService_BatchRemoveTasks_FullMethodName  = "/pb.TasksService/BatchRemoveTasks"
 )
 
 // TasksServiceClient is the client API for TasksService service.
@@ -34,8 +63,37 @@ const (
 type TasksServiceClient interface {
 	AddTask(ctx context.Context, in *TaskImportData, opts ...grpc.CallOption) (*TaskExportData, error)
 	RemoveTask(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TaskList, error)
+	RestoreTask(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskExportData, error)
+	PurgeTask(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetTaskTree(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskTree, error)
+	GetTaskHistory(ctx context.Context, in *TaskHistoryRequest, opts ...grpc.CallOption) (*TaskHistoryPage, error)
 	ListAllTasks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TaskList, error)
+	ListTasks(ctx context.Context, in *TaskFilter, opts ...grpc.CallOption) (*TaskList, error)
+	SearchTasks(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResult, error)
+	WatchTasks(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TaskEvent], error)
+	GetChangesSince(ctx context.Context, in *ChangesRequest, opts ...grpc.CallOption) (*Changes, error)
 	MarkTaskFinished(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskExportData, error)
+	ReopenTask(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskExportData, error)
+	SetTaskPriority(ctx context.Context, in *TaskPriority, opts ...grpc.CallOption) (*TaskExportData, error)
+	MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...grpc.CallOption) (*TaskExportData, error)
+	SetTaskRecurrence(ctx context.Context, in *TaskRecurrence, opts ...grpc.CallOption) (*TaskExportData, error)
+	SkipOccurrence(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskExportData, error)
+	AddTaskTags(ctx context.Context, in *TaskTags, opts ...grpc.CallOption) (*TaskExportData, error)
+	RemoveTaskTags(ctx context.Context, in *TaskTags, opts ...grpc.CallOption) (*TaskExportData, error)
+	ListTags(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TagList, error)
+	RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*TagChange, error)
+	MergeTags(ctx context.Context, in *MergeTagsRequest, opts ...grpc.CallOption) (*TagChange, error)
+	CreateProject(ctx context.Context, in *CreateProjectRequest, opts ...grpc.CallOption) (*Project, error)
+	ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ProjectList, error)
+	RenameProject(ctx context.Context, in *RenameProjectRequest, opts ...grpc.CallOption) (*Project, error)
+	ArchiveProject(ctx context.Context, in *ArchiveProjectRequest, opts ...grpc.CallOption) (*Project, error)
+	DeleteProject(ctx context.Context, in *ProjectId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	MoveTaskToProject(ctx context.Context, in *TaskProject, opts ...grpc.CallOption) (*TaskExportData, error)
+	GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*Stats, error)
+	BatchAddTasks(ctx context.Context, in *BatchAddTasksRequest, opts ...grpc.CallOption) (*BatchResult, error)
+	BatchMarkFinished(ctx context.Context, in *TaskIds, opts ...grpc.CallOption) (*BatchResult, error)
+	BatchRemoveTasks(ctx context.Context, in *TaskIds, opts ...grpc.CallOption) (*BatchResult, error)
 }
 
 type tasksServiceClient struct {
@@ -66,6 +124,56 @@ func (c *tasksServiceClient) RemoveTask(ctx context.Context, in *TaskId, opts ..
 	return out, nil
 }
 
+func (c *tasksServiceClient) ListTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TaskList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskList)
+	err := c.cc.Invoke(ctx, TasksService_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) RestoreTask(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_RestoreTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) PurgeTask(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TasksService_PurgeTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) GetTaskTree(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskTree, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskTree)
+	err := c.cc.Invoke(ctx, TasksService_GetTaskTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) GetTaskHistory(ctx context.Context, in *TaskHistoryRequest, opts ...grpc.CallOption) (*TaskHistoryPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskHistoryPage)
+	err := c.cc.Invoke(ctx, TasksService_GetTaskHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tasksServiceClient) ListAllTasks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TaskList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TaskList)
@@ -76,6 +184,55 @@ func (c *tasksServiceClient) ListAllTasks(ctx context.Context, in *emptypb.Empty
 	return out, nil
 }
 
+func (c *tasksServiceClient) ListTasks(ctx context.Context, in *TaskFilter, opts ...grpc.CallOption) (*TaskList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskList)
+	err := c.cc.Invoke(ctx, TasksService_ListTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) SearchTasks(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResult)
+	err := c.cc.Invoke(ctx, TasksService_SearchTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) WatchTasks(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TaskEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TasksService_ServiceDesc.Streams[0], TasksService_WatchTasks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTasksRequest, TaskEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TasksService_WatchTasksClient = grpc.ServerStreamingClient[TaskEvent]
+
+func (c *tasksServiceClient) GetChangesSince(ctx context.Context, in *ChangesRequest, opts ...grpc.CallOption) (*Changes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Changes)
+	err := c.cc.Invoke(ctx, TasksService_GetChangesSince_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tasksServiceClient) MarkTaskFinished(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskExportData, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TaskExportData)
@@ -86,6 +243,206 @@ func (c *tasksServiceClient) MarkTaskFinished(ctx context.Context, in *TaskId, o
 	return out, nil
 }
 
+func (c *tasksServiceClient) ReopenTask(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_ReopenTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) SetTaskPriority(ctx context.Context, in *TaskPriority, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_SetTaskPriority_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_MoveTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) SetTaskRecurrence(ctx context.Context, in *TaskRecurrence, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_SetTaskRecurrence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) SkipOccurrence(ctx context.Context, in *TaskId, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_SkipOccurrence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) AddTaskTags(ctx context.Context, in *TaskTags, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_AddTaskTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) RemoveTaskTags(ctx context.Context, in *TaskTags, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_RemoveTaskTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) ListTags(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TagList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TagList)
+	err := c.cc.Invoke(ctx, TasksService_ListTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*TagChange, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TagChange)
+	err := c.cc.Invoke(ctx, TasksService_RenameTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) MergeTags(ctx context.Context, in *MergeTagsRequest, opts ...grpc.CallOption) (*TagChange, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TagChange)
+	err := c.cc.Invoke(ctx, TasksService_MergeTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) CreateProject(ctx context.Context, in *CreateProjectRequest, opts ...grpc.CallOption) (*Project, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Project)
+	err := c.cc.Invoke(ctx, TasksService_CreateProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ProjectList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProjectList)
+	err := c.cc.Invoke(ctx, TasksService_ListProjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) RenameProject(ctx context.Context, in *RenameProjectRequest, opts ...grpc.CallOption) (*Project, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Project)
+	err := c.cc.Invoke(ctx, TasksService_RenameProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) ArchiveProject(ctx context.Context, in *ArchiveProjectRequest, opts ...grpc.CallOption) (*Project, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Project)
+	err := c.cc.Invoke(ctx, TasksService_ArchiveProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) DeleteProject(ctx context.Context, in *ProjectId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TasksService_DeleteProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) MoveTaskToProject(ctx context.Context, in *TaskProject, opts ...grpc.CallOption) (*TaskExportData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskExportData)
+	err := c.cc.Invoke(ctx, TasksService_MoveTaskToProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, TasksService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) BatchAddTasks(ctx context.Context, in *BatchAddTasksRequest, opts ...grpc.CallOption) (*BatchResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResult)
+	err := c.cc.Invoke(ctx, TasksService_BatchAddTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) BatchMarkFinished(ctx context.Context, in *TaskIds, opts ...grpc.CallOption) (*BatchResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResult)
+	err := c.cc.Invoke(ctx, TasksService_BatchMarkFinished_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksServiceClient) BatchRemoveTasks(ctx context.Context, in *TaskIds, opts ...grpc.CallOption) (*BatchResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResult)
+	err := c.cc.Invoke(ctx, TasksService_BatchRemoveTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TasksServiceServer is the server API for TasksService service.
 // All implementations must embed UnimplementedTasksServiceServer
 // for forward compatibility.
@@ -94,8 +451,37 @@ func (c *tasksServiceClient) MarkTaskFinished(ctx context.Context, in *TaskId, o
 type TasksServiceServer interface {
 	AddTask(context.Context, *TaskImportData) (*TaskExportData, error)
 	RemoveTask(context.Context, *TaskId) (*emptypb.Empty, error)
+	ListTrash(context.Context, *emptypb.Empty) (*TaskList, error)
+	RestoreTask(context.Context, *TaskId) (*TaskExportData, error)
+	PurgeTask(context.Context, *TaskId) (*emptypb.Empty, error)
+	GetTaskTree(context.Context, *TaskId) (*TaskTree, error)
+	GetTaskHistory(context.Context, *TaskHistoryRequest) (*TaskHistoryPage, error)
 	ListAllTasks(context.Context, *emptypb.Empty) (*TaskList, error)
+	ListTasks(context.Context, *TaskFilter) (*TaskList, error)
+	SearchTasks(context.Context, *SearchRequest) (*SearchResult, error)
+	WatchTasks(*WatchTasksRequest, grpc.ServerStreamingServer[TaskEvent]) error
+	GetChangesSince(context.Context, *ChangesRequest) (*Changes, error)
 	MarkTaskFinished(context.Context, *TaskId) (*TaskExportData, error)
+	ReopenTask(context.Context, *TaskId) (*TaskExportData, error)
+	SetTaskPriority(context.Context, *TaskPriority) (*TaskExportData, error)
+	MoveTask(context.Context, *MoveTaskRequest) (*TaskExportData, error)
+	SetTaskRecurrence(context.Context, *TaskRecurrence) (*TaskExportData, error)
+	SkipOccurrence(context.Context, *TaskId) (*TaskExportData, error)
+	AddTaskTags(context.Context, *TaskTags) (*TaskExportData, error)
+	RemoveTaskTags(context.Context, *TaskTags) (*TaskExportData, error)
+	ListTags(context.Context, *emptypb.Empty) (*TagList, error)
+	RenameTag(context.Context, *RenameTagRequest) (*TagChange, error)
+	MergeTags(context.Context, *MergeTagsRequest) (*TagChange, error)
+	CreateProject(context.Context, *CreateProjectRequest) (*Project, error)
+	ListProjects(context.Context, *ListProjectsRequest) (*ProjectList, error)
+	RenameProject(context.Context, *RenameProjectRequest) (*Project, error)
+	ArchiveProject(context.Context, *ArchiveProjectRequest) (*Project, error)
+	DeleteProject(context.Context, *ProjectId) (*emptypb.Empty, error)
+	MoveTaskToProject(context.Context, *TaskProject) (*TaskExportData, error)
+	GetStats(context.Context, *StatsRequest) (*Stats, error)
+	BatchAddTasks(context.Context, *BatchAddTasksRequest) (*BatchResult, error)
+	BatchMarkFinished(context.Context, *TaskIds) (*BatchResult, error)
+	BatchRemoveTasks(context.Context, *TaskIds) (*BatchResult, error)
 	mustEmbedUnimplementedTasksServiceServer()
 }
 
@@ -112,12 +498,99 @@ func (UnimplementedTasksServiceServer) AddTask(context.Context, *TaskImportData)
 func (UnimplementedTasksServiceServer) RemoveTask(context.Context, *TaskId) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method RemoveTask not implemented")
 }
+func (UnimplementedTasksServiceServer) ListTrash(context.Context, *emptypb.Empty) (*TaskList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedTasksServiceServer) RestoreTask(context.Context, *TaskId) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreTask not implemented")
+}
+func (UnimplementedTasksServiceServer) PurgeTask(context.Context, *TaskId) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method PurgeTask not implemented")
+}
+func (UnimplementedTasksServiceServer) GetTaskTree(context.Context, *TaskId) (*TaskTree, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTaskTree not implemented")
+}
+func (UnimplementedTasksServiceServer) GetTaskHistory(context.Context, *TaskHistoryRequest) (*TaskHistoryPage, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTaskHistory not implemented")
+}
 func (UnimplementedTasksServiceServer) ListAllTasks(context.Context, *emptypb.Empty) (*TaskList, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListAllTasks not implemented")
 }
+func (UnimplementedTasksServiceServer) ListTasks(context.Context, *TaskFilter) (*TaskList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedTasksServiceServer) SearchTasks(context.Context, *SearchRequest) (*SearchResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method SearchTasks not implemented")
+}
+func (UnimplementedTasksServiceServer) WatchTasks(*WatchTasksRequest, grpc.ServerStreamingServer[TaskEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchTasks not implemented")
+}
+func (UnimplementedTasksServiceServer) GetChangesSince(context.Context, *ChangesRequest) (*Changes, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetChangesSince not implemented")
+}
 func (UnimplementedTasksServiceServer) MarkTaskFinished(context.Context, *TaskId) (*TaskExportData, error) {
 	return nil, status.Error(codes.Unimplemented, "method MarkTaskFinished not implemented")
 }
+func (UnimplementedTasksServiceServer) ReopenTask(context.Context, *TaskId) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReopenTask not implemented")
+}
+func (UnimplementedTasksServiceServer) SetTaskPriority(context.Context, *TaskPriority) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetTaskPriority not implemented")
+}
+func (UnimplementedTasksServiceServer) MoveTask(context.Context, *MoveTaskRequest) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method MoveTask not implemented")
+}
+func (UnimplementedTasksServiceServer) SetTaskRecurrence(context.Context, *TaskRecurrence) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetTaskRecurrence not implemented")
+}
+func (UnimplementedTasksServiceServer) SkipOccurrence(context.Context, *TaskId) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method SkipOccurrence not implemented")
+}
+func (UnimplementedTasksServiceServer) AddTaskTags(context.Context, *TaskTags) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddTaskTags not implemented")
+}
+func (UnimplementedTasksServiceServer) RemoveTaskTags(context.Context, *TaskTags) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveTaskTags not implemented")
+}
+func (UnimplementedTasksServiceServer) ListTags(context.Context, *emptypb.Empty) (*TagList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTags not implemented")
+}
+func (UnimplementedTasksServiceServer) RenameTag(context.Context, *RenameTagRequest) (*TagChange, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenameTag not implemented")
+}
+func (UnimplementedTasksServiceServer) MergeTags(context.Context, *MergeTagsRequest) (*TagChange, error) {
+	return nil, status.Error(codes.Unimplemented, "method MergeTags not implemented")
+}
+func (UnimplementedTasksServiceServer) CreateProject(context.Context, *CreateProjectRequest) (*Project, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateProject not implemented")
+}
+func (UnimplementedTasksServiceServer) ListProjects(context.Context, *ListProjectsRequest) (*ProjectList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (UnimplementedTasksServiceServer) RenameProject(context.Context, *RenameProjectRequest) (*Project, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenameProject not implemented")
+}
+func (UnimplementedTasksServiceServer) ArchiveProject(context.Context, *ArchiveProjectRequest) (*Project, error) {
+	return nil, status.Error(codes.Unimplemented, "method ArchiveProject not implemented")
+}
+func (UnimplementedTasksServiceServer) DeleteProject(context.Context, *ProjectId) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteProject not implemented")
+}
+func (UnimplementedTasksServiceServer) MoveTaskToProject(context.Context, *TaskProject) (*TaskExportData, error) {
+	return nil, status.Error(codes.Unimplemented, "method MoveTaskToProject not implemented")
+}
+func (UnimplementedTasksServiceServer) GetStats(context.Context, *StatsRequest) (*Stats, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedTasksServiceServer) BatchAddTasks(context.Context, *BatchAddTasksRequest) (*BatchResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchAddTasks not implemented")
+}
+func (UnimplementedTasksServiceServer) BatchMarkFinished(context.Context, *TaskIds) (*BatchResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchMarkFinished not implemented")
+}
+func (UnimplementedTasksServiceServer) BatchRemoveTasks(context.Context, *TaskIds) (*BatchResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchRemoveTasks not implemented")
+}
 func (UnimplementedTasksServiceServer) mustEmbedUnimplementedTasksServiceServer() {}
 func (UnimplementedTasksServiceServer) testEmbeddedByValue()                      {}
 
@@ -175,35 +648,190 @@ func _TasksService_RemoveTask_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TasksService_ListAllTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _TasksService_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TasksServiceServer).ListAllTasks(ctx, in)
+		return srv.(TasksServiceServer).ListTrash(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: TasksService_ListAllTasks_FullMethodName,
+		FullMethod: TasksService_ListTrash_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TasksServiceServer).ListAllTasks(ctx, req.(*emptypb.Empty))
+		return srv.(TasksServiceServer).ListTrash(ctx, req.(*emptypb.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TasksService_MarkTaskFinished_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _TasksService_RestoreTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TaskId)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TasksServiceServer).MarkTaskFinished(ctx, in)
+		return srv.(TasksServiceServer).RestoreTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: TasksService_MarkTaskFinished_FullMethodName,
+		FullMethod: TasksService_RestoreTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).RestoreTask(ctx, req.(*TaskId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_PurgeTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).PurgeTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_PurgeTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).PurgeTask(ctx, req.(*TaskId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_GetTaskTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).GetTaskTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_GetTaskTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).GetTaskTree(ctx, req.(*TaskId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_GetTaskHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).GetTaskHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_GetTaskHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).GetTaskHistory(ctx, req.(*TaskHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_ListAllTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).ListAllTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_ListAllTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).ListAllTasks(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_ListTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).ListTasks(ctx, req.(*TaskFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_SearchTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).SearchTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_SearchTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).SearchTasks(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_WatchTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTasksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TasksServiceServer).WatchTasks(m, &grpc.GenericServerStream[WatchTasksRequest, TaskEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TasksService_WatchTasksServer = grpc.ServerStreamingServer[TaskEvent]
+
+func _TasksService_GetChangesSince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).GetChangesSince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_GetChangesSince_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).GetChangesSince(ctx, req.(*ChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_MarkTaskFinished_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).MarkTaskFinished(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_MarkTaskFinished_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(TasksServiceServer).MarkTaskFinished(ctx, req.(*TaskId))
@@ -211,6 +839,366 @@ func _TasksService_MarkTaskFinished_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TasksService_ReopenTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).ReopenTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_ReopenTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).ReopenTask(ctx, req.(*TaskId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_SetTaskPriority_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskPriority)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).SetTaskPriority(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_SetTaskPriority_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).SetTaskPriority(ctx, req.(*TaskPriority))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_MoveTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).MoveTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_MoveTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).MoveTask(ctx, req.(*MoveTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_SetTaskRecurrence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRecurrence)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).SetTaskRecurrence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_SetTaskRecurrence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).SetTaskRecurrence(ctx, req.(*TaskRecurrence))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_SkipOccurrence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).SkipOccurrence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_SkipOccurrence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).SkipOccurrence(ctx, req.(*TaskId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_AddTaskTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskTags)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).AddTaskTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_AddTaskTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).AddTaskTags(ctx, req.(*TaskTags))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_RemoveTaskTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskTags)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).RemoveTaskTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_RemoveTaskTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).RemoveTaskTags(ctx, req.(*TaskTags))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_ListTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).ListTags(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_RenameTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).RenameTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_RenameTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).RenameTag(ctx, req.(*RenameTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_MergeTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).MergeTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_MergeTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).MergeTags(ctx, req.(*MergeTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_CreateProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).CreateProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_CreateProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).CreateProject(ctx, req.(*CreateProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_ListProjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).ListProjects(ctx, req.(*ListProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_RenameProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).RenameProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_RenameProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).RenameProject(ctx, req.(*RenameProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_ArchiveProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).ArchiveProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_ArchiveProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).ArchiveProject(ctx, req.(*ArchiveProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_DeleteProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProjectId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).DeleteProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_DeleteProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).DeleteProject(ctx, req.(*ProjectId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_MoveTaskToProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskProject)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).MoveTaskToProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_MoveTaskToProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).MoveTaskToProject(ctx, req.(*TaskProject))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).GetStats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_BatchAddTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAddTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).BatchAddTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_BatchAddTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).BatchAddTasks(ctx, req.(*BatchAddTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_BatchMarkFinished_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskIds)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).BatchMarkFinished(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_BatchMarkFinished_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).BatchMarkFinished(ctx, req.(*TaskIds))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksService_BatchRemoveTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskIds)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksServiceServer).BatchRemoveTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksService_BatchRemoveTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksServiceServer).BatchRemoveTasks(ctx, req.(*TaskIds))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TasksService_ServiceDesc is the grpc.ServiceDesc for TasksService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -226,15 +1214,133 @@ var TasksService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveTask",
 			Handler:    _TasksService_RemoveTask_Handler,
 		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _TasksService_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreTask",
+			Handler:    _TasksService_RestoreTask_Handler,
+		},
+		{
+			MethodName: "PurgeTask",
+			Handler:    _TasksService_PurgeTask_Handler,
+		},
+		{
+			MethodName: "GetTaskTree",
+			Handler:    _TasksService_GetTaskTree_Handler,
+		},
+		{
+			MethodName: "GetTaskHistory",
+			Handler:    _TasksService_GetTaskHistory_Handler,
+		},
 		{
 			MethodName: "ListAllTasks",
 			Handler:    _TasksService_ListAllTasks_Handler,
 		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _TasksService_ListTasks_Handler,
+		},
+		{
+			MethodName: "SearchTasks",
+			Handler:    _TasksService_SearchTasks_Handler,
+		},
+		{
+			MethodName: "GetChangesSince",
+			Handler:    _TasksService_GetChangesSince_Handler,
+		},
 		{
 			MethodName: "MarkTaskFinished",
 			Handler:    _TasksService_MarkTaskFinished_Handler,
 		},
+		{
+			MethodName: "ReopenTask",
+			Handler:    _TasksService_ReopenTask_Handler,
+		},
+		{
+			MethodName: "SetTaskPriority",
+			Handler:    _TasksService_SetTaskPriority_Handler,
+		},
+		{
+			MethodName: "MoveTask",
+			Handler:    _TasksService_MoveTask_Handler,
+		},
+		{
+			MethodName: "SetTaskRecurrence",
+			Handler:    _TasksService_SetTaskRecurrence_Handler,
+		},
+		{
+			MethodName: "SkipOccurrence",
+			Handler:    _TasksService_SkipOccurrence_Handler,
+		},
+		{
+			MethodName: "AddTaskTags",
+			Handler:    _TasksService_AddTaskTags_Handler,
+		},
+		{
+			MethodName: "RemoveTaskTags",
+			Handler:    _TasksService_RemoveTaskTags_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _TasksService_ListTags_Handler,
+		},
+		{
+			MethodName: "RenameTag",
+			Handler:    _TasksService_RenameTag_Handler,
+		},
+		{
+			MethodName: "MergeTags",
+			Handler:    _TasksService_MergeTags_Handler,
+		},
+		{
+			MethodName: "CreateProject",
+			Handler:    _TasksService_CreateProject_Handler,
+		},
+		{
+			MethodName: "ListProjects",
+			Handler:    _TasksService_ListProjects_Handler,
+		},
+		{
+			MethodName: "RenameProject",
+			Handler:    _TasksService_RenameProject_Handler,
+		},
+		{
+			MethodName: "ArchiveProject",
+			Handler:    _TasksService_ArchiveProject_Handler,
+		},
+		{
+			MethodName: "DeleteProject",
+			Handler:    _TasksService_DeleteProject_Handler,
+		},
+		{
+			MethodName: "MoveTaskToProject",
+			Handler:    _TasksService_MoveTaskToProject_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _TasksService_GetStats_Handler,
+		},
+		{
+			MethodName: "BatchAddTasks",
+			Handler:    _TasksService_BatchAddTasks_Handler,
+		},
+		{
+			MethodName: "BatchMarkFinished",
+			Handler:    _TasksService_BatchMarkFinished_Handler,
+		},
+		{
+			MethodName: "BatchRemoveTasks",
+			Handler:    _TasksService_BatchRemoveTasks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTasks",
+			Handler:       _TasksService_WatchTasks_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
 }
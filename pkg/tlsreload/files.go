@@ -0,0 +1,98 @@
+// Package tlsreload reads TLS certificates from disk and reads them again
+// when they change, so renewed certificates are used without a restart.
+package tlsreload
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Files holds an optional key pair and an optional CA read from disk and
+// reads them again once any of the files changes. Files that can't be read
+// or parsed keep the previous ones in use until the next check. That covers
+// a renewal caught half-written too: a new key with the old certificate
+// doesn't load as a pair.
+type Files struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu       sync.Mutex
+	loaded   bool
+	modTimes []time.Time
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+}
+
+// Load reads the files; certFile and keyFile, or caFile, may be empty.
+func Load(certFile, keyFile, caFile string) (*Files, error) {
+	files := &Files{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := files.reload(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Current returns the key pair and the CA pool, nil for the files not set.
+func (f *Files) Current() (*tls.Certificate, *x509.CertPool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.reload(); err != nil {
+		log.Printf("reload TLS certificates error, keeping the previous ones: %v\n", err)
+	}
+	return f.cert, f.caPool
+}
+
+func (f *Files) reload() error {
+	var paths []string
+	if f.certFile != "" {
+		paths = append(paths, f.certFile, f.keyFile)
+	}
+	if f.caFile != "" {
+		paths = append(paths, f.caFile)
+	}
+	modTimes := make([]time.Time, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	if f.loaded && slices.EqualFunc(modTimes, f.modTimes, time.Time.Equal) {
+		return nil
+	}
+
+	var cert *tls.Certificate
+	if f.certFile != "" {
+		keyPair, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+		if err != nil {
+			return fmt.Errorf("key pair: %w", err)
+		}
+		cert = &keyPair
+	}
+	var caPool *x509.CertPool
+	if f.caFile != "" {
+		caPEM, err := os.ReadFile(f.caFile)
+		if err != nil {
+			return fmt.Errorf("CA file: %w", err)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("CA file %s has no certificates", f.caFile)
+		}
+	}
+
+	if f.loaded {
+		log.Println("reloaded TLS certificates")
+	}
+	f.loaded, f.modTimes, f.cert, f.caPool = true, modTimes, cert, caPool
+	return nil
+}
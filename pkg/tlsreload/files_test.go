@@ -0,0 +1,118 @@
+package tlsreload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeKeyPair writes a self-signed certificate with serial and its key, and
+// moves their modification time forward so a rewrite within the same clock
+// tick still looks changed.
+func writeKeyPair(t *testing.T, certPath, keyPath string, serial int64) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "db-service"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), serial)
+	writeFile(t, keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), serial)
+}
+
+func writeFile(t *testing.T, path string, data []byte, tick int64) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(time.Duration(tick) * time.Minute)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func serialOf(t *testing.T, files *Files) int64 {
+	t.Helper()
+	cert, _ := files.Current()
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.SerialNumber.Int64()
+}
+
+func TestFiles_ReloadsChangedKeyPair(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeKeyPair(t, certPath, keyPath, 1)
+
+	files, err := Load(certPath, keyPath, "")
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	writeKeyPair(t, certPath, keyPath, 2)
+
+	if serial := serialOf(t, files); serial != 2 {
+		t.Fatalf("expected the renewed certificate, got serial %d", serial)
+	}
+}
+
+func TestFiles_HalfWrittenRenewal_KeepsPreviousPair(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeKeyPair(t, certPath, keyPath, 1)
+	files, err := Load(certPath, keyPath, "")
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	// Only the new key has been written so far.
+	renewedCert, renewedKey := filepath.Join(dir, "renewed.pem"), filepath.Join(dir, "renewed-key.pem")
+	writeKeyPair(t, renewedCert, renewedKey, 2)
+	keyPEM, err := os.ReadFile(renewedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, keyPath, keyPEM, 2)
+
+	if serial := serialOf(t, files); serial != 1 {
+		t.Fatalf("expected the previous certificate, got serial %d", serial)
+	}
+
+	certPEM, err := os.ReadFile(renewedCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, certPath, certPEM, 3)
+
+	if serial := serialOf(t, files); serial != 2 {
+		t.Fatalf("expected the renewed certificate once complete, got serial %d", serial)
+	}
+}
+
+func TestLoad_MissingCA_ReturnsError(t *testing.T) {
+	if _, err := Load("", "", filepath.Join(t.TempDir(), "ca.pem")); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/dodocheck/go-pet-project-1/pkg/tlsreload
+
+go 1.25.4
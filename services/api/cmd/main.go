@@ -6,6 +6,10 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/dodocheck/go-pet-project-1/pkg/pb"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
@@ -13,8 +17,6 @@ import (
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/logger"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/transport/http"
 	"github.com/segmentio/kafka-go"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 func main() {
@@ -31,8 +33,7 @@ func main() {
 	log.SetOutput(io.MultiWriter(os.Stdout, f))
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 
-	dbAddr := "db-service:" + os.Getenv("DB_SERVICE_INTERNAL_PORT")
-	conn, err := grpc.NewClient(dbAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dbgrpc.NewConn(dbConnOptions())
 	if err != nil {
 		log.Fatal("Failed to dial grpc db:", err)
 	}
@@ -59,3 +60,44 @@ func main() {
 		log.Fatal("Failed to start http web server:", err)
 	}
 }
+
+// dbConnOptions reads DB_SERVICE_ADDRESSES (comma-separated, by default the
+// db-service of the compose file), DB_SERVICE_TOKEN, the DB_SERVICE_TLS*
+// variables, DB_RPC_TIMEOUT_SECONDS, DB_BREAKER_FAILURES and
+// DB_BREAKER_COOLDOWN_SECONDS. Unset or non-positive numbers fall back to the
+// defaults.
+func dbConnOptions() dbgrpc.Options {
+	opts := dbgrpc.Options{
+		Timeout:          positiveSeconds("DB_RPC_TIMEOUT_SECONDS", dbgrpc.DefaultTimeout),
+		BreakerThreshold: dbgrpc.DefaultBreakerThreshold,
+		BreakerCooldown:  positiveSeconds("DB_BREAKER_COOLDOWN_SECONDS", dbgrpc.DefaultBreakerCooldown),
+		Token:            os.Getenv("DB_SERVICE_TOKEN"),
+		TLS: dbgrpc.TLSOptions{
+			CAFile:     os.Getenv("DB_SERVICE_TLS_CA_FILE"),
+			CertFile:   os.Getenv("DB_SERVICE_TLS_CLIENT_CERT_FILE"),
+			KeyFile:    os.Getenv("DB_SERVICE_TLS_CLIENT_KEY_FILE"),
+			ServerName: os.Getenv("DB_SERVICE_TLS_SERVER_NAME"),
+		},
+	}
+	opts.TLS.Enabled, _ = strconv.ParseBool(os.Getenv("DB_SERVICE_TLS"))
+	for _, address := range strings.Split(os.Getenv("DB_SERVICE_ADDRESSES"), ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			opts.Addresses = append(opts.Addresses, address)
+		}
+	}
+	if len(opts.Addresses) == 0 {
+		opts.Addresses = []string{"db-service:" + os.Getenv("DB_SERVICE_INTERNAL_PORT")}
+	}
+	if threshold, err := strconv.Atoi(os.Getenv("DB_BREAKER_FAILURES")); err == nil && threshold > 0 {
+		opts.BreakerThreshold = threshold
+	}
+	return opts
+}
+
+func positiveSeconds(name string, fallback time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
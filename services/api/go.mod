@@ -4,8 +4,11 @@ go 1.25.4
 
 require (
 	github.com/dodocheck/go-pet-project-1/pkg/pb v0.0.0-20251224110946-e14a26199fc6
+	github.com/dodocheck/go-pet-project-1/pkg/tlsreload v0.0.0-00010101000000-000000000000
 	github.com/gorilla/mux v1.8.1
+	github.com/gorilla/websocket v1.5.3
 	github.com/segmentio/kafka-go v0.4.49
+	golang.org/x/time v0.14.0
 	google.golang.org/grpc v1.77.0
 	google.golang.org/protobuf v1.36.11
 )
@@ -18,3 +21,10 @@ require (
 	golang.org/x/text v0.30.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251022142026-3a174f9686a8 // indirect
 )
+
+// The service is built together with the shared packages of the same
+// commit; see its Dockerfile.
+replace (
+	github.com/dodocheck/go-pet-project-1/pkg/pb => ../../pkg/pb
+	github.com/dodocheck/go-pet-project-1/pkg/tlsreload => ../../pkg/tlsreload
+)
@@ -0,0 +1,85 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type positionedTask struct {
+	Id       int    `json:"Id"`
+	Position string `json:"Position"`
+}
+
+func TestConcurrentCreate_GivesEveryTaskItsOwnPosition(t *testing.T) {
+	baseURL := os.Getenv("API_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:9089"
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	waitForAPI(t, client, baseURL)
+
+	// Задачи создаются одновременно, чтобы транзакции читали одну и ту же
+	// последнюю позицию, если выдача позиций не сериализована.
+	const n = 20
+	tasks := make([]positionedTask, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tasks[i], errs[i] = createTask(client, baseURL, fmt.Sprintf("pos-%d-%d", time.Now().UnixNano(), i))
+		}()
+	}
+	wg.Wait()
+
+	positions := make(map[string]int, n)
+	for i, task := range tasks {
+		if errs[i] != nil {
+			t.Errorf("create %d: %v", i, errs[i])
+			continue
+		}
+		if other, ok := positions[task.Position]; ok {
+			t.Errorf("tasks %d and %d got the same position %q", other, task.Id, task.Position)
+		}
+		positions[task.Position] = task.Id
+	}
+
+	for _, task := range tasks {
+		if task.Id != 0 {
+			doJSON(t, client, "DELETE", baseURL+"/delete", map[string]any{"Id": task.Id}, nil, http.StatusNoContent)
+		}
+	}
+}
+
+func createTask(client *http.Client, baseURL, title string) (positionedTask, error) {
+	b, err := json.Marshal(map[string]any{"title": title, "text": "from integration test"})
+	if err != nil {
+		return positionedTask{}, err
+	}
+
+	resp, err := client.Post(baseURL+"/create", "application/json", bytes.NewReader(b))
+	if err != nil {
+		return positionedTask{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return positionedTask{}, fmt.Errorf("want status=%d got=%d", http.StatusCreated, resp.StatusCode)
+	}
+
+	var task positionedTask
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return positionedTask{}, err
+	}
+	return task, nil
+}
@@ -0,0 +1,16 @@
+package app
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor records who makes the request, so that db-service can attribute
+// the task changes to them.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -9,6 +9,37 @@ import (
 type DBClient interface {
 	AddTask(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error)
 	RemoveTask(ctx context.Context, id int) error
-	ListAllTasks(ctx context.Context) ([]models.TaskExportData, error)
+	GetTaskTree(ctx context.Context, id int) (models.TaskTree, error)
+	// ListAllTasks and ListTasks report the list as stale when db-service
+	// served it from its cache because Postgres is down.
+	ListAllTasks(ctx context.Context) ([]models.TaskExportData, bool, error)
+	ListTasks(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error)
+	SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error)
+	WatchTasks(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error
+	GetChangesSince(ctx context.Context, token string, limit int) (models.Changes, error)
+	ListTrash(ctx context.Context) ([]models.TaskExportData, error)
+	RestoreTask(ctx context.Context, id int) (models.TaskExportData, error)
+	PurgeTask(ctx context.Context, id int) error
 	MarkTaskFinished(ctx context.Context, id int) (models.TaskExportData, error)
+	ReopenTask(ctx context.Context, id int) (models.TaskExportData, error)
+	SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error)
+	MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error)
+	SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error)
+	SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error)
+	AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error)
+	RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error)
+	ListTags(ctx context.Context) ([]models.TagUsage, error)
+	RenameTag(ctx context.Context, name, newName string) (models.TagChange, error)
+	MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error)
+	CreateProject(ctx context.Context, name string) (models.Project, error)
+	ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error)
+	RenameProject(ctx context.Context, id int, name string) (models.Project, error)
+	ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error)
+	DeleteProject(ctx context.Context, id int) error
+	MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error)
+	GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error)
+	GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error)
+	BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error)
+	BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error)
+	BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error)
 }
@@ -0,0 +1,14 @@
+package app
+
+import "errors"
+
+var (
+	ErrNotFound             = errors.New("not found")
+	ErrAlreadyExists        = errors.New("already exists")
+	ErrConflict             = errors.New("conflict")
+	ErrInvalidArgument      = errors.New("invalid argument")
+	ErrPreconditionFailed   = errors.New("task was changed by someone else")
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for another request")
+	ErrUnavailable          = errors.New("service unavailable")
+	ErrTimeout              = errors.New("timed out")
+)
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"log"
+	"slices"
 
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/logger"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
@@ -31,7 +32,7 @@ func (s *Service) AddTask(ctx context.Context, task models.TaskImportData) (mode
 	createdTask, err := s.dbClient.AddTask(ctx, task)
 
 	if err == nil {
-		s.logAction(actionLog)
+		s.logAction(logger.WithTask(actionLog, createdTask))
 		log.Printf("OUT(OK): add task: %+v\n", createdTask)
 	} else {
 		log.Printf("OUT(ERR): add task: %v\n", err)
@@ -49,6 +50,7 @@ func (s *Service) RemoveTask(ctx context.Context, id int) error {
 	err := s.dbClient.RemoveTask(ctx, id)
 
 	if err == nil {
+		actionLog.TaskId = id
 		s.logAction(actionLog)
 		log.Printf("OUT(OK): remove task with ID %v\n", id)
 	} else {
@@ -58,21 +60,107 @@ func (s *Service) RemoveTask(ctx context.Context, id int) error {
 	return err
 }
 
-func (s *Service) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
+func (s *Service) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	log.Println("IN: list trash")
+
+	actionLog := logger.CreateListTrashLog()
+
+	tasks, err := s.dbClient.ListTrash(ctx)
+
+	if err == nil {
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): list trash: %+v\n", tasks)
+	} else {
+		log.Printf("OUT(ERR): list trash: %v\n", err)
+	}
+
+	return tasks, err
+}
+
+func (s *Service) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	log.Printf("IN: restore task with ID: %v\n", id)
+
+	actionLog := logger.CreateTaskRestoredLog()
+
+	restoredTask, err := s.dbClient.RestoreTask(ctx, id)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, restoredTask))
+		log.Printf("OUT(OK): restore task with ID %v\n", id)
+	} else {
+		log.Printf("OUT(ERR): restore task with ID %v: %v\n", id, err)
+	}
+
+	return restoredTask, err
+}
+
+func (s *Service) PurgeTask(ctx context.Context, id int) error {
+	log.Printf("IN: purge task with ID: %v\n", id)
+
+	actionLog := logger.CreateTaskPurgedLog()
+
+	err := s.dbClient.PurgeTask(ctx, id)
+
+	if err == nil {
+		actionLog.TaskId = id
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): purge task with ID %v\n", id)
+	} else {
+		log.Printf("OUT(ERR): purge task with ID %v: %v\n", id, err)
+	}
+
+	return err
+}
+
+func (s *Service) GetTaskTree(ctx context.Context, id int) (models.TaskTree, error) {
+	log.Printf("IN: get task tree with ID: %v\n", id)
+
+	actionLog := logger.CreateGetTaskTreeLog()
+
+	tree, err := s.dbClient.GetTaskTree(ctx, id)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, tree.Task))
+		log.Printf("OUT(OK): get task tree with ID %v: %+v\n", id, tree)
+	} else {
+		log.Printf("OUT(ERR): get task tree with ID %v: %v\n", id, err)
+	}
+
+	return tree, err
+}
+
+func (s *Service) ListAllTasks(ctx context.Context) ([]models.TaskExportData, bool, error) {
 	log.Println("IN: list tasks")
 
 	actionLog := logger.CreateListTasksLog()
 
-	tasks, err := s.dbClient.ListAllTasks(ctx)
+	tasks, stale, err := s.dbClient.ListAllTasks(ctx)
 
 	if err == nil {
 		s.logAction(actionLog)
-		log.Printf("OUT(OK): list tasks: %+v\n", tasks)
+		log.Printf("OUT(OK): list tasks (stale: %v): %+v\n", stale, tasks)
 	} else {
 		log.Printf("OUT(ERR): list tasks: %v\n", err)
 	}
 
-	return tasks, err
+	return tasks, stale, err
+}
+
+func (s *Service) ListTasks(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+	log.Printf("IN: list tasks with filter: %+v\n", filter)
+
+	actionLog := logger.CreateListTasksLog()
+
+	tasks, stale, err := s.dbClient.ListTasks(ctx, filter)
+
+	if err == nil {
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): list tasks with filter (stale: %v): %+v\n", stale, tasks)
+	} else {
+		log.Printf("OUT(ERR): list tasks with filter: %v\n", err)
+	}
+
+	return tasks, stale, err
 }
 
 func (s *Service) MarkTaskFinished(ctx context.Context, id int) (models.TaskExportData, error) {
@@ -83,7 +171,7 @@ func (s *Service) MarkTaskFinished(ctx context.Context, id int) (models.TaskExpo
 	updatedTask, err := s.dbClient.MarkTaskFinished(ctx, id)
 
 	if err == nil {
-		s.logAction(actionLog)
+		s.logAction(logger.WithTask(actionLog, updatedTask))
 		log.Printf("OUT(OK): finish task with ID %v\n", id)
 	} else {
 		log.Printf("OUT(ERR): finish task with ID %v: %v\n", id, err)
@@ -92,6 +180,413 @@ func (s *Service) MarkTaskFinished(ctx context.Context, id int) (models.TaskExpo
 	return updatedTask, err
 }
 
+func (s *Service) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	log.Printf("IN: reopen task with ID: %v\n", id)
+
+	actionLog := logger.CreateTaskReopenedLog()
+
+	updatedTask, err := s.dbClient.ReopenTask(ctx, id)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, updatedTask))
+		log.Printf("OUT(OK): reopen task with ID %v\n", id)
+	} else {
+		log.Printf("OUT(ERR): reopen task with ID %v: %v\n", id, err)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	log.Printf("IN: set priority %v for task with ID: %v\n", priority, id)
+
+	actionLog := logger.CreateTaskPriorityLog()
+
+	updatedTask, err := s.dbClient.SetTaskPriority(ctx, id, priority)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, updatedTask))
+		log.Printf("OUT(OK): set priority for task with ID %v\n", id)
+	} else {
+		log.Printf("OUT(ERR): set priority for task with ID %v: %v\n", id, err)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	log.Printf("IN: move task: %+v\n", move)
+
+	actionLog := logger.CreateTaskMovedLog()
+
+	movedTask, err := s.dbClient.MoveTask(ctx, move)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, movedTask))
+		log.Printf("OUT(OK): move task with ID %v\n", move.Id)
+	} else {
+		log.Printf("OUT(ERR): move task with ID %v: %v\n", move.Id, err)
+	}
+
+	return movedTask, err
+}
+
+func (s *Service) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	log.Printf("IN: set recurrence %+v for task with ID: %v\n", rule, id)
+
+	actionLog := logger.CreateTaskRecurrenceLog()
+
+	updatedTask, err := s.dbClient.SetTaskRecurrence(ctx, id, rule)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, updatedTask))
+		log.Printf("OUT(OK): set recurrence for task with ID %v\n", id)
+	} else {
+		log.Printf("OUT(ERR): set recurrence for task with ID %v: %v\n", id, err)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	log.Printf("IN: skip occurrence of task with ID: %v\n", id)
+
+	actionLog := logger.CreateOccurrenceSkippedLog()
+
+	updatedTask, err := s.dbClient.SkipOccurrence(ctx, id)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, updatedTask))
+		log.Printf("OUT(OK): skip occurrence of task with ID %v\n", id)
+	} else {
+		log.Printf("OUT(ERR): skip occurrence of task with ID %v: %v\n", id, err)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	log.Printf("IN: add tags %v to task with ID: %v\n", tags, id)
+
+	actionLog := logger.CreateTaskTagsAddedLog()
+
+	updatedTask, err := s.dbClient.AddTaskTags(ctx, id, tags)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, updatedTask))
+		log.Printf("OUT(OK): add tags to task with ID %v: %v\n", id, updatedTask.Tags)
+	} else {
+		log.Printf("OUT(ERR): add tags to task with ID %v: %v\n", id, err)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	log.Printf("IN: remove tags %v from task with ID: %v\n", tags, id)
+
+	actionLog := logger.CreateTaskTagsRemovedLog()
+
+	updatedTask, err := s.dbClient.RemoveTaskTags(ctx, id, tags)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, updatedTask))
+		log.Printf("OUT(OK): remove tags from task with ID %v: %v\n", id, updatedTask.Tags)
+	} else {
+		log.Printf("OUT(ERR): remove tags from task with ID %v: %v\n", id, err)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	log.Println("IN: list tags")
+
+	actionLog := logger.CreateListTagsLog()
+
+	tags, err := s.dbClient.ListTags(ctx)
+
+	if err == nil {
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): list tags: %+v\n", tags)
+	} else {
+		log.Printf("OUT(ERR): list tags: %v\n", err)
+	}
+
+	return tags, err
+}
+
+func (s *Service) RenameTag(ctx context.Context, name, newName string) (models.TagChange, error) {
+	log.Printf("IN: rename tag %q to %q\n", name, newName)
+
+	actionLog := logger.CreateTagRenamedLog()
+
+	change, err := s.dbClient.RenameTag(ctx, name, newName)
+
+	if err == nil {
+		actionLog.Tags = []string{name, change.Name}
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): rename tag %q: %+v\n", name, change)
+	} else {
+		log.Printf("OUT(ERR): rename tag %q: %v\n", name, err)
+	}
+
+	return change, err
+}
+
+func (s *Service) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	log.Printf("IN: merge tags %v into %q\n", sources, target)
+
+	actionLog := logger.CreateTagsMergedLog()
+
+	change, err := s.dbClient.MergeTags(ctx, sources, target)
+
+	if err == nil {
+		actionLog.Tags = append(slices.Clone(sources), change.Name)
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): merge tags into %q: %+v\n", target, change)
+	} else {
+		log.Printf("OUT(ERR): merge tags into %q: %v\n", target, err)
+	}
+
+	return change, err
+}
+
+func (s *Service) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	log.Printf("IN: create project %q\n", name)
+
+	actionLog := logger.CreateProjectCreatedLog()
+
+	project, err := s.dbClient.CreateProject(ctx, name)
+
+	if err == nil {
+		actionLog.ProjectId = project.Id
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): create project: %+v\n", project)
+	} else {
+		log.Printf("OUT(ERR): create project %q: %v\n", name, err)
+	}
+
+	return project, err
+}
+
+func (s *Service) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	log.Printf("IN: list projects, include archived: %v\n", includeArchived)
+
+	actionLog := logger.CreateListProjectsLog()
+
+	projects, err := s.dbClient.ListProjects(ctx, includeArchived)
+
+	if err == nil {
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): list projects: %+v\n", projects)
+	} else {
+		log.Printf("OUT(ERR): list projects: %v\n", err)
+	}
+
+	return projects, err
+}
+
+func (s *Service) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	log.Printf("IN: rename project with ID %v to %q\n", id, name)
+
+	actionLog := logger.CreateProjectRenamedLog()
+
+	project, err := s.dbClient.RenameProject(ctx, id, name)
+
+	if err == nil {
+		actionLog.ProjectId = project.Id
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): rename project: %+v\n", project)
+	} else {
+		log.Printf("OUT(ERR): rename project with ID %v: %v\n", id, err)
+	}
+
+	return project, err
+}
+
+func (s *Service) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	log.Printf("IN: set archived=%v for project with ID: %v\n", archived, id)
+
+	actionLog := logger.CreateProjectArchivedLog()
+	if !archived {
+		actionLog = logger.CreateProjectRestoredLog()
+	}
+
+	project, err := s.dbClient.ArchiveProject(ctx, id, archived)
+
+	if err == nil {
+		actionLog.ProjectId = project.Id
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): archive project: %+v\n", project)
+	} else {
+		log.Printf("OUT(ERR): archive project with ID %v: %v\n", id, err)
+	}
+
+	return project, err
+}
+
+func (s *Service) DeleteProject(ctx context.Context, id int) error {
+	log.Printf("IN: delete project with ID: %v\n", id)
+
+	actionLog := logger.CreateProjectDeletedLog()
+
+	err := s.dbClient.DeleteProject(ctx, id)
+
+	if err == nil {
+		actionLog.ProjectId = id
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): delete project with ID %v\n", id)
+	} else {
+		log.Printf("OUT(ERR): delete project with ID %v: %v\n", id, err)
+	}
+
+	return err
+}
+
+func (s *Service) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	log.Printf("IN: move task with ID %v to project with ID: %v\n", id, projectId)
+
+	actionLog := logger.CreateTaskProjectChangedLog()
+
+	movedTask, err := s.dbClient.MoveTaskToProject(ctx, id, projectId)
+
+	if err == nil {
+		s.logAction(logger.WithTask(actionLog, movedTask))
+		log.Printf("OUT(OK): move task with ID %v to project with ID %v\n", id, movedTask.ProjectId)
+	} else {
+		log.Printf("OUT(ERR): move task with ID %v to project: %v\n", id, err)
+	}
+
+	return movedTask, err
+}
+
+func (s *Service) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	log.Printf("IN: get stats: %+v\n", req)
+
+	actionLog := logger.CreateGetStatsLog()
+
+	stats, err := s.dbClient.GetStats(ctx, req)
+
+	if err == nil {
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): get stats: %+v\n", stats)
+	} else {
+		log.Printf("OUT(ERR): get stats: %v\n", err)
+	}
+
+	return stats, err
+}
+
+func (s *Service) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	log.Printf("IN: search tasks: %+v\n", req)
+
+	actionLog := logger.CreateSearchTasksLog()
+
+	hits, err := s.dbClient.SearchTasks(ctx, req)
+
+	if err == nil {
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): search tasks: %d hits\n", len(hits))
+	} else {
+		log.Printf("OUT(ERR): search tasks: %v\n", err)
+	}
+
+	return hits, err
+}
+
+// WatchTasks passes the task events matching req to send until ctx is done,
+// send fails or db-service ends the stream. The watch is logged once it ends
+// normally.
+func (s *Service) WatchTasks(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+	log.Printf("IN: watch tasks: %+v\n", req)
+
+	actionLog := logger.CreateWatchTasksLog()
+
+	err := s.dbClient.WatchTasks(ctx, req, send)
+
+	if err == nil {
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): watch tasks: %+v\n", req)
+	} else {
+		log.Printf("OUT(ERR): watch tasks: %v\n", err)
+	}
+
+	return err
+}
+
+func (s *Service) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	log.Printf("IN: get history of task with ID %v: %+v\n", req.TaskId, req)
+
+	actionLog := logger.CreateGetTaskHistoryLog()
+
+	page, err := s.dbClient.GetTaskHistory(ctx, req)
+
+	if err == nil {
+		actionLog.TaskId = req.TaskId
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): get history of task with ID %v: %v changes\n", req.TaskId, len(page.Changes))
+	} else {
+		log.Printf("OUT(ERR): get history of task with ID %v: %v\n", req.TaskId, err)
+	}
+
+	return page, err
+}
+
+func (s *Service) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	log.Printf("IN: batch add %d tasks\n", len(tasks))
+
+	actionLog := logger.CreateTasksBatchAddedLog()
+
+	items, err := s.dbClient.BatchAddTasks(ctx, tasks)
+
+	if err == nil {
+		actionLog = logger.WithBatch(actionLog, items)
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): batch add tasks: %d failed\n", actionLog.Failed)
+	} else {
+		log.Printf("OUT(ERR): batch add tasks: %v\n", err)
+	}
+
+	return items, err
+}
+
+func (s *Service) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	log.Printf("IN: batch finish tasks with IDs: %v\n", ids)
+
+	actionLog := logger.CreateTasksBatchDoneLog()
+
+	items, err := s.dbClient.BatchMarkFinished(ctx, ids)
+
+	if err == nil {
+		actionLog = logger.WithBatch(actionLog, items)
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): batch finish tasks: %d failed\n", actionLog.Failed)
+	} else {
+		log.Printf("OUT(ERR): batch finish tasks: %v\n", err)
+	}
+
+	return items, err
+}
+
+func (s *Service) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	log.Printf("IN: batch delete tasks with IDs: %v\n", ids)
+
+	actionLog := logger.CreateTasksBatchDeletedLog()
+
+	items, err := s.dbClient.BatchRemoveTasks(ctx, ids)
+
+	if err == nil {
+		actionLog = logger.WithBatch(actionLog, items)
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): batch delete tasks: %d failed\n", actionLog.Failed)
+	} else {
+		log.Printf("OUT(ERR): batch delete tasks: %v\n", err)
+	}
+
+	return items, err
+}
+
 func (s *Service) logAction(actionLog models.ActionLog) {
 	select {
 	case s.logChannel <- actionLog:
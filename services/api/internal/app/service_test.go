@@ -3,21 +3,81 @@ package app
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
 )
 
 type fakeDBClient struct {
-	addFn    func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error)
-	removeFn func(ctx context.Context, id int) error
-	listFn   func(ctx context.Context) ([]models.TaskExportData, error)
-	doneFn   func(ctx context.Context, id int) (models.TaskExportData, error)
+	addFn            func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error)
+	removeFn         func(ctx context.Context, id int) error
+	listFn           func(ctx context.Context) ([]models.TaskExportData, bool, error)
+	doneFn           func(ctx context.Context, id int) (models.TaskExportData, error)
+	statsFn          func(ctx context.Context, req models.StatsRequest) (models.Stats, error)
+	filterFn         func(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error)
+	priorityFn       func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error)
+	moveFn           func(ctx context.Context, move models.TaskMove) (models.TaskExportData, error)
+	addTagsFn        func(ctx context.Context, id int, tags []string) (models.TaskExportData, error)
+	removeTagsFn     func(ctx context.Context, id int, tags []string) (models.TaskExportData, error)
+	listTagsFn       func(ctx context.Context) ([]models.TagUsage, error)
+	renameTagFn      func(ctx context.Context, name, newName string) (models.TagChange, error)
+	mergeTagsFn      func(ctx context.Context, sources []string, target string) (models.TagChange, error)
+	createProjectFn  func(ctx context.Context, name string) (models.Project, error)
+	listProjectsFn   func(ctx context.Context, includeArchived bool) ([]models.Project, error)
+	renameProjectFn  func(ctx context.Context, id int, name string) (models.Project, error)
+	archiveProjectFn func(ctx context.Context, id int, archived bool) (models.Project, error)
+	deleteProjectFn  func(ctx context.Context, id int) error
+	taskProjectFn    func(ctx context.Context, id, projectId int) (models.TaskExportData, error)
+	taskTreeFn       func(ctx context.Context, id int) (models.TaskTree, error)
+	recurrenceFn     func(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error)
+	skipFn           func(ctx context.Context, id int) (models.TaskExportData, error)
+	reopenFn         func(ctx context.Context, id int) (models.TaskExportData, error)
+	listTrashFn      func(ctx context.Context) ([]models.TaskExportData, error)
+	restoreFn        func(ctx context.Context, id int) (models.TaskExportData, error)
+	purgeFn          func(ctx context.Context, id int) error
+	historyFn        func(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error)
+	batchAddFn       func(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error)
+	batchFinishFn    func(ctx context.Context, ids []int) ([]models.BatchItem, error)
+	batchRemoveFn    func(ctx context.Context, ids []int) ([]models.BatchItem, error)
+	searchFn         func(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error)
+	watchFn          func(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error
+	changesFn        func(ctx context.Context, token string, limit int) (models.Changes, error)
 
-	addCalls    int
-	removeCalls int
-	listCalls   int
-	doneCalls   int
+	addCalls            int
+	removeCalls         int
+	listCalls           int
+	doneCalls           int
+	statsCalls          int
+	filterCalls         int
+	priorityCalls       int
+	moveCalls           int
+	addTagsCalls        int
+	removeTagsCalls     int
+	listTagsCalls       int
+	renameTagCalls      int
+	mergeTagsCalls      int
+	createProjectCalls  int
+	listProjectsCalls   int
+	renameProjectCalls  int
+	archiveProjectCalls int
+	deleteProjectCalls  int
+	taskProjectCalls    int
+	taskTreeCalls       int
+	recurrenceCalls     int
+	skipCalls           int
+	reopenCalls         int
+	listTrashCalls      int
+	restoreCalls        int
+	purgeCalls          int
+	historyCalls        int
+	batchAddCalls       int
+	batchFinishCalls    int
+	batchRemoveCalls    int
+	searchCalls         int
+	watchCalls          int
+	changesCalls        int
 
 	gotAddCtx  context.Context
 	gotAddTask models.TaskImportData
@@ -29,6 +89,90 @@ type fakeDBClient struct {
 
 	gotDoneCtx context.Context
 	gotDoneId  int
+
+	gotStatsCtx context.Context
+	gotStatsReq models.StatsRequest
+
+	gotFilterCtx context.Context
+	gotFilter    models.TaskFilter
+
+	gotPriorityCtx context.Context
+	gotPriorityId  int
+	gotPriority    models.Priority
+
+	gotMoveCtx context.Context
+	gotMove    models.TaskMove
+
+	gotAddTagsCtx context.Context
+	gotAddTagsId  int
+	gotAddTags    []string
+
+	gotRemoveTagsCtx context.Context
+	gotRemoveTagsId  int
+	gotRemoveTags    []string
+
+	gotListTagsCtx context.Context
+
+	gotRenameTagCtx     context.Context
+	gotRenameTagName    string
+	gotRenameTagNewName string
+
+	gotMergeTagsCtx context.Context
+	gotMergeSources []string
+	gotMergeTarget  string
+
+	gotCreateProjectCtx  context.Context
+	gotCreateProjectName string
+
+	gotListProjectsCtx context.Context
+	gotIncludeArchived bool
+
+	gotRenameProjectCtx  context.Context
+	gotRenameProjectId   int
+	gotRenameProjectName string
+
+	gotArchiveProjectCtx context.Context
+	gotArchiveProjectId  int
+	gotArchived          bool
+
+	gotDeleteProjectCtx context.Context
+	gotDeleteProjectId  int
+
+	gotTaskProjectCtx context.Context
+	gotTaskProjectId  int
+	gotProjectId      int
+
+	gotTaskTreeCtx context.Context
+	gotTaskTreeId  int
+
+	gotRecurrenceCtx context.Context
+	gotRecurrenceId  int
+	gotRecurrence    *models.Recurrence
+
+	gotSkipCtx context.Context
+	gotSkipId  int
+
+	gotReopenCtx context.Context
+	gotReopenId  int
+
+	gotRestoreCtx context.Context
+	gotRestoreId  int
+
+	gotPurgeCtx context.Context
+	gotPurgeId  int
+
+	gotHistoryCtx context.Context
+	gotHistoryReq models.TaskHistoryRequest
+
+	gotBatchTasks []models.TaskImportData
+
+	gotBatchFinishIds []int
+
+	gotBatchRemoveIds []int
+
+	gotSearch models.SearchRequest
+
+	gotWatch models.WatchRequest
 }
 
 func (f *fakeDBClient) AddTask(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
@@ -55,7 +199,7 @@ func (f *fakeDBClient) RemoveTask(ctx context.Context, id int) error {
 	return f.removeFn(ctx, id)
 }
 
-func (f *fakeDBClient) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
+func (f *fakeDBClient) ListAllTasks(ctx context.Context) ([]models.TaskExportData, bool, error) {
 	f.listCalls++
 	f.gotListCtx = ctx
 
@@ -78,6 +222,353 @@ func (f *fakeDBClient) MarkTaskFinished(ctx context.Context, id int) (models.Tas
 	return f.doneFn(ctx, id)
 }
 
+func (f *fakeDBClient) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	f.statsCalls++
+	f.gotStatsCtx = ctx
+	f.gotStatsReq = req
+
+	if f.statsFn == nil {
+		panic("GetStats called but statsFn not set")
+	}
+
+	return f.statsFn(ctx, req)
+}
+
+func (f *fakeDBClient) ListTasks(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+	f.filterCalls++
+	f.gotFilterCtx = ctx
+	f.gotFilter = filter
+
+	if f.filterFn == nil {
+		panic("ListTasks called but filterFn not set")
+	}
+
+	return f.filterFn(ctx, filter)
+}
+
+func (f *fakeDBClient) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	f.priorityCalls++
+	f.gotPriorityCtx = ctx
+	f.gotPriorityId = id
+	f.gotPriority = priority
+
+	if f.priorityFn == nil {
+		panic("SetTaskPriority called but priorityFn not set")
+	}
+
+	return f.priorityFn(ctx, id, priority)
+}
+
+func (f *fakeDBClient) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	f.moveCalls++
+	f.gotMoveCtx = ctx
+	f.gotMove = move
+
+	if f.moveFn == nil {
+		panic("MoveTask called but moveFn not set")
+	}
+
+	return f.moveFn(ctx, move)
+}
+
+func (f *fakeDBClient) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	f.addTagsCalls++
+	f.gotAddTagsCtx = ctx
+	f.gotAddTagsId = id
+	f.gotAddTags = tags
+
+	if f.addTagsFn == nil {
+		panic("AddTaskTags called but addTagsFn not set")
+	}
+
+	return f.addTagsFn(ctx, id, tags)
+}
+
+func (f *fakeDBClient) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	f.removeTagsCalls++
+	f.gotRemoveTagsCtx = ctx
+	f.gotRemoveTagsId = id
+	f.gotRemoveTags = tags
+
+	if f.removeTagsFn == nil {
+		panic("RemoveTaskTags called but removeTagsFn not set")
+	}
+
+	return f.removeTagsFn(ctx, id, tags)
+}
+
+func (f *fakeDBClient) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	f.listTagsCalls++
+	f.gotListTagsCtx = ctx
+
+	if f.listTagsFn == nil {
+		panic("ListTags called but listTagsFn not set")
+	}
+
+	return f.listTagsFn(ctx)
+}
+
+func (f *fakeDBClient) RenameTag(ctx context.Context, name, newName string) (models.TagChange, error) {
+	f.renameTagCalls++
+	f.gotRenameTagCtx = ctx
+	f.gotRenameTagName = name
+	f.gotRenameTagNewName = newName
+
+	if f.renameTagFn == nil {
+		panic("RenameTag called but renameTagFn not set")
+	}
+
+	return f.renameTagFn(ctx, name, newName)
+}
+
+func (f *fakeDBClient) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	f.mergeTagsCalls++
+	f.gotMergeTagsCtx = ctx
+	f.gotMergeSources = sources
+	f.gotMergeTarget = target
+
+	if f.mergeTagsFn == nil {
+		panic("MergeTags called but mergeTagsFn not set")
+	}
+
+	return f.mergeTagsFn(ctx, sources, target)
+}
+
+func (f *fakeDBClient) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	f.createProjectCalls++
+	f.gotCreateProjectCtx = ctx
+	f.gotCreateProjectName = name
+
+	if f.createProjectFn == nil {
+		panic("CreateProject called but createProjectFn not set")
+	}
+
+	return f.createProjectFn(ctx, name)
+}
+
+func (f *fakeDBClient) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	f.listProjectsCalls++
+	f.gotListProjectsCtx = ctx
+	f.gotIncludeArchived = includeArchived
+
+	if f.listProjectsFn == nil {
+		panic("ListProjects called but listProjectsFn not set")
+	}
+
+	return f.listProjectsFn(ctx, includeArchived)
+}
+
+func (f *fakeDBClient) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	f.renameProjectCalls++
+	f.gotRenameProjectCtx = ctx
+	f.gotRenameProjectId = id
+	f.gotRenameProjectName = name
+
+	if f.renameProjectFn == nil {
+		panic("RenameProject called but renameProjectFn not set")
+	}
+
+	return f.renameProjectFn(ctx, id, name)
+}
+
+func (f *fakeDBClient) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	f.archiveProjectCalls++
+	f.gotArchiveProjectCtx = ctx
+	f.gotArchiveProjectId = id
+	f.gotArchived = archived
+
+	if f.archiveProjectFn == nil {
+		panic("ArchiveProject called but archiveProjectFn not set")
+	}
+
+	return f.archiveProjectFn(ctx, id, archived)
+}
+
+func (f *fakeDBClient) DeleteProject(ctx context.Context, id int) error {
+	f.deleteProjectCalls++
+	f.gotDeleteProjectCtx = ctx
+	f.gotDeleteProjectId = id
+
+	if f.deleteProjectFn == nil {
+		panic("DeleteProject called but deleteProjectFn not set")
+	}
+
+	return f.deleteProjectFn(ctx, id)
+}
+
+func (f *fakeDBClient) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	f.taskProjectCalls++
+	f.gotTaskProjectCtx = ctx
+	f.gotTaskProjectId = id
+	f.gotProjectId = projectId
+
+	if f.taskProjectFn == nil {
+		panic("MoveTaskToProject called but taskProjectFn not set")
+	}
+
+	return f.taskProjectFn(ctx, id, projectId)
+}
+
+func (f *fakeDBClient) GetTaskTree(ctx context.Context, id int) (models.TaskTree, error) {
+	f.taskTreeCalls++
+	f.gotTaskTreeCtx = ctx
+	f.gotTaskTreeId = id
+
+	if f.taskTreeFn == nil {
+		panic("GetTaskTree called but taskTreeFn not set")
+	}
+
+	return f.taskTreeFn(ctx, id)
+}
+
+func (f *fakeDBClient) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	f.recurrenceCalls++
+	f.gotRecurrenceCtx = ctx
+	f.gotRecurrenceId = id
+	f.gotRecurrence = rule
+
+	if f.recurrenceFn == nil {
+		panic("SetTaskRecurrence called but recurrenceFn not set")
+	}
+
+	return f.recurrenceFn(ctx, id, rule)
+}
+
+func (f *fakeDBClient) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.skipCalls++
+	f.gotSkipCtx = ctx
+	f.gotSkipId = id
+
+	if f.skipFn == nil {
+		panic("SkipOccurrence called but skipFn not set")
+	}
+
+	return f.skipFn(ctx, id)
+}
+
+func (f *fakeDBClient) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.reopenCalls++
+	f.gotReopenCtx = ctx
+	f.gotReopenId = id
+
+	if f.reopenFn == nil {
+		panic("ReopenTask called but reopenFn not set")
+	}
+
+	return f.reopenFn(ctx, id)
+}
+
+func (f *fakeDBClient) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	f.listTrashCalls++
+
+	if f.listTrashFn == nil {
+		panic("ListTrash called but listTrashFn not set")
+	}
+
+	return f.listTrashFn(ctx)
+}
+
+func (f *fakeDBClient) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.restoreCalls++
+	f.gotRestoreCtx = ctx
+	f.gotRestoreId = id
+
+	if f.restoreFn == nil {
+		panic("RestoreTask called but restoreFn not set")
+	}
+
+	return f.restoreFn(ctx, id)
+}
+
+func (f *fakeDBClient) PurgeTask(ctx context.Context, id int) error {
+	f.purgeCalls++
+	f.gotPurgeCtx = ctx
+	f.gotPurgeId = id
+
+	if f.purgeFn == nil {
+		panic("PurgeTask called but purgeFn not set")
+	}
+
+	return f.purgeFn(ctx, id)
+}
+
+func (f *fakeDBClient) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	f.historyCalls++
+	f.gotHistoryCtx = ctx
+	f.gotHistoryReq = req
+
+	if f.historyFn == nil {
+		panic("GetTaskHistory called but historyFn not set")
+	}
+
+	return f.historyFn(ctx, req)
+}
+
+func (f *fakeDBClient) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	f.batchAddCalls++
+	f.gotBatchTasks = tasks
+
+	if f.batchAddFn == nil {
+		panic("BatchAddTasks called but batchAddFn not set")
+	}
+
+	return f.batchAddFn(ctx, tasks)
+}
+
+func (f *fakeDBClient) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	f.batchFinishCalls++
+	f.gotBatchFinishIds = ids
+
+	if f.batchFinishFn == nil {
+		panic("BatchMarkFinished called but batchFinishFn not set")
+	}
+
+	return f.batchFinishFn(ctx, ids)
+}
+
+func (f *fakeDBClient) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	f.batchRemoveCalls++
+	f.gotBatchRemoveIds = ids
+
+	if f.batchRemoveFn == nil {
+		panic("BatchRemoveTasks called but batchRemoveFn not set")
+	}
+
+	return f.batchRemoveFn(ctx, ids)
+}
+
+func (f *fakeDBClient) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	f.searchCalls++
+	f.gotSearch = req
+
+	if f.searchFn == nil {
+		panic("SearchTasks called but searchFn not set")
+	}
+
+	return f.searchFn(ctx, req)
+}
+
+func (f *fakeDBClient) WatchTasks(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+	f.watchCalls++
+	f.gotWatch = req
+
+	if f.watchFn == nil {
+		panic("WatchTasks called but watchFn not set")
+	}
+
+	return f.watchFn(ctx, req, send)
+}
+
+func (f *fakeDBClient) GetChangesSince(ctx context.Context, token string, limit int) (models.Changes, error) {
+	f.changesCalls++
+
+	if f.changesFn == nil {
+		panic("GetChangesSince called but changesFn not set")
+	}
+
+	return f.changesFn(ctx, token, limit)
+}
+
 func mustLog(t *testing.T, ch <-chan models.ActionLog) models.ActionLog {
 	t.Helper()
 	select {
@@ -187,14 +678,14 @@ func TestService_RemoveTask_Error_DoesNotSendLog(t *testing.T) {
 
 func TestService_ListAllTasks_Success_SendsLog(t *testing.T) {
 	db := &fakeDBClient{
-		listFn: func(ctx context.Context) ([]models.TaskExportData, error) {
-			return nil, nil
+		listFn: func(ctx context.Context) ([]models.TaskExportData, bool, error) {
+			return nil, false, nil
 		},
 	}
 
 	svc := NewService(db)
 
-	_, err := svc.ListAllTasks(context.Background())
+	_, _, err := svc.ListAllTasks(context.Background())
 
 	if err != nil {
 		t.Fatalf("expected nil, got %v", err)
@@ -210,14 +701,14 @@ func TestService_ListAllTasks_Success_SendsLog(t *testing.T) {
 func TestService_ListAllTasks_Error_DoesNotSendLog(t *testing.T) {
 	wantErr := errors.New("my db error")
 	db := &fakeDBClient{
-		listFn: func(ctx context.Context) ([]models.TaskExportData, error) {
-			return nil, wantErr
+		listFn: func(ctx context.Context) ([]models.TaskExportData, bool, error) {
+			return nil, false, wantErr
 		},
 	}
 
 	svc := NewService(db)
 
-	_, err := svc.ListAllTasks(context.Background())
+	_, _, err := svc.ListAllTasks(context.Background())
 
 	if !errors.Is(err, wantErr) {
 		t.Fatalf("expected %v, got %v", wantErr, err)
@@ -273,3 +764,974 @@ func TestService_MarkTaskFinished_Error_DoesNotSendLog(t *testing.T) {
 	}
 	mustNotLog(t, svc.GetLogChannel())
 }
+
+func TestService_GetStats_Success_SendsLog(t *testing.T) {
+	wantReq := models.StatsRequest{Bucket: models.StatsBucketWeek, TimeZone: "UTC"}
+	wantStats := models.Stats{OpenCount: 3, CurrentStreak: 2}
+	db := &fakeDBClient{
+		statsFn: func(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+			return wantStats, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.GetStats(context.Background(), wantReq)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.statsCalls != 1 {
+		t.Fatalf("expected GetStats calls = 1, got %d", db.statsCalls)
+	}
+	if db.gotStatsReq != wantReq {
+		t.Fatalf("expected request %+v, got %+v", wantReq, db.gotStatsReq)
+	}
+	if got.OpenCount != 3 || got.CurrentStreak != 2 {
+		t.Fatalf("unexpected stats %+v", got)
+	}
+
+	logCh := svc.GetLogChannel()
+	mustLog(t, logCh)
+}
+
+func TestService_GetStats_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("my db error")
+	db := &fakeDBClient{
+		statsFn: func(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+			return models.Stats{}, wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.GetStats(context.Background(), models.StatsRequest{})
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_ListTasks_Success_SendsLog(t *testing.T) {
+	wantFilter := models.TaskFilter{Due: models.DueFilterToday, TimeZone: "Europe/Moscow"}
+	db := &fakeDBClient{
+		filterFn: func(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+			return []models.TaskExportData{{Id: 7}}, false, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, _, err := svc.ListTasks(context.Background(), wantFilter)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.filterCalls != 1 {
+		t.Fatalf("expected ListTasks calls = 1, got %d", db.filterCalls)
+	}
+	if !reflect.DeepEqual(db.gotFilter, wantFilter) {
+		t.Fatalf("expected filter %+v, got %+v", wantFilter, db.gotFilter)
+	}
+	if len(got) != 1 || got[0].Id != 7 {
+		t.Fatalf("unexpected tasks %+v", got)
+	}
+
+	logCh := svc.GetLogChannel()
+	mustLog(t, logCh)
+}
+
+func TestService_ListTasks_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("my db error")
+	db := &fakeDBClient{
+		filterFn: func(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+			return nil, false, wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	_, _, err := svc.ListTasks(context.Background(), models.TaskFilter{})
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_SetTaskPriority_Success_SendsLog(t *testing.T) {
+	db := &fakeDBClient{
+		priorityFn: func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, Priority: priority}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.SetTaskPriority(context.Background(), 4, models.PriorityHigh)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.priorityCalls != 1 {
+		t.Fatalf("expected SetTaskPriority calls = 1, got %d", db.priorityCalls)
+	}
+	if db.gotPriorityId != 4 || db.gotPriority != models.PriorityHigh {
+		t.Fatalf("unexpected args: id=%d priority=%v", db.gotPriorityId, db.gotPriority)
+	}
+	if got.Priority != models.PriorityHigh {
+		t.Fatalf("unexpected task %+v", got)
+	}
+
+	logCh := svc.GetLogChannel()
+	mustLog(t, logCh)
+}
+
+func TestService_SetTaskPriority_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("my db error")
+	db := &fakeDBClient{
+		priorityFn: func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+			return models.TaskExportData{}, wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.SetTaskPriority(context.Background(), 1, models.PriorityLow)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_MoveTask_Success_SendsLog(t *testing.T) {
+	wantMove := models.TaskMove{Id: 1, AfterId: 2}
+	db := &fakeDBClient{
+		moveFn: func(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: move.Id, Position: "ai"}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.MoveTask(context.Background(), wantMove)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.moveCalls != 1 {
+		t.Fatalf("expected MoveTask calls = 1, got %d", db.moveCalls)
+	}
+	if db.gotMove != wantMove {
+		t.Fatalf("expected move %+v, got %+v", wantMove, db.gotMove)
+	}
+	if got.Position != "ai" {
+		t.Fatalf("unexpected task %+v", got)
+	}
+
+	logCh := svc.GetLogChannel()
+	mustLog(t, logCh)
+}
+
+func TestService_MoveTask_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("my db error")
+	db := &fakeDBClient{
+		moveFn: func(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+			return models.TaskExportData{}, wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.MoveTask(context.Background(), models.TaskMove{Id: 1, BeforeId: 2})
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_AddTaskTags_Success_SendsLogWithTags(t *testing.T) {
+	ctx := context.Background()
+	db := &fakeDBClient{
+		addTagsFn: func(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, Tags: []string{"home", "work"}}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.AddTaskTags(ctx, 4, []string{"work"})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.addTagsCalls != 1 || db.gotAddTagsCtx != ctx {
+		t.Fatalf("expected AddTaskTags called once with ctx, got %d calls", db.addTagsCalls)
+	}
+	if db.gotAddTagsId != 4 || !reflect.DeepEqual(db.gotAddTags, []string{"work"}) {
+		t.Fatalf("unexpected args: id=%d tags=%v", db.gotAddTagsId, db.gotAddTags)
+	}
+	if got.Id != 4 {
+		t.Fatalf("unexpected task %+v", got)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "task tags added" || actionLog.TaskId != 4 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+	if !reflect.DeepEqual(actionLog.Tags, []string{"home", "work"}) {
+		t.Fatalf("expected log tags %v, got %v", []string{"home", "work"}, actionLog.Tags)
+	}
+}
+
+func TestService_RemoveTaskTags_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("my db error")
+	db := &fakeDBClient{
+		removeTagsFn: func(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+			return models.TaskExportData{}, wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.RemoveTaskTags(context.Background(), 4, []string{"work"})
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_ListTags_Success_SendsLog(t *testing.T) {
+	db := &fakeDBClient{
+		listTagsFn: func(ctx context.Context) ([]models.TagUsage, error) {
+			return []models.TagUsage{{Name: "home", Count: 1}}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.ListTags(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.listTagsCalls != 1 {
+		t.Fatalf("expected ListTags calls = 1, got %d", db.listTagsCalls)
+	}
+	if len(got) != 1 || got[0].Name != "home" {
+		t.Fatalf("unexpected tags %+v", got)
+	}
+
+	mustLog(t, svc.GetLogChannel())
+}
+
+func TestService_RenameTag_Success_SendsLogWithBothNames(t *testing.T) {
+	db := &fakeDBClient{
+		renameTagFn: func(ctx context.Context, name, newName string) (models.TagChange, error) {
+			return models.TagChange{Name: newName, TaskIds: []int{2}}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.RenameTag(context.Background(), "work", "job")
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.gotRenameTagName != "work" || db.gotRenameTagNewName != "job" {
+		t.Fatalf("unexpected args: %q -> %q", db.gotRenameTagName, db.gotRenameTagNewName)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if !reflect.DeepEqual(actionLog.Tags, []string{"work", "job"}) {
+		t.Fatalf("expected log tags %v, got %v", []string{"work", "job"}, actionLog.Tags)
+	}
+}
+
+func TestService_MergeTags_Success_SendsLogWithAllTags(t *testing.T) {
+	sources := []string{"job", "office"}
+	db := &fakeDBClient{
+		mergeTagsFn: func(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+			return models.TagChange{Name: target}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.MergeTags(context.Background(), sources, "work")
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !reflect.DeepEqual(db.gotMergeSources, sources) || db.gotMergeTarget != "work" {
+		t.Fatalf("unexpected args: %v -> %q", db.gotMergeSources, db.gotMergeTarget)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if !reflect.DeepEqual(actionLog.Tags, []string{"job", "office", "work"}) {
+		t.Fatalf("unexpected log tags %v", actionLog.Tags)
+	}
+	if !reflect.DeepEqual(sources, []string{"job", "office"}) {
+		t.Fatalf("expected sources untouched, got %v", sources)
+	}
+}
+
+func TestService_CreateProject_Success_SendsLogWithProject(t *testing.T) {
+	ctx := context.Background()
+	db := &fakeDBClient{
+		createProjectFn: func(ctx context.Context, name string) (models.Project, error) {
+			return models.Project{Id: 3, Name: name}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.CreateProject(ctx, "Work")
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.createProjectCalls != 1 || db.gotCreateProjectCtx != ctx || db.gotCreateProjectName != "Work" {
+		t.Fatalf("unexpected call: calls=%d name=%q", db.createProjectCalls, db.gotCreateProjectName)
+	}
+	if got.Id != 3 {
+		t.Fatalf("unexpected project %+v", got)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "project created" || actionLog.ProjectId != 3 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_ArchiveProject_Restore_SendsRestoredLog(t *testing.T) {
+	db := &fakeDBClient{
+		archiveProjectFn: func(ctx context.Context, id int, archived bool) (models.Project, error) {
+			return models.Project{Id: id, Archived: archived}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.ArchiveProject(context.Background(), 3, false)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.gotArchiveProjectId != 3 || db.gotArchived {
+		t.Fatalf("unexpected args: id=%d archived=%v", db.gotArchiveProjectId, db.gotArchived)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "project restored" {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_DeleteProject_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("my db error")
+	db := &fakeDBClient{
+		deleteProjectFn: func(ctx context.Context, id int) error {
+			return wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	err := svc.DeleteProject(context.Background(), 3)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if db.deleteProjectCalls != 1 || db.gotDeleteProjectId != 3 {
+		t.Fatalf("unexpected call: calls=%d id=%d", db.deleteProjectCalls, db.gotDeleteProjectId)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_MoveTaskToProject_Success_SendsLogWithTask(t *testing.T) {
+	db := &fakeDBClient{
+		taskProjectFn: func(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, ProjectId: projectId}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.MoveTaskToProject(context.Background(), 5, 3)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.gotTaskProjectId != 5 || db.gotProjectId != 3 {
+		t.Fatalf("unexpected args: id=%d project=%d", db.gotTaskProjectId, db.gotProjectId)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.TaskId != 5 || actionLog.ProjectId != 3 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_GetTaskTree_Success_SendsLogWithTask(t *testing.T) {
+	ctx := context.Background()
+	db := &fakeDBClient{
+		taskTreeFn: func(ctx context.Context, id int) (models.TaskTree, error) {
+			return models.TaskTree{
+				Task:     models.TaskExportData{Id: id, ProjectId: 2, SubtasksTotal: 1},
+				Subtasks: []models.TaskTree{{Task: models.TaskExportData{Id: 8, ParentId: id}}},
+			}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.GetTaskTree(ctx, 7)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.gotTaskTreeCtx != ctx || db.gotTaskTreeId != 7 {
+		t.Fatalf("unexpected args: id=%d", db.gotTaskTreeId)
+	}
+	if len(got.Subtasks) != 1 || got.Subtasks[0].Task.ParentId != 7 {
+		t.Fatalf("unexpected tree %+v", got)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.TaskId != 7 || actionLog.ProjectId != 2 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_GetTaskTree_Error_DoesNotSendLog(t *testing.T) {
+	db := &fakeDBClient{
+		taskTreeFn: func(ctx context.Context, id int) (models.TaskTree, error) {
+			return models.TaskTree{}, ErrNotFound
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.GetTaskTree(context.Background(), 7)
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_SetTaskRecurrence_Success_SendsLogWithTask(t *testing.T) {
+	rule := &models.Recurrence{Frequency: models.RecurrenceDaily, Interval: 1}
+	db := &fakeDBClient{
+		recurrenceFn: func(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, Recurrence: rule}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.SetTaskRecurrence(context.Background(), 4, rule)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.gotRecurrenceId != 4 || db.gotRecurrence != rule {
+		t.Fatalf("unexpected args: id=%d rule=%+v", db.gotRecurrenceId, db.gotRecurrence)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "task recurrence changed" || actionLog.TaskId != 4 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_SkipOccurrence_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("my db error")
+	db := &fakeDBClient{
+		skipFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{}, wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.SkipOccurrence(context.Background(), 4)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if db.skipCalls != 1 || db.gotSkipId != 4 {
+		t.Fatalf("unexpected call: calls=%d id=%d", db.skipCalls, db.gotSkipId)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_ReopenTask_Success_SendsLogWithTask(t *testing.T) {
+	db := &fakeDBClient{
+		reopenFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, Title: "gym"}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.ReopenTask(context.Background(), 8)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.reopenCalls != 1 || db.gotReopenId != 8 || got.Finished {
+		t.Fatalf("unexpected call: calls=%d id=%d task=%+v", db.reopenCalls, db.gotReopenId, got)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "task reopened" || actionLog.TaskId != 8 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_ReopenTask_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("my db error")
+	db := &fakeDBClient{
+		reopenFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{}, wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.ReopenTask(context.Background(), 8)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_RestoreTask_Success_SendsLogWithTask(t *testing.T) {
+	db := &fakeDBClient{
+		restoreFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, ProjectId: 2}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.RestoreTask(context.Background(), 6)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.gotRestoreId != 6 {
+		t.Fatalf("unexpected id %d", db.gotRestoreId)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "task restored" || actionLog.TaskId != 6 || actionLog.ProjectId != 2 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_PurgeTask_Success_SendsLogWithTaskId(t *testing.T) {
+	db := &fakeDBClient{
+		purgeFn: func(ctx context.Context, id int) error {
+			return nil
+		},
+	}
+
+	svc := NewService(db)
+
+	err := svc.PurgeTask(context.Background(), 6)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "task purged" || actionLog.TaskId != 6 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_PurgeTask_Error_DoesNotSendLog(t *testing.T) {
+	db := &fakeDBClient{
+		purgeFn: func(ctx context.Context, id int) error {
+			return ErrConflict
+		},
+	}
+
+	svc := NewService(db)
+
+	err := svc.PurgeTask(context.Background(), 6)
+
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected %v, got %v", ErrConflict, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_GetTaskHistory_Success_SendsLog(t *testing.T) {
+	ctx := context.Background()
+	wantReq := models.TaskHistoryRequest{TaskId: 4, Limit: 2, Cursor: 10}
+	wantPage := models.TaskHistoryPage{
+		Changes:    []models.TaskChange{{Id: 9, TaskId: 4, Field: "title"}, {Id: 8, TaskId: 4, Field: "priority"}},
+		NextCursor: 8,
+	}
+	db := &fakeDBClient{
+		historyFn: func(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+			return wantPage, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	got, err := svc.GetTaskHistory(ctx, wantReq)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.historyCalls != 1 {
+		t.Fatalf("expected GetTaskHistory calls = 1, got %d", db.historyCalls)
+	}
+	if db.gotHistoryCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if db.gotHistoryReq != wantReq {
+		t.Fatalf("expected request %+v, got %+v", wantReq, db.gotHistoryReq)
+	}
+	if !reflect.DeepEqual(got, wantPage) {
+		t.Fatalf("expected page %+v, got %+v", wantPage, got)
+	}
+
+	logCh := svc.GetLogChannel()
+	gotLog := mustLog(t, logCh)
+	if gotLog.Action != "get task history" || gotLog.TaskId != 4 {
+		t.Fatalf("unexpected log %+v", gotLog)
+	}
+}
+
+func TestService_GetTaskHistory_Error_DoesNotSendLog(t *testing.T) {
+	db := &fakeDBClient{
+		historyFn: func(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+			return models.TaskHistoryPage{}, ErrNotFound
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.GetTaskHistory(context.Background(), models.TaskHistoryRequest{TaskId: 4})
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestActorFrom(t *testing.T) {
+	if got := ActorFrom(context.Background()); got != "" {
+		t.Fatalf("expected empty actor, got %q", got)
+	}
+	if got := ActorFrom(WithActor(context.Background(), "alice")); got != "alice" {
+		t.Fatalf("expected %q, got %q", "alice", got)
+	}
+}
+
+func TestService_BatchMarkFinished_Success_SendsOneBatchLog(t *testing.T) {
+	db := &fakeDBClient{
+		batchFinishFn: func(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+			return []models.BatchItem{
+				{Id: 1, Task: models.TaskExportData{Id: 1, Finished: true}},
+				{Id: 2, Err: ErrNotFound},
+				{Id: 3, Task: models.TaskExportData{Id: 3, Finished: true}},
+			}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	items, err := svc.BatchMarkFinished(context.Background(), []int{1, 2, 3})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(items) != 3 || !reflect.DeepEqual(db.gotBatchFinishIds, []int{1, 2, 3}) {
+		t.Fatalf("unexpected items %+v for ids %v", items, db.gotBatchFinishIds)
+	}
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "tasks done in batch" || !reflect.DeepEqual(actionLog.TaskIds, []int{1, 3}) || actionLog.Failed != 1 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_BatchRemoveTasks_Error_DoesNotSendLog(t *testing.T) {
+	db := &fakeDBClient{
+		batchRemoveFn: func(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+			return nil, ErrInvalidArgument
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.BatchRemoveTasks(context.Background(), nil)
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_SearchTasks_Success_SendsLog(t *testing.T) {
+	db := &fakeDBClient{
+		searchFn: func(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+			return []models.SearchHit{{Task: models.TaskExportData{Id: 2}, Rank: 0.3}}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	hits, err := svc.SearchTasks(context.Background(), models.SearchRequest{Query: "milk", Limit: 5})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(hits) != 1 || db.gotSearch.Query != "milk" || db.gotSearch.Limit != 5 {
+		t.Fatalf("unexpected hits %+v for request %+v", hits, db.gotSearch)
+	}
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "search tasks" {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_SearchTasks_Error_DoesNotSendLog(t *testing.T) {
+	db := &fakeDBClient{
+		searchFn: func(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+			return nil, ErrInvalidArgument
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.SearchTasks(context.Background(), models.SearchRequest{Query: "?"})
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_WatchTasks_Success_PassesEventsAndSendsLog(t *testing.T) {
+	db := &fakeDBClient{
+		watchFn: func(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+			return send(models.TaskEvent{Id: 8, Type: models.TaskEventCreated, TaskId: 2})
+		},
+	}
+
+	svc := NewService(db)
+
+	var got []models.TaskEvent
+	err := svc.WatchTasks(context.Background(), models.WatchRequest{ProjectId: 1, LastEventId: 7}, func(event models.TaskEvent) error {
+		got = append(got, event)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.gotWatch != (models.WatchRequest{ProjectId: 1, LastEventId: 7}) {
+		t.Fatalf("unexpected request %+v", db.gotWatch)
+	}
+	if len(got) != 1 || got[0].Id != 8 {
+		t.Fatalf("unexpected events %+v", got)
+	}
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "watch tasks" {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_WatchTasks_Error_DoesNotSendLog(t *testing.T) {
+	wantErr := errors.New("stream broken")
+	db := &fakeDBClient{
+		watchFn: func(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+			return wantErr
+		},
+	}
+
+	svc := NewService(db)
+
+	err := svc.WatchTasks(context.Background(), models.WatchRequest{}, func(models.TaskEvent) error { return nil })
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_GetChangesSince_Success_SendsLog(t *testing.T) {
+	db := &fakeDBClient{
+		changesFn: func(ctx context.Context, token string, limit int) (models.Changes, error) {
+			if token != "abc" || limit != 10 {
+				t.Fatalf("unexpected token %q, limit %d", token, limit)
+			}
+			return models.Changes{Tasks: []models.TaskExportData{{Id: 2}}, DeletedIds: []int{3}, Token: "def"}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	changes, err := svc.GetChangesSince(context.Background(), "abc", 10)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if changes.Token != "def" || len(changes.Tasks) != 1 || len(changes.DeletedIds) != 1 {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	actionLog := mustLog(t, svc.GetLogChannel())
+	if actionLog.Action != "get changes" {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+}
+
+func TestService_GetChangesSince_Error_DoesNotSendLog(t *testing.T) {
+	db := &fakeDBClient{
+		changesFn: func(ctx context.Context, token string, limit int) (models.Changes, error) {
+			return models.Changes{}, ErrInvalidArgument
+		},
+	}
+
+	svc := NewService(db)
+
+	_, err := svc.GetChangesSince(context.Background(), "bad", 0)
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	mustNotLog(t, svc.GetLogChannel())
+}
+
+func TestService_ApplySyncMutations_LastWriterWins_IgnoresVersionsAndKeysCreations(t *testing.T) {
+	var gotVersions []int
+	db := &fakeDBClient{
+		addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: 7, Title: task.Title}, nil
+		},
+		doneFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			gotVersions = append(gotVersions, ExpectedVersionFrom(ctx))
+			return models.TaskExportData{Id: id, Finished: true}, nil
+		},
+		removeFn: func(ctx context.Context, id int) error {
+			return nil
+		},
+	}
+
+	svc := NewService(db)
+
+	mutations := []models.Mutation{
+		{Id: "m1", Type: "create", Task: &models.TaskImportData{Title: "Buy milk"}},
+		{Id: "m2", Type: "done", TaskId: 3, Version: 2},
+		{Id: "m3", Type: "delete", TaskId: 4},
+	}
+	results, err := svc.ApplySyncMutations(WithActor(context.Background(), "alice"), models.ConflictLastWriterWins, mutations)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if db.gotAddTask.IdempotencyKey != "sync:alice:m1" {
+		t.Fatalf("expected idempotency key sync:alice:m1, got %q", db.gotAddTask.IdempotencyKey)
+	}
+	if mutations[0].Task.IdempotencyKey != "" {
+		t.Fatal("expected the caller's task to stay unchanged")
+	}
+	if len(gotVersions) != 1 || gotVersions[0] != 0 {
+		t.Fatalf("expected no expected version, got %v", gotVersions)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	if results[0].Id != "m1" || results[0].Task == nil || results[0].Task.Id != 7 {
+		t.Fatalf("unexpected create result %+v", results[0])
+	}
+	if results[1].Task == nil || !results[1].Task.Finished {
+		t.Fatalf("unexpected done result %+v", results[1])
+	}
+	if results[2].Task != nil || results[2].Err != nil {
+		t.Fatalf("unexpected delete result %+v", results[2])
+	}
+}
+
+func TestService_ApplySyncMutations_VersionConflict_ReportsCurrentTask(t *testing.T) {
+	db := &fakeDBClient{
+		priorityFn: func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+			if ExpectedVersionFrom(ctx) != 2 {
+				t.Fatalf("expected version 2, got %d", ExpectedVersionFrom(ctx))
+			}
+			return models.TaskExportData{}, ErrPreconditionFailed
+		},
+		taskTreeFn: func(ctx context.Context, id int) (models.TaskTree, error) {
+			return models.TaskTree{Task: models.TaskExportData{Id: id, Version: 5}}, nil
+		},
+	}
+
+	svc := NewService(db)
+
+	results, err := svc.ApplySyncMutations(context.Background(), models.ConflictVersion, []models.Mutation{
+		{Id: "m1", Type: "priority", TaskId: 3, Priority: models.PriorityHigh, Version: 2},
+		{Id: "m2", Type: "done"},
+	})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrPreconditionFailed) || results[0].Current == nil || results[0].Current.Version != 5 {
+		t.Fatalf("unexpected conflict result %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, ErrInvalidArgument) {
+		t.Fatalf("expected %v for a mutation without task_id, got %+v", ErrInvalidArgument, results[1])
+	}
+	if db.doneCalls != 0 {
+		t.Fatalf("expected MarkTaskFinished not called, got %d calls", db.doneCalls)
+	}
+}
+
+func TestService_ApplySyncMutations_BadId_IsRejectedBeforeCreate(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := NewService(db)
+
+	results, err := svc.ApplySyncMutations(context.Background(), models.ConflictVersion, []models.Mutation{
+		{Type: "create", Task: &models.TaskImportData{Title: "Buy milk"}},
+		{Id: strings.Repeat("m", MaxMutationIdLength+1), Type: "create", Task: &models.TaskImportData{Title: "Buy milk"}},
+	})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	for i, result := range results {
+		if !errors.Is(result.Err, ErrInvalidArgument) {
+			t.Fatalf("mutation %d: expected %v, got %+v", i, ErrInvalidArgument, result)
+		}
+	}
+	if db.addCalls != 0 {
+		t.Fatalf("expected AddTask not called, got %d calls", db.addCalls)
+	}
+}
+
+func TestService_ApplySyncMutations_BadSize_ReturnsInvalidArgument(t *testing.T) {
+	svc := NewService(&fakeDBClient{})
+
+	for _, n := range []int{0, MaxSyncMutations + 1} {
+		_, err := svc.ApplySyncMutations(context.Background(), models.ConflictVersion, make([]models.Mutation, n))
+
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%d mutations: expected %v, got %v", n, ErrInvalidArgument, err)
+		}
+	}
+}
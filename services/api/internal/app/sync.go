@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/logger"
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+)
+
+// MaxSyncMutations limits how many queued mutations one push may apply.
+const MaxSyncMutations = 500
+
+// MaxMutationIdLength limits the id of a queued mutation, which is a part of
+// the idempotency key of a creation.
+const MaxMutationIdLength = 128
+
+func (s *Service) GetChangesSince(ctx context.Context, token string, limit int) (models.Changes, error) {
+	log.Printf("IN: get changes since %q, limit %d\n", token, limit)
+
+	actionLog := logger.CreateGetChangesLog()
+
+	changes, err := s.dbClient.GetChangesSince(ctx, token, limit)
+
+	if err == nil {
+		s.logAction(actionLog)
+		log.Printf("OUT(OK): get changes: %d changed, %d deleted\n", len(changes.Tasks), len(changes.DeletedIds))
+	} else {
+		log.Printf("OUT(ERR): get changes: %v\n", err)
+	}
+
+	return changes, err
+}
+
+// ApplyMutation makes one client change with the service method for it.
+// Deleting returns no task.
+func (s *Service) ApplyMutation(ctx context.Context, m models.Mutation) (models.TaskExportData, error) {
+	if m.Version > 0 {
+		ctx = WithExpectedVersion(ctx, m.Version)
+	}
+	if m.Type != "create" && m.TaskId <= 0 {
+		return models.TaskExportData{}, fmt.Errorf("%w: task_id must be a positive task ID", ErrInvalidArgument)
+	}
+
+	switch m.Type {
+	case "create":
+		if m.Task == nil {
+			return models.TaskExportData{}, fmt.Errorf("%w: task is required", ErrInvalidArgument)
+		}
+		return s.AddTask(ctx, *m.Task)
+	case "done":
+		return s.MarkTaskFinished(ctx, m.TaskId)
+	case "reopen":
+		return s.ReopenTask(ctx, m.TaskId)
+	case "priority":
+		return s.SetTaskPriority(ctx, m.TaskId, m.Priority)
+	case "delete":
+		return models.TaskExportData{}, s.RemoveTask(ctx, m.TaskId)
+	default:
+		return models.TaskExportData{}, fmt.Errorf("%w: unknown mutation type %q, expected create, done, reopen, priority or delete",
+			ErrInvalidArgument, m.Type)
+	}
+}
+
+// ApplySyncMutations applies the mutations queued by an offline client in
+// order; a failed one doesn't stop the rest. Creations use the actor and the
+// mutation id as their idempotency key, so a push retried after a lost
+// answer doesn't duplicate tasks. The key is prefixed with "sync:" to stay
+// apart from the Idempotency-Key of POST /create.
+func (s *Service) ApplySyncMutations(ctx context.Context, policy models.ConflictPolicy, mutations []models.Mutation) ([]models.MutationResult, error) {
+	if len(mutations) == 0 || len(mutations) > MaxSyncMutations {
+		return nil, fmt.Errorf("%w: a push takes from 1 to %d mutations", ErrInvalidArgument, MaxSyncMutations)
+	}
+
+	results := make([]models.MutationResult, 0, len(mutations))
+	for _, m := range mutations {
+		if policy == models.ConflictLastWriterWins {
+			m.Version = 0
+		}
+		result := models.MutationResult{Id: m.Id}
+		if m.Id == "" || len(m.Id) > MaxMutationIdLength {
+			result.Err = fmt.Errorf("%w: id must be 1 to %d bytes long", ErrInvalidArgument, MaxMutationIdLength)
+			results = append(results, result)
+			continue
+		}
+		if m.Type == "create" && m.Task != nil {
+			task := *m.Task
+			task.IdempotencyKey = "sync:" + ActorFrom(ctx) + ":" + m.Id
+			m.Task = &task
+		}
+
+		task, err := s.ApplyMutation(ctx, m)
+		switch {
+		case errors.Is(err, ErrPreconditionFailed):
+			result.Err = err
+			if tree, err := s.GetTaskTree(ctx, m.TaskId); err == nil {
+				result.Current = &tree.Task
+			}
+		case err != nil:
+			result.Err = err
+		case task.Id != 0:
+			result.Task = &task
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,16 @@
+package app
+
+import "context"
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion makes db-service reject changes to a task whose version
+// differs from version with ErrPreconditionFailed. Zero disables the check.
+func WithExpectedVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+func ExpectedVersionFrom(ctx context.Context) int {
+	version, _ := ctx.Value(expectedVersionKey{}).(int)
+	return version
+}
@@ -0,0 +1,21 @@
+package dbgrpc
+
+import (
+	"context"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// actorMetadataKey must match the key db-service reads the actor from.
+const actorMetadataKey = "x-actor"
+
+// ActorInterceptor forwards app.ActorFrom(ctx) to db-service in the request
+// metadata.
+func ActorInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if actor := app.ActorFrom(ctx); actor != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, actorMetadataKey, actor)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,111 @@
+package dbgrpc
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 5 * time.Second
+)
+
+var errBreakerOpen = status.Error(codes.Unavailable, "db-service is unavailable")
+
+// Breaker fails calls fast while db-service is down. After threshold calls
+// in a row that couldn't reach db-service or timed out, it opens: calls fail
+// with Unavailable without being sent. After cooldown a single call is let
+// through as a probe, and the breaker closes once db-service answers it.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+	probing  bool
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// UnaryInterceptor puts unary calls behind the breaker.
+func (b *Breaker) UnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	probe, err := b.acquire()
+	if err != nil {
+		return err
+	}
+
+	var p peer.Peer
+	err = invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&p))...)
+	b.record(probe, unreachable(err, p.Addr != nil))
+	return err
+}
+
+func (b *Breaker) acquire() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return false, nil
+	}
+	if b.probing || b.now().Before(b.openedAt.Add(b.cooldown)) {
+		return false, errBreakerOpen
+	}
+	b.probing = true
+	return true, nil
+}
+
+func (b *Breaker) record(probe, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+	if !failed {
+		if b.open {
+			log.Println("db-service is available again, closed the breaker")
+		}
+		b.open = false
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if probe || (!b.open && b.failures >= b.threshold) {
+		if !b.open {
+			log.Printf("db-service failed %d calls in a row, opened the breaker\n", b.failures)
+		}
+		b.open = true
+		b.openedAt = b.now()
+	}
+}
+
+// unreachable tells db-service being down from the errors it answered with.
+// It answers Unavailable itself while Postgres is down, but then it still
+// serves cached reads, so those calls don't count.
+func unreachable(err error, answered bool) bool {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		return true
+	case codes.Unavailable:
+		return !answered
+	default:
+		return false
+	}
+}
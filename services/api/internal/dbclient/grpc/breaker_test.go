@@ -0,0 +1,124 @@
+package dbgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeInvoker answers with err; answered sets the peer, as if db-service
+// was reached.
+type fakeInvoker struct {
+	err      error
+	answered bool
+	calls    int
+}
+
+func (fi *fakeInvoker) invoke(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	fi.calls++
+	for _, opt := range opts {
+		if p, ok := opt.(grpc.PeerCallOption); ok && fi.answered {
+			*p.PeerAddr = peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 9091}}
+		}
+	}
+	return fi.err
+}
+
+func newTestBreaker() (*Breaker, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func callBreaker(b *Breaker, fi *fakeInvoker) error {
+	return b.UnaryInterceptor(context.Background(), "/pb.TasksService/ListAllTasks", nil, nil, nil, fi.invoke)
+}
+
+func TestBreaker_UnreachableInARow_FailsFast(t *testing.T) {
+	b, _ := newTestBreaker()
+	fi := &fakeInvoker{err: status.Error(codes.Unavailable, "connection refused")}
+
+	_ = callBreaker(b, fi)
+	_ = callBreaker(b, fi)
+	err := callBreaker(b, fi)
+
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Unavailable, err)
+	}
+	if fi.calls != 2 {
+		t.Fatalf("expected the call after opening not sent, got %d calls", fi.calls)
+	}
+}
+
+func TestBreaker_AnsweredErrors_DontOpenIt(t *testing.T) {
+	b, _ := newTestBreaker()
+	fi := &fakeInvoker{err: status.Error(codes.Unavailable, "list tasks error: postgres is down"), answered: true}
+
+	_ = callBreaker(b, fi)
+	_ = callBreaker(b, fi)
+	fi.err = status.Error(codes.NotFound, "task not found")
+	_ = callBreaker(b, fi)
+
+	if fi.calls != 3 {
+		t.Fatalf("expected every call sent, got %d calls", fi.calls)
+	}
+}
+
+func TestBreaker_Timeouts_OpenIt(t *testing.T) {
+	b, _ := newTestBreaker()
+	fi := &fakeInvoker{err: status.Error(codes.DeadlineExceeded, "context deadline exceeded"), answered: true}
+
+	_ = callBreaker(b, fi)
+	_ = callBreaker(b, fi)
+	_ = callBreaker(b, fi)
+
+	if fi.calls != 2 {
+		t.Fatalf("expected the call after opening not sent, got %d calls", fi.calls)
+	}
+}
+
+func TestBreaker_AfterCooldown_ProbeClosesIt(t *testing.T) {
+	b, now := newTestBreaker()
+	fi := &fakeInvoker{err: status.Error(codes.Unavailable, "connection refused")}
+	_ = callBreaker(b, fi)
+	_ = callBreaker(b, fi)
+
+	*now = now.Add(time.Minute)
+	fi.err = nil
+	probeErr := callBreaker(b, fi)
+	err := callBreaker(b, fi)
+
+	if probeErr != nil || err != nil {
+		t.Fatalf("expected nil, got probe=%v next=%v", probeErr, err)
+	}
+	if fi.calls != 4 {
+		t.Fatalf("expected the probe and the next call sent, got %d calls", fi.calls)
+	}
+}
+
+func TestBreaker_FailedProbe_ReopensIt(t *testing.T) {
+	b, now := newTestBreaker()
+	fi := &fakeInvoker{err: status.Error(codes.Unavailable, "connection refused")}
+	_ = callBreaker(b, fi)
+	_ = callBreaker(b, fi)
+
+	*now = now.Add(time.Minute)
+	_ = callBreaker(b, fi)
+	*now = now.Add(time.Second)
+	fi.err = nil
+	err := callBreaker(b, fi)
+
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Unavailable, err)
+	}
+	if fi.calls != 3 {
+		t.Fatalf("expected only the probe sent, got %d calls", fi.calls)
+	}
+}
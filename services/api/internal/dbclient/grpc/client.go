@@ -2,9 +2,15 @@ package dbgrpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 
 	"github.com/dodocheck/go-pet-project-1/pkg/pb"
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -16,22 +22,217 @@ func NewDBClient(grpcClient pb.TasksServiceClient) *DBClient {
 	return &DBClient{grpcClient: grpcClient}
 }
 
+// taskChange identifies the task to change along with the version the caller
+// expects it to have, see app.WithExpectedVersion.
+func taskChange(ctx context.Context, id int) *pb.TaskId {
+	return &pb.TaskId{Id: int64(id), ExpectedVersion: expectedVersion(ctx)}
+}
+
+func expectedVersion(ctx context.Context) int64 {
+	return int64(app.ExpectedVersionFrom(ctx))
+}
+
 func (c *DBClient) AddTask(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
 	createdTask, err := c.grpcClient.AddTask(ctx, taskImportDataToPB(task))
-	return taskExportDataFromPB(createdTask), err
+	return taskExportDataFromPB(createdTask), addTaskError(err)
+}
+
+// addTaskError is errorFromStatus for AddTask, which only reports
+// AlreadyExists for an idempotency key that was used with other task data.
+func addTaskError(err error) error {
+	if status.Code(err) == codes.AlreadyExists {
+		return fmt.Errorf("%w: %s", app.ErrIdempotencyKeyReused, status.Convert(err).Message())
+	}
+	return errorFromStatus(err)
 }
 
 func (c *DBClient) RemoveTask(ctx context.Context, id int) error {
-	_, err := c.grpcClient.RemoveTask(ctx, taskIdToPB(id))
-	return err
+	_, err := c.grpcClient.RemoveTask(ctx, taskChange(ctx, id))
+	return errorFromStatus(err)
+}
+
+func (c *DBClient) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	taskList, err := c.grpcClient.ListTrash(ctx, &emptypb.Empty{})
+	return taskSliceFromPB(taskList), errorFromStatus(err)
 }
 
-func (c *DBClient) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
+func (c *DBClient) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	restoredTask, err := c.grpcClient.RestoreTask(ctx, taskChange(ctx, id))
+	return taskExportDataFromPB(restoredTask), errorFromStatus(err)
+}
+
+func (c *DBClient) PurgeTask(ctx context.Context, id int) error {
+	_, err := c.grpcClient.PurgeTask(ctx, taskChange(ctx, id))
+	return errorFromStatus(err)
+}
+
+func (c *DBClient) GetTaskTree(ctx context.Context, id int) (models.TaskTree, error) {
+	tree, err := c.grpcClient.GetTaskTree(ctx, taskIdToPB(id))
+	return taskTreeFromPB(tree), errorFromStatus(err)
+}
+
+func (c *DBClient) ListAllTasks(ctx context.Context) ([]models.TaskExportData, bool, error) {
 	taskList, err := c.grpcClient.ListAllTasks(ctx, &emptypb.Empty{})
-	return taskSliceFromPB(taskList), err
+	return taskSliceFromPB(taskList), taskList.GetStale(), errorFromStatus(err)
+}
+
+func (c *DBClient) ListTasks(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+	taskList, err := c.grpcClient.ListTasks(ctx, taskFilterToPB(filter))
+	return taskSliceFromPB(taskList), taskList.GetStale(), errorFromStatus(err)
 }
 
 func (c *DBClient) MarkTaskFinished(ctx context.Context, id int) (models.TaskExportData, error) {
-	updatedTask, err := c.grpcClient.MarkTaskFinished(ctx, taskIdToPB(id))
-	return taskExportDataFromPB(updatedTask), err
+	updatedTask, err := c.grpcClient.MarkTaskFinished(ctx, taskChange(ctx, id))
+	return taskExportDataFromPB(updatedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	updatedTask, err := c.grpcClient.ReopenTask(ctx, taskChange(ctx, id))
+	return taskExportDataFromPB(updatedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	updatedTask, err := c.grpcClient.SetTaskPriority(ctx, &pb.TaskPriority{Id: int64(id), Priority: pb.Priority(priority), ExpectedVersion: expectedVersion(ctx)})
+	return taskExportDataFromPB(updatedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	req := taskMoveToPB(move)
+	req.ExpectedVersion = expectedVersion(ctx)
+	movedTask, err := c.grpcClient.MoveTask(ctx, req)
+	return taskExportDataFromPB(movedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	updatedTask, err := c.grpcClient.SetTaskRecurrence(ctx, &pb.TaskRecurrence{Id: int64(id), Recurrence: recurrenceToPB(rule), ExpectedVersion: expectedVersion(ctx)})
+	return taskExportDataFromPB(updatedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	updatedTask, err := c.grpcClient.SkipOccurrence(ctx, taskChange(ctx, id))
+	return taskExportDataFromPB(updatedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	updatedTask, err := c.grpcClient.AddTaskTags(ctx, &pb.TaskTags{Id: int64(id), Tags: tags, ExpectedVersion: expectedVersion(ctx)})
+	return taskExportDataFromPB(updatedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	updatedTask, err := c.grpcClient.RemoveTaskTags(ctx, &pb.TaskTags{Id: int64(id), Tags: tags, ExpectedVersion: expectedVersion(ctx)})
+	return taskExportDataFromPB(updatedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	tagList, err := c.grpcClient.ListTags(ctx, &emptypb.Empty{})
+	return tagSliceFromPB(tagList), errorFromStatus(err)
+}
+
+func (c *DBClient) RenameTag(ctx context.Context, name, newName string) (models.TagChange, error) {
+	change, err := c.grpcClient.RenameTag(ctx, &pb.RenameTagRequest{Name: name, NewName: newName})
+	return tagChangeFromPB(change), errorFromStatus(err)
+}
+
+func (c *DBClient) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	change, err := c.grpcClient.MergeTags(ctx, &pb.MergeTagsRequest{Sources: sources, Target: target})
+	return tagChangeFromPB(change), errorFromStatus(err)
+}
+
+func (c *DBClient) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	project, err := c.grpcClient.CreateProject(ctx, &pb.CreateProjectRequest{Name: name})
+	return projectFromPB(project), errorFromStatus(err)
+}
+
+func (c *DBClient) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	projectList, err := c.grpcClient.ListProjects(ctx, &pb.ListProjectsRequest{IncludeArchived: includeArchived})
+	return projectSliceFromPB(projectList), errorFromStatus(err)
+}
+
+func (c *DBClient) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	project, err := c.grpcClient.RenameProject(ctx, &pb.RenameProjectRequest{Id: int64(id), Name: name})
+	return projectFromPB(project), errorFromStatus(err)
+}
+
+func (c *DBClient) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	project, err := c.grpcClient.ArchiveProject(ctx, &pb.ArchiveProjectRequest{Id: int64(id), Archived: archived})
+	return projectFromPB(project), errorFromStatus(err)
+}
+
+func (c *DBClient) DeleteProject(ctx context.Context, id int) error {
+	_, err := c.grpcClient.DeleteProject(ctx, &pb.ProjectId{Id: int64(id)})
+	return errorFromStatus(err)
+}
+
+func (c *DBClient) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	movedTask, err := c.grpcClient.MoveTaskToProject(ctx, &pb.TaskProject{Id: int64(id), ProjectId: int64(projectId), ExpectedVersion: expectedVersion(ctx)})
+	return taskExportDataFromPB(movedTask), errorFromStatus(err)
+}
+
+func (c *DBClient) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	stats, err := c.grpcClient.GetStats(ctx, statsRequestToPB(req))
+	return statsFromPB(stats), errorFromStatus(err)
+}
+
+func (c *DBClient) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	page, err := c.grpcClient.GetTaskHistory(ctx, &pb.TaskHistoryRequest{
+		TaskId: int64(req.TaskId),
+		Limit:  int32(req.Limit),
+		Cursor: int64(req.Cursor),
+	})
+	return taskHistoryPageFromPB(page), errorFromStatus(err)
+}
+
+func (c *DBClient) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	result, err := c.grpcClient.SearchTasks(ctx, &pb.SearchRequest{Query: req.Query, Limit: int32(req.Limit)})
+	return searchHitsFromPB(result), errorFromStatus(err)
+}
+
+// WatchTasks passes the task events to send until the stream ends. The end of
+// ctx is a normal end of the watch, not an error.
+func (c *DBClient) WatchTasks(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+	stream, err := c.grpcClient.WatchTasks(ctx, &pb.WatchTasksRequest{
+		ProjectId:   int64(req.ProjectId),
+		LastEventId: int64(req.LastEventId),
+	})
+	if err != nil {
+		return errorFromStatus(err)
+	}
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return errorFromStatus(err)
+		}
+		if err := send(taskEventFromPB(event)); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *DBClient) GetChangesSince(ctx context.Context, token string, limit int) (models.Changes, error) {
+	changes, err := c.grpcClient.GetChangesSince(ctx, &pb.ChangesRequest{Token: token, Limit: int32(limit)})
+	return changesFromPB(changes), errorFromStatus(err)
+}
+
+func (c *DBClient) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	req := &pb.BatchAddTasksRequest{Tasks: make([]*pb.TaskImportData, 0, len(tasks))}
+	for _, task := range tasks {
+		req.Tasks = append(req.Tasks, taskImportDataToPB(task))
+	}
+
+	result, err := c.grpcClient.BatchAddTasks(ctx, req)
+	return batchItemsFromPB(result, addTaskError), errorFromStatus(err)
+}
+
+func (c *DBClient) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	result, err := c.grpcClient.BatchMarkFinished(ctx, taskIdsToPB(ids))
+	return batchItemsFromPB(result, errorFromStatus), errorFromStatus(err)
+}
+
+func (c *DBClient) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	result, err := c.grpcClient.BatchRemoveTasks(ctx, taskIdsToPB(ids))
+	return batchItemsFromPB(result, errorFromStatus), errorFromStatus(err)
 }
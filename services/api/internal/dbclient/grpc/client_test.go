@@ -3,24 +3,90 @@ package dbgrpc
 import (
 	"context"
 	"errors"
+	"io"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/dodocheck/go-pet-project-1/pkg/pb"
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type fakeGrpcClient struct {
-	addFn    func(ctx context.Context, in *pb.TaskImportData, opts ...grpc.CallOption) (*pb.TaskExportData, error)
-	removeFn func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	listFn   func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TaskList, error)
-	doneFn   func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	addFn            func(ctx context.Context, in *pb.TaskImportData, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	removeFn         func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	listFn           func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TaskList, error)
+	doneFn           func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	statsFn          func(ctx context.Context, in *pb.StatsRequest, opts ...grpc.CallOption) (*pb.Stats, error)
+	filterFn         func(ctx context.Context, in *pb.TaskFilter, opts ...grpc.CallOption) (*pb.TaskList, error)
+	priorityFn       func(ctx context.Context, in *pb.TaskPriority, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	moveFn           func(ctx context.Context, in *pb.MoveTaskRequest, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	addTagsFn        func(ctx context.Context, in *pb.TaskTags, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	removeTagsFn     func(ctx context.Context, in *pb.TaskTags, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	listTagsFn       func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TagList, error)
+	renameTagFn      func(ctx context.Context, in *pb.RenameTagRequest, opts ...grpc.CallOption) (*pb.TagChange, error)
+	mergeTagsFn      func(ctx context.Context, in *pb.MergeTagsRequest, opts ...grpc.CallOption) (*pb.TagChange, error)
+	createProjectFn  func(ctx context.Context, in *pb.CreateProjectRequest, opts ...grpc.CallOption) (*pb.Project, error)
+	listProjectsFn   func(ctx context.Context, in *pb.ListProjectsRequest, opts ...grpc.CallOption) (*pb.ProjectList, error)
+	renameProjectFn  func(ctx context.Context, in *pb.RenameProjectRequest, opts ...grpc.CallOption) (*pb.Project, error)
+	archiveProjectFn func(ctx context.Context, in *pb.ArchiveProjectRequest, opts ...grpc.CallOption) (*pb.Project, error)
+	deleteProjectFn  func(ctx context.Context, in *pb.ProjectId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	taskProjectFn    func(ctx context.Context, in *pb.TaskProject, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	taskTreeFn       func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskTree, error)
+	recurrenceFn     func(ctx context.Context, in *pb.TaskRecurrence, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	skipFn           func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	reopenFn         func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	listTrashFn      func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TaskList, error)
+	restoreFn        func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error)
+	purgeFn          func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	historyFn        func(ctx context.Context, in *pb.TaskHistoryRequest, opts ...grpc.CallOption) (*pb.TaskHistoryPage, error)
+	batchAddFn       func(ctx context.Context, in *pb.BatchAddTasksRequest, opts ...grpc.CallOption) (*pb.BatchResult, error)
+	batchFinishFn    func(ctx context.Context, in *pb.TaskIds, opts ...grpc.CallOption) (*pb.BatchResult, error)
+	batchRemoveFn    func(ctx context.Context, in *pb.TaskIds, opts ...grpc.CallOption) (*pb.BatchResult, error)
+	searchFn         func(ctx context.Context, in *pb.SearchRequest, opts ...grpc.CallOption) (*pb.SearchResult, error)
+	watchFn          func(ctx context.Context, in *pb.WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[pb.TaskEvent], error)
+	changesFn        func(ctx context.Context, in *pb.ChangesRequest, opts ...grpc.CallOption) (*pb.Changes, error)
 
-	addCalls    int
-	removeCalls int
-	listCalls   int
-	doneCalls   int
+	addCalls            int
+	removeCalls         int
+	listCalls           int
+	doneCalls           int
+	statsCalls          int
+	filterCalls         int
+	priorityCalls       int
+	moveCalls           int
+	addTagsCalls        int
+	removeTagsCalls     int
+	listTagsCalls       int
+	renameTagCalls      int
+	mergeTagsCalls      int
+	createProjectCalls  int
+	listProjectsCalls   int
+	renameProjectCalls  int
+	archiveProjectCalls int
+	deleteProjectCalls  int
+	taskProjectCalls    int
+	taskTreeCalls       int
+	recurrenceCalls     int
+	skipCalls           int
+	reopenCalls         int
+	listTrashCalls      int
+	restoreCalls        int
+	purgeCalls          int
+	historyCalls        int
+	batchAddCalls       int
+	batchFinishCalls    int
+	batchRemoveCalls    int
+	searchCalls         int
+	watchCalls          int
+	changesCalls        int
 
 	gotAddCtx  context.Context
 	gotAddTask *pb.TaskImportData
@@ -32,6 +98,81 @@ type fakeGrpcClient struct {
 
 	gotDoneCtx context.Context
 	gotDoneId  *pb.TaskId
+
+	gotStatsCtx context.Context
+	gotStatsReq *pb.StatsRequest
+
+	gotFilterCtx context.Context
+	gotFilter    *pb.TaskFilter
+
+	gotPriorityCtx context.Context
+	gotPriority    *pb.TaskPriority
+
+	gotMoveCtx context.Context
+	gotMove    *pb.MoveTaskRequest
+
+	gotAddTagsCtx context.Context
+	gotAddTags    *pb.TaskTags
+
+	gotRemoveTagsCtx context.Context
+	gotRemoveTags    *pb.TaskTags
+
+	gotListTagsCtx context.Context
+
+	gotRenameTagCtx context.Context
+	gotRenameTag    *pb.RenameTagRequest
+
+	gotMergeTagsCtx context.Context
+	gotMergeTags    *pb.MergeTagsRequest
+
+	gotCreateProjectCtx context.Context
+	gotCreateProject    *pb.CreateProjectRequest
+
+	gotListProjectsCtx context.Context
+	gotListProjects    *pb.ListProjectsRequest
+
+	gotRenameProjectCtx context.Context
+	gotRenameProject    *pb.RenameProjectRequest
+
+	gotArchiveProjectCtx context.Context
+	gotArchiveProject    *pb.ArchiveProjectRequest
+
+	gotDeleteProjectCtx context.Context
+	gotDeleteProject    *pb.ProjectId
+
+	gotTaskProjectCtx context.Context
+	gotTaskProject    *pb.TaskProject
+
+	gotTaskTreeCtx context.Context
+	gotTaskTreeId  *pb.TaskId
+
+	gotRecurrenceCtx context.Context
+	gotRecurrence    *pb.TaskRecurrence
+
+	gotSkipCtx context.Context
+	gotSkip    *pb.TaskId
+
+	gotReopenCtx context.Context
+	gotReopen    *pb.TaskId
+
+	gotRestoreCtx context.Context
+	gotRestore    *pb.TaskId
+
+	gotPurgeCtx context.Context
+	gotPurge    *pb.TaskId
+
+	gotHistoryCtx context.Context
+	gotHistoryReq *pb.TaskHistoryRequest
+
+	gotBatchAdd *pb.BatchAddTasksRequest
+
+	gotBatchFinish *pb.TaskIds
+
+	gotBatchRemove *pb.TaskIds
+
+	gotSearch *pb.SearchRequest
+
+	gotWatch *pb.WatchTasksRequest
 }
 
 func (f *fakeGrpcClient) AddTask(ctx context.Context, in *pb.TaskImportData, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
@@ -81,6 +222,344 @@ func (f *fakeGrpcClient) MarkTaskFinished(ctx context.Context, in *pb.TaskId, op
 	return f.doneFn(ctx, in)
 }
 
+func (f *fakeGrpcClient) GetStats(ctx context.Context, in *pb.StatsRequest, opts ...grpc.CallOption) (*pb.Stats, error) {
+	f.statsCalls++
+	f.gotStatsCtx = ctx
+	f.gotStatsReq = in
+
+	if f.statsFn == nil {
+		panic("GetStats called but statsFn not set")
+	}
+
+	return f.statsFn(ctx, in)
+}
+
+func (f *fakeGrpcClient) ListTasks(ctx context.Context, in *pb.TaskFilter, opts ...grpc.CallOption) (*pb.TaskList, error) {
+	f.filterCalls++
+	f.gotFilterCtx = ctx
+	f.gotFilter = in
+
+	if f.filterFn == nil {
+		panic("ListTasks called but filterFn not set")
+	}
+
+	return f.filterFn(ctx, in)
+}
+
+func (f *fakeGrpcClient) SetTaskPriority(ctx context.Context, in *pb.TaskPriority, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.priorityCalls++
+	f.gotPriorityCtx = ctx
+	f.gotPriority = in
+
+	if f.priorityFn == nil {
+		panic("SetTaskPriority called but priorityFn not set")
+	}
+
+	return f.priorityFn(ctx, in)
+}
+
+func (f *fakeGrpcClient) MoveTask(ctx context.Context, in *pb.MoveTaskRequest, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.moveCalls++
+	f.gotMoveCtx = ctx
+	f.gotMove = in
+
+	if f.moveFn == nil {
+		panic("MoveTask called but moveFn not set")
+	}
+
+	return f.moveFn(ctx, in)
+}
+
+func (f *fakeGrpcClient) AddTaskTags(ctx context.Context, in *pb.TaskTags, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.addTagsCalls++
+	f.gotAddTagsCtx = ctx
+	f.gotAddTags = in
+
+	if f.addTagsFn == nil {
+		panic("AddTaskTags called but addTagsFn not set")
+	}
+
+	return f.addTagsFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) RemoveTaskTags(ctx context.Context, in *pb.TaskTags, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.removeTagsCalls++
+	f.gotRemoveTagsCtx = ctx
+	f.gotRemoveTags = in
+
+	if f.removeTagsFn == nil {
+		panic("RemoveTaskTags called but removeTagsFn not set")
+	}
+
+	return f.removeTagsFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) ListTags(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TagList, error) {
+	f.listTagsCalls++
+	f.gotListTagsCtx = ctx
+
+	if f.listTagsFn == nil {
+		panic("ListTags called but listTagsFn not set")
+	}
+
+	return f.listTagsFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) RenameTag(ctx context.Context, in *pb.RenameTagRequest, opts ...grpc.CallOption) (*pb.TagChange, error) {
+	f.renameTagCalls++
+	f.gotRenameTagCtx = ctx
+	f.gotRenameTag = in
+
+	if f.renameTagFn == nil {
+		panic("RenameTag called but renameTagFn not set")
+	}
+
+	return f.renameTagFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) MergeTags(ctx context.Context, in *pb.MergeTagsRequest, opts ...grpc.CallOption) (*pb.TagChange, error) {
+	f.mergeTagsCalls++
+	f.gotMergeTagsCtx = ctx
+	f.gotMergeTags = in
+
+	if f.mergeTagsFn == nil {
+		panic("MergeTags called but mergeTagsFn not set")
+	}
+
+	return f.mergeTagsFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) CreateProject(ctx context.Context, in *pb.CreateProjectRequest, opts ...grpc.CallOption) (*pb.Project, error) {
+	f.createProjectCalls++
+	f.gotCreateProjectCtx = ctx
+	f.gotCreateProject = in
+
+	if f.createProjectFn == nil {
+		panic("CreateProject called but createProjectFn not set")
+	}
+
+	return f.createProjectFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) ListProjects(ctx context.Context, in *pb.ListProjectsRequest, opts ...grpc.CallOption) (*pb.ProjectList, error) {
+	f.listProjectsCalls++
+	f.gotListProjectsCtx = ctx
+	f.gotListProjects = in
+
+	if f.listProjectsFn == nil {
+		panic("ListProjects called but listProjectsFn not set")
+	}
+
+	return f.listProjectsFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) RenameProject(ctx context.Context, in *pb.RenameProjectRequest, opts ...grpc.CallOption) (*pb.Project, error) {
+	f.renameProjectCalls++
+	f.gotRenameProjectCtx = ctx
+	f.gotRenameProject = in
+
+	if f.renameProjectFn == nil {
+		panic("RenameProject called but renameProjectFn not set")
+	}
+
+	return f.renameProjectFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) ArchiveProject(ctx context.Context, in *pb.ArchiveProjectRequest, opts ...grpc.CallOption) (*pb.Project, error) {
+	f.archiveProjectCalls++
+	f.gotArchiveProjectCtx = ctx
+	f.gotArchiveProject = in
+
+	if f.archiveProjectFn == nil {
+		panic("ArchiveProject called but archiveProjectFn not set")
+	}
+
+	return f.archiveProjectFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) DeleteProject(ctx context.Context, in *pb.ProjectId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	f.deleteProjectCalls++
+	f.gotDeleteProjectCtx = ctx
+	f.gotDeleteProject = in
+
+	if f.deleteProjectFn == nil {
+		panic("DeleteProject called but deleteProjectFn not set")
+	}
+
+	return f.deleteProjectFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) MoveTaskToProject(ctx context.Context, in *pb.TaskProject, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.taskProjectCalls++
+	f.gotTaskProjectCtx = ctx
+	f.gotTaskProject = in
+
+	if f.taskProjectFn == nil {
+		panic("MoveTaskToProject called but taskProjectFn not set")
+	}
+
+	return f.taskProjectFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) GetTaskTree(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskTree, error) {
+	f.taskTreeCalls++
+	f.gotTaskTreeCtx = ctx
+	f.gotTaskTreeId = in
+
+	if f.taskTreeFn == nil {
+		panic("GetTaskTree called but taskTreeFn not set")
+	}
+
+	return f.taskTreeFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) SetTaskRecurrence(ctx context.Context, in *pb.TaskRecurrence, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.recurrenceCalls++
+	f.gotRecurrenceCtx = ctx
+	f.gotRecurrence = in
+
+	if f.recurrenceFn == nil {
+		panic("SetTaskRecurrence called but recurrenceFn not set")
+	}
+
+	return f.recurrenceFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) SkipOccurrence(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.skipCalls++
+	f.gotSkipCtx = ctx
+	f.gotSkip = in
+
+	if f.skipFn == nil {
+		panic("SkipOccurrence called but skipFn not set")
+	}
+
+	return f.skipFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) ReopenTask(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.reopenCalls++
+	f.gotReopenCtx = ctx
+	f.gotReopen = in
+
+	if f.reopenFn == nil {
+		panic("ReopenTask called but reopenFn not set")
+	}
+
+	return f.reopenFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) ListTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TaskList, error) {
+	f.listTrashCalls++
+
+	if f.listTrashFn == nil {
+		panic("ListTrash called but listTrashFn not set")
+	}
+
+	return f.listTrashFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) RestoreTask(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+	f.restoreCalls++
+	f.gotRestoreCtx = ctx
+	f.gotRestore = in
+
+	if f.restoreFn == nil {
+		panic("RestoreTask called but restoreFn not set")
+	}
+
+	return f.restoreFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) PurgeTask(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	f.purgeCalls++
+	f.gotPurgeCtx = ctx
+	f.gotPurge = in
+
+	if f.purgeFn == nil {
+		panic("PurgeTask called but purgeFn not set")
+	}
+
+	return f.purgeFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) GetTaskHistory(ctx context.Context, in *pb.TaskHistoryRequest, opts ...grpc.CallOption) (*pb.TaskHistoryPage, error) {
+	f.historyCalls++
+	f.gotHistoryCtx = ctx
+	f.gotHistoryReq = in
+
+	if f.historyFn == nil {
+		panic("GetTaskHistory called but historyFn not set")
+	}
+
+	return f.historyFn(ctx, in)
+}
+
+func (f *fakeGrpcClient) BatchAddTasks(ctx context.Context, in *pb.BatchAddTasksRequest, opts ...grpc.CallOption) (*pb.BatchResult, error) {
+	f.batchAddCalls++
+	f.gotBatchAdd = in
+
+	if f.batchAddFn == nil {
+		panic("BatchAddTasks called but batchAddFn not set")
+	}
+
+	return f.batchAddFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) BatchMarkFinished(ctx context.Context, in *pb.TaskIds, opts ...grpc.CallOption) (*pb.BatchResult, error) {
+	f.batchFinishCalls++
+	f.gotBatchFinish = in
+
+	if f.batchFinishFn == nil {
+		panic("BatchMarkFinished called but batchFinishFn not set")
+	}
+
+	return f.batchFinishFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) BatchRemoveTasks(ctx context.Context, in *pb.TaskIds, opts ...grpc.CallOption) (*pb.BatchResult, error) {
+	f.batchRemoveCalls++
+	f.gotBatchRemove = in
+
+	if f.batchRemoveFn == nil {
+		panic("BatchRemoveTasks called but batchRemoveFn not set")
+	}
+
+	return f.batchRemoveFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) SearchTasks(ctx context.Context, in *pb.SearchRequest, opts ...grpc.CallOption) (*pb.SearchResult, error) {
+	f.searchCalls++
+	f.gotSearch = in
+
+	if f.searchFn == nil {
+		panic("SearchTasks called but searchFn not set")
+	}
+
+	return f.searchFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) WatchTasks(ctx context.Context, in *pb.WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[pb.TaskEvent], error) {
+	f.watchCalls++
+	f.gotWatch = in
+
+	if f.watchFn == nil {
+		panic("WatchTasks called but watchFn not set")
+	}
+
+	return f.watchFn(ctx, in, opts...)
+}
+
+func (f *fakeGrpcClient) GetChangesSince(ctx context.Context, in *pb.ChangesRequest, opts ...grpc.CallOption) (*pb.Changes, error) {
+	f.changesCalls++
+
+	if f.changesFn == nil {
+		panic("GetChangesSince called but changesFn not set")
+	}
+
+	return f.changesFn(ctx, in, opts...)
+}
+
 func TestAddTask_DelegatesToGrpcClient(t *testing.T) {
 	wantTask := &pb.TaskExportData{
 		Id:    1,
@@ -112,6 +591,26 @@ func TestAddTask_DelegatesToGrpcClient(t *testing.T) {
 	}
 }
 
+func TestAddTask_AlreadyExists_IsIdempotencyKeyReuse(t *testing.T) {
+	var gotKey string
+	fakeClient := &fakeGrpcClient{
+		addFn: func(ctx context.Context, in *pb.TaskImportData, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			gotKey = in.GetIdempotencyKey()
+			return nil, status.Error(codes.AlreadyExists, "idempotency key was already used")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, gotErr := dbClient.AddTask(context.Background(), models.TaskImportData{Title: "t", IdempotencyKey: "abc"})
+
+	if !errors.Is(gotErr, app.ErrIdempotencyKeyReused) {
+		t.Fatalf("expected err %v, got %v", app.ErrIdempotencyKeyReused, gotErr)
+	}
+	if gotKey != "abc" {
+		t.Fatalf("expected idempotency key abc, got %q", gotKey)
+	}
+}
+
 func TestRemoveTask_DelegatesToGrpcClient(t *testing.T) {
 	wantErr := errors.New("boom")
 	fakeClient := &fakeGrpcClient{
@@ -128,6 +627,54 @@ func TestRemoveTask_DelegatesToGrpcClient(t *testing.T) {
 	}
 }
 
+func TestListAllTasks_StaleList_IsReported(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		listFn: func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TaskList, error) {
+			return &pb.TaskList{Tasks: []*pb.TaskExportData{{Id: 1}}, Stale: true}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, stale, err := dbClient.ListAllTasks(context.Background())
+
+	if err != nil || !stale {
+		t.Fatalf("expected a stale list, got stale=%v err=%v", stale, err)
+	}
+	if len(got) != 1 || got[0].Id != 1 {
+		t.Fatalf("unexpected tasks %+v", got)
+	}
+}
+
+func TestListAllTasks_Unavailable_ReturnsErrUnavailable(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		listFn: func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TaskList, error) {
+			return nil, status.Error(codes.Unavailable, "list tasks error: connection refused")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, _, gotErr := dbClient.ListAllTasks(context.Background())
+
+	if !errors.Is(gotErr, app.ErrUnavailable) {
+		t.Fatalf("expected err %v, got %v", app.ErrUnavailable, gotErr)
+	}
+}
+
+func TestListAllTasks_DeadlineExceeded_ReturnsErrTimeout(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		listFn: func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TaskList, error) {
+			return nil, status.Error(codes.DeadlineExceeded, "context deadline exceeded")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, _, gotErr := dbClient.ListAllTasks(context.Background())
+
+	if !errors.Is(gotErr, app.ErrTimeout) {
+		t.Fatalf("expected err %v, got %v", app.ErrTimeout, gotErr)
+	}
+}
+
 func TestListAllTasks_DelegatesToGrpcClient(t *testing.T) {
 	wantList := &pb.TaskList{
 		Tasks: []*pb.TaskExportData{
@@ -151,7 +698,7 @@ func TestListAllTasks_DelegatesToGrpcClient(t *testing.T) {
 	}
 	dbClient := NewDBClient(fakeClient)
 
-	gotTask, gotErr := dbClient.ListAllTasks(context.Background())
+	gotTask, _, gotErr := dbClient.ListAllTasks(context.Background())
 
 	if !errors.Is(gotErr, wantErr) {
 		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
@@ -205,3 +752,757 @@ func TestMarkTaskFinished_DelegatesToGrpcClient(t *testing.T) {
 		t.Fatalf("expected Text %q, got %q", wantTaskConv.Text, gotTask.Text)
 	}
 }
+
+func TestGetStats_DelegatesToGrpcClient(t *testing.T) {
+	wantStats := &pb.Stats{
+		Points: []*pb.StatsPoint{
+			{Created: 3, Completed: 2},
+		},
+		OpenCount:     5,
+		LongestStreak: 4,
+	}
+	wantErr := errors.New("boom")
+	fakeClient := &fakeGrpcClient{
+		statsFn: func(ctx context.Context, in *pb.StatsRequest, opts ...grpc.CallOption) (*pb.Stats, error) {
+			return wantStats, wantErr
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	gotStats, gotErr := dbClient.GetStats(context.Background(), models.StatsRequest{
+		Bucket:   models.StatsBucketWeek,
+		TimeZone: "Europe/Moscow",
+	})
+
+	if !errors.Is(gotErr, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
+	}
+	if fakeClient.gotStatsReq.GetBucket() != pb.StatsBucket_STATS_BUCKET_WEEK ||
+		fakeClient.gotStatsReq.GetTimeZone() != "Europe/Moscow" {
+		t.Fatalf("unexpected stats request %+v", fakeClient.gotStatsReq)
+	}
+	if gotStats.OpenCount != 5 || gotStats.LongestStreak != 4 || len(gotStats.Points) != 1 {
+		t.Fatalf("unexpected stats %+v", gotStats)
+	}
+}
+
+func TestListTasks_DelegatesToGrpcClient(t *testing.T) {
+	dueTS := time.Date(2025, 12, 20, 18, 0, 0, 0, time.UTC)
+	wantList := &pb.TaskList{
+		Tasks: []*pb.TaskExportData{
+			{Id: 1, Title: "a", DueAt: timestamppb.New(dueTS), Overdue: true},
+		},
+	}
+	wantErr := errors.New("boom")
+	fakeClient := &fakeGrpcClient{
+		filterFn: func(ctx context.Context, in *pb.TaskFilter, opts ...grpc.CallOption) (*pb.TaskList, error) {
+			return wantList, wantErr
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	gotTasks, _, gotErr := dbClient.ListTasks(context.Background(), models.TaskFilter{
+		Due:      models.DueFilterOverdue,
+		TimeZone: "Europe/Moscow",
+	})
+
+	if !errors.Is(gotErr, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
+	}
+	if fakeClient.gotFilter.GetDue() != pb.DueFilter_DUE_FILTER_OVERDUE ||
+		fakeClient.gotFilter.GetTimeZone() != "Europe/Moscow" {
+		t.Fatalf("unexpected filter %+v", fakeClient.gotFilter)
+	}
+	if len(gotTasks) != 1 || !gotTasks[0].Overdue || gotTasks[0].DueAt == nil || !gotTasks[0].DueAt.Equal(dueTS) {
+		t.Fatalf("unexpected tasks %+v", gotTasks)
+	}
+}
+
+func TestSetTaskPriority_DelegatesToGrpcClient(t *testing.T) {
+	wantErr := errors.New("boom")
+	fakeClient := &fakeGrpcClient{
+		priorityFn: func(ctx context.Context, in *pb.TaskPriority, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return &pb.TaskExportData{Id: in.GetId(), Priority: in.GetPriority()}, wantErr
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.SetTaskPriority(context.Background(), 3, models.PriorityUrgent)
+
+	if !errors.Is(gotErr, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
+	}
+	if fakeClient.gotPriority.GetId() != 3 || fakeClient.gotPriority.GetPriority() != pb.Priority_PRIORITY_URGENT {
+		t.Fatalf("unexpected priority request %+v", fakeClient.gotPriority)
+	}
+	if got.Id != 3 || got.Priority != models.PriorityUrgent {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestMoveTask_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		moveFn: func(ctx context.Context, in *pb.MoveTaskRequest, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return &pb.TaskExportData{Id: in.GetId(), Position: "ai"}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.MoveTask(context.Background(), models.TaskMove{Id: 1, BeforeId: 2})
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.gotMove.GetId() != 1 || fakeClient.gotMove.GetBeforeId() != 2 || fakeClient.gotMove.GetAfterId() != 0 {
+		t.Fatalf("unexpected move request %+v", fakeClient.gotMove)
+	}
+	if got.Id != 1 || got.Position != "ai" {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestMoveTask_StatusErrors_AreTranslated(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    codes.Code
+		wantErr error
+	}{
+		{name: "invalid argument", code: codes.InvalidArgument, wantErr: app.ErrInvalidArgument},
+		{name: "not found", code: codes.NotFound, wantErr: app.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakeGrpcClient{
+				moveFn: func(ctx context.Context, in *pb.MoveTaskRequest, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+					return nil, status.Error(tt.code, "boom")
+				},
+			}
+			dbClient := NewDBClient(fakeClient)
+
+			_, gotErr := dbClient.MoveTask(context.Background(), models.TaskMove{Id: 1, AfterId: 2})
+
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("expected err %v, got %v", tt.wantErr, gotErr)
+			}
+		})
+	}
+}
+
+func TestAddTaskTags_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		addTagsFn: func(ctx context.Context, in *pb.TaskTags, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return &pb.TaskExportData{Id: in.GetId(), Tags: in.GetTags()}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.AddTaskTags(context.Background(), 3, []string{"home"})
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.gotAddTags.GetId() != 3 || len(fakeClient.gotAddTags.GetTags()) != 1 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotAddTags)
+	}
+	if got.Id != 3 || len(got.Tags) != 1 || got.Tags[0] != "home" {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestRemoveTaskTags_NotFound_IsTranslated(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		removeTagsFn: func(ctx context.Context, in *pb.TaskTags, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return nil, status.Error(codes.NotFound, "task not found")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, gotErr := dbClient.RemoveTaskTags(context.Background(), 3, []string{"home"})
+
+	if !errors.Is(gotErr, app.ErrNotFound) {
+		t.Fatalf("expected err %v, got %v", app.ErrNotFound, gotErr)
+	}
+}
+
+func TestListTags_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		listTagsFn: func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TagList, error) {
+			return &pb.TagList{Tags: []*pb.TagUsage{{Name: "home", Count: 2}}}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.ListTags(context.Background())
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.listTagsCalls != 1 {
+		t.Fatalf("expected ListTags calls = 1, got %d", fakeClient.listTagsCalls)
+	}
+	if len(got) != 1 || got[0] != (models.TagUsage{Name: "home", Count: 2}) {
+		t.Fatalf("unexpected tags %+v", got)
+	}
+}
+
+func TestRenameTag_AlreadyExists_IsTranslated(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		renameTagFn: func(ctx context.Context, in *pb.RenameTagRequest, opts ...grpc.CallOption) (*pb.TagChange, error) {
+			return nil, status.Error(codes.AlreadyExists, "tag already exists")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, gotErr := dbClient.RenameTag(context.Background(), "work", "job")
+
+	if !errors.Is(gotErr, app.ErrAlreadyExists) {
+		t.Fatalf("expected err %v, got %v", app.ErrAlreadyExists, gotErr)
+	}
+	if fakeClient.gotRenameTag.GetName() != "work" || fakeClient.gotRenameTag.GetNewName() != "job" {
+		t.Fatalf("unexpected request %+v", fakeClient.gotRenameTag)
+	}
+}
+
+func TestMergeTags_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		mergeTagsFn: func(ctx context.Context, in *pb.MergeTagsRequest, opts ...grpc.CallOption) (*pb.TagChange, error) {
+			return &pb.TagChange{Name: in.GetTarget(), TaskIds: []int64{1, 4}}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.MergeTags(context.Background(), []string{"job"}, "work")
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if len(fakeClient.gotMergeTags.GetSources()) != 1 || fakeClient.gotMergeTags.GetTarget() != "work" {
+		t.Fatalf("unexpected request %+v", fakeClient.gotMergeTags)
+	}
+	if got.Name != "work" || len(got.TaskIds) != 2 || got.TaskIds[1] != 4 {
+		t.Fatalf("unexpected change %+v", got)
+	}
+}
+
+func TestListProjects_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		listProjectsFn: func(ctx context.Context, in *pb.ListProjectsRequest, opts ...grpc.CallOption) (*pb.ProjectList, error) {
+			return &pb.ProjectList{Projects: []*pb.Project{{Id: 1, Name: "Inbox", Inbox: true}}}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.ListProjects(context.Background(), true)
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if !fakeClient.gotListProjects.GetIncludeArchived() {
+		t.Fatalf("unexpected request %+v", fakeClient.gotListProjects)
+	}
+	if len(got) != 1 || got[0].Id != 1 || !got[0].Inbox {
+		t.Fatalf("unexpected projects %+v", got)
+	}
+}
+
+func TestArchiveProject_FailedPrecondition_IsTranslated(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		archiveProjectFn: func(ctx context.Context, in *pb.ArchiveProjectRequest, opts ...grpc.CallOption) (*pb.Project, error) {
+			return nil, status.Error(codes.FailedPrecondition, "inbox can't be archived or deleted")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, gotErr := dbClient.ArchiveProject(context.Background(), 1, true)
+
+	if !errors.Is(gotErr, app.ErrConflict) {
+		t.Fatalf("expected err %v, got %v", app.ErrConflict, gotErr)
+	}
+	if fakeClient.gotArchiveProject.GetId() != 1 || !fakeClient.gotArchiveProject.GetArchived() {
+		t.Fatalf("unexpected request %+v", fakeClient.gotArchiveProject)
+	}
+}
+
+func TestDeleteProject_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		deleteProjectFn: func(ctx context.Context, in *pb.ProjectId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+			return &emptypb.Empty{}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	gotErr := dbClient.DeleteProject(context.Background(), 4)
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.gotDeleteProject.GetId() != 4 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotDeleteProject)
+	}
+}
+
+func TestMoveTaskToProject_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		taskProjectFn: func(ctx context.Context, in *pb.TaskProject, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return &pb.TaskExportData{Id: in.GetId(), ProjectId: in.GetProjectId()}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.MoveTaskToProject(context.Background(), 5, 2)
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if got.Id != 5 || got.ProjectId != 2 {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestGetTaskTree_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		taskTreeFn: func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskTree, error) {
+			return &pb.TaskTree{
+				Task:     &pb.TaskExportData{Id: in.GetId(), SubtasksTotal: 1},
+				Subtasks: []*pb.TaskTree{{Task: &pb.TaskExportData{Id: 6, ParentId: in.GetId()}}},
+			}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.GetTaskTree(context.Background(), 5)
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.gotTaskTreeId.GetId() != 5 {
+		t.Fatalf("unexpected id %d", fakeClient.gotTaskTreeId.GetId())
+	}
+	want := models.TaskTree{
+		Task: models.TaskExportData{Id: 5, SubtasksTotal: 1},
+		Subtasks: []models.TaskTree{
+			{Task: models.TaskExportData{Id: 6, ParentId: 5}, Subtasks: []models.TaskTree{}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tree mismatch: want %+v got %+v", want, got)
+	}
+}
+
+func TestSetTaskRecurrence_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		recurrenceFn: func(ctx context.Context, in *pb.TaskRecurrence, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return &pb.TaskExportData{Id: in.GetId(), Recurrence: in.GetRecurrence()}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.SetTaskRecurrence(context.Background(), 5, &models.Recurrence{Frequency: models.RecurrenceDaily, Interval: 3})
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.gotRecurrence.GetRecurrence().GetFrequency() != pb.RecurrenceFrequency_RECURRENCE_FREQUENCY_DAILY {
+		t.Fatalf("unexpected request %+v", fakeClient.gotRecurrence)
+	}
+	if got.Id != 5 || got.Recurrence == nil || got.Recurrence.Interval != 3 {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestSkipOccurrence_FailedPrecondition_IsTranslated(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		skipFn: func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return nil, status.Error(codes.FailedPrecondition, "task doesn't repeat")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, gotErr := dbClient.SkipOccurrence(context.Background(), 7)
+
+	if !errors.Is(gotErr, app.ErrConflict) {
+		t.Fatalf("expected err %v, got %v", app.ErrConflict, gotErr)
+	}
+	if fakeClient.gotSkip.GetId() != 7 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotSkip)
+	}
+}
+
+func TestReopenTask_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		reopenFn: func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return &pb.TaskExportData{Id: in.GetId()}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.ReopenTask(context.Background(), 3)
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.reopenCalls != 1 || fakeClient.gotReopen.GetId() != 3 {
+		t.Fatalf("unexpected call: calls=%d request=%+v", fakeClient.reopenCalls, fakeClient.gotReopen)
+	}
+	if got.Id != 3 || got.Finished {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestListTrash_DelegatesToGrpcClient(t *testing.T) {
+	deletedAtTS := time.Date(2025, 12, 11, 1, 2, 3, 0, time.UTC)
+	fakeClient := &fakeGrpcClient{
+		listTrashFn: func(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.TaskList, error) {
+			return &pb.TaskList{Tasks: []*pb.TaskExportData{{Id: 3, DeletedAt: timestamppb.New(deletedAtTS)}}}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	got, gotErr := dbClient.ListTrash(context.Background())
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if len(got) != 1 || got[0].Id != 3 || got[0].DeletedAt == nil || !got[0].DeletedAt.Equal(deletedAtTS) {
+		t.Fatalf("unexpected trash %+v", got)
+	}
+}
+
+func TestRestoreTask_FailedPrecondition_IsTranslated(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		restoreFn: func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return nil, status.Error(codes.FailedPrecondition, "parent task is in the trash")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, gotErr := dbClient.RestoreTask(context.Background(), 5)
+
+	if !errors.Is(gotErr, app.ErrConflict) {
+		t.Fatalf("expected err %v, got %v", app.ErrConflict, gotErr)
+	}
+	if fakeClient.gotRestore.GetId() != 5 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotRestore)
+	}
+}
+
+func TestPurgeTask_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		purgeFn: func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+			return &emptypb.Empty{}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	gotErr := dbClient.PurgeTask(context.Background(), 4)
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.purgeCalls != 1 || fakeClient.gotPurge.GetId() != 4 {
+		t.Fatalf("unexpected call: calls=%d request=%+v", fakeClient.purgeCalls, fakeClient.gotPurge)
+	}
+}
+
+func TestGetTaskHistory_DelegatesToGrpcClient(t *testing.T) {
+	changedAtTS := time.Date(2025, 12, 2, 10, 0, 0, 0, time.UTC)
+	wantErr := errors.New("boom")
+	fakeClient := &fakeGrpcClient{
+		historyFn: func(ctx context.Context, in *pb.TaskHistoryRequest, opts ...grpc.CallOption) (*pb.TaskHistoryPage, error) {
+			return &pb.TaskHistoryPage{
+				Changes:    []*pb.TaskChange{{Id: 5, TaskId: 3, ChangedAt: timestamppb.New(changedAtTS), Field: "title"}},
+				NextCursor: 5,
+			}, wantErr
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+	ctx := context.Background()
+
+	gotPage, gotErr := dbClient.GetTaskHistory(ctx, models.TaskHistoryRequest{TaskId: 3, Limit: 1, Cursor: 9})
+
+	if !errors.Is(gotErr, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
+	}
+	if fakeClient.historyCalls != 1 {
+		t.Fatalf("expected GetTaskHistory calls=1, got %d", fakeClient.historyCalls)
+	}
+	if fakeClient.gotHistoryCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if fakeClient.gotHistoryReq.GetTaskId() != 3 || fakeClient.gotHistoryReq.GetLimit() != 1 ||
+		fakeClient.gotHistoryReq.GetCursor() != 9 {
+		t.Fatalf("unexpected history request %+v", fakeClient.gotHistoryReq)
+	}
+	if gotPage.NextCursor != 5 || len(gotPage.Changes) != 1 || gotPage.Changes[0].Field != "title" {
+		t.Fatalf("unexpected history page %+v", gotPage)
+	}
+}
+
+func TestActorInterceptor_AddsActorToMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{name: "with actor", ctx: app.WithActor(context.Background(), "alice"), want: []string{"alice"}},
+		{name: "without actor", ctx: context.Background(), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				got = md.Get(actorMetadataKey)
+				return nil
+			}
+
+			if err := ActorInterceptor(tt.ctx, "/TasksService/AddTask", nil, nil, nil, invoker); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected actor %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetTaskPriority_SendsExpectedVersion(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		priorityFn: func(ctx context.Context, in *pb.TaskPriority, opts ...grpc.CallOption) (*pb.TaskExportData, error) {
+			return nil, status.Error(codes.Aborted, "task was changed by someone else")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, gotErr := dbClient.SetTaskPriority(app.WithExpectedVersion(context.Background(), 4), 3, models.PriorityLow)
+
+	if !errors.Is(gotErr, app.ErrPreconditionFailed) {
+		t.Fatalf("expected err %v, got %v", app.ErrPreconditionFailed, gotErr)
+	}
+	if fakeClient.gotPriority.GetExpectedVersion() != 4 {
+		t.Fatalf("expected version 4 in request %+v", fakeClient.gotPriority)
+	}
+}
+
+func TestRemoveTask_SendsExpectedVersion(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		removeFn: func(ctx context.Context, in *pb.TaskId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+			return &emptypb.Empty{}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	if err := dbClient.RemoveTask(app.WithExpectedVersion(context.Background(), 2), 9); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeClient.gotRemoveId.GetId() != 9 || fakeClient.gotRemoveId.GetExpectedVersion() != 2 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotRemoveId)
+	}
+}
+
+func TestBatchAddTasks_TranslatesItemErrors(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		batchAddFn: func(ctx context.Context, in *pb.BatchAddTasksRequest, opts ...grpc.CallOption) (*pb.BatchResult, error) {
+			return &pb.BatchResult{Items: []*pb.BatchItemResult{
+				{Id: 7, Task: &pb.TaskExportData{Id: 7, Title: "a"}},
+				{Code: int32(codes.AlreadyExists), Error: "idempotency key was already used"},
+				{Code: int32(codes.NotFound), Error: "task not found"},
+			}}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	items, gotErr := dbClient.BatchAddTasks(context.Background(), []models.TaskImportData{{Title: "a"}, {Title: "b"}, {Title: "c", ParentId: 9}})
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if len(fakeClient.gotBatchAdd.GetTasks()) != 3 || fakeClient.gotBatchAdd.GetTasks()[2].GetParentId() != 9 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotBatchAdd)
+	}
+	if len(items) != 3 || items[0].Err != nil || items[0].Task.Title != "a" {
+		t.Fatalf("unexpected items %+v", items)
+	}
+	if !errors.Is(items[1].Err, app.ErrIdempotencyKeyReused) {
+		t.Fatalf("expected err %v, got %v", app.ErrIdempotencyKeyReused, items[1].Err)
+	}
+	if !errors.Is(items[2].Err, app.ErrNotFound) {
+		t.Fatalf("expected err %v, got %v", app.ErrNotFound, items[2].Err)
+	}
+}
+
+func TestBatchRemoveTasks_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		batchRemoveFn: func(ctx context.Context, in *pb.TaskIds, opts ...grpc.CallOption) (*pb.BatchResult, error) {
+			return nil, status.Error(codes.InvalidArgument, "batch must have 1 to 500 items")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	items, gotErr := dbClient.BatchRemoveTasks(context.Background(), []int{4, 5})
+
+	if !errors.Is(gotErr, app.ErrInvalidArgument) {
+		t.Fatalf("expected err %v, got %v", app.ErrInvalidArgument, gotErr)
+	}
+	if items != nil {
+		t.Fatalf("expected no items, got %+v", items)
+	}
+	if ids := fakeClient.gotBatchRemove.GetIds(); len(ids) != 2 || ids[0] != 4 || ids[1] != 5 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotBatchRemove)
+	}
+}
+
+func TestSearchTasks_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		searchFn: func(ctx context.Context, in *pb.SearchRequest, opts ...grpc.CallOption) (*pb.SearchResult, error) {
+			return &pb.SearchResult{Hits: []*pb.SearchHit{
+				{Task: &pb.TaskExportData{Id: 2, Title: "Buy milk"}, Rank: 0.5, TitleSnippet: "Buy <b>milk</b>"},
+			}}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	hits, gotErr := dbClient.SearchTasks(context.Background(), models.SearchRequest{Query: "mil", Limit: 3})
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.gotSearch.GetQuery() != "mil" || fakeClient.gotSearch.GetLimit() != 3 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotSearch)
+	}
+	if len(hits) != 1 || hits[0].Task.Id != 2 || hits[0].Rank != 0.5 || hits[0].TitleSnippet != "Buy <b>milk</b>" {
+		t.Fatalf("unexpected hits %+v", hits)
+	}
+}
+
+type fakeEventStream struct {
+	grpc.ClientStream
+	events []*pb.TaskEvent
+	err    error
+}
+
+// Recv returns the events one by one and then err.
+func (f *fakeEventStream) Recv() (*pb.TaskEvent, error) {
+	if len(f.events) == 0 {
+		return nil, f.err
+	}
+	event := f.events[0]
+	f.events = f.events[1:]
+	return event, nil
+}
+
+func TestWatchTasks_PassesEventsUntilEOF(t *testing.T) {
+	occurredAt := time.Date(2025, 12, 24, 10, 0, 0, 0, time.UTC)
+	fakeClient := &fakeGrpcClient{
+		watchFn: func(ctx context.Context, in *pb.WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[pb.TaskEvent], error) {
+			return &fakeEventStream{
+				events: []*pb.TaskEvent{{
+					Id:         8,
+					Type:       pb.TaskEventType_TASK_EVENT_TYPE_FINISHED,
+					TaskId:     2,
+					ProjectId:  1,
+					Version:    3,
+					Actor:      "alice",
+					OccurredAt: timestamppb.New(occurredAt),
+				}},
+				err: io.EOF,
+			}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	var got []models.TaskEvent
+	gotErr := dbClient.WatchTasks(context.Background(), models.WatchRequest{ProjectId: 1, LastEventId: 7}, func(event models.TaskEvent) error {
+		got = append(got, event)
+		return nil
+	})
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeClient.gotWatch.GetProjectId() != 1 || fakeClient.gotWatch.GetLastEventId() != 7 {
+		t.Fatalf("unexpected request %+v", fakeClient.gotWatch)
+	}
+	want := []models.TaskEvent{{Id: 8, Type: models.TaskEventFinished, TaskId: 2, ProjectId: 1, Version: 3, Actor: "alice", OccurredAt: occurredAt}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("events: got %+v want %+v", got, want)
+	}
+}
+
+func TestWatchTasks_StreamError_IsTranslated(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		watchFn: func(ctx context.Context, in *pb.WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[pb.TaskEvent], error) {
+			return &fakeEventStream{err: status.Error(codes.InvalidArgument, "bad project")}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	gotErr := dbClient.WatchTasks(context.Background(), models.WatchRequest{}, func(models.TaskEvent) error { return nil })
+
+	if !errors.Is(gotErr, app.ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", app.ErrInvalidArgument, gotErr)
+	}
+}
+
+func TestWatchTasks_CanceledContext_ReturnsNil(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fakeClient := &fakeGrpcClient{
+		watchFn: func(ctx context.Context, in *pb.WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[pb.TaskEvent], error) {
+			cancel()
+			return &fakeEventStream{err: status.Error(codes.Canceled, "context canceled")}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	gotErr := dbClient.WatchTasks(ctx, models.WatchRequest{}, func(models.TaskEvent) error { return nil })
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+}
+
+func TestGetChangesSince_DelegatesToGrpcClient(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		changesFn: func(ctx context.Context, in *pb.ChangesRequest, opts ...grpc.CallOption) (*pb.Changes, error) {
+			if in.GetToken() != "abc" || in.GetLimit() != 50 {
+				t.Fatalf("unexpected request %+v", in)
+			}
+			return &pb.Changes{
+				Tasks:      []*pb.TaskExportData{{Id: 2, Title: "Buy milk"}},
+				DeletedIds: []int64{5},
+				Token:      "def",
+				HasMore:    true,
+			}, nil
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	changes, gotErr := dbClient.GetChangesSince(context.Background(), "abc", 50)
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if len(changes.Tasks) != 1 || changes.Tasks[0].Title != "Buy milk" || len(changes.DeletedIds) != 1 || changes.DeletedIds[0] != 5 {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if changes.Token != "def" || !changes.HasMore || changes.Full {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+}
+
+func TestGetChangesSince_ExpiredToken_ReturnsInvalidArgument(t *testing.T) {
+	fakeClient := &fakeGrpcClient{
+		changesFn: func(ctx context.Context, in *pb.ChangesRequest, opts ...grpc.CallOption) (*pb.Changes, error) {
+			return nil, status.Error(codes.InvalidArgument, "bad sync token")
+		},
+	}
+	dbClient := NewDBClient(fakeClient)
+
+	_, gotErr := dbClient.GetChangesSince(context.Background(), "???", 0)
+
+	if !errors.Is(gotErr, app.ErrInvalidArgument) {
+		t.Fatalf("expected err %v, got %v", app.ErrInvalidArgument, gotErr)
+	}
+}
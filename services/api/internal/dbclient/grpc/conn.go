@@ -0,0 +1,142 @@
+package dbgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+const (
+	DefaultTimeout = 5 * time.Second
+
+	// keepaliveTime must not be below the MinTime db-service enforces.
+	keepaliveTime    = 30 * time.Second
+	keepaliveTimeout = 10 * time.Second
+)
+
+// retriedMethods are the reads, which are safe to send again. A write is
+// not retried: the first attempt may have been applied with its answer
+// lost, and the retry would fail its version check or apply it twice.
+var retriedMethods = []string{
+	"ListTrash",
+	"GetTaskTree",
+	"GetTaskHistory",
+	"ListAllTasks",
+	"ListTasks",
+	"SearchTasks",
+	"GetChangesSince",
+	"ListTags",
+	"ListProjects",
+	"GetStats",
+}
+
+// Options configure the connection to db-service.
+type Options struct {
+	// Addresses of the db-service replicas; calls are spread across them
+	// round-robin.
+	Addresses []string
+	// Timeout bounds every unary call, retries included.
+	Timeout          time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// Token identifies api-service to db-service; empty sends none.
+	Token string
+	TLS   TLSOptions
+}
+
+// NewConn connects to db-service. Calls forward the actor, get a deadline
+// and go through a Breaker; reads that fail with Unavailable are retried.
+func NewConn(opts Options) (*grpc.ClientConn, error) {
+	serviceConfig, err := serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]resolver.Address, 0, len(opts.Addresses))
+	for _, address := range opts.Addresses {
+		addresses = append(addresses, resolver.Address{Addr: address})
+	}
+	replicas := manual.NewBuilderWithScheme("db-service")
+	replicas.InitialState(resolver.State{Addresses: addresses})
+
+	transportCreds := insecure.NewCredentials()
+	if opts.TLS.Enabled {
+		tlsConfig, err := clientTLSConfig(opts.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("TLS: %w", err)
+		}
+		transportCreds = credentials.NewTLS(tlsConfig)
+	}
+
+	breaker := NewBreaker(opts.BreakerThreshold, opts.BreakerCooldown)
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithResolvers(replicas),
+		grpc.WithTransportCredentials(transportCreds),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                keepaliveTime,
+			Timeout:             keepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithChainUnaryInterceptor(ActorInterceptor, breaker.UnaryInterceptor, DeadlineInterceptor(opts.Timeout)),
+	}
+	if opts.Token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(callerToken(opts.Token)))
+	}
+
+	return grpc.NewClient(replicas.Scheme()+":///db-service", dialOpts...)
+}
+
+// DeadlineInterceptor gives every unary call at most timeout, or less when
+// the caller's deadline is earlier. Streams are long-lived and get none.
+func DeadlineInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// serviceConfig balances calls round-robin and retries retriedMethods. The
+// retry budget stops retries from piling onto a db-service that fails most
+// calls.
+func serviceConfig() (string, error) {
+	type methodName struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	names := make([]methodName, 0, len(retriedMethods))
+	for _, method := range retriedMethods {
+		names = append(names, methodName{Service: pb.TasksService_ServiceDesc.ServiceName, Method: method})
+	}
+
+	config := map[string]any{
+		"loadBalancingConfig": []any{map[string]any{"round_robin": map[string]any{}}},
+		"methodConfig": []any{map[string]any{
+			"name": names,
+			"retryPolicy": map[string]any{
+				"maxAttempts":          3,
+				"initialBackoff":       "0.1s",
+				"maxBackoff":           "1s",
+				"backoffMultiplier":    2,
+				"retryableStatusCodes": []string{"UNAVAILABLE"},
+			},
+		}},
+		"retryThrottling": map[string]any{
+			"maxTokens":  10,
+			"tokenRatio": 0.1,
+		},
+	}
+	b, err := json.Marshal(config)
+	return string(b), err
+}
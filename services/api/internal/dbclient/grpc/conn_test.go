@@ -0,0 +1,97 @@
+package dbgrpc
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/pkg/pb"
+	"google.golang.org/grpc"
+)
+
+func TestNewConn_ServiceConfigIsValid(t *testing.T) {
+	conn, err := NewConn(Options{
+		Addresses:        []string{"db-1:9091", "db-2:9091"},
+		Timeout:          time.Second,
+		BreakerThreshold: DefaultBreakerThreshold,
+		BreakerCooldown:  DefaultBreakerCooldown,
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	_ = conn.Close()
+}
+
+func TestNewConn_MissingTLSFiles_ReturnsError(t *testing.T) {
+	_, err := NewConn(Options{
+		Addresses: []string{"db-1:9091"},
+		TLS:       TLSOptions{Enabled: true, CAFile: t.TempDir() + "/ca.pem"},
+	})
+
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestRetriedMethods_AreReads(t *testing.T) {
+	var unary []string
+	for _, method := range pb.TasksService_ServiceDesc.Methods {
+		unary = append(unary, method.MethodName)
+	}
+
+	for _, method := range retriedMethods {
+		if !slices.Contains(unary, method) {
+			t.Errorf("%s is not a unary method of %s", method, pb.TasksService_ServiceDesc.ServiceName)
+		}
+		if !slices.ContainsFunc([]string{"List", "Get", "Search"}, func(prefix string) bool {
+			return len(method) > len(prefix) && method[:len(prefix)] == prefix
+		}) {
+			t.Errorf("%s doesn't look like a read, retrying it may apply a change twice", method)
+		}
+	}
+}
+
+func TestDeadlineInterceptor_BoundsTheCall(t *testing.T) {
+	var deadline time.Time
+	var ok bool
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		deadline, ok = ctx.Deadline()
+		return nil
+	}
+
+	before := time.Now()
+	_ = DeadlineInterceptor(time.Second)(context.Background(), "/pb.TasksService/GetStats", nil, nil, nil, invoker)
+
+	if !ok || deadline.Before(before.Add(time.Second)) || deadline.After(time.Now().Add(time.Second)) {
+		t.Fatalf("expected a deadline in a second, got %v (set: %v)", deadline, ok)
+	}
+}
+
+func TestDeadlineInterceptor_KeepsEarlierCallerDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	var got time.Time
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		got, _ = ctx.Deadline()
+		return nil
+	}
+
+	_ = DeadlineInterceptor(time.Minute)(ctx, "/pb.TasksService/GetStats", nil, nil, nil, invoker)
+
+	if !got.Equal(want) {
+		t.Fatalf("expected deadline %v, got %v", want, got)
+	}
+}
+
+func TestCallerToken_SendsBearerToken(t *testing.T) {
+	got, err := callerToken("secret").GetRequestMetadata(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got["authorization"] != "Bearer secret" {
+		t.Fatalf("expected bearer token, got %v", got)
+	}
+}
@@ -1,15 +1,32 @@
 package dbgrpc
 
 import (
+	"time"
+
 	"github.com/dodocheck/go-pet-project-1/pkg/pb"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func taskImportDataToPB(task models.TaskImportData) *pb.TaskImportData {
-	return &pb.TaskImportData{
-		Title: task.Title,
-		Text:  task.Text,
+	out := &pb.TaskImportData{
+		Title:          task.Title,
+		Text:           task.Text,
+		Priority:       pb.Priority(task.Priority),
+		Tags:           task.Tags,
+		ProjectId:      int64(task.ProjectId),
+		ParentId:       int64(task.ParentId),
+		Recurrence:     recurrenceToPB(task.Recurrence),
+		IdempotencyKey: task.IdempotencyKey,
+	}
+
+	if task.DueAt != nil {
+		out.DueAt = timestamppb.New(*task.DueAt)
 	}
+
+	return out
 }
 
 func taskExportDataFromPB(task *pb.TaskExportData) models.TaskExportData {
@@ -18,10 +35,21 @@ func taskExportDataFromPB(task *pb.TaskExportData) models.TaskExportData {
 	}
 
 	out := models.TaskExportData{
-		Id:       int(task.GetId()),
-		Title:    task.GetTitle(),
-		Text:     task.GetText(),
-		Finished: task.GetFinished(),
+		Id:               int(task.GetId()),
+		Title:            task.GetTitle(),
+		Text:             task.GetText(),
+		Finished:         task.GetFinished(),
+		Overdue:          task.GetOverdue(),
+		Priority:         models.Priority(task.GetPriority()),
+		Position:         task.GetPosition(),
+		Tags:             task.GetTags(),
+		ProjectId:        int(task.GetProjectId()),
+		ParentId:         int(task.GetParentId()),
+		SubtasksTotal:    int(task.GetSubtasksTotal()),
+		SubtasksDone:     int(task.GetSubtasksDone()),
+		Recurrence:       recurrenceFromPB(task.GetRecurrence()),
+		NextOccurrenceId: int(task.GetNextOccurrenceId()),
+		Version:          int(task.GetVersion()),
 	}
 
 	if task.GetCreatedAt() != nil {
@@ -33,6 +61,70 @@ func taskExportDataFromPB(task *pb.TaskExportData) models.TaskExportData {
 		out.FinishedAt = &ts
 	}
 
+	if task.GetDueAt() != nil {
+		ts := task.GetDueAt().AsTime()
+		out.DueAt = &ts
+	}
+
+	if task.GetDeletedAt() != nil {
+		ts := task.GetDeletedAt().AsTime()
+		out.DeletedAt = &ts
+	}
+
+	return out
+}
+
+func recurrenceToPB(rule *models.Recurrence) *pb.Recurrence {
+	if rule == nil {
+		return nil
+	}
+
+	out := &pb.Recurrence{
+		Frequency: pb.RecurrenceFrequency(rule.Frequency),
+		Interval:  int32(rule.Interval),
+		MonthDay:  int32(rule.MonthDay),
+		TimeZone:  rule.TimeZone,
+	}
+	for _, day := range rule.Weekdays {
+		out.Weekdays = append(out.Weekdays, int32(day))
+	}
+	if rule.Until != nil {
+		out.Until = timestamppb.New(*rule.Until)
+	}
+
+	return out
+}
+
+func recurrenceFromPB(rule *pb.Recurrence) *models.Recurrence {
+	if rule == nil {
+		return nil
+	}
+
+	out := &models.Recurrence{
+		Frequency: models.RecurrenceFrequency(rule.GetFrequency()),
+		Interval:  int(rule.GetInterval()),
+		MonthDay:  int(rule.GetMonthDay()),
+		TimeZone:  rule.GetTimeZone(),
+	}
+	for _, day := range rule.GetWeekdays() {
+		out.Weekdays = append(out.Weekdays, time.Weekday(day))
+	}
+	if rule.GetUntil() != nil {
+		until := rule.GetUntil().AsTime()
+		out.Until = &until
+	}
+
+	return out
+}
+
+func taskTreeFromPB(tree *pb.TaskTree) models.TaskTree {
+	out := models.TaskTree{
+		Task:     taskExportDataFromPB(tree.GetTask()),
+		Subtasks: make([]models.TaskTree, 0, len(tree.GetSubtasks())),
+	}
+	for _, subtask := range tree.GetSubtasks() {
+		out.Subtasks = append(out.Subtasks, taskTreeFromPB(subtask))
+	}
 	return out
 }
 
@@ -48,6 +140,88 @@ func taskSliceFromPB(tasks *pb.TaskList) []models.TaskExportData {
 	return taskSlice
 }
 
+func taskFilterToPB(filter models.TaskFilter) *pb.TaskFilter {
+	return &pb.TaskFilter{
+		Due:       pb.DueFilter(filter.Due),
+		TimeZone:  filter.TimeZone,
+		Sort:      pb.TaskSort(filter.Sort),
+		Tags:      filter.Tags,
+		TagMatch:  pb.TagMatch(filter.TagMatch),
+		ProjectId: int64(filter.ProjectId),
+	}
+}
+
+func tagSliceFromPB(tags *pb.TagList) []models.TagUsage {
+	if tags == nil {
+		return nil
+	}
+
+	tagSlice := make([]models.TagUsage, 0, len(tags.GetTags()))
+	for _, v := range tags.GetTags() {
+		tagSlice = append(tagSlice, models.TagUsage{
+			Name:  v.GetName(),
+			Count: int(v.GetCount()),
+		})
+	}
+	return tagSlice
+}
+
+func tagChangeFromPB(change *pb.TagChange) models.TagChange {
+	if change == nil {
+		return models.TagChange{}
+	}
+
+	out := models.TagChange{
+		Name:    change.GetName(),
+		TaskIds: make([]int, 0, len(change.GetTaskIds())),
+	}
+
+	for _, id := range change.GetTaskIds() {
+		out.TaskIds = append(out.TaskIds, int(id))
+	}
+
+	return out
+}
+
+func projectFromPB(project *pb.Project) models.Project {
+	if project == nil {
+		return models.Project{}
+	}
+
+	out := models.Project{
+		Id:       int(project.GetId()),
+		Name:     project.GetName(),
+		Inbox:    project.GetInbox(),
+		Archived: project.GetArchived(),
+	}
+
+	if project.GetCreatedAt() != nil {
+		out.CreatedAt = project.GetCreatedAt().AsTime()
+	}
+
+	return out
+}
+
+func projectSliceFromPB(projects *pb.ProjectList) []models.Project {
+	if projects == nil {
+		return nil
+	}
+
+	projectSlice := make([]models.Project, 0, len(projects.GetProjects()))
+	for _, v := range projects.GetProjects() {
+		projectSlice = append(projectSlice, projectFromPB(v))
+	}
+	return projectSlice
+}
+
+func taskMoveToPB(move models.TaskMove) *pb.MoveTaskRequest {
+	return &pb.MoveTaskRequest{
+		Id:       int64(move.Id),
+		BeforeId: int64(move.BeforeId),
+		AfterId:  int64(move.AfterId),
+	}
+}
+
 func taskIdToPB(id int) *pb.TaskId {
 	if id < 0 {
 		return nil
@@ -57,3 +231,151 @@ func taskIdToPB(id int) *pb.TaskId {
 		Id: int64(id),
 	}
 }
+
+func statsRequestToPB(req models.StatsRequest) *pb.StatsRequest {
+	out := &pb.StatsRequest{
+		Bucket:   pb.StatsBucket(req.Bucket),
+		TimeZone: req.TimeZone,
+	}
+
+	if !req.From.IsZero() {
+		out.From = timestamppb.New(req.From)
+	}
+	if !req.To.IsZero() {
+		out.To = timestamppb.New(req.To)
+	}
+
+	return out
+}
+
+func statsFromPB(stats *pb.Stats) models.Stats {
+	if stats == nil {
+		return models.Stats{}
+	}
+
+	out := models.Stats{
+		Points:            make([]models.StatsPoint, 0, len(stats.GetPoints())),
+		CreatedTotal:      int(stats.GetCreatedTotal()),
+		CompletedTotal:    int(stats.GetCompletedTotal()),
+		AvgTimeToComplete: stats.GetAvgTimeToComplete().AsDuration(),
+		OpenCount:         int(stats.GetOpenCount()),
+		CurrentStreak:     int(stats.GetCurrentStreak()),
+		LongestStreak:     int(stats.GetLongestStreak()),
+	}
+
+	for _, v := range stats.GetPoints() {
+		out.Points = append(out.Points, models.StatsPoint{
+			Start:     v.GetStart().AsTime(),
+			Created:   int(v.GetCreated()),
+			Completed: int(v.GetCompleted()),
+		})
+	}
+
+	return out
+}
+
+func taskHistoryPageFromPB(page *pb.TaskHistoryPage) models.TaskHistoryPage {
+	if page == nil {
+		return models.TaskHistoryPage{}
+	}
+
+	out := models.TaskHistoryPage{
+		Changes:    make([]models.TaskChange, 0, len(page.GetChanges())),
+		NextCursor: int(page.GetNextCursor()),
+	}
+
+	for _, change := range page.GetChanges() {
+		out.Changes = append(out.Changes, models.TaskChange{
+			Id:        int(change.GetId()),
+			TaskId:    int(change.GetTaskId()),
+			ChangedAt: change.GetChangedAt().AsTime(),
+			ChangedBy: change.GetChangedBy(),
+			Field:     change.GetField(),
+			OldValue:  change.GetOldValue(),
+			NewValue:  change.GetNewValue(),
+		})
+	}
+
+	return out
+}
+
+func taskIdsToPB(ids []int) *pb.TaskIds {
+	out := &pb.TaskIds{Ids: make([]int64, 0, len(ids))}
+	for _, id := range ids {
+		out.Ids = append(out.Ids, int64(id))
+	}
+	return out
+}
+
+// batchItemsFromPB turns the code of a failed item into the error the single
+// request would have returned, translated by itemError.
+func batchItemsFromPB(result *pb.BatchResult, itemError func(error) error) []models.BatchItem {
+	if result == nil {
+		return nil
+	}
+
+	out := make([]models.BatchItem, 0, len(result.GetItems()))
+	for _, item := range result.GetItems() {
+		batchItem := models.BatchItem{Id: int(item.GetId())}
+		if code := codes.Code(item.GetCode()); code != codes.OK {
+			batchItem.Err = itemError(status.Error(code, item.GetError()))
+		} else if item.GetTask() != nil {
+			batchItem.Task = taskExportDataFromPB(item.GetTask())
+		}
+		out = append(out, batchItem)
+	}
+	return out
+}
+
+func searchHitsFromPB(result *pb.SearchResult) []models.SearchHit {
+	if result == nil {
+		return nil
+	}
+
+	out := make([]models.SearchHit, 0, len(result.GetHits()))
+	for _, hit := range result.GetHits() {
+		out = append(out, models.SearchHit{
+			Task:         taskExportDataFromPB(hit.GetTask()),
+			Rank:         hit.GetRank(),
+			TitleSnippet: hit.GetTitleSnippet(),
+			TextSnippet:  hit.GetTextSnippet(),
+		})
+	}
+	return out
+}
+
+func taskEventFromPB(event *pb.TaskEvent) models.TaskEvent {
+	out := models.TaskEvent{
+		Id:        int(event.GetId()),
+		Type:      models.TaskEventType(event.GetType()),
+		TaskId:    int(event.GetTaskId()),
+		ProjectId: int(event.GetProjectId()),
+		Version:   int(event.GetVersion()),
+		Actor:     event.GetActor(),
+	}
+	if event.GetOccurredAt() != nil {
+		out.OccurredAt = event.GetOccurredAt().AsTime()
+	}
+	return out
+}
+
+func changesFromPB(changes *pb.Changes) models.Changes {
+	if changes == nil {
+		return models.Changes{}
+	}
+
+	out := models.Changes{
+		Tasks:      make([]models.TaskExportData, 0, len(changes.GetTasks())),
+		DeletedIds: make([]int, 0, len(changes.GetDeletedIds())),
+		Token:      changes.GetToken(),
+		HasMore:    changes.GetHasMore(),
+		Full:       changes.GetFull(),
+	}
+	for _, task := range changes.GetTasks() {
+		out.Tasks = append(out.Tasks, taskExportDataFromPB(task))
+	}
+	for _, id := range changes.GetDeletedIds() {
+		out.DeletedIds = append(out.DeletedIds, int(id))
+	}
+	return out
+}
@@ -1,15 +1,19 @@
 package dbgrpc
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/dodocheck/go-pet-project-1/pkg/pb"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestTaskImportDataToPB(t *testing.T) {
+	dueAtTS := time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name string
 		in   models.TaskImportData
@@ -26,6 +30,60 @@ func TestTaskImportDataToPB(t *testing.T) {
 				Text:  "2 bottles",
 			},
 		},
+		{
+			name: "task with idempotency key",
+			in: models.TaskImportData{
+				Title:          "Buy milk",
+				IdempotencyKey: "abc",
+			},
+			want: &pb.TaskImportData{
+				Title:          "Buy milk",
+				IdempotencyKey: "abc",
+			},
+		},
+		{
+			name: "task with due date",
+			in: models.TaskImportData{
+				Title: "Pay rent",
+				DueAt: &dueAtTS,
+			},
+			want: &pb.TaskImportData{
+				Title: "Pay rent",
+				DueAt: timestamppb.New(dueAtTS),
+			},
+		},
+		{
+			name: "task with tags",
+			in: models.TaskImportData{
+				Title: "Call mom",
+				Tags:  []string{"family"},
+			},
+			want: &pb.TaskImportData{
+				Title: "Call mom",
+				Tags:  []string{"family"},
+			},
+		},
+		{
+			name: "recurring task",
+			in: models.TaskImportData{
+				Title: "Water plants",
+				Recurrence: &models.Recurrence{
+					Frequency: models.RecurrenceWeekly,
+					Interval:  2,
+					Weekdays:  []time.Weekday{time.Monday, time.Thursday},
+					TimeZone:  "Europe/Moscow",
+				},
+			},
+			want: &pb.TaskImportData{
+				Title: "Water plants",
+				Recurrence: &pb.Recurrence{
+					Frequency: pb.RecurrenceFrequency_RECURRENCE_FREQUENCY_WEEKLY,
+					Interval:  2,
+					Weekdays:  []int32{1, 4},
+					TimeZone:  "Europe/Moscow",
+				},
+			},
+		},
 		{
 			name: "empty task",
 			in:   models.TaskImportData{},
@@ -48,10 +106,49 @@ func TestTaskImportDataToPB(t *testing.T) {
 			if got.Text != tt.want.Text {
 				t.Fatalf("expected text %q, got %q", tt.want.Text, got.Text)
 			}
+			if (got.GetDueAt() == nil) != (tt.want.GetDueAt() == nil) ||
+				(got.GetDueAt() != nil && !got.GetDueAt().AsTime().Equal(tt.want.GetDueAt().AsTime())) {
+				t.Fatalf("expected due at %v, got %v", tt.want.GetDueAt(), got.GetDueAt())
+			}
+			if !reflect.DeepEqual(got.GetTags(), tt.want.GetTags()) {
+				t.Fatalf("expected tags %v, got %v", tt.want.GetTags(), got.GetTags())
+			}
+			if got.GetRecurrence().GetFrequency() != tt.want.GetRecurrence().GetFrequency() ||
+				got.GetRecurrence().GetInterval() != tt.want.GetRecurrence().GetInterval() ||
+				!reflect.DeepEqual(got.GetRecurrence().GetWeekdays(), tt.want.GetRecurrence().GetWeekdays()) ||
+				got.GetRecurrence().GetTimeZone() != tt.want.GetRecurrence().GetTimeZone() {
+				t.Fatalf("expected recurrence %v, got %v", tt.want.GetRecurrence(), got.GetRecurrence())
+			}
 		})
 	}
 }
 
+func TestRecurrenceFromPB(t *testing.T) {
+	untilTS := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	got := recurrenceFromPB(&pb.Recurrence{
+		Frequency: pb.RecurrenceFrequency_RECURRENCE_FREQUENCY_MONTHLY,
+		Interval:  1,
+		MonthDay:  31,
+		Until:     timestamppb.New(untilTS),
+		TimeZone:  "UTC",
+	})
+	want := &models.Recurrence{
+		Frequency: models.RecurrenceMonthly,
+		Interval:  1,
+		MonthDay:  31,
+		Until:     &untilTS,
+		TimeZone:  "UTC",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if recurrenceFromPB(nil) != nil {
+		t.Fatal("expected nil recurrence for nil message")
+	}
+}
+
 func TestTaskExportDataFromPB(t *testing.T) {
 	createdAtTS := time.Date(2025, 12, 11, 1, 2, 3, 4, time.UTC)
 	finishedAtTS := time.Date(2025, 11, 22, 2, 3, 4, 5, time.UTC)
@@ -80,6 +177,21 @@ func TestTaskExportDataFromPB(t *testing.T) {
 				FinishedAt: nil,
 			},
 		},
+		{
+			name: "task with version",
+			in: &pb.TaskExportData{
+				Id:        2,
+				Title:     "Edited",
+				CreatedAt: timestamppb.New(createdAtTS),
+				Version:   6,
+			},
+			want: models.TaskExportData{
+				Id:        2,
+				Title:     "Edited",
+				CreatedAt: createdAtTS,
+				Version:   6,
+			},
+		},
 		{
 			name: "finished task",
 			in: &pb.TaskExportData{
@@ -99,6 +211,25 @@ func TestTaskExportDataFromPB(t *testing.T) {
 				FinishedAt: &finishedAtTS,
 			},
 		},
+		{
+			name: "ranked task",
+			in: &pb.TaskExportData{
+				Id:        3,
+				Title:     "Plan sprint",
+				CreatedAt: timestamppb.New(createdAtTS),
+				Priority:  pb.Priority_PRIORITY_HIGH,
+				Position:  "ai",
+				Tags:      []string{"work"},
+			},
+			want: models.TaskExportData{
+				Id:        3,
+				Title:     "Plan sprint",
+				CreatedAt: createdAtTS,
+				Priority:  models.PriorityHigh,
+				Position:  "ai",
+				Tags:      []string{"work"},
+			},
+		},
 		{
 			name: "nil task",
 			in:   nil,
@@ -130,6 +261,12 @@ func TestTaskExportDataFromPB(t *testing.T) {
 					!got.FinishedAt.Equal(*tt.want.FinishedAt) {
 					t.Fatalf("expected FinishedAt %v, got %v", tt.want.FinishedAt, got.FinishedAt)
 				}
+				if got.Priority != tt.want.Priority || got.Position != tt.want.Position {
+					t.Fatalf("expected priority %v at %q, got %v at %q", tt.want.Priority, tt.want.Position, got.Priority, got.Position)
+				}
+				if !reflect.DeepEqual(got.Tags, tt.want.Tags) {
+					t.Fatalf("expected Tags %v, got %v", tt.want.Tags, got.Tags)
+				}
 			}
 		})
 	}
@@ -264,3 +401,246 @@ func TestTaskIdToPB(t *testing.T) {
 		})
 	}
 }
+
+func TestStatsRequestToPB(t *testing.T) {
+	fromTS := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	toTS := time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   models.StatsRequest
+		want *pb.StatsRequest
+	}{
+		{
+			name: "regular request",
+			in: models.StatsRequest{
+				From:     fromTS,
+				To:       toTS,
+				Bucket:   models.StatsBucketWeek,
+				TimeZone: "Europe/Moscow",
+			},
+			want: &pb.StatsRequest{
+				From:     timestamppb.New(fromTS),
+				To:       timestamppb.New(toTS),
+				Bucket:   pb.StatsBucket_STATS_BUCKET_WEEK,
+				TimeZone: "Europe/Moscow",
+			},
+		},
+		{
+			name: "request without range",
+			in:   models.StatsRequest{},
+			want: &pb.StatsRequest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statsRequestToPB(tt.in)
+			if got.GetBucket() != tt.want.GetBucket() {
+				t.Fatalf("expected bucket %v, got %v", tt.want.GetBucket(), got.GetBucket())
+			}
+			if got.GetTimeZone() != tt.want.GetTimeZone() {
+				t.Fatalf("expected time zone %q, got %q", tt.want.GetTimeZone(), got.GetTimeZone())
+			}
+			if (got.GetFrom() == nil) != (tt.want.GetFrom() == nil) ||
+				(got.GetFrom() != nil && !got.GetFrom().AsTime().Equal(tt.want.GetFrom().AsTime())) {
+				t.Fatalf("expected from %v, got %v", tt.want.GetFrom(), got.GetFrom())
+			}
+			if (got.GetTo() == nil) != (tt.want.GetTo() == nil) ||
+				(got.GetTo() != nil && !got.GetTo().AsTime().Equal(tt.want.GetTo().AsTime())) {
+				t.Fatalf("expected to %v, got %v", tt.want.GetTo(), got.GetTo())
+			}
+		})
+	}
+}
+
+func TestStatsFromPB(t *testing.T) {
+	startTS := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   *pb.Stats
+		want models.Stats
+	}{
+		{
+			name: "regular stats",
+			in: &pb.Stats{
+				Points: []*pb.StatsPoint{
+					{Start: timestamppb.New(startTS), Created: 4, Completed: 3},
+				},
+				CreatedTotal:      4,
+				CompletedTotal:    3,
+				AvgTimeToComplete: durationpb.New(time.Hour),
+				OpenCount:         2,
+				CurrentStreak:     1,
+				LongestStreak:     6,
+			},
+			want: models.Stats{
+				Points: []models.StatsPoint{
+					{Start: startTS, Created: 4, Completed: 3},
+				},
+				CreatedTotal:      4,
+				CompletedTotal:    3,
+				AvgTimeToComplete: time.Hour,
+				OpenCount:         2,
+				CurrentStreak:     1,
+				LongestStreak:     6,
+			},
+		},
+		{
+			name: "nil stats",
+			in:   nil,
+			want: models.Stats{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statsFromPB(tt.in)
+			if len(got.Points) != len(tt.want.Points) {
+				t.Fatalf("expected %d points, got %d", len(tt.want.Points), len(got.Points))
+			}
+			for i := range got.Points {
+				if !got.Points[i].Start.Equal(tt.want.Points[i].Start) ||
+					got.Points[i].Created != tt.want.Points[i].Created ||
+					got.Points[i].Completed != tt.want.Points[i].Completed {
+					t.Fatalf("point %d mismatch: want %+v, got %+v", i, tt.want.Points[i], got.Points[i])
+				}
+			}
+			if got.AvgTimeToComplete != tt.want.AvgTimeToComplete {
+				t.Fatalf("expected avg %v, got %v", tt.want.AvgTimeToComplete, got.AvgTimeToComplete)
+			}
+			if got.CreatedTotal != tt.want.CreatedTotal || got.CompletedTotal != tt.want.CompletedTotal ||
+				got.OpenCount != tt.want.OpenCount || got.CurrentStreak != tt.want.CurrentStreak ||
+				got.LongestStreak != tt.want.LongestStreak {
+				t.Fatalf("totals mismatch: want %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTaskMoveToPB(t *testing.T) {
+	got := taskMoveToPB(models.TaskMove{Id: 5, AfterId: 8})
+
+	if got.GetId() != 5 || got.GetBeforeId() != 0 || got.GetAfterId() != 8 {
+		t.Fatalf("unexpected move request %+v", got)
+	}
+}
+
+func TestTaskFilterToPB(t *testing.T) {
+	tests := []struct {
+		name string
+		in   models.TaskFilter
+		want *pb.TaskFilter
+	}{
+		{
+			name: "regular filter",
+			in:   models.TaskFilter{Due: models.DueFilterToday, TimeZone: "Europe/Moscow", Sort: models.TaskSortPriority},
+			want: &pb.TaskFilter{Due: pb.DueFilter_DUE_FILTER_TODAY, TimeZone: "Europe/Moscow", Sort: pb.TaskSort_TASK_SORT_PRIORITY},
+		},
+		{
+			name: "tag filter",
+			in:   models.TaskFilter{Tags: []string{"home", "work"}, TagMatch: models.TagMatchAll, ProjectId: 2},
+			want: &pb.TaskFilter{Tags: []string{"home", "work"}, TagMatch: pb.TagMatch_TAG_MATCH_ALL, ProjectId: 2},
+		},
+		{
+			name: "empty filter",
+			in:   models.TaskFilter{},
+			want: &pb.TaskFilter{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := taskFilterToPB(tt.in)
+			if got.GetDue() != tt.want.GetDue() {
+				t.Fatalf("expected due %v, got %v", tt.want.GetDue(), got.GetDue())
+			}
+			if got.GetTimeZone() != tt.want.GetTimeZone() {
+				t.Fatalf("expected time zone %q, got %q", tt.want.GetTimeZone(), got.GetTimeZone())
+			}
+			if got.GetSort() != tt.want.GetSort() {
+				t.Fatalf("expected sort %v, got %v", tt.want.GetSort(), got.GetSort())
+			}
+			if !reflect.DeepEqual(got.GetTags(), tt.want.GetTags()) || got.GetTagMatch() != tt.want.GetTagMatch() {
+				t.Fatalf("expected tags %v (%v), got %v (%v)",
+					tt.want.GetTags(), tt.want.GetTagMatch(), got.GetTags(), got.GetTagMatch())
+			}
+			if got.GetProjectId() != tt.want.GetProjectId() {
+				t.Fatalf("expected project %d, got %d", tt.want.GetProjectId(), got.GetProjectId())
+			}
+		})
+	}
+}
+
+func TestTagSliceFromPB(t *testing.T) {
+	got := tagSliceFromPB(&pb.TagList{Tags: []*pb.TagUsage{{Name: "home", Count: 3}, {Name: "work"}}})
+
+	want := []models.TagUsage{{Name: "home", Count: 3}, {Name: "work", Count: 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if tagSliceFromPB(nil) != nil {
+		t.Fatalf("expected nil for nil list")
+	}
+}
+
+func TestTagChangeFromPB(t *testing.T) {
+	got := tagChangeFromPB(&pb.TagChange{Name: "work", TaskIds: []int64{2, 5}})
+
+	want := models.TagChange{Name: "work", TaskIds: []int{2, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestProjectFromPB(t *testing.T) {
+	createdAtTS := time.Date(2025, 12, 11, 1, 2, 3, 4, time.UTC)
+
+	got := projectFromPB(&pb.Project{Id: 2, Name: "Work", Archived: true, CreatedAt: timestamppb.New(createdAtTS)})
+
+	want := models.Project{Id: 2, Name: "Work", Archived: true, CreatedAt: createdAtTS}
+	if got.Id != want.Id || got.Name != want.Name || got.Inbox != want.Inbox || got.Archived != want.Archived ||
+		!got.CreatedAt.Equal(want.CreatedAt) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if projectFromPB(nil) != (models.Project{}) {
+		t.Fatalf("expected zero project for nil")
+	}
+}
+
+func TestTaskHistoryPageFromPB(t *testing.T) {
+	changedAtTS := time.Date(2025, 12, 2, 10, 0, 0, 0, time.UTC)
+
+	got := taskHistoryPageFromPB(&pb.TaskHistoryPage{
+		Changes: []*pb.TaskChange{{
+			Id:        7,
+			TaskId:    2,
+			ChangedAt: timestamppb.New(changedAtTS),
+			ChangedBy: "alice",
+			Field:     "priority",
+			OldValue:  "0",
+			NewValue:  "3",
+		}},
+		NextCursor: 7,
+	})
+
+	want := models.TaskHistoryPage{
+		Changes: []models.TaskChange{{
+			Id:        7,
+			TaskId:    2,
+			ChangedAt: changedAtTS,
+			ChangedBy: "alice",
+			Field:     "priority",
+			OldValue:  "0",
+			NewValue:  "3",
+		}},
+		NextCursor: 7,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if !reflect.DeepEqual(taskHistoryPageFromPB(nil), models.TaskHistoryPage{}) {
+		t.Fatalf("expected zero page for nil")
+	}
+}
@@ -0,0 +1,37 @@
+package dbgrpc
+
+import (
+	"fmt"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorFromStatus translates db-service status codes into app errors, so that
+// transports can react to them without knowing about gRPC.
+func errorFromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return fmt.Errorf("%w: %s", app.ErrInvalidArgument, st.Message())
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", app.ErrNotFound, st.Message())
+	case codes.AlreadyExists:
+		return fmt.Errorf("%w: %s", app.ErrAlreadyExists, st.Message())
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: %s", app.ErrConflict, st.Message())
+	case codes.Aborted:
+		return fmt.Errorf("%w: %s", app.ErrPreconditionFailed, st.Message())
+	case codes.Unavailable:
+		return fmt.Errorf("%w: %s", app.ErrUnavailable, st.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", app.ErrTimeout, st.Message())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,81 @@
+package dbgrpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+
+	"github.com/dodocheck/go-pet-project-1/pkg/tlsreload"
+)
+
+// defaultServerName is the name the db-service certificate is issued for.
+const defaultServerName = "db-service"
+
+// TLSOptions configure TLS to db-service; without Enabled calls go in
+// plaintext.
+type TLSOptions struct {
+	Enabled bool
+	// CAFile is the CA the db-service certificate is checked with, the
+	// system CAs when empty.
+	CAFile string
+	// CertFile and KeyFile, when set, are presented to db-service for mutual
+	// TLS.
+	CertFile string
+	KeyFile  string
+	// ServerName the db-service certificate must be issued for,
+	// "db-service" when empty.
+	ServerName string
+}
+
+// clientTLSConfig builds a config that reads the certificate files again
+// when they change, so renewed certificates are used without a restart.
+func clientTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	serverName := opts.ServerName
+	if serverName == "" {
+		serverName = defaultServerName
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+	if opts.CertFile == "" && opts.CAFile == "" {
+		return config, nil
+	}
+
+	files, err := tlsreload.Load(opts.CertFile, opts.KeyFile, opts.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	if opts.CertFile != "" {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := files.Current()
+			return cert, nil
+		}
+	}
+	if opts.CAFile != "" {
+		// RootCAs can't change once the config is in use, so the server
+		// certificate is checked here against the current CA instead.
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			_, roots := files.Current()
+			return verifyServer(state, roots, serverName)
+		}
+	}
+	return config, nil
+}
+
+func verifyServer(state tls.ConnectionState, roots *x509.CertPool, serverName string) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("db-service sent no certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       serverName,
+	})
+	return err
+}
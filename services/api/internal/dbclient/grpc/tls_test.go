@@ -0,0 +1,218 @@
+package dbgrpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testCA{cert: cert, key: key}
+}
+
+func (ca testCA) writeCert(t *testing.T, path string) {
+	t.Helper()
+	writeTestFile(t, path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+}
+
+// issue writes a certificate for name signed by the CA and its key.
+func (ca testCA) issue(t *testing.T, certPath, keyPath, name string, serial int64, usage x509.ExtKeyUsage) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeTestFile(t, keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// touch moves the modification time of paths forward, so a rewrite within
+// the same clock tick still looks changed.
+func touch(t *testing.T, paths ...string) {
+	t.Helper()
+	modTime := time.Now().Add(time.Minute)
+	for _, path := range paths {
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// serveTLS accepts connections with config until the test ends, completing
+// the handshake on each.
+func serveTLS(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func dialTLS(address string, config *tls.Config) (*tls.ConnectionState, error) {
+	conn, err := tls.Dial("tcp", address, config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	// A rejected client certificate only shows on the first read in TLS 1.3.
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	state := conn.ConnectionState()
+	return &state, nil
+}
+
+func TestClientTLSConfig_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	ca.writeCert(t, filepath.Join(dir, "ca.pem"))
+	ca.issue(t, filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), "db-service", 2, x509.ExtKeyUsageServerAuth)
+	ca.issue(t, filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem"), "api-service", 3, x509.ExtKeyUsageClientAuth)
+	serverCert, err := tls.LoadX509KeyPair(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	address := serveTLS(t, &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	opts := TLSOptions{
+		Enabled:  true,
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client-key.pem"),
+	}
+
+	config, err := clientTLSConfig(opts)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if _, err := dialTLS(address, config); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	opts.ServerName = "other-service"
+	config, err = clientTLSConfig(opts)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if _, err := dialTLS(address, config); err == nil {
+		t.Fatalf("expected a certificate for another name to be rejected")
+	}
+}
+
+func TestClientTLSConfig_ReloadsRenewedCA(t *testing.T) {
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	oldCA, newCA := newTestCA(t), newTestCA(t)
+	oldCA.writeCert(t, caPath)
+	newCA.issue(t, filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), "db-service", 2, x509.ExtKeyUsageServerAuth)
+	serverCert, err := tls.LoadX509KeyPair(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := serveTLS(t, &tls.Config{Certificates: []tls.Certificate{serverCert}})
+
+	config, err := clientTLSConfig(TLSOptions{Enabled: true, CAFile: caPath})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if _, err := dialTLS(address, config); err == nil {
+		t.Fatalf("expected a server of an unknown CA to be rejected")
+	}
+	newCA.writeCert(t, caPath)
+	touch(t, caPath)
+
+	if _, err := dialTLS(address, config); err != nil {
+		t.Fatalf("expected the renewed CA to be trusted, got %v", err)
+	}
+}
+
+func TestClientTLSConfig_NoFiles_UsesSystemCAs(t *testing.T) {
+	config, err := clientTLSConfig(TLSOptions{Enabled: true})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if config.InsecureSkipVerify || config.ServerName != defaultServerName {
+		t.Fatalf("expected verification against system CAs for %q, got %+v", defaultServerName, config)
+	}
+}
@@ -0,0 +1,17 @@
+package dbgrpc
+
+import "context"
+
+// callerToken sends the token db-service authenticates api-service by with
+// every call, streams included.
+type callerToken string
+
+func (t callerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false as db-service may be reached over the
+// internal network without TLS.
+func (callerToken) RequireTransportSecurity() bool {
+	return false
+}
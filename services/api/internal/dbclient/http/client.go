@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
@@ -87,28 +88,30 @@ func (c *DBClient) RemoveTask(ctx context.Context, id int) error {
 	return nil
 }
 
-func (c *DBClient) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
+// ListAllTasks reports the list as stale when db-service marked it with a
+// Warning header.
+func (c *DBClient) ListAllTasks(ctx context.Context) ([]models.TaskExportData, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.dbUrl+"/tasks", nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("db-service returned unexpected status " + resp.Status)
+		return nil, false, errors.New("db-service returned unexpected status " + resp.Status)
 	}
 
 	var tasks []models.TaskExportData
 	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return tasks, nil
+	return tasks, strings.HasPrefix(resp.Header.Get("Warning"), "110 "), nil
 }
 
 func (c *DBClient) MarkTaskFinished(ctx context.Context, id int) (models.TaskExportData, error) {
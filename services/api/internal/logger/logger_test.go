@@ -81,3 +81,14 @@ func TestRun_RetriesToWriteMessage(t *testing.T) {
 	}
 
 }
+
+func TestWithTask_AttachesTaskIdAndTags(t *testing.T) {
+	actionLog := WithTask(CreateTaskDoneLog(), models.TaskExportData{Id: 7, Tags: []string{"home"}})
+
+	if actionLog.Action != "task done" || actionLog.TaskId != 7 {
+		t.Fatalf("unexpected log %+v", actionLog)
+	}
+	if len(actionLog.Tags) != 1 || actionLog.Tags[0] != "home" {
+		t.Fatalf("expected tags [home], got %v", actionLog.Tags)
+	}
+}
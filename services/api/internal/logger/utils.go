@@ -20,6 +20,48 @@ func CreateTaskDeletedLog() models.ActionLog {
 	}
 }
 
+func CreateListTrashLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "list trash",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTaskRestoredLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task restored",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTaskPurgedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task purged",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTasksBatchAddedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "tasks created in batch",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTasksBatchDoneLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "tasks done in batch",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTasksBatchDeletedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "tasks deleted in batch",
+		Time:   time.Now(),
+	}
+}
+
 func CreateTaskDoneLog() models.ActionLog {
 	return models.ActionLog{
 		Action: "task done",
@@ -27,9 +69,191 @@ func CreateTaskDoneLog() models.ActionLog {
 	}
 }
 
+func CreateTaskReopenedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task reopened",
+		Time:   time.Now(),
+	}
+}
+
+func CreateGetTaskTreeLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "get task tree",
+		Time:   time.Now(),
+	}
+}
+
 func CreateListTasksLog() models.ActionLog {
 	return models.ActionLog{
 		Action: "list tasks",
 		Time:   time.Now(),
 	}
 }
+
+func CreateGetStatsLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "get stats",
+		Time:   time.Now(),
+	}
+}
+
+func CreateSearchTasksLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "search tasks",
+		Time:   time.Now(),
+	}
+}
+
+func CreateWatchTasksLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "watch tasks",
+		Time:   time.Now(),
+	}
+}
+
+func CreateGetChangesLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "get changes",
+		Time:   time.Now(),
+	}
+}
+
+func CreateGetTaskHistoryLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "get task history",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTaskPriorityLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task priority changed",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTaskMovedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task moved",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTaskRecurrenceLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task recurrence changed",
+		Time:   time.Now(),
+	}
+}
+
+func CreateOccurrenceSkippedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task occurrence skipped",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTaskTagsAddedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task tags added",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTaskTagsRemovedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task tags removed",
+		Time:   time.Now(),
+	}
+}
+
+func CreateListTagsLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "list tags",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTagRenamedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "tag renamed",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTagsMergedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "tags merged",
+		Time:   time.Now(),
+	}
+}
+
+func CreateProjectCreatedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "project created",
+		Time:   time.Now(),
+	}
+}
+
+func CreateListProjectsLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "list projects",
+		Time:   time.Now(),
+	}
+}
+
+func CreateProjectRenamedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "project renamed",
+		Time:   time.Now(),
+	}
+}
+
+func CreateProjectArchivedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "project archived",
+		Time:   time.Now(),
+	}
+}
+
+func CreateProjectRestoredLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "project restored",
+		Time:   time.Now(),
+	}
+}
+
+func CreateProjectDeletedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "project deleted",
+		Time:   time.Now(),
+	}
+}
+
+func CreateTaskProjectChangedLog() models.ActionLog {
+	return models.ActionLog{
+		Action: "task moved to project",
+		Time:   time.Now(),
+	}
+}
+
+// WithBatch records the tasks changed by a batch action and the number of
+// failed items.
+func WithBatch(actionLog models.ActionLog, items []models.BatchItem) models.ActionLog {
+	for _, item := range items {
+		if item.Err != nil {
+			actionLog.Failed++
+		} else {
+			actionLog.TaskIds = append(actionLog.TaskIds, item.Id)
+		}
+	}
+	return actionLog
+}
+
+// WithTask attaches the task a logged action was applied to.
+func WithTask(actionLog models.ActionLog, task models.TaskExportData) models.ActionLog {
+	actionLog.TaskId = task.Id
+	actionLog.ProjectId = task.ProjectId
+	actionLog.Tags = task.Tags
+	return actionLog
+}
@@ -0,0 +1,9 @@
+package models
+
+// BatchItem is the outcome of one item of a batch operation: the created or
+// changed task, or Err when the item failed. Removed tasks only have Id.
+type BatchItem struct {
+	Id   int
+	Task TaskExportData
+	Err  error
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type TaskEventType int
+
+const (
+	TaskEventNone TaskEventType = iota
+	TaskEventCreated
+	TaskEventUpdated
+	TaskEventFinished
+	TaskEventDeleted
+	// TaskEventReset means events were missed and the tasks must be reloaded.
+	TaskEventReset
+)
+
+var taskEventTypeNames = []string{"none", "created", "updated", "finished", "deleted", "reset"}
+
+func (t TaskEventType) String() string {
+	if t < TaskEventNone || t > TaskEventReset {
+		return "unknown"
+	}
+	return taskEventTypeNames[t]
+}
+
+// TaskEvent is a committed change of a task. Moving a task to the trash is
+// reported as deleted and restoring it as created.
+type TaskEvent struct {
+	Id         int
+	Type       TaskEventType
+	TaskId     int
+	ProjectId  int
+	Version    int
+	Actor      string
+	OccurredAt time.Time
+}
+
+// WatchRequest subscribes to the changes of the tasks in project ProjectId,
+// or of all tasks when it is 0, after the event LastEventId if it is set.
+type WatchRequest struct {
+	ProjectId   int
+	LastEventId int
+}
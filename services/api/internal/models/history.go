@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// TaskChange is one entry of the task history. OldValue and NewValue hold
+// the field values as JSON; an empty string means there was no value.
+type TaskChange struct {
+	Id        int
+	TaskId    int
+	ChangedAt time.Time
+	ChangedBy string
+	Field     string
+	OldValue  string
+	NewValue  string
+}
+
+type TaskHistoryRequest struct {
+	TaskId int
+	Limit  int
+	Cursor int
+}
+
+// TaskHistoryPage lists changes from newest to oldest; a non-zero NextCursor
+// requests the following page.
+type TaskHistoryPage struct {
+	Changes    []TaskChange
+	NextCursor int
+}
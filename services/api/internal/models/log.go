@@ -3,6 +3,13 @@ package models
 import "time"
 
 type ActionLog struct {
-	Action string
-	Time   time.Time
+	Action    string
+	Time      time.Time
+	TaskId    int      `json:",omitempty"`
+	ProjectId int      `json:",omitempty"`
+	Tags      []string `json:",omitempty"`
+	// TaskIds and Failed describe a batch action: the tasks it changed and
+	// how many items failed.
+	TaskIds []int `json:",omitempty"`
+	Failed  int   `json:",omitempty"`
 }
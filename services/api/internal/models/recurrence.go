@@ -0,0 +1,53 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type RecurrenceFrequency int
+
+const (
+	RecurrenceNone RecurrenceFrequency = iota
+	RecurrenceDaily
+	RecurrenceWeekly
+	RecurrenceMonthly
+	// RecurrenceAfterCompletion repeats Interval days after the task is done.
+	RecurrenceAfterCompletion
+)
+
+var recurrenceFrequencyNames = []string{"none", "daily", "weekly", "monthly", "after_completion"}
+
+func (f RecurrenceFrequency) String() string {
+	if f < RecurrenceNone || f > RecurrenceAfterCompletion {
+		return "unknown"
+	}
+	return recurrenceFrequencyNames[f]
+}
+
+func (f RecurrenceFrequency) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+func (f *RecurrenceFrequency) UnmarshalText(text []byte) error {
+	for i, name := range recurrenceFrequencyNames {
+		if string(text) == name {
+			*f = RecurrenceFrequency(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown recurrence frequency %q, expected one of none, daily, weekly, monthly, after_completion", text)
+}
+
+// Recurrence is the schedule of a recurring task. Interval counts days, weeks
+// or months depending on Frequency. Empty Weekdays and MonthDay fall back to
+// the due date.
+type Recurrence struct {
+	Frequency RecurrenceFrequency
+	Interval  int
+	Weekdays  []time.Weekday
+	MonthDay  int
+	// Until ends the series: no occurrence is due after it.
+	Until    *time.Time
+	TimeZone string
+}
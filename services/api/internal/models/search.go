@@ -0,0 +1,17 @@
+package models
+
+// SearchRequest asks for up to Limit tasks with words starting with every
+// word of Query; Limit 0 uses the db-service default.
+type SearchRequest struct {
+	Query string
+	Limit int
+}
+
+// SearchHit is a found task with its relevance and the title and text
+// fragments around the matched words, marked with <b> and </b>.
+type SearchHit struct {
+	Task         TaskExportData
+	Rank         float64
+	TitleSnippet string
+	TextSnippet  string
+}
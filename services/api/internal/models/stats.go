@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+type StatsBucket int
+
+const (
+	StatsBucketDay StatsBucket = iota
+	StatsBucketWeek
+)
+
+func (b StatsBucket) String() string {
+	switch b {
+	case StatsBucketDay:
+		return "day"
+	case StatsBucketWeek:
+		return "week"
+	default:
+		return "unknown"
+	}
+}
+
+type StatsRequest struct {
+	From     time.Time
+	To       time.Time
+	Bucket   StatsBucket
+	TimeZone string
+}
+
+type StatsPoint struct {
+	Start     time.Time
+	Created   int
+	Completed int
+}
+
+type Stats struct {
+	Points            []StatsPoint
+	CreatedTotal      int
+	CompletedTotal    int
+	AvgTimeToComplete time.Duration
+	OpenCount         int
+	CurrentStreak     int
+	LongestStreak     int
+}
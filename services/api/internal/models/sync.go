@@ -0,0 +1,47 @@
+package models
+
+// Changes lists the tasks changed since the sync token and the ids of the
+// tasks deleted or moved to the trash since then. Token starts the next
+// sync; HasMore asks to sync again right away. Full means the old token
+// expired and the client has to replace its tasks with the ones synced from
+// this answer on.
+type Changes struct {
+	Tasks      []TaskExportData
+	DeletedIds []int
+	Token      string
+	HasMore    bool
+	Full       bool
+}
+
+// Mutation is a change made by a client, possibly while it was offline:
+// "create" takes Task, "done", "reopen" and "delete" take TaskId, "priority"
+// takes TaskId and Priority. A non-zero Version makes the change fail when
+// the task has another version.
+type Mutation struct {
+	Id       string
+	Type     string
+	TaskId   int
+	Task     *TaskImportData
+	Priority Priority
+	Version  int
+}
+
+type ConflictPolicy int
+
+const (
+	// ConflictLastWriterWins applies queued mutations over whatever changed
+	// on the server meanwhile.
+	ConflictLastWriterWins ConflictPolicy = iota
+	// ConflictVersion rejects mutations of tasks that changed after the
+	// version the client saw and reports the current task.
+	ConflictVersion
+)
+
+// MutationResult is the outcome of a mutation with the same Id: the created
+// or changed task, or Err. A version conflict carries the current task.
+type MutationResult struct {
+	Id      string
+	Task    *TaskExportData
+	Current *TaskExportData
+	Err     error
+}
@@ -0,0 +1,20 @@
+package models
+
+type TagMatch int
+
+const (
+	TagMatchAny TagMatch = iota
+	TagMatchAll
+)
+
+type TagUsage struct {
+	Name  string
+	Count int
+}
+
+// TagChange is the result of a tag rename or merge: the resulting tag and the
+// tasks whose tag set has changed.
+type TagChange struct {
+	Name    string
+	TaskIds []int
+}
@@ -1,10 +1,59 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityUrgent {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+// MarshalText makes priorities readable in JSON: "high" instead of 3.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Priority) UnmarshalText(text []byte) error {
+	for i, name := range priorityNames {
+		if string(text) == name {
+			*p = Priority(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown priority %q, expected one of none, low, medium, high, urgent", text)
+}
 
 type TaskImportData struct {
-	Title string
-	Text  string
+	Title    string
+	Text     string
+	DueAt    *time.Time
+	Priority Priority
+	Tags     []string
+	// ProjectId 0 puts the task into the inbox. Subtasks always live in
+	// their parent's project.
+	ProjectId int
+	// ParentId 0 creates a top-level task.
+	ParentId   int
+	Recurrence *Recurrence
+	// IdempotencyKey lets the client retry the request without creating
+	// the task twice. Empty disables it.
+	IdempotencyKey string
 }
 
 type TaskExportData struct {
@@ -14,4 +63,62 @@ type TaskExportData struct {
 	Finished   bool
 	CreatedAt  time.Time
 	FinishedAt *time.Time
+	DueAt      *time.Time
+	Overdue    bool
+	Priority   Priority
+	Position   string
+	Tags       []string
+	ProjectId  int
+	ParentId   int
+	// SubtasksTotal and SubtasksDone count direct subtasks only.
+	SubtasksTotal int
+	SubtasksDone  int
+	Recurrence    *Recurrence
+	// NextOccurrenceId is set once a recurring task is done.
+	NextOccurrenceId int
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt *time.Time
+	// Version grows with every change of the task or its tags; the HTTP API
+	// returns it as the ETag.
+	Version int
+}
+
+// TaskTree is a task with its subtasks, nested down to the deepest level.
+type TaskTree struct {
+	Task     TaskExportData
+	Subtasks []TaskTree
+}
+
+// TaskMove places task Id right before BeforeId or right after AfterId.
+type TaskMove struct {
+	Id       int
+	BeforeId int
+	AfterId  int
+}
+
+type DueFilter int
+
+const (
+	DueFilterAny DueFilter = iota
+	DueFilterOverdue
+	DueFilterToday
+	DueFilterThisWeek
+)
+
+type TaskSort int
+
+const (
+	TaskSortId TaskSort = iota
+	TaskSortPriority
+	TaskSortPosition
+)
+
+type TaskFilter struct {
+	Due      DueFilter
+	TimeZone string
+	Sort     TaskSort
+	Tags     []string
+	TagMatch TagMatch
+	// ProjectId 0 means tasks of all projects.
+	ProjectId int
 }
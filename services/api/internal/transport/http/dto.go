@@ -2,7 +2,10 @@ package http
 
 import (
 	"encoding/json"
+	"net/http"
 	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
 )
 
 type ErrorDTO struct {
@@ -25,6 +28,389 @@ func (e *ErrorDTO) ToString() string {
 }
 
 type TaskDTO struct {
-	Title string `json:"title"`
-	Text  string `json:"text"`
+	Title     string          `json:"title"`
+	Text      string          `json:"text"`
+	DueAt     *time.Time      `json:"due_at"`
+	Priority  models.Priority `json:"priority"`
+	Tags      []string        `json:"tags"`
+	ProjectId int             `json:"project_id"`
+	ParentId  int             `json:"parent_id"`
+	// Recurrence is optional; null means the task doesn't repeat.
+	Recurrence *RecurrenceDTO `json:"recurrence"`
+}
+
+type RecurrenceDTO struct {
+	Frequency models.RecurrenceFrequency `json:"frequency"`
+	Interval  int                        `json:"interval"`
+	Weekdays  []time.Weekday             `json:"weekdays"`
+	MonthDay  int                        `json:"month_day"`
+	Until     *time.Time                 `json:"until"`
+	TimeZone  string                     `json:"tz"`
+}
+
+func taskImportDataFromDTO(dto TaskDTO) models.TaskImportData {
+	return models.TaskImportData{
+		Title:      dto.Title,
+		Text:       dto.Text,
+		DueAt:      dto.DueAt,
+		Priority:   dto.Priority,
+		Tags:       dto.Tags,
+		ProjectId:  dto.ProjectId,
+		ParentId:   dto.ParentId,
+		Recurrence: recurrenceFromDTO(dto.Recurrence)}
+}
+
+func recurrenceFromDTO(dto *RecurrenceDTO) *models.Recurrence {
+	if dto == nil {
+		return nil
+	}
+	return &models.Recurrence{
+		Frequency: dto.Frequency,
+		Interval:  dto.Interval,
+		Weekdays:  dto.Weekdays,
+		MonthDay:  dto.MonthDay,
+		Until:     dto.Until,
+		TimeZone:  dto.TimeZone,
+	}
+}
+
+type TaskRecurrenceDTO struct {
+	Id         int
+	Recurrence *RecurrenceDTO `json:"recurrence"`
+}
+
+type TaskPriorityDTO struct {
+	Id       int
+	Priority models.Priority `json:"priority"`
+}
+
+type TaskMoveDTO struct {
+	Id       int
+	BeforeId int `json:"before_id"`
+	AfterId  int `json:"after_id"`
+}
+
+type TaskTagsDTO struct {
+	Id   int
+	Tags []string `json:"tags"`
+}
+
+type RenameTagDTO struct {
+	Name    string `json:"name"`
+	NewName string `json:"new_name"`
+}
+
+type MergeTagsDTO struct {
+	Sources []string `json:"sources"`
+	Target  string   `json:"target"`
+}
+
+type ProjectDTO struct {
+	Id       int
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+}
+
+type TaskProjectDTO struct {
+	Id        int
+	ProjectId int `json:"project_id"`
+}
+
+type StatsPointDTO struct {
+	Start     time.Time `json:"start"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+type StatsDTO struct {
+	Bucket                   string          `json:"bucket"`
+	TimeZone                 string          `json:"timeZone"`
+	Points                   []StatsPointDTO `json:"points"`
+	CreatedTotal             int             `json:"createdTotal"`
+	CompletedTotal           int             `json:"completedTotal"`
+	AvgTimeToCompleteSeconds float64         `json:"avgTimeToCompleteSeconds"`
+	OpenCount                int             `json:"openCount"`
+	CurrentStreak            int             `json:"currentStreak"`
+	LongestStreak            int             `json:"longestStreak"`
+}
+
+// NewStatsDTO renders bucket starts in the requested time zone so that
+// charts can use them as axis labels as is.
+func NewStatsDTO(req models.StatsRequest, stats models.Stats) StatsDTO {
+	loc, err := time.LoadLocation(req.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	out := StatsDTO{
+		Bucket:                   req.Bucket.String(),
+		TimeZone:                 req.TimeZone,
+		Points:                   make([]StatsPointDTO, 0, len(stats.Points)),
+		CreatedTotal:             stats.CreatedTotal,
+		CompletedTotal:           stats.CompletedTotal,
+		AvgTimeToCompleteSeconds: stats.AvgTimeToComplete.Seconds(),
+		OpenCount:                stats.OpenCount,
+		CurrentStreak:            stats.CurrentStreak,
+		LongestStreak:            stats.LongestStreak,
+	}
+
+	for _, v := range stats.Points {
+		out.Points = append(out.Points, StatsPointDTO{
+			Start:     v.Start.In(loc),
+			Created:   v.Created,
+			Completed: v.Completed,
+		})
+	}
+
+	return out
+}
+
+type TaskChangeDTO struct {
+	Id        int             `json:"id"`
+	ChangedAt time.Time       `json:"changed_at"`
+	ChangedBy string          `json:"changed_by"`
+	Field     string          `json:"field"`
+	OldValue  json.RawMessage `json:"old_value,omitempty"`
+	NewValue  json.RawMessage `json:"new_value,omitempty"`
+}
+
+type TaskHistoryDTO struct {
+	TaskId     int             `json:"task_id"`
+	Changes    []TaskChangeDTO `json:"changes"`
+	NextCursor int             `json:"next_cursor,omitempty"`
+}
+
+// NewTaskHistoryDTO embeds the stored JSON values as is, so that a changed
+// title stays a string and a changed due date stays a timestamp.
+func NewTaskHistoryDTO(taskId int, page models.TaskHistoryPage) TaskHistoryDTO {
+	out := TaskHistoryDTO{
+		TaskId:     taskId,
+		Changes:    make([]TaskChangeDTO, 0, len(page.Changes)),
+		NextCursor: page.NextCursor,
+	}
+
+	for _, change := range page.Changes {
+		dto := TaskChangeDTO{
+			Id:        change.Id,
+			ChangedAt: change.ChangedAt,
+			ChangedBy: change.ChangedBy,
+			Field:     change.Field,
+		}
+		if change.OldValue != "" {
+			dto.OldValue = json.RawMessage(change.OldValue)
+		}
+		if change.NewValue != "" {
+			dto.NewValue = json.RawMessage(change.NewValue)
+		}
+		out.Changes = append(out.Changes, dto)
+	}
+
+	return out
+}
+
+// BatchRequestDTO is one batch action: "create" takes Tasks, "done" and
+// "delete" take Ids.
+type BatchRequestDTO struct {
+	Action string    `json:"action"`
+	Tasks  []TaskDTO `json:"tasks"`
+	Ids    []int     `json:"ids"`
+}
+
+// BatchItemDTO carries the status code the item would have got as a single
+// request.
+type BatchItemDTO struct {
+	Id     int                    `json:"id,omitempty"`
+	Status int                    `json:"status"`
+	Task   *models.TaskExportData `json:"task,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+type BatchResultDTO struct {
+	Action  string         `json:"action"`
+	Results []BatchItemDTO `json:"results"`
+}
+
+// NewBatchResultDTO reports successful items with successStatus and keeps
+// the request order.
+func NewBatchResultDTO(action string, items []models.BatchItem, successStatus int) BatchResultDTO {
+	out := BatchResultDTO{
+		Action:  action,
+		Results: make([]BatchItemDTO, 0, len(items)),
+	}
+
+	for _, item := range items {
+		dto := BatchItemDTO{Id: item.Id, Status: successStatus}
+		switch {
+		case item.Err != nil:
+			dto.Status = statusCodeFor(item.Err)
+			dto.Error = item.Err.Error()
+		case item.Task.Id != 0:
+			task := item.Task
+			dto.Task = &task
+		}
+		out.Results = append(out.Results, dto)
+	}
+
+	return out
+}
+
+type SearchHitDTO struct {
+	Task         models.TaskExportData `json:"task"`
+	Rank         float64               `json:"rank"`
+	TitleSnippet string                `json:"title_snippet"`
+	TextSnippet  string                `json:"text_snippet,omitempty"`
+}
+
+type SearchResultDTO struct {
+	Query string         `json:"query"`
+	Hits  []SearchHitDTO `json:"hits"`
+}
+
+func NewSearchResultDTO(query string, hits []models.SearchHit) SearchResultDTO {
+	out := SearchResultDTO{
+		Query: query,
+		Hits:  make([]SearchHitDTO, 0, len(hits)),
+	}
+
+	for _, hit := range hits {
+		out.Hits = append(out.Hits, SearchHitDTO{
+			Task:         hit.Task,
+			Rank:         hit.Rank,
+			TitleSnippet: hit.TitleSnippet,
+			TextSnippet:  hit.TextSnippet,
+		})
+	}
+
+	return out
+}
+
+type TaskEventDTO struct {
+	Id         int       `json:"id,omitempty"`
+	Type       string    `json:"type"`
+	TaskId     int       `json:"task_id,omitempty"`
+	ProjectId  int       `json:"project_id,omitempty"`
+	Version    int       `json:"version,omitempty"`
+	Actor      string    `json:"actor,omitempty"`
+	OccurredAt time.Time `json:"occurred_at,omitzero"`
+}
+
+func NewTaskEventDTO(event models.TaskEvent) TaskEventDTO {
+	return TaskEventDTO{
+		Id:         event.Id,
+		Type:       event.Type.String(),
+		TaskId:     event.TaskId,
+		ProjectId:  event.ProjectId,
+		Version:    event.Version,
+		Actor:      event.Actor,
+		OccurredAt: event.OccurredAt,
+	}
+}
+
+// MutationDTO is a change sent over the WebSocket or queued by an offline
+// client. Id is chosen by the client and comes back in the answer; Version
+// works like If-Match.
+type MutationDTO struct {
+	Id       string          `json:"id"`
+	Type     string          `json:"type"`
+	Task     *TaskDTO        `json:"task,omitempty"`
+	TaskId   int             `json:"task_id,omitempty"`
+	Priority models.Priority `json:"priority,omitempty"`
+	Version  int             `json:"version,omitempty"`
+}
+
+func mutationFromDTO(dto MutationDTO) models.Mutation {
+	m := models.Mutation{
+		Id:       dto.Id,
+		Type:     dto.Type,
+		TaskId:   dto.TaskId,
+		Priority: dto.Priority,
+		Version:  dto.Version,
+	}
+	if dto.Task != nil {
+		task := taskImportDataFromDTO(*dto.Task)
+		m.Task = &task
+	}
+	return m
+}
+
+// mutationStatus is the status code the mutation gets as a single request.
+func mutationStatus(m models.Mutation, err error) int {
+	switch {
+	case err != nil:
+		return statusCodeFor(err)
+	case m.Type == "create":
+		return http.StatusCreated
+	default:
+		return http.StatusOK
+	}
+}
+
+// WSMessageDTO is sent to the WebSocket client: an "ack" or "error" for the
+// request with the same Id, or an "event" about a task change.
+type WSMessageDTO struct {
+	Type   string                 `json:"type"`
+	Id     string                 `json:"id,omitempty"`
+	Status int                    `json:"status,omitempty"`
+	Task   *models.TaskExportData `json:"task,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+	Event  *TaskEventDTO          `json:"event,omitempty"`
+}
+
+func NewWSAckDTO(id string, status int, task *models.TaskExportData) WSMessageDTO {
+	return WSMessageDTO{Type: "ack", Id: id, Status: status, Task: task}
+}
+
+func NewWSErrorDTO(id string, status int, err error) WSMessageDTO {
+	return WSMessageDTO{Type: "error", Id: id, Status: status, Error: err.Error()}
+}
+
+func NewWSEventDTO(event models.TaskEvent) WSMessageDTO {
+	eventDTO := NewTaskEventDTO(event)
+	return WSMessageDTO{Type: "event", Event: &eventDTO}
+}
+
+type SyncChangesDTO struct {
+	Tasks      []models.TaskExportData `json:"tasks"`
+	DeletedIds []int                   `json:"deleted_ids"`
+	Token      string                  `json:"token"`
+	HasMore    bool                    `json:"has_more"`
+	Full       bool                    `json:"full"`
+}
+
+func NewSyncChangesDTO(changes models.Changes) SyncChangesDTO {
+	out := SyncChangesDTO{
+		Tasks:      changes.Tasks,
+		DeletedIds: changes.DeletedIds,
+		Token:      changes.Token,
+		HasMore:    changes.HasMore,
+		Full:       changes.Full,
+	}
+	if out.Tasks == nil {
+		out.Tasks = []models.TaskExportData{}
+	}
+	if out.DeletedIds == nil {
+		out.DeletedIds = []int{}
+	}
+	return out
+}
+
+// SyncPushDTO carries the mutations queued by an offline client. Conflict
+// is "last_writer_wins" (default) or "version".
+type SyncPushDTO struct {
+	Conflict  string        `json:"conflict"`
+	Mutations []MutationDTO `json:"mutations"`
+}
+
+// MutationResultDTO carries the status code the mutation would have got as
+// a single request; a version conflict also has the current task.
+type MutationResultDTO struct {
+	Id      string                 `json:"id"`
+	Status  int                    `json:"status"`
+	Task    *models.TaskExportData `json:"task,omitempty"`
+	Current *models.TaskExportData `json:"current,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+type SyncPushResultDTO struct {
+	Results []MutationResultDTO `json:"results"`
 }
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
+)
+
+// statusCodeFor picks the HTTP status for an error returned by the service.
+func statusCodeFor(err error) int {
+	switch {
+	case errors.Is(err, app.ErrInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, app.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrAlreadyExists), errors.Is(err, app.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, app.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, app.ErrIdempotencyKeyReused):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, app.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, app.ErrTimeout):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorText is the message of err, or "" for no error.
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+)
+
+// taskETag is a strong ETag made of the task version. It is what If-Match
+// expects on the routes changing the task.
+func taskETag(task models.TaskExportData) string {
+	return `"` + strconv.Itoa(task.Version) + `"`
+}
+
+// treeETag is a weak ETag of the task and its subtasks, good for
+// If-None-Match only. Even a task without subtasks doesn't get its strong
+// taskETag here: Overdue turns on as time passes without a new version.
+func treeETag(tree models.TaskTree) string {
+	var tasks []models.TaskExportData
+	var walk func(models.TaskTree)
+	walk = func(node models.TaskTree) {
+		tasks = append(tasks, node.Task)
+		for _, subtask := range node.Subtasks {
+			walk(subtask)
+		}
+	}
+	walk(tree)
+
+	return listETag(tasks)
+}
+
+// listETag is a weak ETag that changes whenever a task is added to or leaves
+// the list, or any task in it changes.
+func listETag(tasks []models.TaskExportData) string {
+	h := fnv.New64a()
+	for _, task := range tasks {
+		// Overdue and subtask progress change without a new version.
+		_, _ = fmt.Fprintf(h, "%d:%d:%t:%d:%d;", task.Id, task.Version, task.Overdue, task.SubtasksTotal, task.SubtasksDone)
+	}
+	return fmt.Sprintf(`W/"%x"`, h.Sum64())
+}
+
+// parseIfMatch returns the task version from If-Match, or 0 when the header
+// is missing or "*".
+func parseIfMatch(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	unquoted, ok := strings.CutPrefix(value, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	version, err := strconv.Atoi(unquoted)
+	if !ok || err != nil || version <= 0 {
+		return 0, errors.New("If-Match must be a single task ETag like \"3\"")
+	}
+	return version, nil
+}
+
+// notModified sets the ETag of the response and reports whether the client
+// already has it according to If-None-Match, using the weak comparison.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// staleWarning marks a list db-service served from its cache while Postgres
+// is down; it may miss the latest changes.
+const staleWarning = `110 - "Response is Stale"`
+
+func markStale(w http.ResponseWriter, stale bool) {
+	if stale {
+		w.Header().Set("Warning", staleWarning)
+	}
+}
@@ -1,17 +1,24 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+	"github.com/gorilla/websocket"
 )
 
 type HttpHandlers struct {
 	service     *app.Service
 	closeServer func() error
+	// wsTokens maps the tokens of WebSocket clients to their actors.
+	wsTokens map[string]string
 }
 
 func NewHttpHandlers(service *app.Service) *HttpHandlers {
@@ -20,6 +27,10 @@ func NewHttpHandlers(service *app.Service) *HttpHandlers {
 		closeServer: nil}
 }
 
+func (h *HttpHandlers) SetWebSocketTokens(tokens map[string]string) {
+	h.wsTokens = tokens
+}
+
 func (h *HttpHandlers) SetCloseServerFunc(f func() error) {
 	h.closeServer = f
 }
@@ -27,14 +38,15 @@ func (h *HttpHandlers) SetCloseServerFunc(f func() error) {
 /*
 pattern: /tasks
 method: POST
-info: JSON in HTTP request body
+info: JSON in HTTP request body; optional Idempotency-Key header, a retry with
+the same key and body returns the task created by the first request
 
 success:
   - status code: 201 Created
   - response body: JSON represented created data
 
 failure:
-  - status code: 400, 404, 500
+  - status code: 400, 404, 422, 500
   - response body: JSON with error + time
 */
 func (h *HttpHandlers) handleAddTask(w http.ResponseWriter, r *http.Request) {
@@ -46,18 +58,18 @@ func (h *HttpHandlers) handleAddTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	taskImportData := models.TaskImportData{
-		Title: taskDTO.Title,
-		Text:  taskDTO.Text}
+	taskImportData := taskImportDataFromDTO(taskDTO)
+	taskImportData.IdempotencyKey = r.Header.Get("Idempotency-Key")
 
 	ctx := r.Context()
 	createdTask, err := h.service.AddTask(ctx, taskImportData)
 	if err != nil {
 		errorDTO := NewErrorDTO(err.Error())
-		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
 		return
 	}
 
+	w.Header().Set("ETag", taskETag(createdTask))
 	w.WriteHeader(http.StatusCreated)
 	b, err := json.MarshalIndent(createdTask, "", "    ")
 	if err != nil {
@@ -72,6 +84,54 @@ func (h *HttpHandlers) handleAddTask(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+/*
+pattern: /task
+method: GET
+info: query parameter id
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented task with its subtasks, nested
+  - status code: 304 Not Modified, if If-None-Match has the current ETag
+
+failure:
+  - status code: 400, 404, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleGetTaskTree(w http.ResponseWriter, r *http.Request) {
+	id, err := parseTaskId(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	tree, err := h.service.GetTaskTree(ctx, id)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	if notModified(w, r, treeETag(tree)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	b, err := json.MarshalIndent(tree, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
 /*
 pattern: /tasks
 method: GET
@@ -80,17 +140,27 @@ info: -
 success:
   - status code: 200 Ok
   - response body: JSON represented found data
+  - status code: 304 Not Modified, if If-None-Match has the current ETag
+  - header Warning: 110 - "Response is Stale", if Postgres is down and the
+    list came from the cache
 
 failure:
-  - status code: 500
+  - status code: 500, 503 if Postgres is down and the cache has no list
   - response body: JSON with error + time
 */
 func (h *HttpHandlers) handleListAllTasks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	tasks, err := h.service.ListAllTasks(ctx)
+	tasks, stale, err := h.service.ListAllTasks(ctx)
 	if err != nil {
 		errorDTO := NewErrorDTO(err.Error())
-		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	markStale(w, stale)
+
+	if notModified(w, r, listETag(tasks)) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -109,15 +179,68 @@ func (h *HttpHandlers) handleListAllTasks(w http.ResponseWriter, r *http.Request
 
 /*
 pattern: /tasks
+method: GET
+info: query parameters due (overdue|today|week), tz, sort (id|priority|position),
+tag (repeated or comma-separated), tag_match (any|all), project
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented found data
+  - status code: 304 Not Modified, if If-None-Match has the current ETag
+  - header Warning: 110 - "Response is Stale", if Postgres is down and the
+    list came from the cache
+
+failure:
+  - status code: 400, 500, 503 if Postgres is down and the cache has no list
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleListTasks(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	tasks, stale, err := h.service.ListTasks(ctx, filter)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	markStale(w, stale)
+
+	if notModified(w, r, listETag(tasks)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	b, err := json.MarshalIndent(tasks, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /delete
 method: DELETE
-info: JSON in HTTP request body
+info: JSON in HTTP request body; the task with its subtasks goes to the trash; optional If-Match with the task ETag
 
 success:
   - status code: 204 No Content
   - response body: -
 
 failure:
-  - status code: 400, 404, 429, 500
+  - status code: 400, 404, 412, 429, 500
   - response body: JSON with error + time
 */
 func (h *HttpHandlers) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
@@ -132,25 +255,144 @@ func (h *HttpHandlers) handleDeleteTask(w http.ResponseWriter, r *http.Request)
 
 	ctx := r.Context()
 	if err := h.service.RemoveTask(ctx, idDTO.Id); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/*
+pattern: /trash
+method: GET
+info: -
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented trashed tasks, most recently deleted first
+  - status code: 304 Not Modified, if If-None-Match has the current ETag
+
+failure:
+  - status code: 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleListTrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tasks, err := h.service.ListTrash(ctx)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	if notModified(w, r, listETag(tasks)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	b, err := json.MarshalIndent(tasks, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /restore
+method: PUT
+info: JSON in HTTP request body with Id of a trashed task; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented restored task
+
+failure:
+  - status code: 400, 404, 409, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleRestoreTask(w http.ResponseWriter, r *http.Request) {
+	var idDTO struct {
+		Id int
+	}
+	if err := json.NewDecoder(r.Body).Decode(&idDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	restoredTask, err := h.service.RestoreTask(ctx, idDTO.Id)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(restoredTask))
+	b, err := json.MarshalIndent(restoredTask, "", "    ")
+	if err != nil {
 		errorDTO := NewErrorDTO(err.Error())
 		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
 		return
 	}
 
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /trash
+method: DELETE
+info: JSON in HTTP request body with Id of a trashed task to delete permanently; optional If-Match with the task ETag
+
+success:
+  - status code: 204 No Content
+  - response body: -
+
+failure:
+  - status code: 400, 404, 409, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handlePurgeTask(w http.ResponseWriter, r *http.Request) {
+	var idDTO struct {
+		Id int
+	}
+	if err := json.NewDecoder(r.Body).Decode(&idDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.service.PurgeTask(ctx, idDTO.Id); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
 /*
 pattern: /tasks
 method: PATCH
-info: JSON in HTTP request body
+info: JSON in HTTP request body; optional If-Match with the task ETag
 
 success:
   - status code: 200 Ok
   - response body: JSON represented updated data
 
 failure:
-  - status code: 400, 404, 429, 500
+  - status code: 400, 404, 412, 429, 500
   - response body: JSON with error + time
 */
 func (h *HttpHandlers) handleFinishTask(w http.ResponseWriter, r *http.Request) {
@@ -165,12 +407,99 @@ func (h *HttpHandlers) handleFinishTask(w http.ResponseWriter, r *http.Request)
 
 	ctx := r.Context()
 	updatedTask, err := h.service.MarkTaskFinished(ctx, idDTO.Id)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(updatedTask))
+	b, err := json.MarshalIndent(updatedTask, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /tasks/{id}/reopen
+method: POST
+info: task Id in the path; reopening an open task changes nothing; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented updated task
+
+failure:
+  - status code: 400, 404, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleReopenTask(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathTaskId(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	updatedTask, err := h.service.ReopenTask(ctx, id)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(updatedTask))
+	b, err := json.MarshalIndent(updatedTask, "", "    ")
 	if err != nil {
 		errorDTO := NewErrorDTO(err.Error())
 		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
 		return
 	}
 
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /priority
+method: PUT
+info: JSON in HTTP request body, priority is one of none, low, medium, high, urgent; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented updated data
+
+failure:
+  - status code: 400, 404, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleSetTaskPriority(w http.ResponseWriter, r *http.Request) {
+	var priorityDTO TaskPriorityDTO
+	if err := json.NewDecoder(r.Body).Decode(&priorityDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	updatedTask, err := h.service.SetTaskPriority(ctx, priorityDTO.Id, priorityDTO.Priority)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(updatedTask))
 	b, err := json.MarshalIndent(updatedTask, "", "    ")
 	if err != nil {
 		errorDTO := NewErrorDTO(err.Error())
@@ -183,3 +512,1029 @@ func (h *HttpHandlers) handleFinishTask(w http.ResponseWriter, r *http.Request)
 		return
 	}
 }
+
+/*
+pattern: /move
+method: PUT
+info: JSON in HTTP request body with exactly one of before_id and after_id; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented moved task with its new position
+
+failure:
+  - status code: 400, 404, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleMoveTask(w http.ResponseWriter, r *http.Request) {
+	var moveDTO TaskMoveDTO
+	if err := json.NewDecoder(r.Body).Decode(&moveDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	taskMove := models.TaskMove{
+		Id:       moveDTO.Id,
+		BeforeId: moveDTO.BeforeId,
+		AfterId:  moveDTO.AfterId}
+
+	ctx := r.Context()
+	movedTask, err := h.service.MoveTask(ctx, taskMove)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(movedTask))
+	b, err := json.MarshalIndent(movedTask, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /recurrence
+method: PUT
+info: JSON in HTTP request body with task Id and recurrence; null recurrence ends the series; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented updated task
+
+failure:
+  - status code: 400, 404, 409, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleSetTaskRecurrence(w http.ResponseWriter, r *http.Request) {
+	var recurrenceDTO TaskRecurrenceDTO
+	if err := json.NewDecoder(r.Body).Decode(&recurrenceDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	updatedTask, err := h.service.SetTaskRecurrence(ctx, recurrenceDTO.Id, recurrenceFromDTO(recurrenceDTO.Recurrence))
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(updatedTask))
+	b, err := json.MarshalIndent(updatedTask, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /skip
+method: PUT
+info: JSON in HTTP request body with Id of a recurring task; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented task moved to its next due date
+
+failure:
+  - status code: 400, 404, 409, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleSkipOccurrence(w http.ResponseWriter, r *http.Request) {
+	var idDTO struct {
+		Id int
+	}
+	if err := json.NewDecoder(r.Body).Decode(&idDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	updatedTask, err := h.service.SkipOccurrence(ctx, idDTO.Id)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(updatedTask))
+	b, err := json.MarshalIndent(updatedTask, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /tags/add
+method: PUT
+info: JSON in HTTP request body with task Id and tags to attach; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented updated task
+
+failure:
+  - status code: 400, 404, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleAddTaskTags(w http.ResponseWriter, r *http.Request) {
+	var tagsDTO TaskTagsDTO
+	if err := json.NewDecoder(r.Body).Decode(&tagsDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	updatedTask, err := h.service.AddTaskTags(ctx, tagsDTO.Id, tagsDTO.Tags)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(updatedTask))
+	b, err := json.MarshalIndent(updatedTask, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /tags/remove
+method: PUT
+info: JSON in HTTP request body with task Id and tags to detach; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented updated task
+
+failure:
+  - status code: 400, 404, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleRemoveTaskTags(w http.ResponseWriter, r *http.Request) {
+	var tagsDTO TaskTagsDTO
+	if err := json.NewDecoder(r.Body).Decode(&tagsDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	updatedTask, err := h.service.RemoveTaskTags(ctx, tagsDTO.Id, tagsDTO.Tags)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(updatedTask))
+	b, err := json.MarshalIndent(updatedTask, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /tags
+method: GET
+info: -
+
+success:
+  - status code: 200 Ok
+  - response body: JSON list of tags with the number of tasks using each one
+
+failure:
+  - status code: 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleListTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tags, err := h.service.ListTags(ctx)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(tags, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /tags/rename
+method: PUT
+info: JSON in HTTP request body with name and new_name
+
+success:
+  - status code: 200 Ok
+  - response body: JSON with the new tag name and IDs of affected tasks
+
+failure:
+  - status code: 400, 404, 409, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleRenameTag(w http.ResponseWriter, r *http.Request) {
+	var renameDTO RenameTagDTO
+	if err := json.NewDecoder(r.Body).Decode(&renameDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	change, err := h.service.RenameTag(ctx, renameDTO.Name, renameDTO.NewName)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(change, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /tags/merge
+method: PUT
+info: JSON in HTTP request body with sources merged into target, target is created if missing
+
+success:
+  - status code: 200 Ok
+  - response body: JSON with the target tag name and IDs of affected tasks
+
+failure:
+  - status code: 400, 404, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleMergeTags(w http.ResponseWriter, r *http.Request) {
+	var mergeDTO MergeTagsDTO
+	if err := json.NewDecoder(r.Body).Decode(&mergeDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	change, err := h.service.MergeTags(ctx, mergeDTO.Sources, mergeDTO.Target)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(change, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /project
+method: PUT
+info: JSON in HTTP request body with task Id and project_id, 0 moves the task to the inbox; optional If-Match with the task ETag
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented updated task
+
+failure:
+  - status code: 400, 404, 409, 412, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleMoveTaskToProject(w http.ResponseWriter, r *http.Request) {
+	var taskProjectDTO TaskProjectDTO
+	if err := json.NewDecoder(r.Body).Decode(&taskProjectDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	movedTask, err := h.service.MoveTaskToProject(ctx, taskProjectDTO.Id, taskProjectDTO.ProjectId)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(movedTask))
+	b, err := json.MarshalIndent(movedTask, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /projects
+method: GET
+info: query parameter archived (true|false)
+
+success:
+  - status code: 200 Ok
+  - response body: JSON list of projects, the inbox goes first
+
+failure:
+  - status code: 400, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleListProjects(w http.ResponseWriter, r *http.Request) {
+	includeArchived, err := parseIncludeArchived(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	projects, err := h.service.ListProjects(ctx, includeArchived)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(projects, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /projects
+method: POST
+info: JSON in HTTP request body with name
+
+success:
+  - status code: 201 Created
+  - response body: JSON represented created project
+
+failure:
+  - status code: 400, 409, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleCreateProject(w http.ResponseWriter, r *http.Request) {
+	var projectDTO ProjectDTO
+	if err := json.NewDecoder(r.Body).Decode(&projectDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	project, err := h.service.CreateProject(ctx, projectDTO.Name)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(project, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /projects/rename
+method: PUT
+info: JSON in HTTP request body with project Id and name
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented updated project
+
+failure:
+  - status code: 400, 404, 409, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleRenameProject(w http.ResponseWriter, r *http.Request) {
+	var projectDTO ProjectDTO
+	if err := json.NewDecoder(r.Body).Decode(&projectDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	project, err := h.service.RenameProject(ctx, projectDTO.Id, projectDTO.Name)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(project, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /projects/archive
+method: PUT
+info: JSON in HTTP request body with project Id and archived (false restores the project)
+
+success:
+  - status code: 200 Ok
+  - response body: JSON represented updated project
+
+failure:
+  - status code: 400, 404, 409, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleArchiveProject(w http.ResponseWriter, r *http.Request) {
+	var projectDTO ProjectDTO
+	if err := json.NewDecoder(r.Body).Decode(&projectDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	project, err := h.service.ArchiveProject(ctx, projectDTO.Id, projectDTO.Archived)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(project, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /projects
+method: DELETE
+info: JSON in HTTP request body with project Id, its tasks are moved to the inbox
+
+success:
+  - status code: 204 No Content
+  - response body: -
+
+failure:
+  - status code: 400, 404, 409, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleDeleteProject(w http.ResponseWriter, r *http.Request) {
+	var projectDTO ProjectDTO
+	if err := json.NewDecoder(r.Body).Decode(&projectDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.service.DeleteProject(ctx, projectDTO.Id); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/*
+pattern: /stats
+method: GET
+info: query parameters from, to, bucket (day|week), tz
+
+success:
+  - status code: 200 Ok
+  - response body: JSON with per-bucket created/completed series and totals
+
+failure:
+  - status code: 400, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleGetStats(w http.ResponseWriter, r *http.Request) {
+	statsRequest, err := parseStatsRequest(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	stats, err := h.service.GetStats(ctx, statsRequest)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(NewStatsDTO(statsRequest, stats), "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /tasks/{id}/history
+method: GET
+info: query parameters limit, cursor
+
+success:
+  - status code: 200 Ok
+  - response body: JSON with the task changes, newest first, and next_cursor when there are more
+
+failure:
+  - status code: 400, 404, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleGetTaskHistory(w http.ResponseWriter, r *http.Request) {
+	historyRequest, err := parseTaskHistoryRequest(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	page, err := h.service.GetTaskHistory(ctx, historyRequest)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(NewTaskHistoryDTO(historyRequest.TaskId, page), "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /tasks:batch
+method: POST
+info: JSON in HTTP request body with action create and tasks, or action done / delete and ids;
+all items are applied in one transaction, a failed item changes nothing and doesn't stop the others
+
+success:
+  - status code: 200 Ok
+  - response body: JSON with a result per item in request order: id, status code of the item, task or error
+
+failure:
+  - status code: 400, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var batchDTO BatchRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&batchDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	var (
+		items         []models.BatchItem
+		err           error
+		successStatus = http.StatusOK
+	)
+	switch batchDTO.Action {
+	case "create":
+		tasks := make([]models.TaskImportData, 0, len(batchDTO.Tasks))
+		for _, taskDTO := range batchDTO.Tasks {
+			tasks = append(tasks, taskImportDataFromDTO(taskDTO))
+		}
+		items, err = h.service.BatchAddTasks(ctx, tasks)
+		successStatus = http.StatusCreated
+	case "done":
+		items, err = h.service.BatchMarkFinished(ctx, batchDTO.Ids)
+	case "delete":
+		items, err = h.service.BatchRemoveTasks(ctx, batchDTO.Ids)
+	default:
+		errorDTO := NewErrorDTO(fmt.Sprintf("unknown batch action %q, expected create, done or delete", batchDTO.Action))
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(NewBatchResultDTO(batchDTO.Action, items, successStatus), "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /events
+method: GET
+info: Server-Sent Events stream of task changes; query parameter project (optional) limits it to one project,
+the Last-Event-ID header or last_event_id parameter resumes after that event
+
+success:
+  - status code: 200 Ok
+  - response body: text/event-stream with created, updated, finished, deleted and reset events, each with JSON data,
+    and heartbeat comments
+
+failure:
+  - status code: 400
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleWatchTasks(w http.ResponseWriter, r *http.Request) {
+	watchRequest, err := parseWatchRequest(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// The events are received apart from writing them, so that heartbeats
+	// go out while no events come. A full buffer holds the stream back
+	// instead of growing.
+	events := make(chan models.TaskEvent, sseEventBuffer)
+	done := make(chan error, 1)
+	go func() {
+		done <- h.service.WatchTasks(ctx, watchRequest, func(event models.TaskEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	stream := newSSEWriter(w)
+	if err := stream.retry(sseRetryDelay); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			err = stream.event(event)
+		case <-heartbeat.C:
+			err = stream.heartbeat()
+		case watchErr := <-done:
+			// The client reconnects by itself and resumes after the last
+			// event it got, so the stream just ends.
+			if watchErr != nil {
+				log.Println("Task events stream ended:", watchErr)
+			}
+			for {
+				select {
+				case event := <-events:
+					if stream.event(event) != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+
+		if err != nil {
+			log.Println("Failed to send task event:", err)
+			return
+		}
+	}
+}
+
+/*
+pattern: /ws
+method: GET
+info: WebSocket upgrade; token in the Authorization: Bearer header or the access_token parameter,
+query parameters project and last_event_id (or the Last-Event-ID header) as for /events
+
+success:
+  - status code: 101 Switching Protocols
+  - messages: task events as in /events, acknowledgements of the create, done and delete requests
+    with the request id and the status the HTTP endpoint would return
+
+failure:
+  - status code: 400, 401
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.authenticateWS(r)
+	if !ok {
+		errorDTO := NewErrorDTO("missing or unknown token")
+		http.Error(w, errorDTO.ToString(), http.StatusUnauthorized)
+		return
+	}
+
+	watchRequest, err := parseWatchRequest(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	// The upgrader answers failed handshakes itself.
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("WebSocket upgrade failed:", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(app.WithActor(r.Context(), actor))
+	session := newWSSession(conn, h.service, cancel)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		session.writeLoop(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		session.watch(ctx, watchRequest)
+	}()
+
+	session.readLoop(ctx)
+	session.close(websocket.CloseNormalClosure, "")
+	wg.Wait()
+}
+
+/*
+pattern: /sync
+method: GET
+info: query parameters since (token from the previous sync, empty for the first one), limit (optional)
+
+success:
+  - status code: 200 Ok
+  - response body: JSON with the changed tasks, ids of the deleted ones, the token for the next sync, has_more and full
+
+failure:
+  - status code: 400, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleGetChanges(w http.ResponseWriter, r *http.Request) {
+	token, limit, err := parseChangesRequest(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	changes, err := h.service.GetChangesSince(ctx, token, limit)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(NewSyncChangesDTO(changes), "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /sync
+method: POST
+info: JSON in request body with conflict ("last_writer_wins" or "version") and the queued mutations
+
+success:
+  - status code: 200 Ok
+  - response body: JSON with a result for every mutation in request order: the status code of the single request,
+    the task or the error and, for a version conflict, the current task
+
+failure:
+  - status code: 400
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handlePushChanges(w http.ResponseWriter, r *http.Request) {
+	var pushDTO SyncPushDTO
+	if err := json.NewDecoder(r.Body).Decode(&pushDTO); err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	var policy models.ConflictPolicy
+	switch pushDTO.Conflict {
+	case "", "last_writer_wins":
+		policy = models.ConflictLastWriterWins
+	case "version":
+		policy = models.ConflictVersion
+	default:
+		errorDTO := NewErrorDTO(fmt.Sprintf("unknown conflict policy %q, expected last_writer_wins or version", pushDTO.Conflict))
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	mutations := make([]models.Mutation, 0, len(pushDTO.Mutations))
+	for _, mutationDTO := range pushDTO.Mutations {
+		mutations = append(mutations, mutationFromDTO(mutationDTO))
+	}
+
+	ctx := r.Context()
+	results, err := h.service.ApplySyncMutations(ctx, policy, mutations)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	out := SyncPushResultDTO{Results: make([]MutationResultDTO, 0, len(results))}
+	for i, result := range results {
+		out.Results = append(out.Results, MutationResultDTO{
+			Id:      result.Id,
+			Status:  mutationStatus(mutations[i], result.Err),
+			Task:    result.Task,
+			Current: result.Current,
+			Error:   errorText(result.Err),
+		})
+	}
+
+	b, err := json.MarshalIndent(out, "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
+
+/*
+pattern: /search
+method: GET
+info: query parameters q (words to find, the last one may be unfinished), limit
+
+success:
+  - status code: 200 Ok
+  - response body: JSON with the found tasks, most relevant first, and snippets with matched words in <b></b>
+
+failure:
+  - status code: 400, 500
+  - response body: JSON with error + time
+*/
+func (h *HttpHandlers) handleSearchTasks(w http.ResponseWriter, r *http.Request) {
+	searchRequest, err := parseSearchRequest(r)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	hits, err := h.service.SearchTasks(ctx, searchRequest)
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), statusCodeFor(err))
+		return
+	}
+
+	b, err := json.MarshalIndent(NewSearchResultDTO(searchRequest.Query, hits), "", "    ")
+	if err != nil {
+		errorDTO := NewErrorDTO(err.Error())
+		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(b); err != nil {
+		log.Println("Failed to send http answer:", err)
+		return
+	}
+}
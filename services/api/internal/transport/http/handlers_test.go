@@ -4,32 +4,152 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
 	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 )
 
 type fakeDBClient struct {
-	addFn    func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error)
-	removeFn func(ctx context.Context, id int) error
-	listFn   func(ctx context.Context) ([]models.TaskExportData, error)
-	doneFn   func(ctx context.Context, id int) (models.TaskExportData, error)
+	addFn            func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error)
+	removeFn         func(ctx context.Context, id int) error
+	listFn           func(ctx context.Context) ([]models.TaskExportData, bool, error)
+	doneFn           func(ctx context.Context, id int) (models.TaskExportData, error)
+	statsFn          func(ctx context.Context, req models.StatsRequest) (models.Stats, error)
+	filterFn         func(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error)
+	priorityFn       func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error)
+	moveFn           func(ctx context.Context, move models.TaskMove) (models.TaskExportData, error)
+	addTagsFn        func(ctx context.Context, id int, tags []string) (models.TaskExportData, error)
+	removeTagsFn     func(ctx context.Context, id int, tags []string) (models.TaskExportData, error)
+	listTagsFn       func(ctx context.Context) ([]models.TagUsage, error)
+	renameTagFn      func(ctx context.Context, name, newName string) (models.TagChange, error)
+	mergeTagsFn      func(ctx context.Context, sources []string, target string) (models.TagChange, error)
+	createProjectFn  func(ctx context.Context, name string) (models.Project, error)
+	listProjectsFn   func(ctx context.Context, includeArchived bool) ([]models.Project, error)
+	renameProjectFn  func(ctx context.Context, id int, name string) (models.Project, error)
+	archiveProjectFn func(ctx context.Context, id int, archived bool) (models.Project, error)
+	deleteProjectFn  func(ctx context.Context, id int) error
+	taskProjectFn    func(ctx context.Context, id, projectId int) (models.TaskExportData, error)
+	taskTreeFn       func(ctx context.Context, id int) (models.TaskTree, error)
+	recurrenceFn     func(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error)
+	skipFn           func(ctx context.Context, id int) (models.TaskExportData, error)
+	reopenFn         func(ctx context.Context, id int) (models.TaskExportData, error)
+	listTrashFn      func(ctx context.Context) ([]models.TaskExportData, error)
+	restoreFn        func(ctx context.Context, id int) (models.TaskExportData, error)
+	purgeFn          func(ctx context.Context, id int) error
+	historyFn        func(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error)
+	batchAddFn       func(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error)
+	batchFinishFn    func(ctx context.Context, ids []int) ([]models.BatchItem, error)
+	batchRemoveFn    func(ctx context.Context, ids []int) ([]models.BatchItem, error)
+	searchFn         func(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error)
+	watchFn          func(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error
+	changesFn        func(ctx context.Context, token string, limit int) (models.Changes, error)
 
-	addCalls    int
-	removeCalls int
-	listCalls   int
-	doneCalls   int
+	addCalls            int
+	removeCalls         int
+	listCalls           int
+	doneCalls           int
+	statsCalls          int
+	filterCalls         int
+	priorityCalls       int
+	moveCalls           int
+	addTagsCalls        int
+	removeTagsCalls     int
+	listTagsCalls       int
+	renameTagCalls      int
+	mergeTagsCalls      int
+	createProjectCalls  int
+	listProjectsCalls   int
+	renameProjectCalls  int
+	archiveProjectCalls int
+	deleteProjectCalls  int
+	taskProjectCalls    int
+	taskTreeCalls       int
+	recurrenceCalls     int
+	skipCalls           int
+	reopenCalls         int
+	listTrashCalls      int
+	restoreCalls        int
+	purgeCalls          int
+	historyCalls        int
+	batchAddCalls       int
+	batchFinishCalls    int
+	batchRemoveCalls    int
+	searchCalls         int
+	watchCalls          int
+	changesCalls        int
 
 	gotAddTask models.TaskImportData
 	gotAddCtx  context.Context
 
 	gotRemoveID int
 	gotDoneID   int
+	gotStatsReq models.StatsRequest
+	gotFilter   models.TaskFilter
+
+	gotPriorityID int
+	gotPriority   models.Priority
+	gotMove       models.TaskMove
+
+	gotAddTagsID int
+	gotAddTags   []string
+
+	gotRemoveTagsID int
+	gotRemoveTags   []string
+
+	gotRenameTagName    string
+	gotRenameTagNewName string
+
+	gotMergeSources []string
+	gotMergeTarget  string
+
+	gotCreateProjectName string
+
+	gotIncludeArchived bool
+
+	gotRenameProjectID   int
+	gotRenameProjectName string
+
+	gotArchiveProjectID int
+	gotArchived         bool
+
+	gotDeleteProjectID int
+
+	gotTaskProjectID int
+	gotProjectID     int
+
+	gotTaskTreeID int
+
+	gotRecurrenceID int
+	gotRecurrence   *models.Recurrence
+
+	gotSkipID int
+
+	gotReopenID int
+
+	gotRestoreID int
+
+	gotPurgeID int
+
+	gotHistoryReq models.TaskHistoryRequest
+
+	gotBatchTasks []models.TaskImportData
+
+	gotBatchFinishIds []int
+
+	gotBatchRemoveIds []int
+
+	gotSearch models.SearchRequest
+
+	gotWatch models.WatchRequest
 }
 
 func (f *fakeDBClient) AddTask(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
@@ -51,7 +171,7 @@ func (f *fakeDBClient) RemoveTask(ctx context.Context, id int) error {
 	return f.removeFn(ctx, id)
 }
 
-func (f *fakeDBClient) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
+func (f *fakeDBClient) ListAllTasks(ctx context.Context) ([]models.TaskExportData, bool, error) {
 	f.listCalls++
 	if f.listFn == nil {
 		panic("ListAllTasks called but listFn not set")
@@ -68,6 +188,273 @@ func (f *fakeDBClient) MarkTaskFinished(ctx context.Context, id int) (models.Tas
 	return f.doneFn(ctx, id)
 }
 
+func (f *fakeDBClient) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	f.statsCalls++
+	f.gotStatsReq = req
+	if f.statsFn == nil {
+		panic("GetStats called but statsFn not set")
+	}
+	return f.statsFn(ctx, req)
+}
+
+func (f *fakeDBClient) ListTasks(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+	f.filterCalls++
+	f.gotFilter = filter
+	if f.filterFn == nil {
+		panic("ListTasks called but filterFn not set")
+	}
+	return f.filterFn(ctx, filter)
+}
+
+func (f *fakeDBClient) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	f.priorityCalls++
+	f.gotPriorityID = id
+	f.gotPriority = priority
+	if f.priorityFn == nil {
+		panic("SetTaskPriority called but priorityFn not set")
+	}
+	return f.priorityFn(ctx, id, priority)
+}
+
+func (f *fakeDBClient) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	f.moveCalls++
+	f.gotMove = move
+	if f.moveFn == nil {
+		panic("MoveTask called but moveFn not set")
+	}
+	return f.moveFn(ctx, move)
+}
+
+func (f *fakeDBClient) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	f.addTagsCalls++
+	f.gotAddTagsID = id
+	f.gotAddTags = tags
+	if f.addTagsFn == nil {
+		panic("AddTaskTags called but addTagsFn not set")
+	}
+	return f.addTagsFn(ctx, id, tags)
+}
+
+func (f *fakeDBClient) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	f.removeTagsCalls++
+	f.gotRemoveTagsID = id
+	f.gotRemoveTags = tags
+	if f.removeTagsFn == nil {
+		panic("RemoveTaskTags called but removeTagsFn not set")
+	}
+	return f.removeTagsFn(ctx, id, tags)
+}
+
+func (f *fakeDBClient) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	f.listTagsCalls++
+	if f.listTagsFn == nil {
+		panic("ListTags called but listTagsFn not set")
+	}
+	return f.listTagsFn(ctx)
+}
+
+func (f *fakeDBClient) RenameTag(ctx context.Context, name, newName string) (models.TagChange, error) {
+	f.renameTagCalls++
+	f.gotRenameTagName = name
+	f.gotRenameTagNewName = newName
+	if f.renameTagFn == nil {
+		panic("RenameTag called but renameTagFn not set")
+	}
+	return f.renameTagFn(ctx, name, newName)
+}
+
+func (f *fakeDBClient) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	f.mergeTagsCalls++
+	f.gotMergeSources = sources
+	f.gotMergeTarget = target
+	if f.mergeTagsFn == nil {
+		panic("MergeTags called but mergeTagsFn not set")
+	}
+	return f.mergeTagsFn(ctx, sources, target)
+}
+
+func (f *fakeDBClient) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	f.createProjectCalls++
+	f.gotCreateProjectName = name
+	if f.createProjectFn == nil {
+		panic("CreateProject called but createProjectFn not set")
+	}
+	return f.createProjectFn(ctx, name)
+}
+
+func (f *fakeDBClient) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	f.listProjectsCalls++
+	f.gotIncludeArchived = includeArchived
+	if f.listProjectsFn == nil {
+		panic("ListProjects called but listProjectsFn not set")
+	}
+	return f.listProjectsFn(ctx, includeArchived)
+}
+
+func (f *fakeDBClient) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	f.renameProjectCalls++
+	f.gotRenameProjectID = id
+	f.gotRenameProjectName = name
+	if f.renameProjectFn == nil {
+		panic("RenameProject called but renameProjectFn not set")
+	}
+	return f.renameProjectFn(ctx, id, name)
+}
+
+func (f *fakeDBClient) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	f.archiveProjectCalls++
+	f.gotArchiveProjectID = id
+	f.gotArchived = archived
+	if f.archiveProjectFn == nil {
+		panic("ArchiveProject called but archiveProjectFn not set")
+	}
+	return f.archiveProjectFn(ctx, id, archived)
+}
+
+func (f *fakeDBClient) DeleteProject(ctx context.Context, id int) error {
+	f.deleteProjectCalls++
+	f.gotDeleteProjectID = id
+	if f.deleteProjectFn == nil {
+		panic("DeleteProject called but deleteProjectFn not set")
+	}
+	return f.deleteProjectFn(ctx, id)
+}
+
+func (f *fakeDBClient) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	f.taskProjectCalls++
+	f.gotTaskProjectID = id
+	f.gotProjectID = projectId
+	if f.taskProjectFn == nil {
+		panic("MoveTaskToProject called but taskProjectFn not set")
+	}
+	return f.taskProjectFn(ctx, id, projectId)
+}
+
+func (f *fakeDBClient) GetTaskTree(ctx context.Context, id int) (models.TaskTree, error) {
+	f.taskTreeCalls++
+	f.gotTaskTreeID = id
+	if f.taskTreeFn == nil {
+		panic("GetTaskTree called but taskTreeFn not set")
+	}
+	return f.taskTreeFn(ctx, id)
+}
+
+func (f *fakeDBClient) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	f.recurrenceCalls++
+	f.gotRecurrenceID = id
+	f.gotRecurrence = rule
+	if f.recurrenceFn == nil {
+		panic("SetTaskRecurrence called but recurrenceFn not set")
+	}
+	return f.recurrenceFn(ctx, id, rule)
+}
+
+func (f *fakeDBClient) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.skipCalls++
+	f.gotSkipID = id
+	if f.skipFn == nil {
+		panic("SkipOccurrence called but skipFn not set")
+	}
+	return f.skipFn(ctx, id)
+}
+
+func (f *fakeDBClient) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.reopenCalls++
+	f.gotReopenID = id
+	if f.reopenFn == nil {
+		panic("ReopenTask called but reopenFn not set")
+	}
+	return f.reopenFn(ctx, id)
+}
+
+func (f *fakeDBClient) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	f.listTrashCalls++
+	if f.listTrashFn == nil {
+		panic("ListTrash called but listTrashFn not set")
+	}
+	return f.listTrashFn(ctx)
+}
+
+func (f *fakeDBClient) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.restoreCalls++
+	f.gotRestoreID = id
+	if f.restoreFn == nil {
+		panic("RestoreTask called but restoreFn not set")
+	}
+	return f.restoreFn(ctx, id)
+}
+
+func (f *fakeDBClient) PurgeTask(ctx context.Context, id int) error {
+	f.purgeCalls++
+	f.gotPurgeID = id
+	if f.purgeFn == nil {
+		panic("PurgeTask called but purgeFn not set")
+	}
+	return f.purgeFn(ctx, id)
+}
+
+func (f *fakeDBClient) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	f.historyCalls++
+	f.gotHistoryReq = req
+	if f.historyFn == nil {
+		panic("GetTaskHistory called but historyFn not set")
+	}
+	return f.historyFn(ctx, req)
+}
+
+func (f *fakeDBClient) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	f.batchAddCalls++
+	f.gotBatchTasks = tasks
+	if f.batchAddFn == nil {
+		panic("BatchAddTasks called but batchAddFn not set")
+	}
+	return f.batchAddFn(ctx, tasks)
+}
+
+func (f *fakeDBClient) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	f.batchFinishCalls++
+	f.gotBatchFinishIds = ids
+	if f.batchFinishFn == nil {
+		panic("BatchMarkFinished called but batchFinishFn not set")
+	}
+	return f.batchFinishFn(ctx, ids)
+}
+
+func (f *fakeDBClient) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	f.batchRemoveCalls++
+	f.gotBatchRemoveIds = ids
+	if f.batchRemoveFn == nil {
+		panic("BatchRemoveTasks called but batchRemoveFn not set")
+	}
+	return f.batchRemoveFn(ctx, ids)
+}
+
+func (f *fakeDBClient) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	f.searchCalls++
+	f.gotSearch = req
+	if f.searchFn == nil {
+		panic("SearchTasks called but searchFn not set")
+	}
+	return f.searchFn(ctx, req)
+}
+
+func (f *fakeDBClient) WatchTasks(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+	f.watchCalls++
+	f.gotWatch = req
+	if f.watchFn == nil {
+		panic("WatchTasks called but watchFn not set")
+	}
+	return f.watchFn(ctx, req, send)
+}
+
+func (f *fakeDBClient) GetChangesSince(ctx context.Context, token string, limit int) (models.Changes, error) {
+	f.changesCalls++
+	if f.changesFn == nil {
+		panic("GetChangesSince called but changesFn not set")
+	}
+	return f.changesFn(ctx, token, limit)
+}
+
 func TestHandleAddTask_BadJSON_Returns400_AndDoesNotCallDB(t *testing.T) {
 	db := &fakeDBClient{}
 	svc := app.NewService(db)
@@ -86,8 +473,8 @@ func TestHandleAddTask_BadJSON_Returns400_AndDoesNotCallDB(t *testing.T) {
 	}
 }
 
-func TestHandleAddTask_Returns400_OnServiceError(t *testing.T) {
-	wantErr := errors.New("my error")
+func TestHandleAddTask_Returns400_OnInvalidArgument(t *testing.T) {
+	wantErr := fmt.Errorf("%w: title is empty", app.ErrInvalidArgument)
 	db := &fakeDBClient{
 		addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
 			return models.TaskExportData{}, wantErr
@@ -214,8 +601,8 @@ func TestHandleDeleteTask_Success_Returns204(t *testing.T) {
 func TestHandleListAllTasks_Returns500(t *testing.T) {
 	wantErr := errors.New("my error")
 	db := &fakeDBClient{
-		listFn: func(ctx context.Context) ([]models.TaskExportData, error) {
-			return nil, wantErr
+		listFn: func(ctx context.Context) ([]models.TaskExportData, bool, error) {
+			return nil, false, wantErr
 		},
 	}
 	svc := app.NewService(db)
@@ -236,7 +623,7 @@ func TestHandleListAllTasks_Returns500(t *testing.T) {
 
 func TestHandleListAllTasks_Success_Returns200_AndSliceOfTasks(t *testing.T) {
 	db := &fakeDBClient{
-		listFn: func(ctx context.Context) ([]models.TaskExportData, error) {
+		listFn: func(ctx context.Context) ([]models.TaskExportData, bool, error) {
 			return []models.TaskExportData{
 				{
 					Id:    1,
@@ -248,7 +635,7 @@ func TestHandleListAllTasks_Success_Returns200_AndSliceOfTasks(t *testing.T) {
 					Title: "title2",
 					Text:  "text2",
 				},
-			}, nil
+			}, false, nil
 		},
 	}
 	svc := app.NewService(db)
@@ -278,6 +665,63 @@ func TestHandleListAllTasks_Success_Returns200_AndSliceOfTasks(t *testing.T) {
 	}
 }
 
+func TestHandleListAllTasks_Stale_SetsWarning(t *testing.T) {
+	db := &fakeDBClient{
+		listFn: func(ctx context.Context) ([]models.TaskExportData, bool, error) {
+			return []models.TaskExportData{{Id: 1}}, true, nil
+		},
+	}
+	h := NewHttpHandlers(app.NewService(db))
+
+	req := httptest.NewRequest(http.MethodGet, "/list", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListAllTasks(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Warning"); got != staleWarning {
+		t.Fatalf("expected Warning %q, got %q", staleWarning, got)
+	}
+}
+
+func TestHandleListAllTasks_Unavailable_Returns503(t *testing.T) {
+	db := &fakeDBClient{
+		listFn: func(ctx context.Context) ([]models.TaskExportData, bool, error) {
+			return nil, false, app.ErrUnavailable
+		},
+	}
+	h := NewHttpHandlers(app.NewService(db))
+
+	req := httptest.NewRequest(http.MethodGet, "/list", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListAllTasks(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusServiceUnavailable, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleListAllTasks_Timeout_Returns504(t *testing.T) {
+	db := &fakeDBClient{
+		listFn: func(ctx context.Context) ([]models.TaskExportData, bool, error) {
+			return nil, false, app.ErrTimeout
+		},
+	}
+	h := NewHttpHandlers(app.NewService(db))
+
+	req := httptest.NewRequest(http.MethodGet, "/list", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListAllTasks(rr, req)
+
+	if rr.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusGatewayTimeout, rr.Code, rr.Body.String())
+	}
+}
+
 func TestHandleMarkTaskFinished_BadId_Returns400_AndDoesNotCallDB(t *testing.T) {
 	db := &fakeDBClient{}
 	svc := app.NewService(db)
@@ -366,3 +810,1896 @@ func TestHandleMarkTaskFinished_Success_Returns200AndTaskJSON(t *testing.T) {
 		t.Fatalf("expected FinishedAt=%v, got %v", fixedFinishedTime, *got.FinishedAt)
 	}
 }
+
+func TestHandleGetStats_BadParams_Returns400_AndDoesNotCallDB(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "bad bucket", query: "?bucket=month"},
+		{name: "bad time zone", query: "?tz=Mars/Olympus"},
+		{name: "bad date", query: "?from=yesterday"},
+		{name: "reversed range", query: "?from=2025-12-10&to=2025-12-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDBClient{}
+			svc := app.NewService(db)
+			h := NewHttpHandlers(svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats"+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			h.handleGetStats(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+			}
+			if db.statsCalls != 0 {
+				t.Fatalf("expected GetStats not called, got calls=%d", db.statsCalls)
+			}
+		})
+	}
+}
+
+func TestHandleGetStats_Returns500(t *testing.T) {
+	db := &fakeDBClient{
+		statsFn: func(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+			return models.Stats{}, errors.New("my error")
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleGetStats(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusInternalServerError, rr.Code, rr.Body.String())
+	}
+	if db.statsCalls != 1 {
+		t.Fatalf("expected GetStats calls=1, got %d", db.statsCalls)
+	}
+}
+
+func TestHandleGetStats_Success_Returns200AndChartJSON(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	fromTS := time.Date(2025, 12, 1, 0, 0, 0, 0, moscow)
+	toTS := time.Date(2025, 12, 3, 0, 0, 0, 0, moscow)
+	db := &fakeDBClient{
+		statsFn: func(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+			return models.Stats{
+				Points: []models.StatsPoint{
+					{Start: fromTS, Created: 2, Completed: 1},
+					{Start: fromTS.AddDate(0, 0, 1), Created: 1, Completed: 1},
+				},
+				CreatedTotal:      3,
+				CompletedTotal:    2,
+				AvgTimeToComplete: 90 * time.Minute,
+				OpenCount:         4,
+				CurrentStreak:     2,
+				LongestStreak:     3,
+			}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/stats?from=2025-12-01&to=2025-12-03&bucket=day&tz=Europe/Moscow", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleGetStats(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if !db.gotStatsReq.From.Equal(fromTS) || !db.gotStatsReq.To.Equal(toTS) {
+		t.Fatalf("unexpected stats range %v - %v", db.gotStatsReq.From, db.gotStatsReq.To)
+	}
+	if db.gotStatsReq.Bucket != models.StatsBucketDay || db.gotStatsReq.TimeZone != "Europe/Moscow" {
+		t.Fatalf("unexpected stats request %+v", db.gotStatsReq)
+	}
+	var got StatsDTO
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if got.Bucket != "day" || got.TimeZone != "Europe/Moscow" || len(got.Points) != 2 {
+		t.Fatalf("unexpected stats response %+v", got)
+	}
+	if got.Points[0].Created != 2 || got.Points[1].Completed != 1 {
+		t.Fatalf("unexpected stats points %+v", got.Points)
+	}
+	if got.AvgTimeToCompleteSeconds != 5400 || got.OpenCount != 4 || got.LongestStreak != 3 {
+		t.Fatalf("unexpected stats totals %+v", got)
+	}
+}
+
+func TestHandleListTasks_BadParams_Returns400_AndDoesNotCallDB(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "bad due filter", query: "?due=tomorrow"},
+		{name: "bad time zone", query: "?due=today&tz=Mars/Olympus"},
+		{name: "bad sort", query: "?sort=title"},
+		{name: "bad tag match", query: "?tag=home&tag_match=some"},
+		{name: "bad project", query: "?project=inbox"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDBClient{}
+			svc := app.NewService(db)
+			h := NewHttpHandlers(svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks"+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			h.handleListTasks(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+			}
+			if db.filterCalls != 0 {
+				t.Fatalf("expected ListTasks not called, got calls=%d", db.filterCalls)
+			}
+		})
+	}
+}
+
+func TestHandleListTasks_Returns500(t *testing.T) {
+	db := &fakeDBClient{
+		filterFn: func(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+			return nil, false, errors.New("my error")
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?due=overdue", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListTasks(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusInternalServerError, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleListTasks_Success_Returns200AndJSON(t *testing.T) {
+	dueTS := time.Date(2025, 12, 20, 18, 0, 0, 0, time.UTC)
+	db := &fakeDBClient{
+		filterFn: func(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+			return []models.TaskExportData{
+				{Id: 1, Title: "a", DueAt: &dueTS},
+			}, false, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?due=week&tz=Europe/Moscow&sort=priority&tag=home,work&tag=gym&tag_match=all&project=2", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListTasks(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	wantFilter := models.TaskFilter{
+		Due:       models.DueFilterThisWeek,
+		TimeZone:  "Europe/Moscow",
+		Sort:      models.TaskSortPriority,
+		Tags:      []string{"home", "work", "gym"},
+		TagMatch:  models.TagMatchAll,
+		ProjectId: 2,
+	}
+	if !reflect.DeepEqual(db.gotFilter, wantFilter) {
+		t.Fatalf("expected filter %+v, got %+v", wantFilter, db.gotFilter)
+	}
+	var got []models.TaskExportData
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if len(got) != 1 || got[0].DueAt == nil || !got[0].DueAt.Equal(dueTS) {
+		t.Fatalf("unexpected tasks %+v", got)
+	}
+}
+
+func TestHandleAddTask_UnknownPriority_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/create", strings.NewReader(`{"title":"Buy milk","priority":"asap"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleAddTask(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.addCalls != 0 {
+		t.Fatalf("expected AddTask not called, got calls=%d", db.addCalls)
+	}
+}
+
+func TestHandleSetTaskPriority_Success_Returns200AndTaskJSON(t *testing.T) {
+	db := &fakeDBClient{
+		priorityFn: func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, Title: "a", Priority: priority}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/priority", strings.NewReader(`{"Id":3,"priority":"urgent"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleSetTaskPriority(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotPriorityID != 3 || db.gotPriority != models.PriorityUrgent {
+		t.Fatalf("unexpected args: id=%d priority=%v", db.gotPriorityID, db.gotPriority)
+	}
+	if !strings.Contains(rr.Body.String(), `"Priority": "urgent"`) {
+		t.Fatalf("expected readable priority in body=%s", rr.Body.String())
+	}
+}
+
+func TestHandleSetTaskPriority_NotFound_Returns404(t *testing.T) {
+	db := &fakeDBClient{
+		priorityFn: func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrNotFound
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/priority", strings.NewReader(`{"Id":3,"priority":"low"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleSetTaskPriority(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleMoveTask_BadJSON_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/move", strings.NewReader(`{bad-json}`))
+	rr := httptest.NewRecorder()
+
+	h.handleMoveTask(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.moveCalls != 0 {
+		t.Fatalf("expected MoveTask not called, got calls=%d", db.moveCalls)
+	}
+}
+
+func TestHandleMoveTask_InvalidMove_Returns400(t *testing.T) {
+	db := &fakeDBClient{
+		moveFn: func(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrInvalidArgument
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/move", strings.NewReader(`{"Id":1}`))
+	rr := httptest.NewRecorder()
+
+	h.handleMoveTask(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleMoveTask_Success_Returns200AndTaskJSON(t *testing.T) {
+	db := &fakeDBClient{
+		moveFn: func(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: move.Id, Position: "ai"}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/move", strings.NewReader(`{"Id":1,"before_id":2}`))
+	rr := httptest.NewRecorder()
+
+	h.handleMoveTask(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotMove != (models.TaskMove{Id: 1, BeforeId: 2}) {
+		t.Fatalf("unexpected move %+v", db.gotMove)
+	}
+	var got models.TaskExportData
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if got.Id != 1 || got.Position != "ai" {
+		t.Fatalf("unexpected moved task response %+v", got)
+	}
+}
+
+func TestHandleAddTaskTags_Success_Returns200AndTaskJSON(t *testing.T) {
+	db := &fakeDBClient{
+		addTagsFn: func(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, Tags: tags}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/tags/add", strings.NewReader(`{"Id":3,"tags":["home","work"]}`))
+	rr := httptest.NewRecorder()
+
+	h.handleAddTaskTags(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotAddTagsID != 3 || !reflect.DeepEqual(db.gotAddTags, []string{"home", "work"}) {
+		t.Fatalf("unexpected args: id=%d tags=%v", db.gotAddTagsID, db.gotAddTags)
+	}
+	var got models.TaskExportData
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if !reflect.DeepEqual(got.Tags, []string{"home", "work"}) {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestHandleRemoveTaskTags_BadJSON_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/tags/remove", strings.NewReader(`{bad-json}`))
+	rr := httptest.NewRecorder()
+
+	h.handleRemoveTaskTags(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.removeTagsCalls != 0 {
+		t.Fatalf("expected RemoveTaskTags not called, got calls=%d", db.removeTagsCalls)
+	}
+}
+
+func TestHandleRemoveTaskTags_NotFound_Returns404(t *testing.T) {
+	db := &fakeDBClient{
+		removeTagsFn: func(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrNotFound
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/tags/remove", strings.NewReader(`{"Id":3,"tags":["home"]}`))
+	rr := httptest.NewRecorder()
+
+	h.handleRemoveTaskTags(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleListTags_Success_Returns200AndJSON(t *testing.T) {
+	db := &fakeDBClient{
+		listTagsFn: func(ctx context.Context) ([]models.TagUsage, error) {
+			return []models.TagUsage{{Name: "home", Count: 2}}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListTags(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var got []models.TagUsage
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if !reflect.DeepEqual(got, []models.TagUsage{{Name: "home", Count: 2}}) {
+		t.Fatalf("unexpected tags %+v", got)
+	}
+}
+
+func TestHandleRenameTag_NameTaken_Returns409(t *testing.T) {
+	db := &fakeDBClient{
+		renameTagFn: func(ctx context.Context, name, newName string) (models.TagChange, error) {
+			return models.TagChange{}, app.ErrAlreadyExists
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/tags/rename", strings.NewReader(`{"name":"work","new_name":"job"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleRenameTag(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusConflict, rr.Code, rr.Body.String())
+	}
+	if db.gotRenameTagName != "work" || db.gotRenameTagNewName != "job" {
+		t.Fatalf("unexpected args: %q -> %q", db.gotRenameTagName, db.gotRenameTagNewName)
+	}
+}
+
+func TestHandleMergeTags_Success_Returns200AndJSON(t *testing.T) {
+	db := &fakeDBClient{
+		mergeTagsFn: func(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+			return models.TagChange{Name: target, TaskIds: []int{1, 4}}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/tags/merge", strings.NewReader(`{"sources":["job","office"],"target":"work"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleMergeTags(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if !reflect.DeepEqual(db.gotMergeSources, []string{"job", "office"}) || db.gotMergeTarget != "work" {
+		t.Fatalf("unexpected args: %v -> %q", db.gotMergeSources, db.gotMergeTarget)
+	}
+	var got models.TagChange
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if !reflect.DeepEqual(got, models.TagChange{Name: "work", TaskIds: []int{1, 4}}) {
+		t.Fatalf("unexpected change %+v", got)
+	}
+}
+
+func TestHandleCreateProject_Success_Returns201AndJSON(t *testing.T) {
+	db := &fakeDBClient{
+		createProjectFn: func(ctx context.Context, name string) (models.Project, error) {
+			return models.Project{Id: 2, Name: name}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects", strings.NewReader(`{"name":"Work"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleCreateProject(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	if db.gotCreateProjectName != "Work" {
+		t.Fatalf("unexpected name %q", db.gotCreateProjectName)
+	}
+	var got models.Project
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if got.Id != 2 || got.Name != "Work" {
+		t.Fatalf("unexpected project %+v", got)
+	}
+}
+
+func TestHandleCreateProject_NameTaken_Returns409(t *testing.T) {
+	db := &fakeDBClient{
+		createProjectFn: func(ctx context.Context, name string) (models.Project, error) {
+			return models.Project{}, app.ErrAlreadyExists
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects", strings.NewReader(`{"name":"Work"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleCreateProject(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusConflict, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleListProjects_BadArchivedParam_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/projects?archived=maybe", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListProjects(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.listProjectsCalls != 0 {
+		t.Fatalf("expected ListProjects not called, got calls=%d", db.listProjectsCalls)
+	}
+}
+
+func TestHandleListProjects_Success_Returns200AndJSON(t *testing.T) {
+	db := &fakeDBClient{
+		listProjectsFn: func(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+			return []models.Project{{Id: 1, Name: "Inbox", Inbox: true}}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/projects?archived=true", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListProjects(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if !db.gotIncludeArchived {
+		t.Fatalf("expected archived projects to be requested")
+	}
+	var got []models.Project
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if len(got) != 1 || !got[0].Inbox {
+		t.Fatalf("unexpected projects %+v", got)
+	}
+}
+
+func TestHandleArchiveProject_Inbox_Returns409(t *testing.T) {
+	db := &fakeDBClient{
+		archiveProjectFn: func(ctx context.Context, id int, archived bool) (models.Project, error) {
+			return models.Project{}, app.ErrConflict
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/projects/archive", strings.NewReader(`{"Id":1,"archived":true}`))
+	rr := httptest.NewRecorder()
+
+	h.handleArchiveProject(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusConflict, rr.Code, rr.Body.String())
+	}
+	if db.gotArchiveProjectID != 1 || !db.gotArchived {
+		t.Fatalf("unexpected args: id=%d archived=%v", db.gotArchiveProjectID, db.gotArchived)
+	}
+}
+
+func TestHandleDeleteProject_Success_Returns204(t *testing.T) {
+	db := &fakeDBClient{
+		deleteProjectFn: func(ctx context.Context, id int) error {
+			return nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/projects", strings.NewReader(`{"Id":4}`))
+	rr := httptest.NewRecorder()
+
+	h.handleDeleteProject(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+	if db.gotDeleteProjectID != 4 {
+		t.Fatalf("unexpected id %d", db.gotDeleteProjectID)
+	}
+}
+
+func TestHandleMoveTaskToProject_Success_Returns200AndTaskJSON(t *testing.T) {
+	db := &fakeDBClient{
+		taskProjectFn: func(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, ProjectId: projectId}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/project", strings.NewReader(`{"Id":5,"project_id":2}`))
+	rr := httptest.NewRecorder()
+
+	h.handleMoveTaskToProject(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotTaskProjectID != 5 || db.gotProjectID != 2 {
+		t.Fatalf("unexpected args: id=%d project=%d", db.gotTaskProjectID, db.gotProjectID)
+	}
+	var got models.TaskExportData
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if got.ProjectId != 2 {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestHandleGetTaskTree_BadId_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/task?id=abc", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleGetTaskTree(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.taskTreeCalls != 0 {
+		t.Fatalf("expected GetTaskTree not called, got calls=%d", db.taskTreeCalls)
+	}
+}
+
+func TestHandleGetTaskTree_NotFound_Returns404(t *testing.T) {
+	db := &fakeDBClient{
+		taskTreeFn: func(ctx context.Context, id int) (models.TaskTree, error) {
+			return models.TaskTree{}, app.ErrNotFound
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/task?id=9", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleGetTaskTree(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleGetTaskTree_Success_Returns200AndTreeJSON(t *testing.T) {
+	db := &fakeDBClient{
+		taskTreeFn: func(ctx context.Context, id int) (models.TaskTree, error) {
+			return models.TaskTree{
+				Task: models.TaskExportData{Id: id, SubtasksTotal: 2, SubtasksDone: 1},
+				Subtasks: []models.TaskTree{
+					{Task: models.TaskExportData{Id: 6, ParentId: id, Finished: true}},
+					{Task: models.TaskExportData{Id: 7, ParentId: id}},
+				},
+			}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/task?id=5", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleGetTaskTree(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotTaskTreeID != 5 {
+		t.Fatalf("unexpected id %d", db.gotTaskTreeID)
+	}
+	var got models.TaskTree
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if got.Task.SubtasksDone != 1 || len(got.Subtasks) != 2 || got.Subtasks[1].Task.ParentId != 5 {
+		t.Fatalf("unexpected tree %+v", got)
+	}
+}
+
+func TestHandleAddTask_PassesIdempotencyKey(t *testing.T) {
+	db := &fakeDBClient{
+		addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: 9}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/create", strings.NewReader(`{"title":"t"}`))
+	req.Header.Set("Idempotency-Key", "abc")
+	rr := httptest.NewRecorder()
+
+	h.handleAddTask(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	if db.gotAddTask.IdempotencyKey != "abc" {
+		t.Fatalf("expected idempotency key abc, got %q", db.gotAddTask.IdempotencyKey)
+	}
+}
+
+func TestHandleAddTask_IdempotencyKeyReused_Returns422(t *testing.T) {
+	db := &fakeDBClient{
+		addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrIdempotencyKeyReused
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/create", strings.NewReader(`{"title":"other"}`))
+	req.Header.Set("Idempotency-Key", "abc")
+	rr := httptest.NewRecorder()
+
+	h.handleAddTask(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusUnprocessableEntity, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleAddTask_DBServiceDown_ReturnsItsCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "breaker open", err: app.ErrUnavailable, wantCode: http.StatusServiceUnavailable},
+		{name: "deadline exceeded", err: app.ErrTimeout, wantCode: http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDBClient{
+				addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
+					return models.TaskExportData{}, tt.err
+				},
+			}
+			h := NewHttpHandlers(app.NewService(db))
+
+			req := httptest.NewRequest(http.MethodPost, "/create", strings.NewReader(`{"title":"t"}`))
+			rr := httptest.NewRecorder()
+
+			h.handleAddTask(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected code %d, got %d, body=%s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleAddTask_PassesParentId(t *testing.T) {
+	db := &fakeDBClient{
+		addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: 9, ParentId: task.ParentId}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/create", strings.NewReader(`{"title":"step","parent_id":5}`))
+	rr := httptest.NewRecorder()
+
+	h.handleAddTask(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	if db.gotAddTask.ParentId != 5 {
+		t.Fatalf("expected parent id 5, got %d", db.gotAddTask.ParentId)
+	}
+}
+
+func TestHandleAddTask_PassesRecurrence(t *testing.T) {
+	db := &fakeDBClient{
+		addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: 9, Recurrence: task.Recurrence}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/create", strings.NewReader(
+		`{"title":"gym","recurrence":{"frequency":"weekly","interval":1,"weekdays":[1,3,5],"tz":"Europe/Moscow"}}`))
+	rr := httptest.NewRecorder()
+
+	h.handleAddTask(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	want := &models.Recurrence{
+		Frequency: models.RecurrenceWeekly,
+		Interval:  1,
+		Weekdays:  []time.Weekday{time.Monday, time.Wednesday, time.Friday},
+		TimeZone:  "Europe/Moscow",
+	}
+	if !reflect.DeepEqual(db.gotAddTask.Recurrence, want) {
+		t.Fatalf("expected recurrence %+v, got %+v", want, db.gotAddTask.Recurrence)
+	}
+}
+
+func TestHandleAddTask_UnknownFrequency_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/create", strings.NewReader(`{"title":"gym","recurrence":{"frequency":"hourly"}}`))
+	rr := httptest.NewRecorder()
+
+	h.handleAddTask(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.addCalls != 0 {
+		t.Fatalf("expected AddTask not called, got calls=%d", db.addCalls)
+	}
+}
+
+func TestHandleSetTaskRecurrence_NullRecurrence_EndsSeries(t *testing.T) {
+	db := &fakeDBClient{
+		recurrenceFn: func(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/recurrence", strings.NewReader(`{"Id":3,"recurrence":null}`))
+	rr := httptest.NewRecorder()
+
+	h.handleSetTaskRecurrence(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotRecurrenceID != 3 || db.gotRecurrence != nil {
+		t.Fatalf("unexpected args: id=%d rule=%+v", db.gotRecurrenceID, db.gotRecurrence)
+	}
+}
+
+func TestHandleSetTaskRecurrence_BadJSON_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/recurrence", strings.NewReader(`{"Id":3,`))
+	rr := httptest.NewRecorder()
+
+	h.handleSetTaskRecurrence(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.recurrenceCalls != 0 {
+		t.Fatalf("expected SetTaskRecurrence not called, got calls=%d", db.recurrenceCalls)
+	}
+}
+
+func TestHandleSkipOccurrence_Conflict_Returns409(t *testing.T) {
+	db := &fakeDBClient{
+		skipFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrConflict
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/skip", strings.NewReader(`{"Id":6}`))
+	rr := httptest.NewRecorder()
+
+	h.handleSkipOccurrence(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusConflict, rr.Code, rr.Body.String())
+	}
+	if db.gotSkipID != 6 {
+		t.Fatalf("unexpected id %d", db.gotSkipID)
+	}
+}
+
+func TestHandleReopenTask_Success_Returns200AndTaskJSON(t *testing.T) {
+	db := &fakeDBClient{
+		reopenFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/tasks/7/reopen", nil), map[string]string{"id": "7"})
+	rr := httptest.NewRecorder()
+
+	h.handleReopenTask(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotReopenID != 7 {
+		t.Fatalf("unexpected id %d", db.gotReopenID)
+	}
+	var got models.TaskExportData
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if got.Id != 7 || got.Finished {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestHandleReopenTask_BadId_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/tasks/abc/reopen", nil), map[string]string{"id": "abc"})
+	rr := httptest.NewRecorder()
+
+	h.handleReopenTask(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.reopenCalls != 0 {
+		t.Fatalf("expected ReopenTask not called, got calls=%d", db.reopenCalls)
+	}
+}
+
+func TestHandleReopenTask_NotFound_Returns404(t *testing.T) {
+	db := &fakeDBClient{
+		reopenFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrNotFound
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/tasks/7/reopen", nil), map[string]string{"id": "7"})
+	rr := httptest.NewRecorder()
+
+	h.handleReopenTask(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleDeleteTask_NotFound_Returns404(t *testing.T) {
+	db := &fakeDBClient{
+		removeFn: func(ctx context.Context, id int) error {
+			return app.ErrNotFound
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/delete", strings.NewReader(`{"Id":1}`))
+	rr := httptest.NewRecorder()
+
+	h.handleDeleteTask(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleListTrash_Success_Returns200AndTasksJSON(t *testing.T) {
+	deletedAtTS := time.Date(2025, 12, 11, 1, 2, 3, 0, time.UTC)
+	db := &fakeDBClient{
+		listTrashFn: func(ctx context.Context) ([]models.TaskExportData, error) {
+			return []models.TaskExportData{{Id: 3, DeletedAt: &deletedAtTS}}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleListTrash(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var got []models.TaskExportData
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if len(got) != 1 || got[0].DeletedAt == nil || !got[0].DeletedAt.Equal(deletedAtTS) {
+		t.Fatalf("unexpected trash %+v", got)
+	}
+}
+
+func TestHandleRestoreTask_Success_Returns200AndTaskJSON(t *testing.T) {
+	db := &fakeDBClient{
+		restoreFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/restore", strings.NewReader(`{"Id":3}`))
+	rr := httptest.NewRecorder()
+
+	h.handleRestoreTask(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotRestoreID != 3 {
+		t.Fatalf("unexpected id %d", db.gotRestoreID)
+	}
+}
+
+func TestHandleRestoreTask_Conflict_Returns409(t *testing.T) {
+	db := &fakeDBClient{
+		restoreFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrConflict
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/restore", strings.NewReader(`{"Id":3}`))
+	rr := httptest.NewRecorder()
+
+	h.handleRestoreTask(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusConflict, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandlePurgeTask_Success_Returns204(t *testing.T) {
+	db := &fakeDBClient{
+		purgeFn: func(ctx context.Context, id int) error {
+			return nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/trash", strings.NewReader(`{"Id":3}`))
+	rr := httptest.NewRecorder()
+
+	h.handlePurgeTask(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+	if db.gotPurgeID != 3 {
+		t.Fatalf("unexpected id %d", db.gotPurgeID)
+	}
+}
+
+func TestHandlePurgeTask_BadJSON_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/trash", strings.NewReader(`{"Id":`))
+	rr := httptest.NewRecorder()
+
+	h.handlePurgeTask(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.purgeCalls != 0 {
+		t.Fatalf("expected PurgeTask not called, got calls=%d", db.purgeCalls)
+	}
+}
+
+func TestHandleGetTaskHistory_BadParams_Returns400_AndDoesNotCallDB(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		query string
+	}{
+		{name: "bad id", id: "abc"},
+		{name: "bad limit", id: "3", query: "?limit=many"},
+		{name: "zero limit", id: "3", query: "?limit=0"},
+		{name: "negative cursor", id: "3", query: "?cursor=-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDBClient{}
+			svc := app.NewService(db)
+			h := NewHttpHandlers(svc)
+
+			req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/tasks/"+tt.id+"/history"+tt.query, nil), map[string]string{"id": tt.id})
+			rr := httptest.NewRecorder()
+
+			h.handleGetTaskHistory(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+			}
+			if db.historyCalls != 0 {
+				t.Fatalf("expected GetTaskHistory not called, got calls=%d", db.historyCalls)
+			}
+		})
+	}
+}
+
+func TestHandleGetTaskHistory_NotFound_Returns404(t *testing.T) {
+	db := &fakeDBClient{
+		historyFn: func(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+			return models.TaskHistoryPage{}, app.ErrNotFound
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/tasks/3/history", nil), map[string]string{"id": "3"})
+	rr := httptest.NewRecorder()
+
+	h.handleGetTaskHistory(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleGetTaskHistory_Success_Returns200AndHistoryJSON(t *testing.T) {
+	changedAtTS := time.Date(2025, 12, 2, 10, 0, 0, 0, time.UTC)
+	db := &fakeDBClient{
+		historyFn: func(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+			return models.TaskHistoryPage{
+				Changes: []models.TaskChange{
+					{Id: 12, TaskId: 3, ChangedAt: changedAtTS, ChangedBy: "alice", Field: "title", OldValue: `"Buy bread"`, NewValue: `"Buy milk"`},
+					{Id: 11, TaskId: 3, ChangedAt: changedAtTS, ChangedBy: "alice", Field: "tags", OldValue: `"home"`},
+				},
+				NextCursor: 11,
+			}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/tasks/3/history?limit=2&cursor=20", nil), map[string]string{"id": "3"})
+	rr := httptest.NewRecorder()
+
+	h.handleGetTaskHistory(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	wantReq := models.TaskHistoryRequest{TaskId: 3, Limit: 2, Cursor: 20}
+	if db.gotHistoryReq != wantReq {
+		t.Fatalf("expected request %+v, got %+v", wantReq, db.gotHistoryReq)
+	}
+	var got struct {
+		TaskId  int `json:"task_id"`
+		Changes []struct {
+			Field    string  `json:"field"`
+			OldValue *string `json:"old_value"`
+			NewValue *string `json:"new_value"`
+		} `json:"changes"`
+		NextCursor int `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json response: %v, body=%s", err, rr.Body.String())
+	}
+	if got.TaskId != 3 || got.NextCursor != 11 || len(got.Changes) != 2 {
+		t.Fatalf("unexpected history response %s", rr.Body.String())
+	}
+	if *got.Changes[0].OldValue != "Buy bread" || *got.Changes[0].NewValue != "Buy milk" {
+		t.Fatalf("unexpected title change %s", rr.Body.String())
+	}
+	if got.Changes[1].NewValue != nil {
+		t.Fatalf("expected no new value for removed tag, body=%s", rr.Body.String())
+	}
+}
+
+func TestActorMiddleware_PutsActorIntoContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "actor header", header: "alice", want: "alice"},
+		{name: "no header", header: "", want: "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = app.ActorFrom(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/list", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Actor", tt.header)
+			}
+
+			actorMiddleware(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Fatalf("expected actor %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "no header", value: "", want: 0},
+		{name: "any version", value: "*", want: 0},
+		{name: "task etag", value: `"7"`, want: 7},
+		{name: "unquoted", value: "7", wantErr: true},
+		{name: "weak etag", value: `W/"7"`, wantErr: true},
+		{name: "several etags", value: `"7", "8"`, wantErr: true},
+		{name: "zero version", value: `"0"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseIfMatch(tt.value)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIfMatchMiddleware_PutsVersionIntoContext(t *testing.T) {
+	var got int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = app.ExpectedVersionFrom(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/priority", nil)
+	req.Header.Set("If-Match", `"5"`)
+	rr := httptest.NewRecorder()
+
+	ifMatchMiddleware(next).ServeHTTP(rr, req)
+
+	if got != 5 {
+		t.Fatalf("expected version 5, got %d", got)
+	}
+}
+
+func TestIfMatchMiddleware_BadHeader_Returns400(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/priority", nil)
+	req.Header.Set("If-Match", "five")
+	rr := httptest.NewRecorder()
+
+	ifMatchMiddleware(next).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if called {
+		t.Fatal("expected handler not called")
+	}
+}
+
+func TestHandleSetTaskPriority_Success_SetsETag(t *testing.T) {
+	db := &fakeDBClient{
+		priorityFn: func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: id, Priority: priority, Version: 4}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/priority", strings.NewReader(`{"Id":3,"priority":"low"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleSetTaskPriority(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("ETag"); got != `"4"` {
+		t.Fatalf("expected ETag %q, got %q", `"4"`, got)
+	}
+}
+
+func TestHandleSetTaskPriority_VersionMismatch_Returns412(t *testing.T) {
+	db := &fakeDBClient{
+		priorityFn: func(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrPreconditionFailed
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPut, "/priority", strings.NewReader(`{"Id":3,"priority":"low"}`))
+	rr := httptest.NewRecorder()
+
+	h.handleSetTaskPriority(rr, req)
+
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusPreconditionFailed, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleGetTaskTree_IfNoneMatch_Returns304(t *testing.T) {
+	tree := models.TaskTree{Task: models.TaskExportData{Id: 5, Version: 3}}
+	etag := treeETag(tree)
+	overdueETag := treeETag(models.TaskTree{Task: models.TaskExportData{Id: 5, Version: 3, Overdue: true}})
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantCode    int
+	}{
+		{name: "current etag", ifNoneMatch: etag, wantCode: http.StatusNotModified},
+		{name: "one of etags", ifNoneMatch: `"1", ` + etag, wantCode: http.StatusNotModified},
+		{name: "version etag", ifNoneMatch: `"3"`, wantCode: http.StatusOK},
+		{name: "etag before it became overdue", ifNoneMatch: overdueETag, wantCode: http.StatusOK},
+		{name: "no header", ifNoneMatch: "", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDBClient{
+				taskTreeFn: func(ctx context.Context, id int) (models.TaskTree, error) {
+					return tree, nil
+				},
+			}
+			svc := app.NewService(db)
+			h := NewHttpHandlers(svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/task?id=5", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rr := httptest.NewRecorder()
+
+			h.handleGetTaskTree(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected code %d, got %d, body=%s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if got := rr.Header().Get("ETag"); got != etag {
+				t.Fatalf("expected ETag %q, got %q", etag, got)
+			}
+			if tt.wantCode == http.StatusNotModified && rr.Body.Len() != 0 {
+				t.Fatalf("expected empty body, got %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestListETag_ChangesWithTasks(t *testing.T) {
+	tasks := []models.TaskExportData{{Id: 1, Version: 2}, {Id: 2, Version: 1}}
+	etag := listETag(tasks)
+
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("expected weak etag, got %s", etag)
+	}
+	if listETag(tasks) != etag {
+		t.Fatal("expected the same etag for the same tasks")
+	}
+	if listETag([]models.TaskExportData{{Id: 1, Version: 3}, {Id: 2, Version: 1}}) == etag {
+		t.Fatal("expected another etag after a task changed")
+	}
+	if listETag(tasks[:1]) == etag {
+		t.Fatal("expected another etag after a task left the list")
+	}
+
+	tree := models.TaskTree{Task: tasks[0], Subtasks: []models.TaskTree{{Task: tasks[1]}}}
+	if treeETag(tree) != etag {
+		t.Fatalf("expected tree etag %s, got %s", etag, treeETag(tree))
+	}
+}
+
+func TestHandleBatch_Create_ReturnsResultPerItem(t *testing.T) {
+	db := &fakeDBClient{
+		batchAddFn: func(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+			return []models.BatchItem{
+				{Id: 5, Task: models.TaskExportData{Id: 5, Title: tasks[0].Title}},
+				{Err: fmt.Errorf("%w: parent task", app.ErrNotFound)},
+			}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks:batch",
+		strings.NewReader(`{"action":"create","tasks":[{"title":"a","priority":"high"},{"title":"b","parent_id":9}]}`))
+	rr := httptest.NewRecorder()
+
+	h.handleBatch(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if len(db.gotBatchTasks) != 2 || db.gotBatchTasks[0].Priority != models.PriorityHigh || db.gotBatchTasks[1].ParentId != 9 {
+		t.Fatalf("unexpected tasks %+v", db.gotBatchTasks)
+	}
+	var got BatchResultDTO
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json: %v, body=%s", err, rr.Body.String())
+	}
+	if len(got.Results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", got)
+	}
+	if got.Results[0].Status != http.StatusCreated || got.Results[0].Task == nil || got.Results[0].Task.Title != "a" {
+		t.Fatalf("unexpected first result %+v", got.Results[0])
+	}
+	if got.Results[1].Status != http.StatusNotFound || got.Results[1].Error == "" || got.Results[1].Task != nil {
+		t.Fatalf("unexpected second result %+v", got.Results[1])
+	}
+}
+
+func TestHandleBatch_Delete_PassesIds(t *testing.T) {
+	db := &fakeDBClient{
+		batchRemoveFn: func(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+			return []models.BatchItem{{Id: ids[0]}, {Id: ids[1]}}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks:batch", strings.NewReader(`{"action":"delete","ids":[3,4]}`))
+	rr := httptest.NewRecorder()
+
+	h.handleBatch(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if len(db.gotBatchRemoveIds) != 2 || db.gotBatchRemoveIds[0] != 3 || db.gotBatchRemoveIds[1] != 4 {
+		t.Fatalf("unexpected ids %v", db.gotBatchRemoveIds)
+	}
+	if !strings.Contains(rr.Body.String(), `"status": 200`) {
+		t.Fatalf("expected item status 200, body=%s", rr.Body.String())
+	}
+}
+
+func TestHandleBatch_UnknownAction_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks:batch", strings.NewReader(`{"action":"archive","ids":[1]}`))
+	rr := httptest.NewRecorder()
+
+	h.handleBatch(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if db.batchAddCalls+db.batchFinishCalls+db.batchRemoveCalls != 0 {
+		t.Fatalf("expected no batch calls")
+	}
+}
+
+func TestHandleBatch_ServiceError_ReturnsStatus(t *testing.T) {
+	db := &fakeDBClient{
+		batchFinishFn: func(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+			return nil, fmt.Errorf("%w: batch must have 1 to 500 items", app.ErrInvalidArgument)
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks:batch", strings.NewReader(`{"action":"done","ids":[]}`))
+	rr := httptest.NewRecorder()
+
+	h.handleBatch(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandleSearchTasks_Success_Returns200AndHits(t *testing.T) {
+	db := &fakeDBClient{
+		searchFn: func(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+			return []models.SearchHit{{Task: models.TaskExportData{Id: 2, Title: "Buy milk"}, Rank: 0.5, TitleSnippet: "Buy <b>milk</b>"}}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=+mil+&limit=5", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleSearchTasks(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if db.gotSearch.Query != "mil" || db.gotSearch.Limit != 5 {
+		t.Fatalf("unexpected request %+v", db.gotSearch)
+	}
+	var got SearchResultDTO
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json: %v, body=%s", err, rr.Body.String())
+	}
+	if got.Query != "mil" || len(got.Hits) != 1 || got.Hits[0].Task.Id != 2 || got.Hits[0].TitleSnippet != "Buy <b>milk</b>" {
+		t.Fatalf("unexpected result %+v", got)
+	}
+}
+
+func TestHandleSearchTasks_BadParams_Returns400_AndDoesNotCallDB(t *testing.T) {
+	for _, target := range []string{"/search", "/search?q=+", "/search?q=milk&limit=0", "/search?q=" + strings.Repeat("a", maxSearchQueryLength+1)} {
+		db := &fakeDBClient{}
+		svc := app.NewService(db)
+		h := NewHttpHandlers(svc)
+
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rr := httptest.NewRecorder()
+
+		h.handleSearchTasks(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected code %d, got %d, body=%s", target, http.StatusBadRequest, rr.Code, rr.Body.String())
+		}
+		if db.searchCalls != 0 {
+			t.Fatalf("%s: expected SearchTasks not called", target)
+		}
+	}
+}
+
+func TestHandleWatchTasks_StreamsEventsWithIds(t *testing.T) {
+	db := &fakeDBClient{
+		watchFn: func(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+			if err := send(models.TaskEvent{Id: 8, Type: models.TaskEventFinished, TaskId: 2, ProjectId: 1, Version: 3}); err != nil {
+				return err
+			}
+			return send(models.TaskEvent{Type: models.TaskEventReset})
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?project=1", nil)
+	req.Header.Set("Last-Event-ID", "7")
+	rr := httptest.NewRecorder()
+
+	h.handleWatchTasks(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+	if db.gotWatch != (models.WatchRequest{ProjectId: 1, LastEventId: 7}) {
+		t.Fatalf("unexpected request %+v", db.gotWatch)
+	}
+	want := "retry: 3000\n\n" +
+		"id: 8\nevent: finished\ndata: {\"id\":8,\"type\":\"finished\",\"task_id\":2,\"project_id\":1,\"version\":3}\n\n" +
+		"event: reset\ndata: {\"type\":\"reset\"}\n\n"
+	if rr.Body.String() != want {
+		t.Fatalf("unexpected body:\n%q\nwant:\n%q", rr.Body.String(), want)
+	}
+}
+
+func TestHandleWatchTasks_BadParams_Returns400_AndDoesNotCallDB(t *testing.T) {
+	for _, target := range []string{"/events?project=0", "/events?project=x", "/events?last_event_id=-1"} {
+		db := &fakeDBClient{}
+		svc := app.NewService(db)
+		h := NewHttpHandlers(svc)
+
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rr := httptest.NewRecorder()
+
+		h.handleWatchTasks(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected code %d, got %d, body=%s", target, http.StatusBadRequest, rr.Code, rr.Body.String())
+		}
+		if db.watchCalls != 0 {
+			t.Fatalf("%s: expected WatchTasks not called", target)
+		}
+	}
+}
+
+func TestParseWSTokens(t *testing.T) {
+	got, err := parseWSTokens(" desktop:t1, alice:t2 ,")
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	want := map[string]string{"t1": "desktop", "t2": "alice"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+
+	if _, err := parseWSTokens("desktop"); err == nil {
+		t.Fatalf("expected an error for a pair without a token")
+	}
+}
+
+func TestHandleWebSocket_UnknownToken_Returns401_AndDoesNotCallDB(t *testing.T) {
+	for _, header := range []string{"", "Bearer wrong", "Basic t1"} {
+		db := &fakeDBClient{}
+		h := NewHttpHandlers(app.NewService(db))
+		h.SetWebSocketTokens(map[string]string{"t1": "desktop"})
+
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header.Set("Authorization", header)
+		rr := httptest.NewRecorder()
+
+		h.handleWebSocket(rr, req)
+
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("%q: expected code %d, got %d, body=%s", header, http.StatusUnauthorized, rr.Code, rr.Body.String())
+		}
+		if db.watchCalls != 0 {
+			t.Fatalf("%q: expected WatchTasks not called", header)
+		}
+	}
+}
+
+// dialWS connects to handleWebSocket served by h with the token of actor
+// "desktop"; the events stream sends events and then waits for the end of
+// the connection.
+func dialWS(t *testing.T, db *fakeDBClient, events ...models.TaskEvent) *websocket.Conn {
+	t.Helper()
+
+	db.watchFn = func(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+		for _, event := range events {
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+		<-ctx.Done()
+		return nil
+	}
+	h := NewHttpHandlers(app.NewService(db))
+	h.SetWebSocketTokens(map[string]string{"t1": "desktop"})
+	server := httptest.NewServer(http.HandlerFunc(h.handleWebSocket))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?access_token=t1&project=1", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func exchangeWS(t *testing.T, conn *websocket.Conn, req string) WSMessageDTO {
+	t.Helper()
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var msg WSMessageDTO
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return msg
+}
+
+func TestHandleWebSocket_PushesEventsAndAcksRequests(t *testing.T) {
+	actors := make(chan string, 1)
+	db := &fakeDBClient{
+		addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
+			actors <- app.ActorFrom(ctx)
+			return models.TaskExportData{Id: 5, Title: task.Title}, nil
+		},
+		doneFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{}, fmt.Errorf("%w: task %d", app.ErrNotFound, id)
+		},
+	}
+	conn := dialWS(t, db, models.TaskEvent{Id: 8, Type: models.TaskEventDeleted, TaskId: 2, ProjectId: 1})
+
+	var event WSMessageDTO
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if event.Type != "event" || event.Event == nil || event.Event.Id != 8 || event.Event.Type != "deleted" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	created := exchangeWS(t, conn, `{"id":"c1","type":"create","task":{"title":"Buy milk"}}`)
+	if created.Type != "ack" || created.Id != "c1" || created.Status != http.StatusCreated || created.Task == nil || created.Task.Id != 5 {
+		t.Fatalf("unexpected create ack %+v", created)
+	}
+	if actor := <-actors; actor != "desktop" {
+		t.Fatalf("expected the actor of the token, got %q", actor)
+	}
+
+	tests := []struct {
+		req        string
+		wantId     string
+		wantStatus int
+	}{
+		{req: `{"id":"c2","type":"done","task_id":7}`, wantId: "c2", wantStatus: http.StatusNotFound},
+		{req: `{"id":"c3","type":"done"}`, wantId: "c3", wantStatus: http.StatusBadRequest},
+		{req: `{"id":"c4","type":"rename","task_id":7}`, wantId: "c4", wantStatus: http.StatusBadRequest},
+		{req: `{"id":`, wantId: "", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		got := exchangeWS(t, conn, tt.req)
+		if got.Type != "error" || got.Id != tt.wantId || got.Status != tt.wantStatus || got.Error == "" {
+			t.Fatalf("%s: unexpected answer %+v", tt.req, got)
+		}
+	}
+}
+
+func TestHandleWebSocket_RateLimit_RejectsExtraRequests(t *testing.T) {
+	db := &fakeDBClient{
+		removeFn: func(ctx context.Context, id int) error { return nil },
+	}
+	conn := dialWS(t, db)
+
+	limited := 0
+	for i := range wsRateBurst + 5 {
+		got := exchangeWS(t, conn, fmt.Sprintf(`{"id":"d%d","type":"delete","task_id":1}`, i))
+		if got.Status == http.StatusTooManyRequests {
+			limited++
+		}
+	}
+
+	if limited == 0 {
+		t.Fatalf("expected some of %d requests to be rate limited", wsRateBurst+5)
+	}
+}
+
+func TestHandleGetChanges_Success_Returns200AndChanges(t *testing.T) {
+	db := &fakeDBClient{
+		changesFn: func(ctx context.Context, token string, limit int) (models.Changes, error) {
+			if token != "abc" || limit != 20 {
+				t.Fatalf("unexpected token %q, limit %d", token, limit)
+			}
+			return models.Changes{Tasks: []models.TaskExportData{{Id: 2}}, DeletedIds: []int{3}, Token: "def", HasMore: true}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/sync?since=abc&limit=20", nil)
+	rr := httptest.NewRecorder()
+
+	h.handleGetChanges(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var got SyncChangesDTO
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json: %v, body=%s", err, rr.Body.String())
+	}
+	if len(got.Tasks) != 1 || len(got.DeletedIds) != 1 || got.Token != "def" || !got.HasMore {
+		t.Fatalf("unexpected changes %+v", got)
+	}
+}
+
+func TestHandleGetChanges_BadLimit_Returns400_AndDoesNotCallDB(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	for _, target := range []string{"/sync?limit=0", "/sync?limit=x"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rr := httptest.NewRecorder()
+
+		h.handleGetChanges(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected code %d, got %d", target, http.StatusBadRequest, rr.Code)
+		}
+	}
+	if db.changesCalls != 0 {
+		t.Fatalf("expected GetChangesSince not called, got %d calls", db.changesCalls)
+	}
+}
+
+func TestHandlePushChanges_Success_ReturnsResultPerMutation(t *testing.T) {
+	db := &fakeDBClient{
+		addFn: func(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
+			return models.TaskExportData{Id: 7, Title: task.Title}, nil
+		},
+		doneFn: func(ctx context.Context, id int) (models.TaskExportData, error) {
+			return models.TaskExportData{}, app.ErrPreconditionFailed
+		},
+		taskTreeFn: func(ctx context.Context, id int) (models.TaskTree, error) {
+			return models.TaskTree{Task: models.TaskExportData{Id: id, Version: 4}}, nil
+		},
+	}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	body := `{"conflict":"version","mutations":[
+		{"id":"m1","type":"create","task":{"title":"Buy milk"}},
+		{"id":"m2","type":"done","task_id":3,"version":2},
+		{"id":"m3","type":"create"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/sync", strings.NewReader(body))
+	req.Header.Set("X-Actor", "alice")
+	rr := httptest.NewRecorder()
+
+	actorMiddleware(http.HandlerFunc(h.handlePushChanges)).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected code %d, got %d, body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var got SyncPushResultDTO
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("bad json: %v, body=%s", err, rr.Body.String())
+	}
+	if len(got.Results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", got.Results)
+	}
+	if r := got.Results[0]; r.Id != "m1" || r.Status != http.StatusCreated || r.Task == nil || r.Task.Id != 7 {
+		t.Fatalf("unexpected create result %+v", r)
+	}
+	if r := got.Results[1]; r.Status != http.StatusPreconditionFailed || r.Current == nil || r.Current.Version != 4 || r.Error == "" {
+		t.Fatalf("unexpected conflict result %+v", r)
+	}
+	if r := got.Results[2]; r.Status != http.StatusBadRequest || r.Error == "" {
+		t.Fatalf("unexpected result for create without task %+v", r)
+	}
+	if db.gotAddTask.IdempotencyKey != "sync:alice:m1" {
+		t.Fatalf("expected idempotency key sync:alice:m1, got %q", db.gotAddTask.IdempotencyKey)
+	}
+}
+
+func TestHandlePushChanges_BadRequest_Returns400(t *testing.T) {
+	db := &fakeDBClient{}
+	svc := app.NewService(db)
+	h := NewHttpHandlers(svc)
+
+	for _, body := range []string{
+		`{"conflict":"newest","mutations":[{"id":"m1","type":"done","task_id":1}]}`,
+		`{"mutations":[]}`,
+		`{"mutations":`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/sync", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+
+		h.handlePushChanges(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected code %d, got %d", body, http.StatusBadRequest, rr.Code)
+		}
+	}
+	if db.doneCalls != 0 {
+		t.Fatalf("expected MarkTaskFinished not called, got %d calls", db.doneCalls)
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
+)
+
+const (
+	actorHeader  = "X-Actor"
+	defaultActor = "anonymous"
+)
+
+// actorMiddleware takes the author of the request from the X-Actor header;
+// it ends up in the task history.
+func actorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := r.Header.Get(actorHeader)
+		if actor == "" {
+			actor = defaultActor
+		}
+		next.ServeHTTP(w, r.WithContext(app.WithActor(r.Context(), actor)))
+	})
+}
+
+// ifMatchMiddleware passes the task version from If-Match to the service,
+// which rejects the change with 412 when the task has another version.
+func ifMatchMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			errorDTO := NewErrorDTO(err.Error())
+			http.Error(w, errorDTO.ToString(), http.StatusBadRequest)
+			return
+		}
+		if version != 0 {
+			r = r.WithContext(app.WithExpectedVersion(r.Context(), version))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,289 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+	"github.com/gorilla/mux"
+)
+
+const dateLayout = "2006-01-02"
+
+// parseTimeParam accepts either an RFC 3339 timestamp or a plain date,
+// which is interpreted as midnight in loc.
+func parseTimeParam(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, value, loc)
+	if err != nil {
+		return time.Time{}, errors.New("expected RFC 3339 timestamp or YYYY-MM-DD date, got " + value)
+	}
+	return t, nil
+}
+
+// parseListParam accepts both ?tag=a&tag=b and ?tag=a,b and drops empty items.
+func parseListParam(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+func parseLocationParam(value string) (*time.Location, error) {
+	if value == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, errors.New("unknown time zone " + value)
+	}
+	return loc, nil
+}
+
+/*
+query parameters:
+  - from, to: RFC 3339 timestamp or YYYY-MM-DD date, range is [from, to)
+  - bucket: day (default) or week
+  - tz: IANA time zone used for bucket boundaries and dates, UTC by default
+*/
+func parseStatsRequest(r *http.Request) (models.StatsRequest, error) {
+	query := r.URL.Query()
+
+	loc, err := parseLocationParam(query.Get("tz"))
+	if err != nil {
+		return models.StatsRequest{}, err
+	}
+
+	req := models.StatsRequest{TimeZone: loc.String()}
+
+	switch query.Get("bucket") {
+	case "", "day":
+		req.Bucket = models.StatsBucketDay
+	case "week":
+		req.Bucket = models.StatsBucketWeek
+	default:
+		return models.StatsRequest{}, errors.New("bucket must be day or week")
+	}
+
+	if req.From, err = parseTimeParam(query.Get("from"), loc); err != nil {
+		return models.StatsRequest{}, err
+	}
+	if req.To, err = parseTimeParam(query.Get("to"), loc); err != nil {
+		return models.StatsRequest{}, err
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return models.StatsRequest{}, errors.New("from must be before to")
+	}
+
+	return req, nil
+}
+
+/*
+query parameters:
+  - due: overdue, today or week; omitted means any due date
+  - tz: IANA time zone that defines "today" and "this week", UTC by default
+  - sort: id (default), priority (most urgent first) or position (manual order)
+  - tag: repeated or comma-separated tag names
+  - tag_match: any (default, task has at least one of the tags) or all
+  - project: project ID; omitted means tasks of all projects
+*/
+func parseTaskFilter(r *http.Request) (models.TaskFilter, error) {
+	query := r.URL.Query()
+
+	loc, err := parseLocationParam(query.Get("tz"))
+	if err != nil {
+		return models.TaskFilter{}, err
+	}
+
+	filter := models.TaskFilter{TimeZone: loc.String()}
+
+	switch query.Get("due") {
+	case "":
+		filter.Due = models.DueFilterAny
+	case "overdue":
+		filter.Due = models.DueFilterOverdue
+	case "today":
+		filter.Due = models.DueFilterToday
+	case "week":
+		filter.Due = models.DueFilterThisWeek
+	default:
+		return models.TaskFilter{}, errors.New("due must be overdue, today or week")
+	}
+
+	switch query.Get("sort") {
+	case "", "id":
+		filter.Sort = models.TaskSortId
+	case "priority":
+		filter.Sort = models.TaskSortPriority
+	case "position":
+		filter.Sort = models.TaskSortPosition
+	default:
+		return models.TaskFilter{}, errors.New("sort must be id, priority or position")
+	}
+
+	filter.Tags = parseListParam(query["tag"])
+
+	switch query.Get("tag_match") {
+	case "", "any":
+		filter.TagMatch = models.TagMatchAny
+	case "all":
+		filter.TagMatch = models.TagMatchAll
+	default:
+		return models.TaskFilter{}, errors.New("tag_match must be any or all")
+	}
+
+	if project := query.Get("project"); project != "" {
+		projectId, err := strconv.Atoi(project)
+		if err != nil || projectId <= 0 {
+			return models.TaskFilter{}, errors.New("project must be a positive project ID")
+		}
+		filter.ProjectId = projectId
+	}
+
+	return filter, nil
+}
+
+/*
+query parameters:
+  - archived: true to include archived projects, false by default
+*/
+func parseIncludeArchived(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("archived")
+	if value == "" {
+		return false, nil
+	}
+	includeArchived, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("archived must be true or false")
+	}
+	return includeArchived, nil
+}
+
+/*
+query parameters:
+  - limit: page size, 50 by default
+  - cursor: next_cursor of the previous page; omitted means the newest changes
+*/
+func parseTaskHistoryRequest(r *http.Request) (models.TaskHistoryRequest, error) {
+	id, err := parsePathTaskId(r)
+	if err != nil {
+		return models.TaskHistoryRequest{}, err
+	}
+
+	req := models.TaskHistoryRequest{TaskId: id}
+	query := r.URL.Query()
+
+	if limit := query.Get("limit"); limit != "" {
+		if req.Limit, err = strconv.Atoi(limit); err != nil || req.Limit <= 0 {
+			return models.TaskHistoryRequest{}, errors.New("limit must be a positive number")
+		}
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		if req.Cursor, err = strconv.Atoi(cursor); err != nil || req.Cursor <= 0 {
+			return models.TaskHistoryRequest{}, errors.New("cursor must be a next_cursor value")
+		}
+	}
+
+	return req, nil
+}
+
+// maxSearchQueryLength keeps type-ahead requests small; the db-service
+// limits the number of words.
+const maxSearchQueryLength = 200
+
+func parseSearchRequest(r *http.Request) (models.SearchRequest, error) {
+	query := r.URL.Query()
+	req := models.SearchRequest{Query: strings.TrimSpace(query.Get("q"))}
+
+	if req.Query == "" {
+		return models.SearchRequest{}, errors.New("q must not be empty")
+	}
+	if len(req.Query) > maxSearchQueryLength {
+		return models.SearchRequest{}, fmt.Errorf("q must be at most %d bytes long", maxSearchQueryLength)
+	}
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		if req.Limit, err = strconv.Atoi(limit); err != nil || req.Limit <= 0 {
+			return models.SearchRequest{}, errors.New("limit must be a positive number")
+		}
+	}
+
+	return req, nil
+}
+
+// parseWatchRequest reads the project to watch and the event to resume
+// after. Browsers send the Last-Event-ID header when they reconnect; the
+// last_event_id parameter lets a new page resume as well.
+func parseWatchRequest(r *http.Request) (models.WatchRequest, error) {
+	query := r.URL.Query()
+	var req models.WatchRequest
+
+	if project := query.Get("project"); project != "" {
+		projectId, err := strconv.Atoi(project)
+		if err != nil || projectId <= 0 {
+			return models.WatchRequest{}, errors.New("project must be a positive project ID")
+		}
+		req.ProjectId = projectId
+	}
+
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = query.Get("last_event_id")
+	}
+	if lastEventId != "" {
+		id, err := strconv.Atoi(lastEventId)
+		if err != nil || id < 0 {
+			return models.WatchRequest{}, errors.New("last event ID must be a non-negative number")
+		}
+		req.LastEventId = id
+	}
+
+	return req, nil
+}
+
+// parseChangesRequest reads the sync token and the optional page size;
+// db-service checks the token itself.
+func parseChangesRequest(r *http.Request) (string, int, error) {
+	query := r.URL.Query()
+
+	limit := 0
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		var err error
+		if limit, err = strconv.Atoi(rawLimit); err != nil || limit <= 0 {
+			return "", 0, errors.New("limit must be a positive number")
+		}
+	}
+
+	return query.Get("since"), limit, nil
+}
+
+func parseTaskId(r *http.Request) (int, error) {
+	return taskIdFromString(r.URL.Query().Get("id"))
+}
+
+// parsePathTaskId reads the {id} segment of routes like /tasks/{id}/reopen.
+func parsePathTaskId(r *http.Request) (int, error) {
+	return taskIdFromString(mux.Vars(r)["id"])
+}
+
+func taskIdFromString(raw string) (int, error) {
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return 0, errors.New("id must be a positive task ID")
+	}
+	return id, nil
+}
@@ -18,12 +18,49 @@ func NewHttpServer(service *app.Service) *HttpServer {
 }
 
 func (s *HttpServer) StartServer() error {
-	router := mux.NewRouter()
+	wsTokens, err := parseWSTokens(os.Getenv("WS_TOKENS"))
+	if err != nil {
+		return err
+	}
+	s.httpHandlers.SetWebSocketTokens(wsTokens)
 
+	router := mux.NewRouter()
+	router.Use(actorMiddleware)
+	// The routes that change a single task honour If-Match through
+	// ifMatchMiddleware.
 	router.Path("/create").Methods("POST").HandlerFunc(s.httpHandlers.handleAddTask)
+	router.Path("/task").Methods("GET").HandlerFunc(s.httpHandlers.handleGetTaskTree)
 	router.Path("/list").Methods("GET").HandlerFunc(s.httpHandlers.handleListAllTasks)
-	router.Path("/delete").Methods("DELETE").HandlerFunc(s.httpHandlers.handleDeleteTask)
-	router.Path("/done").Methods("PUT").HandlerFunc(s.httpHandlers.handleFinishTask)
+	router.Path("/tasks").Methods("GET").HandlerFunc(s.httpHandlers.handleListTasks)
+	router.Path("/search").Methods("GET").HandlerFunc(s.httpHandlers.handleSearchTasks)
+	router.Path("/events").Methods("GET").HandlerFunc(s.httpHandlers.handleWatchTasks)
+	router.Path("/ws").Methods("GET").HandlerFunc(s.httpHandlers.handleWebSocket)
+	router.Path("/sync").Methods("GET").HandlerFunc(s.httpHandlers.handleGetChanges)
+	router.Path("/sync").Methods("POST").HandlerFunc(s.httpHandlers.handlePushChanges)
+	router.Path("/tasks:batch").Methods("POST").HandlerFunc(s.httpHandlers.handleBatch)
+	router.Path("/delete").Methods("DELETE").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleDeleteTask)))
+	router.Path("/trash").Methods("GET").HandlerFunc(s.httpHandlers.handleListTrash)
+	router.Path("/trash").Methods("DELETE").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handlePurgeTask)))
+	router.Path("/restore").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleRestoreTask)))
+	router.Path("/done").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleFinishTask)))
+	router.Path("/tasks/{id}/reopen").Methods("POST").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleReopenTask)))
+	router.Path("/tasks/{id}/history").Methods("GET").HandlerFunc(s.httpHandlers.handleGetTaskHistory)
+	router.Path("/priority").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleSetTaskPriority)))
+	router.Path("/move").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleMoveTask)))
+	router.Path("/recurrence").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleSetTaskRecurrence)))
+	router.Path("/skip").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleSkipOccurrence)))
+	router.Path("/tags").Methods("GET").HandlerFunc(s.httpHandlers.handleListTags)
+	router.Path("/tags/add").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleAddTaskTags)))
+	router.Path("/tags/remove").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleRemoveTaskTags)))
+	router.Path("/tags/rename").Methods("PUT").HandlerFunc(s.httpHandlers.handleRenameTag)
+	router.Path("/tags/merge").Methods("PUT").HandlerFunc(s.httpHandlers.handleMergeTags)
+	router.Path("/project").Methods("PUT").Handler(ifMatchMiddleware(http.HandlerFunc(s.httpHandlers.handleMoveTaskToProject)))
+	router.Path("/projects").Methods("GET").HandlerFunc(s.httpHandlers.handleListProjects)
+	router.Path("/projects").Methods("POST").HandlerFunc(s.httpHandlers.handleCreateProject)
+	router.Path("/projects").Methods("DELETE").HandlerFunc(s.httpHandlers.handleDeleteProject)
+	router.Path("/projects/rename").Methods("PUT").HandlerFunc(s.httpHandlers.handleRenameProject)
+	router.Path("/projects/archive").Methods("PUT").HandlerFunc(s.httpHandlers.handleArchiveProject)
+	router.Path("/stats").Methods("GET").HandlerFunc(s.httpHandlers.handleGetStats)
 
 	server := http.Server{Addr: ":" + os.Getenv("API_SERVICE_INTERNAL_PORT"), Handler: router}
 
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+)
+
+const (
+	// sseHeartbeatInterval keeps idle connections from being closed by
+	// proxies and lets the server notice clients that went away.
+	sseHeartbeatInterval = 15 * time.Second
+	// sseWriteTimeout is how long a client may take to accept one event
+	// before it is considered too slow and disconnected.
+	sseWriteTimeout = 10 * time.Second
+	// sseRetryDelay is how long browsers wait before reconnecting.
+	sseRetryDelay = 3 * time.Second
+	// sseEventBuffer is how many events may wait for a slow client. When it
+	// is full, db-service stops sending to this client and eventually drops
+	// it; the client then resumes from the bounded replay buffer.
+	sseEventBuffer = 16
+)
+
+// sseWriter writes Server-Sent Events and flushes each one, so that it
+// reaches the client immediately.
+type sseWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Keeps nginx-like proxies from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	return &sseWriter{w: w, rc: http.NewResponseController(w)}
+}
+
+func (s *sseWriter) write(chunk string) error {
+	// Not every ResponseWriter supports deadlines, e.g. in tests; those
+	// writes just aren't limited.
+	_ = s.rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+
+	if _, err := io.WriteString(s.w, chunk); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+func (s *sseWriter) retry(delay time.Duration) error {
+	return s.write("retry: " + strconv.FormatInt(delay.Milliseconds(), 10) + "\n\n")
+}
+
+func (s *sseWriter) heartbeat() error {
+	return s.write(": heartbeat\n\n")
+}
+
+// event sends a task event named after its type. The id lets the browser
+// resume after it with Last-Event-ID; a reset without a known id has none.
+func (s *sseWriter) event(event models.TaskEvent) error {
+	data, err := json.Marshal(NewTaskEventDTO(event))
+	if err != nil {
+		return err
+	}
+
+	chunk := fmt.Sprintf("event: %s\ndata: %s\n\n", event.Type, data)
+	if event.Id != 0 {
+		chunk = "id: " + strconv.Itoa(event.Id) + "\n" + chunk
+	}
+	return s.write(chunk)
+}
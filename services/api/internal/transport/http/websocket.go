@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/api/internal/models"
+	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// wsPingInterval must be shorter than wsPongTimeout, so that a live
+	// client always answers in time.
+	wsPingInterval = 30 * time.Second
+	wsPongTimeout  = 60 * time.Second
+	wsWriteTimeout = 10 * time.Second
+	// wsMaxMessageSize limits one client message; a task is far smaller.
+	wsMaxMessageSize = 64 << 10
+	// wsSendBuffer is how many messages may wait for a slow client before
+	// the events are held back.
+	wsSendBuffer = 32
+	// wsRateLimit and wsRateBurst limit the requests of one connection;
+	// the ones above the limit are answered with 429 and not executed.
+	wsRateLimit = 10
+	wsRateBurst = 20
+)
+
+// Clients authenticate with a token rather than cookies, so a page from
+// another origin can't act on their behalf and any origin is allowed.
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// parseWSTokens reads the "actor:token,actor:token" list of WebSocket
+// clients. The actor of the token is recorded as the author of the changes.
+func parseWSTokens(raw string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for pair := range strings.SplitSeq(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		actor, token, ok := strings.Cut(pair, ":")
+		if !ok || actor == "" || token == "" {
+			return nil, fmt.Errorf("bad WebSocket token %q, expected actor:token", pair)
+		}
+		tokens[token] = actor
+	}
+	return tokens, nil
+}
+
+// authenticateWS finds the actor of the bearer token. Browsers can't set
+// headers on a WebSocket, so they pass the token in access_token instead.
+func (h *HttpHandlers) authenticateWS(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get("access_token")
+	}
+	if token == "" {
+		return "", false
+	}
+
+	actor, found := "", false
+	for known, knownActor := range h.wsTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(known)) == 1 {
+			actor, found = knownActor, true
+		}
+	}
+	return actor, found
+}
+
+// wsSession serves one WebSocket connection. Only the write loop writes to
+// the connection; the others pass their messages through out.
+type wsSession struct {
+	conn    *websocket.Conn
+	service *app.Service
+	out     chan WSMessageDTO
+	limiter *rate.Limiter
+	cancel  context.CancelFunc
+
+	closeOnce sync.Once
+	closeCode int
+	closeText string
+}
+
+func newWSSession(conn *websocket.Conn, service *app.Service, cancel context.CancelFunc) *wsSession {
+	return &wsSession{
+		conn:      conn,
+		service:   service,
+		out:       make(chan WSMessageDTO, wsSendBuffer),
+		limiter:   rate.NewLimiter(wsRateLimit, wsRateBurst),
+		cancel:    cancel,
+		closeCode: websocket.CloseNormalClosure,
+	}
+}
+
+// close ends the session; the first reason given is sent to the client.
+func (s *wsSession) close(code int, text string) {
+	s.closeOnce.Do(func() {
+		s.closeCode, s.closeText = code, text
+		s.cancel()
+	})
+}
+
+func (s *wsSession) send(ctx context.Context, msg WSMessageDTO) error {
+	select {
+	case s.out <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *wsSession) writeLoop(ctx context.Context) {
+	defer func() { _ = s.conn.Close() }()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg := <-s.out:
+			_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := s.conn.WriteJSON(msg); err != nil {
+				s.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				s.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ctx.Done():
+			// The session also ends without close when the request is
+			// canceled; running the Once keeps closeCode from changing
+			// while it is read.
+			s.closeOnce.Do(func() {})
+			closeMessage := websocket.FormatCloseMessage(s.closeCode, s.closeText)
+			_ = s.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(wsWriteTimeout))
+			return
+		}
+	}
+}
+
+// readLoop executes the client requests in order until the connection
+// fails. Malformed requests are answered with an error and skipped.
+func (s *wsSession) readLoop(ctx context.Context) {
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) && ctx.Err() == nil {
+				log.Println("WebSocket read failed:", err)
+			}
+			return
+		}
+
+		var req MutationDTO
+		switch {
+		case json.Unmarshal(data, &req) != nil:
+			err = s.send(ctx, NewWSErrorDTO("", http.StatusBadRequest, errors.New("request must be a JSON object")))
+		case !s.limiter.Allow():
+			err = s.send(ctx, NewWSErrorDTO(req.Id, http.StatusTooManyRequests, errors.New("rate limit exceeded")))
+		default:
+			err = s.send(ctx, s.execute(ctx, req))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *wsSession) execute(ctx context.Context, dto MutationDTO) WSMessageDTO {
+	m := mutationFromDTO(dto)
+	task, err := s.service.ApplyMutation(ctx, m)
+	switch {
+	case err != nil:
+		return NewWSErrorDTO(dto.Id, mutationStatus(m, err), err)
+	case task.Id == 0:
+		return NewWSAckDTO(dto.Id, mutationStatus(m, nil), nil)
+	default:
+		return NewWSAckDTO(dto.Id, mutationStatus(m, nil), &task)
+	}
+}
+
+// watch forwards the task events to the client. When the events stop for
+// any reason but the end of the session, the client has to reconnect and
+// resume after the last event it got.
+func (s *wsSession) watch(ctx context.Context, req models.WatchRequest) {
+	err := s.service.WatchTasks(ctx, req, func(event models.TaskEvent) error {
+		return s.send(ctx, NewWSEventDTO(event))
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		log.Println("Task events for WebSocket ended:", err)
+	}
+	s.close(websocket.CloseTryAgainLater, "task events interrupted, reconnect with last_event_id")
+}
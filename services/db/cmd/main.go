@@ -7,8 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/breaker"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/localcache"
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/postgres"
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/redis"
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/transport/grpc"
@@ -32,17 +36,52 @@ func main() {
 
 	postgresController := postgres.NewPostgresController()
 
-	ttlSeconds, _ := strconv.Atoi(os.Getenv("REDIS_TTL_SECONDS"))
-	redisCacheController, err := redis.NewRedisController(ctx, "redis:6379", ttlSeconds)
+	redisOptions := redisOptions()
+	redisCacheController, err := redis.NewRedisController(redisOptions)
 	if err != nil {
 		log.Fatalf("failed to create redis cache controller: %v\n", err)
 	}
 
-	cacheDBRepository := app.NewCachedRepository(postgresController, redisCacheController)
+	var cacheController app.CacheController = redisCacheController
+	if localCacheSize, _ := strconv.Atoi(os.Getenv("LOCAL_CACHE_SIZE")); localCacheSize > 0 {
+		localCacheController := localcache.NewLocalCacheController(redisCacheController, redisCacheController,
+			localCacheSize, time.Duration(redisOptions.TTLSeconds)*time.Second)
+		go localCacheController.Listen(ctx)
+		cacheController = localCacheController
+	}
+
+	threshold, cooldown := cacheBreakerOptions()
+	cacheBreaker := breaker.NewCacheBreaker(cacheController, threshold, cooldown)
+	if err := redisCacheController.Ping(ctx); err != nil {
+		log.Printf("redis is unavailable, starting without the cache: %v\n", err)
+		cacheBreaker.Trip()
+	}
+
+	cacheDBRepository := app.NewCachedRepository(postgresController, cacheBreaker)
+	if cacheLock, _ := strconv.ParseBool(os.Getenv("REDIS_CACHE_LOCK")); cacheLock {
+		cacheDBRepository.SetLocker(cacheBreaker.Locker(redisCacheController))
+	}
 
 	service := app.NewService(cacheDBRepository)
 
+	go service.RunTrashPurge(ctx, trashRetention(), time.Hour)
+	go service.RunIdempotencyKeyPurge(ctx, time.Hour)
+	go postgresController.ListenTaskEvents(ctx, service.PublishTaskEvent, service.ResetTaskEvents)
+
 	server := grpc.NewServer(service)
+	callerTokens, err := grpc.ParseCallerTokens(os.Getenv("DB_SERVICE_TOKENS"))
+	if err != nil {
+		log.Fatalf("failed to read DB_SERVICE_TOKENS: %v\n", err)
+	}
+	if len(callerTokens) == 0 {
+		log.Println("DB_SERVICE_TOKENS is empty, calls are not authenticated")
+	}
+	server.SetCallerTokens(callerTokens)
+	server.SetTLS(grpc.TLSOptions{
+		CertFile:     os.Getenv("DB_SERVICE_TLS_CERT_FILE"),
+		KeyFile:      os.Getenv("DB_SERVICE_TLS_KEY_FILE"),
+		ClientCAFile: os.Getenv("DB_SERVICE_TLS_CLIENT_CA_FILE"),
+	})
 
 	dbGrpcServerAddress := ":" + os.Getenv("DB_SERVICE_INTERNAL_PORT")
 	if err := server.StartServer(dbGrpcServerAddress); err != nil {
@@ -50,3 +89,47 @@ func main() {
 	}
 
 }
+
+// trashRetention reads TRASH_RETENTION_HOURS, falling back to the default
+// when it is unset or not a positive number.
+func trashRetention() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_HOURS"))
+	if err != nil || hours <= 0 {
+		return app.DefaultTrashRetention
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// redisOptions reads the REDIS_* variables; the address defaults to the
+// redis service of the compose file.
+func redisOptions() redis.Options {
+	opts := redis.Options{
+		Address:   os.Getenv("REDIS_ADDRESS"),
+		Password:  os.Getenv("REDIS_PASSWORD"),
+		TLSCAFile: os.Getenv("REDIS_TLS_CA_FILE"),
+		KeyPrefix: os.Getenv("REDIS_KEY_PREFIX"),
+	}
+	if opts.Address == "" {
+		opts.Address = "redis:6379"
+	}
+	opts.DB, _ = strconv.Atoi(os.Getenv("REDIS_DB"))
+	opts.TLS, _ = strconv.ParseBool(os.Getenv("REDIS_TLS"))
+	opts.TTLSeconds, _ = strconv.Atoi(os.Getenv("REDIS_TTL_SECONDS"))
+	opts.StaleSeconds, _ = strconv.Atoi(os.Getenv("REDIS_STALE_SECONDS"))
+	return opts
+}
+
+// cacheBreakerOptions reads CACHE_BREAKER_FAILURES and
+// CACHE_BREAKER_COOLDOWN_SECONDS, falling back to the defaults when they are
+// unset or not positive.
+func cacheBreakerOptions() (int, time.Duration) {
+	threshold, err := strconv.Atoi(os.Getenv("CACHE_BREAKER_FAILURES"))
+	if err != nil || threshold <= 0 {
+		threshold = breaker.DefaultThreshold
+	}
+	cooldown := breaker.DefaultCooldown
+	if seconds, err := strconv.Atoi(os.Getenv("CACHE_BREAKER_COOLDOWN_SECONDS")); err == nil && seconds > 0 {
+		cooldown = time.Duration(seconds) * time.Second
+	}
+	return threshold, cooldown
+}
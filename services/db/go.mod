@@ -4,10 +4,12 @@ go 1.25.4
 
 require (
 	github.com/dodocheck/go-pet-project-1/pkg/pb v0.0.0-20251224111728-32ad915ee4c7
+	github.com/dodocheck/go-pet-project-1/pkg/tlsreload v0.0.0-00010101000000-000000000000
 	github.com/google/go-cmp v0.7.0
 	github.com/gorilla/mux v1.8.1
 	github.com/lib/pq v1.10.9
 	github.com/redis/go-redis/v9 v9.17.2
+	golang.org/x/sync v0.18.0
 	google.golang.org/grpc v1.77.0
 	google.golang.org/protobuf v1.36.11
 )
@@ -20,3 +22,10 @@ require (
 	golang.org/x/text v0.30.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251022142026-3a174f9686a8 // indirect
 )
+
+// The service is built together with the shared packages of the same
+// commit; see its Dockerfile.
+replace (
+	github.com/dodocheck/go-pet-project-1/pkg/pb => ../../pkg/pb
+	github.com/dodocheck/go-pet-project-1/pkg/tlsreload => ../../pkg/tlsreload
+)
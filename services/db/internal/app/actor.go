@@ -0,0 +1,17 @@
+package app
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor stores who makes the request, so that repositories can record it
+// in the task history.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor stored by WithActor or "" for background work.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
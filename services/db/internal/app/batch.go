@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// MaxBatchSize limits how many items one batch request may carry.
+const MaxBatchSize = 500
+
+func checkBatchSize(n int) error {
+	if n == 0 || n > MaxBatchSize {
+		return fmt.Errorf("%w: batch must have 1 to %d items, got %d", ErrInvalidArgument, MaxBatchSize, n)
+	}
+	return nil
+}
+
+// BatchAddTasks creates the tasks in one transaction and returns a result per
+// task in request order. Tasks that fail validation get their error without
+// reaching the repository.
+func (s *Service) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	log.Printf("IN: batch add %d tasks\n", len(tasks))
+
+	if err := checkBatchSize(len(tasks)); err != nil {
+		log.Printf("OUT(ERR): batch add tasks: %v\n", err)
+		return nil, err
+	}
+
+	items := make([]models.BatchItem, len(tasks))
+	valid := make([]models.TaskImportData, 0, len(tasks))
+	validIndexes := make([]int, 0, len(tasks))
+	for i, task := range tasks {
+		task, err := s.prepareTask(task)
+		if err != nil {
+			items[i].Err = err
+			continue
+		}
+		valid = append(valid, task)
+		validIndexes = append(validIndexes, i)
+	}
+
+	if len(valid) > 0 {
+		created, err := s.dbController.BatchAddTasks(ctx, valid)
+		if err != nil {
+			log.Printf("OUT(ERR): batch add tasks: %v\n", err)
+			return nil, err
+		}
+		for j, item := range created {
+			items[validIndexes[j]] = item
+		}
+	}
+
+	items = s.withOverdueItems(items)
+	log.Printf("OUT(OK): batch add tasks: %d failed\n", countFailed(items))
+
+	return items, nil
+}
+
+// BatchMarkFinished finishes the tasks in one transaction and returns a
+// result per ID in request order.
+func (s *Service) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	log.Printf("IN: batch finish tasks with IDs: %v\n", ids)
+
+	if err := checkBatchSize(len(ids)); err != nil {
+		log.Printf("OUT(ERR): batch finish tasks: %v\n", err)
+		return nil, err
+	}
+
+	items, err := s.dbController.BatchMarkFinished(ctx, ids)
+
+	if err != nil {
+		log.Printf("OUT(ERR): batch finish tasks: %v\n", err)
+		return nil, err
+	}
+
+	items = s.withOverdueItems(items)
+	log.Printf("OUT(OK): batch finish tasks: %d failed\n", countFailed(items))
+
+	return items, nil
+}
+
+// BatchRemoveTasks moves the tasks to the trash in one transaction and
+// returns a result per ID in request order.
+func (s *Service) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	log.Printf("IN: batch delete tasks with IDs: %v\n", ids)
+
+	if err := checkBatchSize(len(ids)); err != nil {
+		log.Printf("OUT(ERR): batch delete tasks: %v\n", err)
+		return nil, err
+	}
+
+	items, err := s.dbController.BatchRemoveTasks(ctx, ids)
+
+	if err != nil {
+		log.Printf("OUT(ERR): batch delete tasks: %v\n", err)
+		return nil, err
+	}
+
+	log.Printf("OUT(OK): batch delete tasks: %d failed\n", countFailed(items))
+
+	return items, nil
+}
+
+func (s *Service) withOverdueItems(items []models.BatchItem) []models.BatchItem {
+	now := s.now()
+	for i := range items {
+		if items[i].Err == nil {
+			items[i].Task = withOverdue(items[i].Task, now)
+		}
+	}
+	return items
+}
+
+func countFailed(items []models.BatchItem) int {
+	failed := 0
+	for _, item := range items {
+		if item.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
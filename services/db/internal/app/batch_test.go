@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+func TestServiceBatchAddTasks_InvalidTasks_SkipTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{batchAddRet: []models.BatchItem{{Id: 10, Task: models.TaskExportData{Id: 10}}}}
+	svc := NewService(fakeRepo)
+
+	items, err := svc.BatchAddTasks(context.Background(), []models.TaskImportData{
+		{Title: "subtask", ParentId: 1, Recurrence: &models.Recurrence{Frequency: models.RecurrenceDaily}},
+		{Title: "valid", Tags: []string{" Home "}},
+	})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.batchAddCalls != 1 {
+		t.Fatalf("expected BatchAddTasks called once, got %d", fakeRepo.batchAddCalls)
+	}
+	wantIn := []models.TaskImportData{{Title: "valid", Tags: []string{"home"}}}
+	if !reflect.DeepEqual(fakeRepo.batchAddIn, wantIn) {
+		t.Fatalf("mismatch tasks in: got:%+v want: %+v", fakeRepo.batchAddIn, wantIn)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	if !errors.Is(items[0].Err, ErrRecurringSubtask) {
+		t.Fatalf("expected first item to fail with %v, got %+v", ErrRecurringSubtask, items[0])
+	}
+	if items[1].Err != nil || items[1].Id != 10 {
+		t.Fatalf("expected second item created as 10, got %+v", items[1])
+	}
+}
+
+func TestServiceBatchAddTasks_AllInvalid_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	items, err := svc.BatchAddTasks(context.Background(), []models.TaskImportData{{Tags: []string{""}}})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.batchAddCalls != 0 {
+		t.Fatalf("expected BatchAddTasks not called, got %d calls", fakeRepo.batchAddCalls)
+	}
+	if len(items) != 1 || !errors.Is(items[0].Err, ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument item, got %+v", items)
+	}
+}
+
+func TestServiceBatch_InvalidSize_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	if _, err := svc.BatchMarkFinished(context.Background(), nil); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("empty batch: expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if _, err := svc.BatchRemoveTasks(context.Background(), make([]int, MaxBatchSize+1)); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("large batch: expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.batchFinishCalls != 0 || fakeRepo.batchRemoveCalls != 0 {
+		t.Fatalf("expected task repo not called")
+	}
+}
+
+func TestServiceBatchMarkFinished_ComputesOverdue(t *testing.T) {
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	dueAt := now.Add(-time.Hour)
+	fakeRepo := &fakeRepo{batchFinishRet: []models.BatchItem{
+		{Id: 1, Task: models.TaskExportData{Id: 1, DueAt: &dueAt}},
+		{Id: 2, Err: ErrTaskNotFound},
+	}}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	items, err := svc.BatchMarkFinished(context.Background(), []int{1, 2})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !reflect.DeepEqual(fakeRepo.batchFinishIn, []int{1, 2}) {
+		t.Fatalf("mismatch ids in: %v", fakeRepo.batchFinishIn)
+	}
+	if !items[0].Task.Overdue {
+		t.Fatalf("expected first task overdue, got %+v", items[0].Task)
+	}
+	if !errors.Is(items[1].Err, ErrTaskNotFound) {
+		t.Fatalf("expected second item to fail, got %+v", items[1])
+	}
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	taskListCacheKey = "tasks"
+	// cacheLoadTimeout bounds a reload shared by several callers, which
+	// doesn't stop when one of them gives up.
+	cacheLoadTimeout = 10 * time.Second
+	// cacheLockTTL frees the reload lock of a replica that died holding it.
+	cacheLockTTL = 5 * time.Second
+	// cacheLockWait is how long a replica waits for another one to reload a
+	// key before going to Postgres itself.
+	cacheLockWait = 2 * time.Second
+	cacheLockPoll = 50 * time.Millisecond
+)
+
+func taskCacheKey(id int) string {
+	return "task:" + strconv.Itoa(id)
+}
+
+// loadShared runs load once for all concurrent callers with the same key and
+// reports whether the result is shared with other callers.
+func loadShared[T any](ctx context.Context, cr *CachedRepository, key string, load func(context.Context) (T, error)) (T, bool, error) {
+	results := cr.loads.DoChan(key, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheLoadTimeout)
+		defer cancel()
+		return load(loadCtx)
+	})
+
+	var zero T
+	select {
+	case res := <-results:
+		if res.Err != nil {
+			return zero, res.Shared, res.Err
+		}
+		return res.Val.(T), res.Shared, nil
+	case <-ctx.Done():
+		return zero, false, ctx.Err()
+	}
+}
+
+// revalidate reloads a stale key in the background. Further stale hits of
+// the key while it is reloaded don't start another reload.
+func revalidate[T any](cr *CachedRepository, key string, load func(context.Context) (T, error)) {
+	if _, running := cr.revalidating.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	cr.background.Add(1)
+	go func() {
+		defer cr.background.Done()
+		defer cr.revalidating.Delete(key)
+
+		if _, _, err := loadShared(context.Background(), cr, key, load); err != nil {
+			log.Printf("cache revalidate %s err: %v\n", key, err)
+		}
+	}()
+}
+
+// lockOrWait makes replicas take turns reloading a key when a CacheLocker is
+// set. The lock holder gets unlock and reloads the key; the others poll the
+// cache until a fresh value appears and get it with found set.
+// When waiting times out or the lock fails, the caller reloads the key
+// without it. unlock is never nil.
+func lockOrWait[T any](ctx context.Context, cr *CachedRepository, key string, cached func(context.Context) (T, error)) (value T, found bool, unlock func()) {
+	unlock = func() {}
+	if cr.locker == nil {
+		return value, false, unlock
+	}
+
+	deadline := time.Now().Add(cacheLockWait)
+	for {
+		release, err := cr.locker.TryLock(ctx, key, cacheLockTTL)
+		switch {
+		case err == nil:
+			unlock = func() {
+				if err := release(ctx); err != nil {
+					log.Printf("cache unlock %s err: %v\n", key, err)
+				}
+			}
+			// The previous holder may have cached the key just now.
+			if v, err := cached(ctx); err == nil {
+				unlock()
+				return v, true, func() {}
+			}
+			return value, false, unlock
+		case !errors.Is(err, ErrLockHeld):
+			logCacheErr("cache lock "+key, err)
+			return value, false, unlock
+		}
+
+		if v, err := cached(ctx); err == nil {
+			return v, true, unlock
+		}
+		if time.Now().After(deadline) {
+			log.Printf("cache lock %s wait timed out, loading from main DB\n", key)
+			return value, false, unlock
+		}
+
+		select {
+		case <-ctx.Done():
+			return value, false, unlock
+		case <-time.After(cacheLockPoll):
+		}
+	}
+}
@@ -2,33 +2,128 @@ package app
 
 import (
 	"context"
+	"errors"
 	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"golang.org/x/sync/singleflight"
 )
 
 type TaskRepository interface {
+	// AddTask returns the task created by the first request when the task
+	// carries an idempotency key that was already used for the same data.
 	AddTask(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error)
-	DeleteTask(ctx context.Context, id int) error
+	// DeleteTask moves the task with all its subtasks to the trash and returns
+	// the IDs of the other tasks it changed: the trashed subtasks and the parent.
+	DeleteTask(ctx context.Context, id int) ([]int, error)
+	ListTrash(ctx context.Context) ([]models.TaskExportData, error)
+	// RestoreTask brings the task back from the trash together with the
+	// subtasks trashed along with it.
+	RestoreTask(ctx context.Context, id int) (models.TaskExportData, error)
+	// PurgeTask permanently deletes a trashed task.
+	PurgeTask(ctx context.Context, id int) error
+	// PurgeTrash permanently deletes the tasks trashed before the given time
+	// and returns how many there were.
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+	// PurgeIdempotencyKeys deletes the keys expired by the given time and
+	// returns how many there were.
+	PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int, error)
+	GetTask(ctx context.Context, id int) (models.TaskExportData, error)
+	// ListSubtasks returns all descendants of the task in manual order.
+	ListSubtasks(ctx context.Context, id int) ([]models.TaskExportData, error)
 	ListAllTasks(ctx context.Context) ([]models.TaskExportData, error)
+	// MarkTaskFinished also finishes the subtasks and creates the next
+	// occurrence of a recurring task.
 	MarkTaskFinished(ctx context.Context, id int) (models.TaskExportData, error)
+	// ReopenTask also reopens the finished ancestors of the task.
+	ReopenTask(ctx context.Context, id int) (models.TaskExportData, error)
+	SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error)
+	MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error)
+	SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error)
+	// SkipOccurrence moves a recurring task to its next due date without
+	// finishing it.
+	SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error)
+	AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error)
+	RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error)
+	ListTags(ctx context.Context) ([]models.TagUsage, error)
+	RenameTag(ctx context.Context, name, newName string) (models.TagChange, error)
+	MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error)
+	CreateProject(ctx context.Context, name string) (models.Project, error)
+	ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error)
+	RenameProject(ctx context.Context, id int, name string) (models.Project, error)
+	ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error)
+	// DeleteProject moves the project's tasks to the inbox and returns their IDs.
+	DeleteProject(ctx context.Context, id int) ([]int, error)
+	MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error)
+	GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error)
+	// BatchAddTasks, BatchMarkFinished and BatchRemoveTasks apply all items in
+	// one transaction and return a result per item in request order; a failed
+	// item changes nothing and doesn't fail the others.
+	BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error)
+	BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error)
+	BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error)
+	// SearchTasks returns the tasks matching the request, most relevant first.
+	SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error)
+	// GetChangesSince returns up to req.Limit changes after req.Since.
+	GetChangesSince(ctx context.Context, req models.ChangesRequest) (models.Changes, error)
+	// GetTasks returns the tasks with the given IDs that exist and aren't in
+	// the trash, ordered by ID.
+	GetTasks(ctx context.Context, ids []int) ([]models.TaskExportData, error)
+	// GetTaskHistory pages through the task's changes from newest to oldest.
+	GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error)
 	Close() error
 }
 
+// CachedTaskList is the task list as cached. MissingIds are listed tasks
+// whose cached copy was dropped; they have to be read from the main DB.
+// Stale means the list is still served, but due to be reloaded.
+type CachedTaskList struct {
+	Tasks      []models.TaskExportData
+	MissingIds []int
+	Stale      bool
+}
+
+// CacheController keeps the task list up to date task by task: CacheTask
+// adds or updates a task in the list, DeleteTaskById only drops the cached
+// copy of a task and RemoveTasksFromList takes tasks out of the list.
 type CacheController interface {
 	CacheTaskList(ctx context.Context, tasks []models.TaskExportData) error
 	DeleteTaskList(ctx context.Context) error
-	GetTaskList(ctx context.Context) ([]models.TaskExportData, error)
+	GetTaskList(ctx context.Context) (CachedTaskList, error)
 	CacheTask(ctx context.Context, task models.TaskExportData) error
 	DeleteTaskById(ctx context.Context, id int) error
-	GetTaskById(ctx context.Context, id int) (models.TaskExportData, error)
+	RemoveTasksFromList(ctx context.Context, ids []int) error
+	// GetTaskById also reports whether the task is stale.
+	GetTaskById(ctx context.Context, id int) (models.TaskExportData, bool, error)
 	FlushAllData(ctx context.Context) error
 	Close() error
 }
 
+// CacheLocker lets one db-service replica at a time reload a cache key.
+type CacheLocker interface {
+	// TryLock returns ErrLockHeld when the lock is taken; otherwise it
+	// returns the function releasing the lock.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error)
+}
+
 type CachedRepository struct {
 	mainDBClient  TaskRepository
 	cacheDBClient CacheController
+	locker        CacheLocker
+
+	// loads coalesces concurrent reloads of the same key, revalidating
+	// holds the keys being reloaded after a stale hit.
+	loads        singleflight.Group
+	revalidating sync.Map
+	background   sync.WaitGroup
+
+	// mainDBDown is set while the last list read from the main DB couldn't
+	// reach it.
+	mainDBDown atomic.Bool
 }
 
 func NewCachedRepository(mainDBClient TaskRepository, cacheDBClient CacheController) *CachedRepository {
@@ -38,7 +133,14 @@ func NewCachedRepository(mainDBClient TaskRepository, cacheDBClient CacheControl
 	}
 }
 
+// SetLocker makes replicas sharing the cache take turns reloading a missing
+// key, so it costs Postgres one query instead of one per replica.
+func (cr *CachedRepository) SetLocker(locker CacheLocker) {
+	cr.locker = locker
+}
+
 func (cr *CachedRepository) Close() error {
+	cr.background.Wait()
 	_ = cr.cacheDBClient.Close()
 	return cr.mainDBClient.Close()
 }
@@ -47,72 +149,528 @@ func (cr *CachedRepository) AddTask(ctx context.Context, task models.TaskImportD
 	createdTask, err := cr.mainDBClient.AddTask(ctx, task)
 
 	if err == nil {
-		if cacheTaskErr := cr.cacheDBClient.CacheTask(ctx, createdTask); cacheTaskErr != nil {
-			log.Printf("cache add task err: %v\n", cacheTaskErr)
-		}
-		if cacheTaskListErr := cr.cacheDBClient.DeleteTaskList(ctx); cacheTaskListErr != nil {
-			log.Printf("cache delete tasklist err: %v\n", cacheTaskListErr)
+		// A repeated request returns the task as it was created, which may be
+		// older than the cached one; the first request has already listed it.
+		if task.Idempotency != nil {
+			cr.evictTasks(ctx, []int{createdTask.Id})
+		} else {
+			cr.refreshTask(ctx, createdTask)
 		}
+		cr.evictParent(ctx, createdTask)
 	}
 
 	return createdTask, err
 }
 
-func (cr *CachedRepository) DeleteTask(ctx context.Context, id int) error {
-	err := cr.mainDBClient.DeleteTask(ctx, id)
+func (cr *CachedRepository) DeleteTask(ctx context.Context, id int) ([]int, error) {
+	staleIds, err := cr.mainDBClient.DeleteTask(ctx, id)
+
+	if err == nil {
+		cr.removeTasks(ctx, []int{id})
+		// The trashed subtasks leave the list when it is read next.
+		cr.evictTasks(ctx, staleIds)
+	}
+
+	return staleIds, err
+}
+
+func (cr *CachedRepository) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	return cr.mainDBClient.ListTrash(ctx)
+}
+
+func (cr *CachedRepository) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	restoredTask, err := cr.mainDBClient.RestoreTask(ctx, id)
 
 	if err == nil {
-		if cacheTaskErr := cr.cacheDBClient.DeleteTaskById(ctx, id); cacheTaskErr != nil {
-			log.Printf("cache delete task err: %v\n", cacheTaskErr)
+		cr.refreshTask(ctx, restoredTask)
+		cr.evictParent(ctx, restoredTask)
+		// The subtasks restored along with the task aren't in the list.
+		if restoredTask.SubtasksTotal > 0 {
+			cr.dropTaskList(ctx)
 		}
-		if cacheTaskListErr := cr.cacheDBClient.DeleteTaskList(ctx); cacheTaskListErr != nil {
-			log.Printf("cache delete tasklist err: %v\n", cacheTaskListErr)
+	}
+
+	return restoredTask, err
+}
+
+// PurgeTask and PurgeTrash only touch trashed tasks, which are never cached.
+func (cr *CachedRepository) PurgeTask(ctx context.Context, id int) error {
+	return cr.mainDBClient.PurgeTask(ctx, id)
+}
+
+func (cr *CachedRepository) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	return cr.mainDBClient.PurgeTrash(ctx, before)
+}
+
+func (cr *CachedRepository) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int, error) {
+	return cr.mainDBClient.PurgeIdempotencyKeys(ctx, now)
+}
+
+func (cr *CachedRepository) GetTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	key := taskCacheKey(id)
+	load := func(ctx context.Context) (models.TaskExportData, error) {
+		return cr.loadTask(ctx, id)
+	}
+
+	cacheTask, stale, cacheErr := cr.cacheDBClient.GetTaskById(ctx, id)
+	if cacheErr == nil {
+		if stale {
+			revalidate(cr, key, load)
+		}
+		return cacheTask, nil
+	}
+
+	if cacheErr != ErrTaskNotFound && cacheErr != ErrCacheUnavailable {
+		log.Printf("cache degraded: %v\n", cacheErr)
+	}
+
+	task, _, err := loadShared(ctx, cr, key, load)
+	return task, err
+}
+
+func (cr *CachedRepository) loadTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	task, found, unlock := lockOrWait(ctx, cr, taskCacheKey(id), func(ctx context.Context) (models.TaskExportData, error) {
+		task, stale, err := cr.cacheDBClient.GetTaskById(ctx, id)
+		if err == nil && stale {
+			err = ErrTaskNotFound
 		}
+		return task, err
+	})
+	if found {
+		return task, nil
+	}
+	defer unlock()
+
+	task, err := cr.mainDBClient.GetTask(ctx, id)
+
+	if err == nil {
+		logCacheErr("cache add task", cr.cacheDBClient.CacheTask(ctx, task))
 	}
 
-	return err
+	return task, err
+}
+
+func (cr *CachedRepository) GetTasks(ctx context.Context, ids []int) ([]models.TaskExportData, error) {
+	return cr.mainDBClient.GetTasks(ctx, ids)
+}
+
+func (cr *CachedRepository) ListSubtasks(ctx context.Context, id int) ([]models.TaskExportData, error) {
+	return cr.mainDBClient.ListSubtasks(ctx, id)
 }
 
 func (cr *CachedRepository) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
-	cacheTasks, cacheErr := cr.cacheDBClient.GetTaskList(ctx)
+	tasks, _, err := cr.ListAllTasksOrStale(ctx)
+	return tasks, err
+}
+
+// ListAllTasksOrStale is ListAllTasks that keeps serving the cached list
+// while the main DB is unreachable, reporting it as stale: past its TTL or
+// without the tasks that had to be read from the main DB.
+func (cr *CachedRepository) ListAllTasksOrStale(ctx context.Context) ([]models.TaskExportData, bool, error) {
+	cacheList, cacheErr := cr.cacheDBClient.GetTaskList(ctx)
 	if cacheErr == nil {
+		tasks, err := cr.completeTaskList(ctx, cacheList)
+		if MainDBUnavailable(err) {
+			log.Printf("main DB unavailable, returning incomplete tasklist from cache: %v\n", err)
+			return cacheList.Tasks, true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if cacheList.Stale {
+			log.Println("stale cache hit! returning tasklist from cache while it is reloaded")
+			revalidate(cr, taskListCacheKey, cr.loadTaskList)
+			// The reload can't succeed while the main DB is down.
+			return tasks, cr.mainDBDown.Load(), nil
+		}
 		log.Println("cache hit! returning tasklist from cache!")
-		return cacheTasks, nil
+		return tasks, false, nil
 	}
 
-	if cacheErr != ErrTaskNotFound {
+	if cacheErr != ErrTaskNotFound && cacheErr != ErrCacheUnavailable {
 		log.Printf("cache degraded: %v\n", cacheErr)
 	}
 
-	tasks, err := cr.mainDBClient.ListAllTasks(ctx)
+	tasks, shared, err := loadShared(ctx, cr, taskListCacheKey, cr.loadTaskList)
+	if err != nil {
+		return nil, false, err
+	}
 
-	if err == nil {
-		if cacheTaskListErr := cr.cacheDBClient.CacheTaskList(ctx, tasks); cacheTaskListErr != nil {
-			log.Printf("cache tasklist err: %v\n", cacheTaskListErr)
-		}
-		for _, task := range tasks {
-			if err := cr.cacheDBClient.CacheTask(ctx, task); err != nil {
-				log.Printf("cache add task err: %v\n", err)
-			}
+	log.Println("returning tasklist from main DB")
+	// Callers may change the tasks they get, so they don't share a slice.
+	if shared {
+		tasks = slices.Clone(tasks)
+	}
+	return tasks, false, nil
+}
+
+// completeTaskList reads the listed tasks whose cached copy was dropped from
+// the main DB and caches them; those gone meanwhile leave the list.
+func (cr *CachedRepository) completeTaskList(ctx context.Context, cacheList CachedTaskList) ([]models.TaskExportData, error) {
+	if len(cacheList.MissingIds) == 0 {
+		return cacheList.Tasks, nil
+	}
+
+	log.Printf("reading %d listed tasks from main DB\n", len(cacheList.MissingIds))
+	missingTasks, err := cr.mainDBClient.GetTasks(ctx, cacheList.MissingIds)
+	cr.mainDBDown.Store(MainDBUnavailable(err))
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := cacheList.Tasks
+	goneIds := slices.Clone(cacheList.MissingIds)
+	for _, task := range missingTasks {
+		logCacheErr("cache add task", cr.cacheDBClient.CacheTask(ctx, task))
+		tasks = append(tasks, task)
+		goneIds = slices.DeleteFunc(goneIds, func(id int) bool { return id == task.Id })
+	}
+	cr.removeTasks(ctx, goneIds)
+
+	slices.SortFunc(tasks, func(a, b models.TaskExportData) int { return a.Id - b.Id })
+	return tasks, nil
+}
+
+func (cr *CachedRepository) loadTaskList(ctx context.Context) ([]models.TaskExportData, error) {
+	tasks, found, unlock := lockOrWait(ctx, cr, taskListCacheKey, func(ctx context.Context) ([]models.TaskExportData, error) {
+		cacheList, err := cr.cacheDBClient.GetTaskList(ctx)
+		if err == nil && (cacheList.Stale || len(cacheList.MissingIds) > 0) {
+			err = ErrTaskNotFound
 		}
-		log.Println("returning tasklist from main DB")
+		return cacheList.Tasks, err
+	})
+	if found {
 		return tasks, nil
 	}
+	defer unlock()
+
+	tasks, err := cr.mainDBClient.ListAllTasks(ctx)
+	cr.mainDBDown.Store(MainDBUnavailable(err))
+	if err != nil {
+		return nil, err
+	}
+
+	logCacheErr("cache tasklist", cr.cacheDBClient.CacheTaskList(ctx, tasks))
 
-	return nil, err
+	return tasks, nil
 }
 
 func (cr *CachedRepository) MarkTaskFinished(ctx context.Context, id int) (models.TaskExportData, error) {
 	updatedTask, err := cr.mainDBClient.MarkTaskFinished(ctx, id)
 
 	if err == nil {
-		if cacheTaskErr := cr.cacheDBClient.CacheTask(ctx, updatedTask); cacheTaskErr != nil {
-			log.Printf("cache add task err: %v\n", cacheTaskErr)
-		}
-		if cacheTaskListErr := cr.cacheDBClient.DeleteTaskList(ctx); cacheTaskListErr != nil {
-			log.Printf("cache delete tasklist err: %v\n", cacheTaskListErr)
+		cr.refreshTask(ctx, updatedTask)
+		cr.evictParent(ctx, updatedTask)
+		cr.refreshSubtasks(ctx, updatedTask)
+		if updatedTask.NextOccurrenceId != 0 {
+			cr.cacheNewTask(ctx, updatedTask.NextOccurrenceId)
 		}
 	}
 
 	return updatedTask, err
 }
+
+func (cr *CachedRepository) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	updatedTask, err := cr.mainDBClient.ReopenTask(ctx, id)
+
+	if err == nil {
+		cr.refreshTask(ctx, updatedTask)
+		cr.refreshAncestors(ctx, updatedTask)
+	}
+
+	return updatedTask, err
+}
+
+func (cr *CachedRepository) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	updatedTask, err := cr.mainDBClient.SetTaskPriority(ctx, id, priority)
+
+	if err == nil {
+		cr.refreshTask(ctx, updatedTask)
+	}
+
+	return updatedTask, err
+}
+
+func (cr *CachedRepository) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	movedTask, err := cr.mainDBClient.MoveTask(ctx, move)
+
+	if err == nil {
+		cr.refreshTask(ctx, movedTask)
+	}
+
+	return movedTask, err
+}
+
+func (cr *CachedRepository) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	updatedTask, err := cr.mainDBClient.SetTaskRecurrence(ctx, id, rule)
+
+	if err == nil {
+		cr.refreshTask(ctx, updatedTask)
+	}
+
+	return updatedTask, err
+}
+
+func (cr *CachedRepository) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	updatedTask, err := cr.mainDBClient.SkipOccurrence(ctx, id)
+
+	if err == nil {
+		cr.refreshTask(ctx, updatedTask)
+	}
+
+	return updatedTask, err
+}
+
+func (cr *CachedRepository) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	updatedTask, err := cr.mainDBClient.AddTaskTags(ctx, id, tags)
+
+	if err == nil {
+		cr.refreshTask(ctx, updatedTask)
+	}
+
+	return updatedTask, err
+}
+
+func (cr *CachedRepository) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	updatedTask, err := cr.mainDBClient.RemoveTaskTags(ctx, id, tags)
+
+	if err == nil {
+		cr.refreshTask(ctx, updatedTask)
+	}
+
+	return updatedTask, err
+}
+
+func (cr *CachedRepository) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	return cr.mainDBClient.ListTags(ctx)
+}
+
+func (cr *CachedRepository) RenameTag(ctx context.Context, name, newName string) (models.TagChange, error) {
+	change, err := cr.mainDBClient.RenameTag(ctx, name, newName)
+
+	if err == nil {
+		cr.evictTasks(ctx, change.TaskIds)
+	}
+
+	return change, err
+}
+
+func (cr *CachedRepository) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	change, err := cr.mainDBClient.MergeTags(ctx, sources, target)
+
+	if err == nil {
+		cr.evictTasks(ctx, change.TaskIds)
+	}
+
+	return change, err
+}
+
+func (cr *CachedRepository) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	return cr.mainDBClient.CreateProject(ctx, name)
+}
+
+func (cr *CachedRepository) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	return cr.mainDBClient.ListProjects(ctx, includeArchived)
+}
+
+func (cr *CachedRepository) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	return cr.mainDBClient.RenameProject(ctx, id, name)
+}
+
+func (cr *CachedRepository) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	return cr.mainDBClient.ArchiveProject(ctx, id, archived)
+}
+
+func (cr *CachedRepository) DeleteProject(ctx context.Context, id int) ([]int, error) {
+	movedIds, err := cr.mainDBClient.DeleteProject(ctx, id)
+
+	if err == nil {
+		cr.evictTasks(ctx, movedIds)
+	}
+
+	return movedIds, err
+}
+
+func (cr *CachedRepository) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	movedTask, err := cr.mainDBClient.MoveTaskToProject(ctx, id, projectId)
+
+	if err == nil {
+		cr.refreshTask(ctx, movedTask)
+		cr.refreshSubtasks(ctx, movedTask)
+	}
+
+	return movedTask, err
+}
+
+// logCacheErr logs a failed cache call. Calls skipped while the cache is
+// down aren't logged: the breaker logs the outage once.
+func logCacheErr(operation string, err error) {
+	if err != nil && !errors.Is(err, ErrCacheUnavailable) {
+		log.Printf("%s err: %v\n", operation, err)
+	}
+}
+
+// refreshTask stores the created or updated task, in the cached list too.
+func (cr *CachedRepository) refreshTask(ctx context.Context, task models.TaskExportData) {
+	logCacheErr("cache add task", cr.cacheDBClient.CacheTask(ctx, task))
+}
+
+// cacheNewTask adds a task created as a side effect, like the next
+// occurrence of a recurring task, to the cached list. When it can't be read,
+// the list is dropped instead.
+func (cr *CachedRepository) cacheNewTask(ctx context.Context, id int) {
+	task, err := cr.mainDBClient.GetTask(ctx, id)
+	if err != nil {
+		log.Printf("get new task err: %v\n", err)
+		cr.dropTaskList(ctx)
+		return
+	}
+	cr.refreshTask(ctx, task)
+}
+
+// removeTasks takes tasks that were trashed out of the cached list.
+func (cr *CachedRepository) removeTasks(ctx context.Context, ids []int) {
+	if len(ids) == 0 {
+		return
+	}
+	logCacheErr("cache remove tasks", cr.cacheDBClient.RemoveTasksFromList(ctx, ids))
+}
+
+// dropTaskList drops the whole cached list when a change adds tasks that
+// can't be added one by one.
+func (cr *CachedRepository) dropTaskList(ctx context.Context) {
+	logCacheErr("cache delete tasklist", cr.cacheDBClient.DeleteTaskList(ctx))
+}
+
+// SearchTasks always goes to the main DB: Redis has no full-text index.
+func (cr *CachedRepository) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	return cr.mainDBClient.SearchTasks(ctx, req)
+}
+
+// GetChangesSince always goes to the main DB: the change sequence is only
+// consistent within one Postgres snapshot.
+func (cr *CachedRepository) GetChangesSince(ctx context.Context, req models.ChangesRequest) (models.Changes, error) {
+	return cr.mainDBClient.GetChangesSince(ctx, req)
+}
+
+func (cr *CachedRepository) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	items, err := cr.mainDBClient.BatchAddTasks(ctx, tasks)
+
+	if err == nil {
+		cr.refreshBatch(ctx, items)
+	}
+
+	return items, err
+}
+
+func (cr *CachedRepository) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	items, err := cr.mainDBClient.BatchMarkFinished(ctx, ids)
+
+	if err == nil {
+		cr.refreshBatch(ctx, items)
+		// The next occurrences of recurring tasks aren't in the items.
+		if slices.ContainsFunc(items, func(item models.BatchItem) bool {
+			return item.Err == nil && item.Task.NextOccurrenceId != 0
+		}) {
+			cr.dropTaskList(ctx)
+		}
+	}
+
+	return items, err
+}
+
+func (cr *CachedRepository) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	items, err := cr.mainDBClient.BatchRemoveTasks(ctx, ids)
+
+	if err == nil {
+		var removedIds []int
+		for _, item := range items {
+			if item.Err == nil {
+				removedIds = append(removedIds, item.Id)
+			}
+		}
+		cr.removeTasks(ctx, removedIds)
+		cr.evictTasks(ctx, batchStaleIds(items))
+	}
+
+	return items, err
+}
+
+// refreshBatch stores the tasks created or updated by the batch and drops
+// the other tasks it changed.
+func (cr *CachedRepository) refreshBatch(ctx context.Context, items []models.BatchItem) {
+	for _, item := range items {
+		if item.Err == nil {
+			cr.refreshTask(ctx, item.Task)
+		}
+	}
+	cr.evictTasks(ctx, batchStaleIds(items))
+}
+
+// batchStaleIds lists the other tasks changed by the batch items once, even
+// if several items changed them.
+func batchStaleIds(items []models.BatchItem) []int {
+	var ids []int
+	for _, item := range items {
+		if item.Err == nil {
+			ids = append(ids, item.StaleIds...)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// evictTasks drops the cached copies of tasks changed in bulk. Listed tasks
+// stay in the list and are read from the main DB with it.
+func (cr *CachedRepository) evictTasks(ctx context.Context, ids []int) {
+	for _, id := range ids {
+		logCacheErr("cache delete task", cr.cacheDBClient.DeleteTaskById(ctx, id))
+	}
+}
+
+// evictParent drops the cached parent, whose subtask progress has changed.
+func (cr *CachedRepository) evictParent(ctx context.Context, task models.TaskExportData) {
+	if task.ParentId == 0 {
+		return
+	}
+	logCacheErr("cache delete task", cr.cacheDBClient.DeleteTaskById(ctx, task.ParentId))
+}
+
+// refreshAncestors re-caches the parent chain after a change propagated up
+// to it. The chain is at most models.MaxTaskDepth long.
+func (cr *CachedRepository) refreshAncestors(ctx context.Context, task models.TaskExportData) {
+	for parentId := task.ParentId; parentId != 0; {
+		parent, err := cr.mainDBClient.GetTask(ctx, parentId)
+		if err != nil {
+			log.Printf("get parent task err: %v\n", err)
+			cr.evictParent(ctx, task)
+			return
+		}
+		logCacheErr("cache add task", cr.cacheDBClient.CacheTask(ctx, parent))
+		task, parentId = parent, parent.ParentId
+	}
+}
+
+// refreshSubtasks re-caches the descendants after a change cascaded to them.
+func (cr *CachedRepository) refreshSubtasks(ctx context.Context, task models.TaskExportData) {
+	if task.SubtasksTotal == 0 {
+		return
+	}
+
+	subtasks, err := cr.mainDBClient.ListSubtasks(ctx, task.Id)
+	if err != nil {
+		log.Printf("list subtasks err: %v\n", err)
+		return
+	}
+	for _, subtask := range subtasks {
+		logCacheErr("cache add task", cr.cacheDBClient.CacheTask(ctx, subtask))
+	}
+}
+
+func (cr *CachedRepository) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	return cr.mainDBClient.GetStats(ctx, req)
+}
+
+func (cr *CachedRepository) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	return cr.mainDBClient.GetTaskHistory(ctx, req)
+}
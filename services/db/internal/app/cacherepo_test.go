@@ -3,7 +3,10 @@ package app
 import (
 	"context"
 	"errors"
+	"net"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,6 +14,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 )
 
+type testCtxKey struct{}
+
 type fakeCacheController struct {
 	cacheTaskListCalls int
 	cacheTaskListCtx   context.Context
@@ -21,10 +26,12 @@ type fakeCacheController struct {
 	deleteTaskListCtx   context.Context
 	deleteTaskListErr   error
 
-	getTaskListCalls int
-	getTaskListCtx   context.Context
-	getTaskListRet   []models.TaskExportData
-	getTaskListErr   error
+	getTaskListCalls   int
+	getTaskListCtx     context.Context
+	getTaskListRet     []models.TaskExportData
+	getTaskListMissing []int
+	getTaskListStale   bool
+	getTaskListErr     error
 
 	cacheTaskCalls int
 	cacheTaskCtx   context.Context
@@ -36,10 +43,15 @@ type fakeCacheController struct {
 	deleteTaskByIdId    int
 	deleteTaskByIdErr   error
 
+	removeTasksCalls int
+	removeTasksIds   []int
+	removeTasksErr   error
+
 	getTaskByIdCalls int
 	getTaskByIdCtx   context.Context
 	getTaskByIdId    int
 	getTaskByIdRet   models.TaskExportData
+	getTaskByIdStale bool
 	getTaskByIdErr   error
 
 	flushAllDataCalls int
@@ -63,10 +75,11 @@ func (fcc *fakeCacheController) DeleteTaskList(ctx context.Context) error {
 	return fcc.deleteTaskListErr
 }
 
-func (fcc *fakeCacheController) GetTaskList(ctx context.Context) ([]models.TaskExportData, error) {
+func (fcc *fakeCacheController) GetTaskList(ctx context.Context) (CachedTaskList, error) {
 	fcc.getTaskListCalls++
 	fcc.getTaskListCtx = ctx
-	return fcc.getTaskListRet, fcc.getTaskListErr
+	list := CachedTaskList{Tasks: fcc.getTaskListRet, MissingIds: fcc.getTaskListMissing, Stale: fcc.getTaskListStale}
+	return list, fcc.getTaskListErr
 }
 
 func (fcc *fakeCacheController) CacheTask(ctx context.Context, task models.TaskExportData) error {
@@ -83,11 +96,17 @@ func (fcc *fakeCacheController) DeleteTaskById(ctx context.Context, id int) erro
 	return fcc.deleteTaskByIdErr
 }
 
-func (fcc *fakeCacheController) GetTaskById(ctx context.Context, id int) (models.TaskExportData, error) {
+func (fcc *fakeCacheController) RemoveTasksFromList(ctx context.Context, ids []int) error {
+	fcc.removeTasksCalls++
+	fcc.removeTasksIds = append(fcc.removeTasksIds, ids...)
+	return fcc.removeTasksErr
+}
+
+func (fcc *fakeCacheController) GetTaskById(ctx context.Context, id int) (models.TaskExportData, bool, error) {
 	fcc.getTaskByIdCalls++
 	fcc.getTaskByIdCtx = ctx
 	fcc.getTaskByIdId = id
-	return fcc.getTaskByIdRet, fcc.getTaskByIdErr
+	return fcc.getTaskByIdRet, fcc.getTaskByIdStale, fcc.getTaskByIdErr
 }
 
 func (fcc *fakeCacheController) FlushAllData(ctx context.Context) error {
@@ -184,11 +203,28 @@ func TestCacheRepoAddTask_Success_CallsCacheController(t *testing.T) {
 	if diff := cmp.Diff(wantTaskOut, fcr.cacheTaskIn[0]); diff != "" {
 		t.Fatal(diff)
 	}
-	if fcr.deleteTaskListCalls != 1 {
-		t.Fatalf("expected DeleteTaskList called once, got %d calls", fcr.deleteTaskListCalls)
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
 	}
-	if fcr.deleteTaskListCtx != ctx {
-		t.Fatalf("context mismatch")
+}
+
+func TestCacheRepoAddTask_IdempotencyKey_EvictsInsteadOfCaching(t *testing.T) {
+	ctx := context.Background()
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(
+		&fakeRepo{addTaskRet: models.TaskExportData{Id: 4}},
+		fcr)
+
+	_, _ = cr.AddTask(ctx, models.TaskImportData{Idempotency: &models.IdempotencyKey{Key: "abc"}})
+
+	if fcr.cacheTaskCalls != 0 {
+		t.Fatalf("expected CacheTask not called, got %d calls", fcr.cacheTaskCalls)
+	}
+	if fcr.deleteTaskByIdCalls != 1 || fcr.deleteTaskByIdId != 4 {
+		t.Fatalf("expected DeleteTaskById(4) called once, got %d calls with %d", fcr.deleteTaskByIdCalls, fcr.deleteTaskByIdId)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
 	}
 }
 
@@ -219,7 +255,7 @@ func TestCacheRepoDeleteTask_DelegatesToTaskRepo(t *testing.T) {
 		fr,
 		&fakeCacheController{})
 
-	err := cr.DeleteTask(ctx, wantId)
+	_, err := cr.DeleteTask(ctx, wantId)
 
 	if fr.deleteTaskCalls != 1 {
 		t.Fatalf("expected DeleteTask called=1, got=%d", fr.deleteTaskCalls)
@@ -243,22 +279,16 @@ func TestCacheRepoDeleteTask_Success_CallsCacheController(t *testing.T) {
 		&fakeRepo{},
 		fcr)
 
-	_ = cr.DeleteTask(ctx, wantId)
+	_, _ = cr.DeleteTask(ctx, wantId)
 
-	if fcr.deleteTaskByIdCalls != 1 {
-		t.Fatalf("expected DeleteTaskById called once, got %d calls", fcr.deleteTaskByIdCalls)
-	}
-	if fcr.deleteTaskByIdCtx != ctx {
-		t.Fatal("context mismatch")
-	}
-	if fcr.deleteTaskByIdId != wantId {
-		t.Fatalf("expected id=%d, got=%d", wantId, fcr.deleteTaskByIdId)
+	if fcr.removeTasksCalls != 1 {
+		t.Fatalf("expected RemoveTasksFromList called once, got %d calls", fcr.removeTasksCalls)
 	}
-	if fcr.deleteTaskListCalls != 1 {
-		t.Fatalf("expected DeleteTaskList called once, got %d calls", fcr.deleteTaskListCalls)
+	if diff := cmp.Diff([]int{wantId}, fcr.removeTasksIds); diff != "" {
+		t.Fatal(diff)
 	}
-	if fcr.deleteTaskListCtx != ctx {
-		t.Fatal("context mismatch")
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
 	}
 }
 
@@ -269,7 +299,7 @@ func TestCacheRepoDeleteTask_Error_DoesNotCallCacheController(t *testing.T) {
 		&fakeRepo{deleteTaskErr: wantErr},
 		fcr)
 
-	_ = cr.DeleteTask(context.Background(), 1)
+	_, _ = cr.DeleteTask(context.Background(), 1)
 	if fcr.deleteTaskByIdCalls != 0 {
 		t.Fatalf("expected DeleteTaskById not called, got %d calls", fcr.deleteTaskByIdCalls)
 	}
@@ -279,7 +309,7 @@ func TestCacheRepoDeleteTask_Error_DoesNotCallCacheController(t *testing.T) {
 }
 
 func TestCacheRepoListAllTasks_CacheMiss_DelegatesToTaskRepo(t *testing.T) {
-	ctx := context.Background()
+	ctx := context.WithValue(context.Background(), testCtxKey{}, "caller")
 	createdAtTS := time.Date(2025, 12, 10, 4, 6, 3, 2, time.UTC)
 	finishedAtTS := time.Date(2025, 12, 10, 3, 5, 2, 1, time.UTC)
 	wantTasksOut := []models.TaskExportData{
@@ -313,7 +343,9 @@ func TestCacheRepoListAllTasks_CacheMiss_DelegatesToTaskRepo(t *testing.T) {
 	if fr.listAllTasksCalls != 1 {
 		t.Fatalf("expected ListAllTasks called=1, got %d", fr.listAllTasksCalls)
 	}
-	if fr.listAllTasksCtx != ctx {
+	// The load is shared with concurrent callers, so it gets a context with
+	// the caller's values rather than the caller's context itself.
+	if fr.listAllTasksCtx.Value(testCtxKey{}) != "caller" {
 		t.Fatalf("context mismatch")
 	}
 	if !reflect.DeepEqual(got, wantTasksOut) {
@@ -375,7 +407,7 @@ func TestCacheRepoListAllTasks_CacheHit_DelegatesToCacheController(t *testing.T)
 }
 
 func TestCacheRepoListAllTasks_CacheMissTaskRepoSuccess_CallsCacheController(t *testing.T) {
-	ctx := context.Background()
+	ctx := context.WithValue(context.Background(), testCtxKey{}, "caller")
 	createdAtTS := time.Date(2025, 12, 10, 4, 6, 3, 2, time.UTC)
 	finishedAtTS := time.Date(2025, 12, 10, 3, 5, 2, 1, time.UTC)
 	wantTasksOut := []models.TaskExportData{
@@ -405,17 +437,15 @@ func TestCacheRepoListAllTasks_CacheMissTaskRepoSuccess_CallsCacheController(t *
 	if fcr.cacheTaskListCalls != 1 {
 		t.Fatalf("expected CacheTaskList called once, got %d calls", fcr.cacheTaskListCalls)
 	}
-	if fcr.cacheTaskListCtx != ctx {
+	if fcr.cacheTaskListCtx.Value(testCtxKey{}) != "caller" {
 		t.Fatal("context mismatch")
 	}
 	if diff := cmp.Diff(fcr.cacheTaskListTasks, wantTasksOut); diff != "" {
 		t.Fatal(diff)
 	}
-	if fcr.cacheTaskCalls != len(wantTasksOut) {
-		t.Fatalf("expected CacheTask calls=%d, got %d calls", len(wantTasksOut), fcr.cacheTaskCalls)
-	}
-	if diff := cmp.Diff(fcr.cacheTaskIn, wantTasksOut); diff != "" {
-		t.Fatal(diff)
+	// CacheTaskList caches the tasks of the list too.
+	if fcr.cacheTaskCalls != 0 {
+		t.Fatalf("expected CacheTask not called, got %d calls", fcr.cacheTaskCalls)
 	}
 }
 
@@ -502,11 +532,8 @@ func TestCacheRepoMarkTaskFinished_Success_CallsCacheController(t *testing.T) {
 	if diff := cmp.Diff(fcr.cacheTaskIn[0], wantTaskOut); diff != "" {
 		t.Fatal(diff)
 	}
-	if fcr.deleteTaskListCalls != 1 {
-		t.Fatalf("expected DeleteTaskList called once, got %d calls", fcr.deleteTaskListCalls)
-	}
-	if fcr.deleteTaskListCtx != ctx {
-		t.Fatal("context mismatch")
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
 	}
 }
 
@@ -525,3 +552,917 @@ func TestCacheRepoMarkTaskFinished_Error_DoesNotCallCacheController(t *testing.T
 		t.Fatalf("expected DeleteTaskList not called, got %d calls", fcr.deleteTaskListCalls)
 	}
 }
+
+func TestCacheRepoGetStats_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantReq := models.StatsRequest{
+		From:     time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC),
+		Bucket:   models.StatsBucketDay,
+		TimeZone: "UTC",
+	}
+	wantStats := models.Stats{OpenCount: 5, LongestStreak: 2}
+	wantErr := errors.New("my error")
+	fr := &fakeRepo{
+		getStatsRet: wantStats,
+		getStatsErr: wantErr,
+	}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.GetStats(ctx, wantReq)
+
+	if fr.getStatsCalls != 1 {
+		t.Fatalf("expected GetStats called=1, got=%d", fr.getStatsCalls)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if fr.getStatsCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if !reflect.DeepEqual(fr.getStatsIn, wantReq) {
+		t.Fatalf("stats request mismatch: want %+v got %+v", wantReq, fr.getStatsIn)
+	}
+	if !reflect.DeepEqual(got, wantStats) {
+		t.Fatalf("stats mismatch: want %+v got %+v", wantStats, got)
+	}
+	if fcr.getTaskListCalls != 0 || fcr.cacheTaskListCalls != 0 {
+		t.Fatalf("expected cache not touched")
+	}
+}
+
+func TestCacheRepoSetTaskPriority_Success_CallsCacheController(t *testing.T) {
+	ctx := context.Background()
+	wantTaskOut := models.TaskExportData{Id: 46, Priority: models.PriorityUrgent}
+	fr := &fakeRepo{setTaskPriorityRet: wantTaskOut}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.SetTaskPriority(ctx, 46, models.PriorityUrgent)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.setTaskPriorityCalls != 1 || fr.setTaskPriorityCtx != ctx {
+		t.Fatalf("expected SetTaskPriority called once with ctx, got %d calls", fr.setTaskPriorityCalls)
+	}
+	if !reflect.DeepEqual(got, wantTaskOut) {
+		t.Fatalf("task out mismatch: want %+v got %+v", wantTaskOut, got)
+	}
+	if fcr.cacheTaskCalls != 1 {
+		t.Fatalf("expected CacheTask called once, got %d calls", fcr.cacheTaskCalls)
+	}
+	if diff := cmp.Diff(fcr.cacheTaskIn[0], wantTaskOut); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoSetTaskPriority_Error_DoesNotCallCacheController(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(
+		&fakeRepo{setTaskPriorityErr: errors.New("boom")},
+		fcr)
+
+	_, _ = cr.SetTaskPriority(context.Background(), 1, models.PriorityLow)
+	if fcr.cacheTaskCalls != 0 {
+		t.Fatalf("expected CacheTask not called, got %d calls", fcr.cacheTaskCalls)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected DeleteTaskList not called, got %d calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoMoveTask_Success_CallsCacheController(t *testing.T) {
+	ctx := context.Background()
+	wantMove := models.TaskMove{Id: 46, AfterId: 3}
+	wantTaskOut := models.TaskExportData{Id: 46, Position: "ai"}
+	fr := &fakeRepo{moveTaskRet: wantTaskOut}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.MoveTask(ctx, wantMove)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.moveTaskCalls != 1 || fr.moveTaskIn != wantMove {
+		t.Fatalf("expected MoveTask called once with %+v, got %d calls with %+v", wantMove, fr.moveTaskCalls, fr.moveTaskIn)
+	}
+	if !reflect.DeepEqual(got, wantTaskOut) {
+		t.Fatalf("task out mismatch: want %+v got %+v", wantTaskOut, got)
+	}
+	if fcr.cacheTaskCalls != 1 {
+		t.Fatalf("expected CacheTask called once, got %d calls", fcr.cacheTaskCalls)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoMoveTask_Error_DoesNotCallCacheController(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(
+		&fakeRepo{moveTaskErr: errors.New("boom")},
+		fcr)
+
+	_, _ = cr.MoveTask(context.Background(), models.TaskMove{Id: 1, AfterId: 2})
+	if fcr.cacheTaskCalls != 0 {
+		t.Fatalf("expected CacheTask not called, got %d calls", fcr.cacheTaskCalls)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected DeleteTaskList not called, got %d calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoAddTaskTags_Success_CallsCacheController(t *testing.T) {
+	ctx := context.Background()
+	wantTaskOut := models.TaskExportData{Id: 46, Tags: []string{"home"}}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{addTaskTagsRet: wantTaskOut}, fcr)
+
+	_, _ = cr.AddTaskTags(ctx, 46, []string{"home"})
+
+	if fcr.cacheTaskCalls != 1 {
+		t.Fatalf("expected CacheTask called once, got %d calls", fcr.cacheTaskCalls)
+	}
+	if diff := cmp.Diff(fcr.cacheTaskIn[0], wantTaskOut); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoRemoveTaskTags_Error_DoesNotCallCacheController(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{removeTaskTagsErr: errors.New("boom")}, fcr)
+
+	_, _ = cr.RemoveTaskTags(context.Background(), 46, []string{"home"})
+
+	if fcr.cacheTaskCalls != 0 {
+		t.Fatalf("expected CacheTask not called, got %d calls", fcr.cacheTaskCalls)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected DeleteTaskList not called, got %d calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoRenameTag_Success_EvictsAffectedTasks(t *testing.T) {
+	ctx := context.Background()
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(
+		&fakeRepo{renameTagRet: models.TagChange{Name: "job", TaskIds: []int{3, 7}}},
+		fcr)
+
+	_, _ = cr.RenameTag(ctx, "work", "job")
+
+	if fcr.deleteTaskByIdCalls != 2 {
+		t.Fatalf("expected DeleteTaskById called twice, got %d calls", fcr.deleteTaskByIdCalls)
+	}
+	if fcr.deleteTaskByIdId != 7 {
+		t.Fatalf("expected last evicted id 7, got %d", fcr.deleteTaskByIdId)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoMergeTags_Error_DoesNotCallCacheController(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{mergeTagsErr: errors.New("boom")}, fcr)
+
+	_, _ = cr.MergeTags(context.Background(), []string{"job"}, "work")
+
+	if fcr.deleteTaskByIdCalls != 0 {
+		t.Fatalf("expected DeleteTaskById not called, got %d calls", fcr.deleteTaskByIdCalls)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected DeleteTaskList not called, got %d calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoListTags_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantTags := []models.TagUsage{{Name: "home", Count: 1}}
+	fr := &fakeRepo{listTagsRet: wantTags}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.ListTags(ctx)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.listTagsCalls != 1 || fr.listTagsCtx != ctx {
+		t.Fatalf("expected ListTags called once with ctx, got %d calls", fr.listTagsCalls)
+	}
+	if !reflect.DeepEqual(got, wantTags) {
+		t.Fatalf("tags mismatch: want %+v got %+v", wantTags, got)
+	}
+}
+
+func TestCacheRepoDeleteProject_Success_EvictsMovedTasks(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{deleteProjectRet: []int{5, 9}}, fcr)
+
+	_, _ = cr.DeleteProject(context.Background(), 3)
+
+	if fcr.deleteTaskByIdCalls != 2 {
+		t.Fatalf("expected DeleteTaskById called twice, got %d calls", fcr.deleteTaskByIdCalls)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoMoveTaskToProject_Success_CallsCacheController(t *testing.T) {
+	wantTaskOut := models.TaskExportData{Id: 46, ProjectId: 3}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{moveTaskToProjectRet: wantTaskOut}, fcr)
+
+	_, _ = cr.MoveTaskToProject(context.Background(), 46, 3)
+
+	if fcr.cacheTaskCalls != 1 {
+		t.Fatalf("expected CacheTask called once, got %d calls", fcr.cacheTaskCalls)
+	}
+	if diff := cmp.Diff(fcr.cacheTaskIn[0], wantTaskOut); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoMoveTaskToProject_Error_DoesNotCallCacheController(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{moveTaskToProjectErr: ErrProjectArchived}, fcr)
+
+	_, _ = cr.MoveTaskToProject(context.Background(), 46, 3)
+
+	if fcr.cacheTaskCalls != 0 || fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected cache untouched, got CacheTask=%d DeleteTaskList=%d", fcr.cacheTaskCalls, fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoDeleteTask_Success_EvictsSubtasksAndParent(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{deleteTaskRet: []int{8, 2}}, fcr)
+
+	_, _ = cr.DeleteTask(context.Background(), 5)
+
+	if diff := cmp.Diff([]int{5}, fcr.removeTasksIds); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskByIdCalls != 2 {
+		t.Fatalf("expected DeleteTaskById called 2 times, got %d calls", fcr.deleteTaskByIdCalls)
+	}
+	if fcr.deleteTaskByIdId != 2 {
+		t.Fatalf("expected last evicted id 2, got %d", fcr.deleteTaskByIdId)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoGetTask_CacheHit_DoesNotCallTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantTask := models.TaskExportData{Id: 3, Title: "cached"}
+	fr := &fakeRepo{}
+	fcr := &fakeCacheController{getTaskByIdRet: wantTask}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.GetTask(ctx, 3)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fcr.getTaskByIdCalls != 1 || fcr.getTaskByIdCtx != ctx || fcr.getTaskByIdId != 3 {
+		t.Fatalf("unexpected GetTaskById call: calls=%d id=%d", fcr.getTaskByIdCalls, fcr.getTaskByIdId)
+	}
+	if fr.getTaskCalls != 0 {
+		t.Fatalf("expected GetTask not called, got %d calls", fr.getTaskCalls)
+	}
+	if diff := cmp.Diff(got, wantTask); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCacheRepoGetTask_CacheMiss_CachesTaskFromTaskRepo(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testCtxKey{}, "caller")
+	wantTask := models.TaskExportData{Id: 3, Title: "from db"}
+	fr := &fakeRepo{getTaskRet: wantTask}
+	fcr := &fakeCacheController{getTaskByIdErr: ErrTaskNotFound}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.GetTask(ctx, 3)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.getTaskCalls != 1 || fr.getTaskCtx.Value(testCtxKey{}) != "caller" || fr.getTaskIn != 3 {
+		t.Fatalf("unexpected GetTask call: calls=%d id=%d", fr.getTaskCalls, fr.getTaskIn)
+	}
+	if fcr.cacheTaskCalls != 1 {
+		t.Fatalf("expected CacheTask called once, got %d calls", fcr.cacheTaskCalls)
+	}
+	if diff := cmp.Diff(got, wantTask); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCacheRepoAddTask_Subtask_EvictsParent(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{addTaskRet: models.TaskExportData{Id: 9, ParentId: 4}}, fcr)
+
+	_, _ = cr.AddTask(context.Background(), models.TaskImportData{ParentId: 4})
+
+	if fcr.deleteTaskByIdCalls != 1 || fcr.deleteTaskByIdId != 4 {
+		t.Fatalf("expected parent 4 evicted once, got calls=%d id=%d", fcr.deleteTaskByIdCalls, fcr.deleteTaskByIdId)
+	}
+}
+
+func TestCacheRepoMarkTaskFinished_WithSubtasks_RecachesSubtasks(t *testing.T) {
+	ctx := context.Background()
+	fr := &fakeRepo{
+		markTaskFinishedRet: models.TaskExportData{Id: 4, ParentId: 1, Finished: true, SubtasksTotal: 2, SubtasksDone: 2},
+		listSubtasksRet:     []models.TaskExportData{{Id: 5, ParentId: 4, Finished: true}, {Id: 6, ParentId: 4, Finished: true}},
+	}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	_, _ = cr.MarkTaskFinished(ctx, 4)
+
+	if fr.listSubtasksCalls != 1 || fr.listSubtasksIn != 4 {
+		t.Fatalf("unexpected ListSubtasks call: calls=%d id=%d", fr.listSubtasksCalls, fr.listSubtasksIn)
+	}
+	if fcr.cacheTaskCalls != 3 {
+		t.Fatalf("expected CacheTask called 3 times, got %d calls", fcr.cacheTaskCalls)
+	}
+	if diff := cmp.Diff(fcr.cacheTaskIn[1:], fr.listSubtasksRet); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskByIdCalls != 1 || fcr.deleteTaskByIdId != 1 {
+		t.Fatalf("expected parent 1 evicted once, got calls=%d id=%d", fcr.deleteTaskByIdCalls, fcr.deleteTaskByIdId)
+	}
+}
+
+func TestCacheRepoSkipOccurrence_Success_CallsCacheController(t *testing.T) {
+	wantTaskOut := models.TaskExportData{Id: 4, Recurrence: &models.Recurrence{Frequency: models.RecurrenceDaily}}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{skipOccurrenceRet: wantTaskOut}, fcr)
+
+	_, _ = cr.SkipOccurrence(context.Background(), 4)
+
+	if fcr.cacheTaskCalls != 1 {
+		t.Fatalf("expected CacheTask called once, got %d calls", fcr.cacheTaskCalls)
+	}
+	if diff := cmp.Diff(fcr.cacheTaskIn[0], wantTaskOut); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoSetTaskRecurrence_Error_DoesNotCallCacheController(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{setTaskRecurrenceErr: ErrRecurringSubtask}, fcr)
+
+	_, _ = cr.SetTaskRecurrence(context.Background(), 4, nil)
+
+	if fcr.cacheTaskCalls != 0 || fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected cache untouched, got CacheTask=%d DeleteTaskList=%d", fcr.cacheTaskCalls, fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoReopenTask_Subtask_RecachesAncestors(t *testing.T) {
+	ctx := context.Background()
+	fr := &fakeRepo{
+		reopenTaskRet: models.TaskExportData{Id: 4, ParentId: 1},
+		getTaskRet:    models.TaskExportData{Id: 1, SubtasksTotal: 1},
+	}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	_, _ = cr.ReopenTask(ctx, 4)
+
+	if fr.getTaskCalls != 1 || fr.getTaskIn != 1 {
+		t.Fatalf("unexpected GetTask call: calls=%d id=%d", fr.getTaskCalls, fr.getTaskIn)
+	}
+	if diff := cmp.Diff(fcr.cacheTaskIn, []models.TaskExportData{fr.reopenTaskRet, fr.getTaskRet}); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoReopenTask_Error_DoesNotCallCacheController(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{reopenTaskErr: ErrTaskNotFound}, fcr)
+
+	_, _ = cr.ReopenTask(context.Background(), 4)
+
+	if fcr.cacheTaskCalls != 0 || fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected cache untouched, got CacheTask=%d DeleteTaskList=%d", fcr.cacheTaskCalls, fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoRestoreTask_Subtask_CachesTaskAndEvictsParent(t *testing.T) {
+	wantTaskOut := models.TaskExportData{Id: 6, ParentId: 2}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{restoreTaskRet: wantTaskOut}, fcr)
+
+	_, _ = cr.RestoreTask(context.Background(), 6)
+
+	if fcr.cacheTaskCalls != 1 {
+		t.Fatalf("expected CacheTask called once, got %d calls", fcr.cacheTaskCalls)
+	}
+	if diff := cmp.Diff(fcr.cacheTaskIn[0], wantTaskOut); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskByIdCalls != 1 || fcr.deleteTaskByIdId != 2 {
+		t.Fatalf("expected parent 2 evicted once, got calls=%d id=%d", fcr.deleteTaskByIdCalls, fcr.deleteTaskByIdId)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoPurgeTask_DoesNotCallCacheController(t *testing.T) {
+	fr := &fakeRepo{}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	err := cr.PurgeTask(context.Background(), 6)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.purgeTaskCalls != 1 || fr.purgeTaskIn != 6 {
+		t.Fatalf("unexpected PurgeTask call: calls=%d id=%d", fr.purgeTaskCalls, fr.purgeTaskIn)
+	}
+	if fcr.cacheTaskCalls != 0 || fcr.deleteTaskByIdCalls != 0 || fcr.deleteTaskListCalls != 0 {
+		t.Fatal("expected cache untouched")
+	}
+}
+
+func TestCacheRepoGetTaskHistory_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantReq := models.TaskHistoryRequest{TaskId: 5, Limit: 20}
+	wantPage := models.TaskHistoryPage{Changes: []models.TaskChange{{Id: 1, TaskId: 5, Field: "created"}}}
+	wantErr := errors.New("my error")
+	fr := &fakeRepo{
+		getTaskHistoryRet: wantPage,
+		getTaskHistoryErr: wantErr,
+	}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.GetTaskHistory(ctx, wantReq)
+
+	if fr.getTaskHistoryCalls != 1 {
+		t.Fatalf("expected GetTaskHistory called=1, got=%d", fr.getTaskHistoryCalls)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if fr.getTaskHistoryCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if !reflect.DeepEqual(fr.getTaskHistoryIn, wantReq) {
+		t.Fatalf("history request mismatch: want %+v got %+v", wantReq, fr.getTaskHistoryIn)
+	}
+	if !reflect.DeepEqual(got, wantPage) {
+		t.Fatalf("history page mismatch: want %+v got %+v", wantPage, got)
+	}
+	if fcr.getTaskListCalls != 0 || fcr.cacheTaskListCalls != 0 {
+		t.Fatalf("expected cache not touched")
+	}
+}
+
+func TestCacheRepoBatchRemoveTasks_RemovesTasksAndEvictsChangedOnce(t *testing.T) {
+	ctx := context.Background()
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(
+		&fakeRepo{batchRemoveRet: []models.BatchItem{
+			{Id: 1, StaleIds: []int{2, 3}},
+			{Id: 4, StaleIds: []int{3}},
+			{Id: 5, Err: ErrTaskNotFound},
+		}},
+		fcr)
+
+	_, _ = cr.BatchRemoveTasks(ctx, []int{1, 4, 5})
+
+	if diff := cmp.Diff([]int{1, 4}, fcr.removeTasksIds); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskByIdCalls != 2 {
+		t.Fatalf("expected DeleteTaskById called for tasks 2 and 3, got %d calls", fcr.deleteTaskByIdCalls)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoBatchMarkFinished_AllFailed_DoesNotCallCacheController(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(
+		&fakeRepo{batchFinishRet: []models.BatchItem{{Id: 1, Err: ErrTaskNotFound}}},
+		fcr)
+
+	_, _ = cr.BatchMarkFinished(context.Background(), []int{1})
+
+	if fcr.deleteTaskByIdCalls != 0 || fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected cache untouched, got %d task and %d list deletes", fcr.deleteTaskByIdCalls, fcr.deleteTaskListCalls)
+	}
+}
+
+type fakeLocker struct {
+	tryLockCalls int
+	tryLockKey   string
+	tryLockErr   error
+	unlockCalls  int
+}
+
+func (fl *fakeLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	fl.tryLockCalls++
+	fl.tryLockKey = key
+	if fl.tryLockErr != nil {
+		return nil, fl.tryLockErr
+	}
+	return func(ctx context.Context) error {
+		fl.unlockCalls++
+		return nil
+	}, nil
+}
+
+func TestCacheRepoLoadShared_ConcurrentCalls_LoadOnce(t *testing.T) {
+	cr := NewCachedRepository(&fakeRepo{}, &fakeCacheController{})
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) ([]models.TaskExportData, error) {
+		loads.Add(1)
+		<-release
+		return []models.TaskExportData{{Id: 1}}, nil
+	}
+
+	const callers = 10
+	var started, done sync.WaitGroup
+	results := make([][]models.TaskExportData, callers)
+	for i := range callers {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			results[i], _, _ = loadShared(context.Background(), cr, taskListCacheKey, load)
+		}()
+	}
+	started.Wait()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Fatalf("expected one load, got %d", got)
+	}
+	for i, result := range results {
+		if len(result) != 1 || result[0].Id != 1 {
+			t.Fatalf("caller %d: unexpected result %+v", i, result)
+		}
+	}
+}
+
+func TestCacheRepoLoadShared_CallerGivesUp_ReturnsContextError(t *testing.T) {
+	cr := NewCachedRepository(&fakeRepo{}, &fakeCacheController{})
+	ctx, cancel := context.WithCancel(context.Background())
+	loadCtxErr := make(chan error, 1)
+	load := func(loadCtx context.Context) (int, error) {
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		loadCtxErr <- loadCtx.Err()
+		return 1, nil
+	}
+
+	_, _, err := loadShared(ctx, cr, "key", load)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if err := <-loadCtxErr; err != nil {
+		t.Fatalf("expected the shared load to go on, got %v", err)
+	}
+}
+
+func TestCacheRepoListAllTasks_StaleHit_ReturnsCachedAndReloads(t *testing.T) {
+	staleTasks := []models.TaskExportData{{Id: 1, Title: "old"}}
+	freshTasks := []models.TaskExportData{{Id: 1, Title: "new"}}
+	fr := &fakeRepo{listAllTasksRet: freshTasks}
+	fcr := &fakeCacheController{getTaskListRet: staleTasks, getTaskListStale: true}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.ListAllTasks(context.Background())
+	cr.background.Wait()
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if diff := cmp.Diff(staleTasks, got); diff != "" {
+		t.Fatal(diff)
+	}
+	if fr.listAllTasksCalls != 1 {
+		t.Fatalf("expected ListAllTasks called once in the background, got %d calls", fr.listAllTasksCalls)
+	}
+	if diff := cmp.Diff(freshTasks, fcr.cacheTaskListTasks); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCacheRepoGetTask_StaleHit_ReturnsCachedAndReloads(t *testing.T) {
+	fr := &fakeRepo{getTaskRet: models.TaskExportData{Id: 3, Title: "new"}}
+	fcr := &fakeCacheController{getTaskByIdRet: models.TaskExportData{Id: 3, Title: "old"}, getTaskByIdStale: true}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.GetTask(context.Background(), 3)
+	cr.background.Wait()
+
+	if err != nil || got.Title != "old" {
+		t.Fatalf("expected the stale task, got %+v, %v", got, err)
+	}
+	if fr.getTaskCalls != 1 || fr.getTaskIn != 3 {
+		t.Fatalf("unexpected GetTask call: calls=%d id=%d", fr.getTaskCalls, fr.getTaskIn)
+	}
+	if len(fcr.cacheTaskIn) != 1 || fcr.cacheTaskIn[0].Title != "new" {
+		t.Fatalf("expected the reloaded task cached, got %+v", fcr.cacheTaskIn)
+	}
+}
+
+func TestCacheRepoListAllTasks_LockHeld_WaitsForOtherReplica(t *testing.T) {
+	fr := &fakeRepo{}
+	fcr := &fakeCacheController{getTaskListErr: ErrTaskNotFound}
+	locker := &fakeLocker{tryLockErr: ErrLockHeld}
+	cr := NewCachedRepository(fr, fcr)
+	cr.SetLocker(locker)
+
+	// The other replica caches the list after our second try.
+	cachedTasks := []models.TaskExportData{{Id: 7}}
+	tasks, found, unlock := lockOrWait(context.Background(), cr, taskListCacheKey, func(ctx context.Context) ([]models.TaskExportData, error) {
+		if locker.tryLockCalls < 2 {
+			return nil, ErrTaskNotFound
+		}
+		return cachedTasks, nil
+	})
+	unlock()
+
+	if !found || len(tasks) != 1 || tasks[0].Id != 7 {
+		t.Fatalf("expected the list cached by the other replica, got %+v, found=%v", tasks, found)
+	}
+	if locker.tryLockKey != taskListCacheKey || locker.unlockCalls != 0 {
+		t.Fatalf("unexpected lock use: key=%q unlocks=%d", locker.tryLockKey, locker.unlockCalls)
+	}
+	if fr.listAllTasksCalls != 0 {
+		t.Fatalf("expected ListAllTasks not called, got %d calls", fr.listAllTasksCalls)
+	}
+}
+
+func TestCacheRepoListAllTasks_LockTaken_LoadsAndUnlocks(t *testing.T) {
+	wantTasks := []models.TaskExportData{{Id: 7}}
+	fr := &fakeRepo{listAllTasksRet: wantTasks}
+	fcr := &fakeCacheController{getTaskListErr: ErrTaskNotFound}
+	locker := &fakeLocker{}
+	cr := NewCachedRepository(fr, fcr)
+	cr.SetLocker(locker)
+
+	got, err := cr.ListAllTasks(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if diff := cmp.Diff(wantTasks, got); diff != "" {
+		t.Fatal(diff)
+	}
+	if locker.tryLockCalls != 1 || locker.unlockCalls != 1 {
+		t.Fatalf("expected lock taken and released once, got %d/%d", locker.tryLockCalls, locker.unlockCalls)
+	}
+	if fr.listAllTasksCalls != 1 || fcr.cacheTaskListCalls != 1 {
+		t.Fatalf("expected list loaded and cached once, got %d/%d", fr.listAllTasksCalls, fcr.cacheTaskListCalls)
+	}
+}
+
+func TestCacheRepoListAllTasks_MissingTasks_ReadsThemFromTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	fr := &fakeRepo{getTasksRet: []models.TaskExportData{{Id: 2, Title: "reread"}}}
+	fcr := &fakeCacheController{
+		getTaskListRet:     []models.TaskExportData{{Id: 1}, {Id: 4}},
+		getTaskListMissing: []int{2, 3},
+	}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.ListAllTasks(ctx)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if diff := cmp.Diff([]int{2, 3}, fr.getTasksIn); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := cmp.Diff(fr.getTasksRet, fcr.cacheTaskIn); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := cmp.Diff([]int{3}, fcr.removeTasksIds); diff != "" {
+		t.Fatal(diff)
+	}
+	want := []models.TaskExportData{{Id: 1}, {Id: 2, Title: "reread"}, {Id: 4}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+	if fr.listAllTasksCalls != 0 {
+		t.Fatalf("mainDB ListAllTasks expected not called, got called=%d", fr.listAllTasksCalls)
+	}
+}
+
+func TestCacheRepoListAllTasks_MissingTasksError_ReturnsError(t *testing.T) {
+	wantErr := errors.New("my error")
+	fcr := &fakeCacheController{getTaskListMissing: []int{2}}
+	cr := NewCachedRepository(&fakeRepo{getTasksErr: wantErr}, fcr)
+
+	_, err := cr.ListAllTasks(context.Background())
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if fcr.removeTasksCalls != 0 {
+		t.Fatalf("expected RemoveTasksFromList not called, got %d calls", fcr.removeTasksCalls)
+	}
+}
+
+func TestCacheRepoMarkTaskFinished_Recurring_CachesNextOccurrence(t *testing.T) {
+	fr := &fakeRepo{
+		markTaskFinishedRet: models.TaskExportData{Id: 4, Finished: true, NextOccurrenceId: 9},
+		getTaskRet:          models.TaskExportData{Id: 9},
+	}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	_, _ = cr.MarkTaskFinished(context.Background(), 4)
+
+	if fr.getTaskCalls != 1 || fr.getTaskIn != 9 {
+		t.Fatalf("unexpected GetTask call: calls=%d id=%d", fr.getTaskCalls, fr.getTaskIn)
+	}
+	if diff := cmp.Diff([]models.TaskExportData{fr.markTaskFinishedRet, fr.getTaskRet}, fcr.cacheTaskIn); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoMarkTaskFinished_NextOccurrenceError_DropsTaskList(t *testing.T) {
+	fr := &fakeRepo{
+		markTaskFinishedRet: models.TaskExportData{Id: 4, Finished: true, NextOccurrenceId: 9},
+		getTaskErr:          errors.New("my error"),
+	}
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(fr, fcr)
+
+	_, _ = cr.MarkTaskFinished(context.Background(), 4)
+
+	if fcr.deleteTaskListCalls != 1 {
+		t.Fatalf("expected DeleteTaskList called once, got %d calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoRestoreTask_WithSubtasks_DropsTaskList(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(&fakeRepo{restoreTaskRet: models.TaskExportData{Id: 6, SubtasksTotal: 2}}, fcr)
+
+	_, _ = cr.RestoreTask(context.Background(), 6)
+
+	if fcr.cacheTaskCalls != 1 {
+		t.Fatalf("expected CacheTask called once, got %d calls", fcr.cacheTaskCalls)
+	}
+	if fcr.deleteTaskListCalls != 1 {
+		t.Fatalf("expected DeleteTaskList called once, got %d calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoBatchAddTasks_CachesCreatedTasks(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(
+		&fakeRepo{batchAddRet: []models.BatchItem{
+			{Id: 1, Task: models.TaskExportData{Id: 1}},
+			{Err: ErrInvalidArgument},
+			{Id: 2, Task: models.TaskExportData{Id: 2, ParentId: 1}, StaleIds: []int{1}},
+		}},
+		fcr)
+
+	_, _ = cr.BatchAddTasks(context.Background(), nil)
+
+	if diff := cmp.Diff([]models.TaskExportData{{Id: 1}, {Id: 2, ParentId: 1}}, fcr.cacheTaskIn); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.deleteTaskByIdCalls != 1 || fcr.deleteTaskByIdId != 1 {
+		t.Fatalf("expected parent 1 evicted once, got calls=%d id=%d", fcr.deleteTaskByIdCalls, fcr.deleteTaskByIdId)
+	}
+	if fcr.deleteTaskListCalls != 0 {
+		t.Fatalf("expected the cached list kept, got %d DeleteTaskList calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoBatchMarkFinished_Recurring_DropsTaskList(t *testing.T) {
+	fcr := &fakeCacheController{}
+	cr := NewCachedRepository(
+		&fakeRepo{batchFinishRet: []models.BatchItem{
+			{Id: 1, Task: models.TaskExportData{Id: 1, Finished: true}},
+			{Id: 2, Task: models.TaskExportData{Id: 2, Finished: true, NextOccurrenceId: 3}},
+		}},
+		fcr)
+
+	_, _ = cr.BatchMarkFinished(context.Background(), []int{1, 2})
+
+	if fcr.cacheTaskCalls != 2 {
+		t.Fatalf("expected CacheTask called 2 times, got %d calls", fcr.cacheTaskCalls)
+	}
+	if fcr.deleteTaskListCalls != 1 {
+		t.Fatalf("expected DeleteTaskList called once, got %d calls", fcr.deleteTaskListCalls)
+	}
+}
+
+func TestCacheRepoListAllTasksOrStale_MainDBDown_ServesIncompleteCachedList(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	fcr := &fakeCacheController{
+		getTaskListRet:     []models.TaskExportData{{Id: 1}, {Id: 4}},
+		getTaskListMissing: []int{2},
+	}
+	cr := NewCachedRepository(&fakeRepo{getTasksErr: dialErr}, fcr)
+
+	got, stale, err := cr.ListAllTasksOrStale(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !stale {
+		t.Fatal("expected the list flagged stale")
+	}
+	if diff := cmp.Diff(fcr.getTaskListRet, got); diff != "" {
+		t.Fatal(diff)
+	}
+	if fcr.removeTasksCalls != 0 {
+		t.Fatalf("expected RemoveTasksFromList not called, got %d calls", fcr.removeTasksCalls)
+	}
+}
+
+func TestCacheRepoListAllTasksOrStale_StaleHitWhileMainDBDown_IsFlagged(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	fcr := &fakeCacheController{getTaskListErr: ErrTaskNotFound}
+	cr := NewCachedRepository(&fakeRepo{listAllTasksErr: dialErr}, fcr)
+	ctx := context.Background()
+
+	if _, _, err := cr.ListAllTasksOrStale(ctx); !MainDBUnavailable(err) {
+		t.Fatalf("expected the main DB error, got %v", err)
+	}
+	fcr.getTaskListErr = nil
+	fcr.getTaskListRet = []models.TaskExportData{{Id: 1}}
+	fcr.getTaskListStale = true
+	got, stale, err := cr.ListAllTasksOrStale(ctx)
+	_ = cr.Close()
+
+	if err != nil || !stale {
+		t.Fatalf("expected a stale list, got stale=%v err=%v", stale, err)
+	}
+	if diff := cmp.Diff(fcr.getTaskListRet, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCacheRepoListAllTasksOrStale_StaleHit_IsNotFlagged(t *testing.T) {
+	fcr := &fakeCacheController{getTaskListRet: []models.TaskExportData{{Id: 1}}, getTaskListStale: true}
+	cr := NewCachedRepository(&fakeRepo{}, fcr)
+
+	_, stale, _ := cr.ListAllTasksOrStale(context.Background())
+	_ = cr.Close()
+
+	if stale {
+		t.Fatal("expected a list reloaded in the background not flagged")
+	}
+}
+
+func TestCacheRepoGetTask_CacheUnavailable_ReadsMainDB(t *testing.T) {
+	fr := &fakeRepo{getTaskRet: models.TaskExportData{Id: 4}}
+	fcr := &fakeCacheController{getTaskByIdErr: ErrCacheUnavailable}
+	cr := NewCachedRepository(fr, fcr)
+
+	got, err := cr.GetTask(context.Background(), 4)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got.Id != 4 || fr.getTaskCalls != 1 {
+		t.Fatalf("expected task 4 read from the main DB, got %+v after %d calls", got, fr.getTaskCalls)
+	}
+}
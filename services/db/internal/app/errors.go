@@ -1,8 +1,41 @@
 package app
 
-import "errors"
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+)
 
 var (
-	ErrTaskAlreadyExists = errors.New("task already exists")
-	ErrTaskNotFound      = errors.New("task not found")
+	ErrTaskAlreadyExists    = errors.New("task already exists")
+	ErrTaskNotFound         = errors.New("task not found")
+	ErrInvalidArgument      = errors.New("invalid argument")
+	ErrTagNotFound          = errors.New("tag not found")
+	ErrTagAlreadyExists     = errors.New("tag already exists")
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrProjectExists        = errors.New("project already exists")
+	ErrProjectArchived      = errors.New("project is archived")
+	ErrInboxProtected       = errors.New("inbox can't be archived or deleted")
+	ErrTaskTooDeep          = errors.New("subtasks are nested too deep")
+	ErrSubtaskMove          = errors.New("subtask can only change project together with its parent")
+	ErrRecurringSubtask     = errors.New("subtasks can't be recurring")
+	ErrNotRecurring         = errors.New("task is not an open recurring task")
+	ErrSeriesEnded          = errors.New("recurring series has no more occurrences")
+	ErrNotInTrash           = errors.New("task is not in the trash")
+	ErrParentInTrash        = errors.New("parent task is in the trash")
+	ErrVersionMismatch      = errors.New("task was changed by someone else")
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for another request")
+	ErrWatcherTooSlow       = errors.New("watcher fell too far behind the task events")
+	ErrLockHeld             = errors.New("lock is held by someone else")
+	// ErrCacheUnavailable is returned by a cache that skips calls while its
+	// backend is down; the caller goes to the main DB without logging it.
+	ErrCacheUnavailable = errors.New("cache is unavailable")
 )
+
+// MainDBUnavailable reports whether err means the main DB couldn't be
+// reached at all, as opposed to a query that failed.
+func MainDBUnavailable(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr)
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const (
+	// eventReplaySize is how many recent events a resuming watcher can catch
+	// up on.
+	eventReplaySize = 1024
+	// watcherBufferSize is how many events may wait for a watcher before it
+	// is considered too slow and dropped.
+	watcherBufferSize = 64
+)
+
+// WatchTasks passes the task events matching req to send until ctx is done
+// or send fails. A watcher that doesn't keep up is dropped with
+// ErrWatcherTooSlow; it may resume from the last event it got.
+func (s *Service) WatchTasks(ctx context.Context, req models.WatchRequest, send func(models.TaskEvent) error) error {
+	log.Printf("IN: watch tasks: %+v\n", req)
+
+	if req.ProjectId < 0 || req.LastEventId < 0 {
+		err := fmt.Errorf("%w: project id and last event id can't be negative", ErrInvalidArgument)
+		log.Printf("OUT(ERR): watch tasks: %v\n", err)
+		return err
+	}
+
+	w := s.events.subscribe(req)
+	defer s.events.unsubscribe(w)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("OUT(OK): watch tasks: %+v\n", req)
+			return nil
+		case event, ok := <-w.events:
+			if !ok {
+				log.Printf("OUT(ERR): watch tasks: %v\n", ErrWatcherTooSlow)
+				return ErrWatcherTooSlow
+			}
+			if err := send(event); err != nil {
+				log.Printf("OUT(ERR): watch tasks: %v\n", err)
+				return err
+			}
+		}
+	}
+}
+
+// PublishTaskEvent passes a committed task event to the watchers.
+func (s *Service) PublishTaskEvent(event models.TaskEvent) {
+	s.events.publish(event)
+}
+
+// ResetTaskEvents tells the watchers that events may have been lost, e.g.
+// while the connection delivering them was down.
+func (s *Service) ResetTaskEvents() {
+	log.Println("task events may have been lost, resetting watchers")
+	s.events.reset()
+}
+
+// eventHub fans task events out to watchers and keeps the most recent ones
+// for watchers that reconnect.
+type eventHub struct {
+	mu         sync.Mutex
+	replaySize int
+	recent     []models.TaskEvent
+	watchers   map[*watcher]struct{}
+}
+
+type watcher struct {
+	projectId int
+	events    chan models.TaskEvent
+}
+
+func newEventHub(replaySize int) *eventHub {
+	return &eventHub{
+		replaySize: replaySize,
+		watchers:   make(map[*watcher]struct{})}
+}
+
+func (w *watcher) wants(event models.TaskEvent) bool {
+	return w.projectId == 0 || event.Type == models.TaskEventReset || event.ProjectId == w.projectId
+}
+
+// subscribe registers a watcher. When req resumes after an event that is no
+// longer kept, the watcher gets a reset event first.
+func (h *eventHub) subscribe(req models.WatchRequest) *watcher {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w := &watcher{projectId: req.ProjectId}
+
+	var replay []models.TaskEvent
+	if req.LastEventId != 0 {
+		i := slices.IndexFunc(h.recent, func(event models.TaskEvent) bool { return event.Id == req.LastEventId })
+		if i < 0 {
+			replay = append(replay, h.resetEvent())
+		} else {
+			for _, event := range h.recent[i+1:] {
+				if w.wants(event) {
+					replay = append(replay, event)
+				}
+			}
+		}
+	}
+
+	w.events = make(chan models.TaskEvent, watcherBufferSize+len(replay))
+	for _, event := range replay {
+		w.events <- event
+	}
+
+	h.watchers[w] = struct{}{}
+	return w
+}
+
+func (h *eventHub) unsubscribe(w *watcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.watchers[w]; ok {
+		delete(h.watchers, w)
+		close(w.events)
+	}
+}
+
+func (h *eventHub) publish(event models.TaskEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.recent = append(h.recent, event)
+	if len(h.recent) > h.replaySize {
+		h.recent = slices.Clone(h.recent[len(h.recent)-h.replaySize:])
+	}
+
+	h.broadcast(event)
+}
+
+// reset forgets the kept events, since the ones after them may be missing,
+// and tells every watcher to reload.
+func (h *eventHub) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.recent = nil
+	h.broadcast(h.resetEvent())
+}
+
+// broadcast must be called with h.mu held. A watcher whose buffer is full is
+// dropped rather than allowed to hold up the others.
+func (h *eventHub) broadcast(event models.TaskEvent) {
+	for w := range h.watchers {
+		if !w.wants(event) {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+			delete(h.watchers, w)
+			close(w.events)
+		}
+	}
+}
+
+// resetEvent carries the id of the latest kept event, if any, so that a
+// watcher resuming from it later can catch up without reloading again.
+func (h *eventHub) resetEvent() models.TaskEvent {
+	event := models.TaskEvent{Type: models.TaskEventReset}
+	if len(h.recent) > 0 {
+		event.Id = h.recent[len(h.recent)-1].Id
+	}
+	return event
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func taskEvent(id, projectId int) models.TaskEvent {
+	return models.TaskEvent{Id: id, Type: models.TaskEventUpdated, TaskId: id, ProjectId: projectId}
+}
+
+func drain(w *watcher) []models.TaskEvent {
+	var out []models.TaskEvent
+	for {
+		select {
+		case event, ok := <-w.events:
+			if !ok {
+				return out
+			}
+			out = append(out, event)
+		default:
+			return out
+		}
+	}
+}
+
+func TestEventHub_PublishesToWatchersOfTheProject(t *testing.T) {
+	hub := newEventHub(10)
+	all := hub.subscribe(models.WatchRequest{})
+	project := hub.subscribe(models.WatchRequest{ProjectId: 2})
+
+	hub.publish(taskEvent(1, 1))
+	hub.publish(taskEvent(2, 2))
+
+	if diff := cmp.Diff([]models.TaskEvent{taskEvent(1, 1), taskEvent(2, 2)}, drain(all)); diff != "" {
+		t.Fatalf("all projects (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]models.TaskEvent{taskEvent(2, 2)}, drain(project)); diff != "" {
+		t.Fatalf("project 2 (-want +got):\n%s", diff)
+	}
+}
+
+func TestEventHub_ResumesAfterLastEventId(t *testing.T) {
+	hub := newEventHub(10)
+	for id := 1; id <= 4; id++ {
+		hub.publish(taskEvent(id, id%2))
+	}
+
+	w := hub.subscribe(models.WatchRequest{ProjectId: 1, LastEventId: 1})
+
+	if diff := cmp.Diff([]models.TaskEvent{taskEvent(3, 1)}, drain(w)); diff != "" {
+		t.Fatalf("replay (-want +got):\n%s", diff)
+	}
+}
+
+func TestEventHub_UnknownLastEventId_SendsReset(t *testing.T) {
+	hub := newEventHub(2)
+	for id := 1; id <= 3; id++ {
+		hub.publish(taskEvent(id, 1))
+	}
+
+	w := hub.subscribe(models.WatchRequest{LastEventId: 1})
+
+	want := []models.TaskEvent{{Id: 3, Type: models.TaskEventReset}}
+	if diff := cmp.Diff(want, drain(w)); diff != "" {
+		t.Fatalf("replay (-want +got):\n%s", diff)
+	}
+}
+
+func TestEventHub_Reset_ForgetsEventsAndNotifiesWatchers(t *testing.T) {
+	hub := newEventHub(10)
+	hub.publish(taskEvent(1, 1))
+	w := hub.subscribe(models.WatchRequest{ProjectId: 2})
+
+	hub.reset()
+
+	if diff := cmp.Diff([]models.TaskEvent{{Type: models.TaskEventReset}}, drain(w)); diff != "" {
+		t.Fatalf("events (-want +got):\n%s", diff)
+	}
+	if len(hub.recent) != 0 {
+		t.Fatalf("expected no kept events, got %v", hub.recent)
+	}
+}
+
+func TestEventHub_SlowWatcher_IsDropped(t *testing.T) {
+	hub := newEventHub(10)
+	slow := hub.subscribe(models.WatchRequest{})
+	other := hub.subscribe(models.WatchRequest{ProjectId: 2})
+
+	for id := 1; id <= watcherBufferSize+1; id++ {
+		hub.publish(taskEvent(id, 1))
+	}
+
+	if got := len(drain(slow)); got != watcherBufferSize {
+		t.Fatalf("expected %d buffered events, got %d", watcherBufferSize, got)
+	}
+	if _, ok := hub.watchers[slow]; ok {
+		t.Fatalf("expected the slow watcher to be dropped")
+	}
+	if _, ok := hub.watchers[other]; !ok {
+		t.Fatalf("expected the other watcher to stay subscribed")
+	}
+}
+
+func TestServiceWatchTasks_SendsEventsUntilSendFails(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	svc.PublishTaskEvent(taskEvent(1, 1))
+	svc.PublishTaskEvent(taskEvent(2, 1))
+	wantErr := errors.New("client gone")
+
+	var got []models.TaskEvent
+	err := svc.WatchTasks(context.Background(), models.WatchRequest{LastEventId: 1}, func(event models.TaskEvent) error {
+		got = append(got, event)
+		svc.PublishTaskEvent(taskEvent(3, 1))
+		if len(got) == 2 {
+			return wantErr
+		}
+		return nil
+	})
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if diff := cmp.Diff([]models.TaskEvent{taskEvent(2, 1), taskEvent(3, 1)}, got); diff != "" {
+		t.Fatalf("events (-want +got):\n%s", diff)
+	}
+	if len(svc.events.watchers) != 0 {
+		t.Fatalf("expected the watcher to be unsubscribed")
+	}
+}
+
+func TestServiceWatchTasks_CanceledContext_ReturnsNil(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := svc.WatchTasks(ctx, models.WatchRequest{}, func(models.TaskEvent) error { return nil })
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
+
+func TestServiceWatchTasks_NegativeIds_ReturnsInvalidArgument(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+
+	err := svc.WatchTasks(context.Background(), models.WatchRequest{ProjectId: -1}, func(models.TaskEvent) error { return nil })
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// withOverdue recomputes the overdue flag against the current time, so that
+// tasks served from the cache agree with freshly loaded ones.
+func withOverdue(task models.TaskExportData, now time.Time) models.TaskExportData {
+	task.Overdue = !task.Finished && task.DueAt != nil && task.DueAt.Before(now)
+	return task
+}
+
+func withOverdueAll(tasks []models.TaskExportData, now time.Time) []models.TaskExportData {
+	if tasks == nil {
+		return nil
+	}
+
+	out := make([]models.TaskExportData, 0, len(tasks))
+	for _, task := range tasks {
+		out = append(out, withOverdue(task, now))
+	}
+	return out
+}
+
+// dueWindow returns the [start, end) interval of due dates matching the filter
+// in the filter's time zone. Weeks start on Monday.
+func dueWindow(filter models.TaskFilter, now time.Time) (time.Time, time.Time, error) {
+	timeZone := filter.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: unknown time zone %q", ErrInvalidArgument, filter.TimeZone)
+	}
+
+	localNow := now.In(loc)
+	startOfDay := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, loc)
+
+	switch filter.Due {
+	case models.DueFilterToday:
+		return startOfDay, startOfDay.AddDate(0, 0, 1), nil
+	case models.DueFilterThisWeek:
+		daysSinceMonday := (int(startOfDay.Weekday()) + 6) % 7
+		startOfWeek := startOfDay.AddDate(0, 0, -daysSinceMonday)
+		return startOfWeek, startOfWeek.AddDate(0, 0, 7), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: due filter %d has no window", ErrInvalidArgument, filter.Due)
+	}
+}
+
+func filterTasks(tasks []models.TaskExportData, filter models.TaskFilter, now time.Time) ([]models.TaskExportData, error) {
+	if filter.TagMatch != models.TagMatchAny && filter.TagMatch != models.TagMatchAll {
+		return nil, fmt.Errorf("%w: unknown tag match %d", ErrInvalidArgument, filter.TagMatch)
+	}
+	tags, err := normalizeTags(filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches func(task models.TaskExportData) bool
+
+	switch filter.Due {
+	case models.DueFilterAny:
+		matches = func(models.TaskExportData) bool { return true }
+	case models.DueFilterOverdue:
+		matches = func(task models.TaskExportData) bool { return task.Overdue }
+	case models.DueFilterToday, models.DueFilterThisWeek:
+		start, end, err := dueWindow(filter, now)
+		if err != nil {
+			return nil, err
+		}
+		matches = func(task models.TaskExportData) bool {
+			return task.DueAt != nil && !task.DueAt.Before(start) && task.DueAt.Before(end)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown due filter %d", ErrInvalidArgument, filter.Due)
+	}
+
+	out := make([]models.TaskExportData, 0)
+	for _, task := range tasks {
+		if filter.ProjectId != 0 && task.ProjectId != filter.ProjectId {
+			continue
+		}
+		if matches(task) && matchesTags(task, tags, filter.TagMatch) {
+			out = append(out, task)
+		}
+	}
+	return out, nil
+}
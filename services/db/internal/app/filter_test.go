@@ -0,0 +1,119 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithOverdue(t *testing.T) {
+	now := time.Date(2025, 12, 17, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		in   models.TaskExportData
+		want bool
+	}{
+		{name: "no due date", in: models.TaskExportData{}, want: false},
+		{name: "due in the future", in: models.TaskExportData{DueAt: &future}, want: false},
+		{name: "due in the past", in: models.TaskExportData{DueAt: &past}, want: true},
+		{name: "finished after due date", in: models.TaskExportData{DueAt: &past, Finished: true}, want: false},
+		{name: "stale flag from cache", in: models.TaskExportData{DueAt: &future, Overdue: true}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withOverdue(tt.in, now)
+			if got.Overdue != tt.want {
+				t.Fatalf("expected overdue=%t, got %t", tt.want, got.Overdue)
+			}
+		})
+	}
+}
+
+func TestFilterTasks(t *testing.T) {
+	// Wednesday 21:30 UTC is already Thursday 00:30 in Moscow,
+	// so "today" depends on the requested time zone.
+	now := time.Date(2025, 12, 17, 21, 30, 0, 0, time.UTC)
+	overdue := now.Add(-time.Hour)
+	laterTodayMoscow := time.Date(2025, 12, 17, 23, 0, 0, 0, time.UTC)
+	sundayMoscow := time.Date(2025, 12, 21, 20, 0, 0, 0, time.UTC)
+	nextMondayMoscow := time.Date(2025, 12, 21, 21, 0, 0, 0, time.UTC)
+
+	tasks := withOverdueAll([]models.TaskExportData{
+		{Id: 1},
+		{Id: 2, DueAt: &overdue},
+		{Id: 3, DueAt: &laterTodayMoscow},
+		{Id: 4, DueAt: &sundayMoscow},
+		{Id: 5, DueAt: &nextMondayMoscow},
+		{Id: 6, DueAt: &overdue, Finished: true},
+	}, now)
+
+	tests := []struct {
+		name    string
+		filter  models.TaskFilter
+		wantIds []int
+		wantErr error
+	}{
+		{
+			name:    "any",
+			filter:  models.TaskFilter{},
+			wantIds: []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:    "overdue",
+			filter:  models.TaskFilter{Due: models.DueFilterOverdue},
+			wantIds: []int{2},
+		},
+		{
+			name:    "today in UTC",
+			filter:  models.TaskFilter{Due: models.DueFilterToday, TimeZone: "UTC"},
+			wantIds: []int{2, 3, 6},
+		},
+		{
+			name:    "today in Moscow",
+			filter:  models.TaskFilter{Due: models.DueFilterToday, TimeZone: "Europe/Moscow"},
+			wantIds: []int{3},
+		},
+		{
+			name:    "this week in Moscow",
+			filter:  models.TaskFilter{Due: models.DueFilterThisWeek, TimeZone: "Europe/Moscow"},
+			wantIds: []int{2, 3, 4, 6},
+		},
+		{
+			name:    "unknown time zone",
+			filter:  models.TaskFilter{Due: models.DueFilterToday, TimeZone: "Mars/Olympus"},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "unknown due filter",
+			filter:  models.TaskFilter{Due: models.DueFilter(42)},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterTasks(tasks, tt.filter, now)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected err %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			gotIds := make([]int, 0, len(got))
+			for _, task := range got {
+				gotIds = append(gotIds, task.Id)
+			}
+			if diff := cmp.Diff(tt.wantIds, gotIds); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// normalizeHistoryRequest applies the default page size and rejects
+// negative cursors and pages larger than maxHistoryLimit.
+func normalizeHistoryRequest(req models.TaskHistoryRequest) (models.TaskHistoryRequest, error) {
+	if req.Limit == 0 {
+		req.Limit = defaultHistoryLimit
+	}
+	if req.Limit < 0 || req.Limit > maxHistoryLimit {
+		return req, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidArgument, maxHistoryLimit)
+	}
+	if req.Cursor < 0 {
+		return req, fmt.Errorf("%w: cursor must not be negative", ErrInvalidArgument)
+	}
+	return req, nil
+}
+
+func (s *Service) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	log.Printf("IN: get task history: %+v\n", req)
+
+	req, err := normalizeHistoryRequest(req)
+	if err != nil {
+		log.Printf("OUT(ERR): get task history: %v\n", err)
+		return models.TaskHistoryPage{}, err
+	}
+
+	page, err := s.dbController.GetTaskHistory(ctx, req)
+
+	if err != nil {
+		log.Printf("OUT(ERR): get task history: %v\n", err)
+	} else {
+		log.Printf("OUT(OK): get task history: %+v\n", page)
+	}
+
+	return page, err
+}
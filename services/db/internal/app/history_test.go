@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeHistoryRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      models.TaskHistoryRequest
+		want    models.TaskHistoryRequest
+		wantErr error
+	}{
+		{
+			name: "explicit request",
+			in:   models.TaskHistoryRequest{TaskId: 7, Limit: 10, Cursor: 42},
+			want: models.TaskHistoryRequest{TaskId: 7, Limit: 10, Cursor: 42},
+		},
+		{
+			name: "default limit",
+			in:   models.TaskHistoryRequest{TaskId: 7},
+			want: models.TaskHistoryRequest{TaskId: 7, Limit: defaultHistoryLimit},
+		},
+		{
+			name:    "negative limit",
+			in:      models.TaskHistoryRequest{TaskId: 7, Limit: -1},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "limit too large",
+			in:      models.TaskHistoryRequest{TaskId: 7, Limit: maxHistoryLimit + 1},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "negative cursor",
+			in:      models.TaskHistoryRequest{TaskId: 7, Cursor: -5},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeHistoryRequest(tt.in)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected err %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestServiceGetTaskHistory_InvalidRequest_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.GetTaskHistory(context.Background(), models.TaskHistoryRequest{TaskId: 1, Limit: -1})
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.getTaskHistoryCalls != 0 {
+		t.Fatalf("expected GetTaskHistory not called, got %d", fakeRepo.getTaskHistoryCalls)
+	}
+}
+
+func TestActorFrom(t *testing.T) {
+	if got := ActorFrom(context.Background()); got != "" {
+		t.Fatalf("expected empty actor, got %q", got)
+	}
+
+	ctx := WithActor(context.Background(), "alice")
+
+	if got := ActorFrom(ctx); got != "alice" {
+		t.Fatalf("expected %q, got %q", "alice", got)
+	}
+}
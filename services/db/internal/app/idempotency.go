@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const (
+	// IdempotencyKeyTTL is how long a repeated create request returns the
+	// task created by the first one.
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+)
+
+// withIdempotency fills the fingerprint and the expiry of the task's
+// idempotency key. The fingerprint covers the normalized request, so requests
+// that differ only in tag case or order are still the same request.
+func withIdempotency(task models.TaskImportData, now time.Time) (models.TaskImportData, error) {
+	if task.Idempotency == nil {
+		return task, nil
+	}
+
+	key := task.Idempotency.Key
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return task, fmt.Errorf("%w: idempotency key must be 1 to %d bytes long", ErrInvalidArgument, maxIdempotencyKeyLength)
+	}
+
+	request := task
+	request.Idempotency = nil
+	data, err := json.Marshal(request)
+	if err != nil {
+		return task, err
+	}
+	sum := sha256.Sum256(data)
+
+	task.Idempotency = &models.IdempotencyKey{
+		Key:         key,
+		Fingerprint: hex.EncodeToString(sum[:]),
+		ExpiresAt:   now.Add(IdempotencyKeyTTL),
+	}
+	return task, nil
+}
+
+// PurgeIdempotencyKeys deletes the expired idempotency keys.
+func (s *Service) PurgeIdempotencyKeys(ctx context.Context) (int, error) {
+	log.Println("IN: purge expired idempotency keys")
+
+	purged, err := s.dbController.PurgeIdempotencyKeys(ctx, s.now())
+
+	if err != nil {
+		log.Printf("OUT(ERR): purge idempotency keys: %v\n", err)
+	} else {
+		log.Printf("OUT(OK): purge idempotency keys: %d keys\n", purged)
+	}
+
+	return purged, err
+}
+
+// RunIdempotencyKeyPurge calls PurgeIdempotencyKeys every interval until ctx
+// is done.
+func (s *Service) RunIdempotencyKeyPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = s.PurgeIdempotencyKeys(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
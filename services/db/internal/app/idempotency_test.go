@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+func TestWithIdempotency_NoKey_KeepsTask(t *testing.T) {
+	task := models.TaskImportData{Title: "title"}
+
+	got, err := withIdempotency(task, time.Now())
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got.Idempotency != nil {
+		t.Fatalf("expected no idempotency key, got %+v", got.Idempotency)
+	}
+}
+
+func TestWithIdempotency_FillsFingerprintAndExpiry(t *testing.T) {
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	task := models.TaskImportData{Title: "title", Idempotency: &models.IdempotencyKey{Key: "abc"}}
+
+	got, err := withIdempotency(task, now)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got.Idempotency.Key != "abc" {
+		t.Fatalf("expected key abc, got %q", got.Idempotency.Key)
+	}
+	if got.Idempotency.Fingerprint == "" {
+		t.Fatalf("expected fingerprint")
+	}
+	if want := now.Add(IdempotencyKeyTTL); !got.Idempotency.ExpiresAt.Equal(want) {
+		t.Fatalf("expected expiry %v, got %v", want, got.Idempotency.ExpiresAt)
+	}
+	if task.Idempotency.Fingerprint != "" {
+		t.Fatalf("expected the original key untouched, got %+v", task.Idempotency)
+	}
+}
+
+func TestWithIdempotency_FingerprintDependsOnRequestOnly(t *testing.T) {
+	now := time.Now()
+	first, _ := withIdempotency(models.TaskImportData{Title: "title", Idempotency: &models.IdempotencyKey{Key: "a"}}, now)
+	sameData, _ := withIdempotency(models.TaskImportData{Title: "title", Idempotency: &models.IdempotencyKey{Key: "b"}}, now.Add(time.Hour))
+	otherData, _ := withIdempotency(models.TaskImportData{Title: "other", Idempotency: &models.IdempotencyKey{Key: "a"}}, now)
+
+	if first.Idempotency.Fingerprint != sameData.Idempotency.Fingerprint {
+		t.Fatalf("expected equal fingerprints for equal data")
+	}
+	if first.Idempotency.Fingerprint == otherData.Idempotency.Fingerprint {
+		t.Fatalf("expected different fingerprints for different data")
+	}
+}
+
+func TestWithIdempotency_InvalidKey(t *testing.T) {
+	for _, key := range []string{"", strings.Repeat("k", maxIdempotencyKeyLength+1)} {
+		_, err := withIdempotency(models.TaskImportData{Idempotency: &models.IdempotencyKey{Key: key}}, time.Now())
+
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("key of %d bytes: expected %v, got %v", len(key), ErrInvalidArgument, err)
+		}
+	}
+}
+
+func TestServiceAddTask_IdempotencyKey_PassesFingerprintToTaskRepo(t *testing.T) {
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.AddTask(context.Background(), models.TaskImportData{
+		Title:       "title",
+		Idempotency: &models.IdempotencyKey{Key: "abc"},
+	})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	key := fakeRepo.addTaskIn.Idempotency
+	if key == nil || key.Key != "abc" || key.Fingerprint == "" || !key.ExpiresAt.Equal(now.Add(IdempotencyKeyTTL)) {
+		t.Fatalf("unexpected idempotency key: %+v", key)
+	}
+}
+
+func TestServiceAddTask_InvalidIdempotencyKey_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.AddTask(context.Background(), models.TaskImportData{Title: "title", Idempotency: &models.IdempotencyKey{}})
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.addTaskCalls != 0 {
+		t.Fatalf("expected AddTask not called, got %d calls", fakeRepo.addTaskCalls)
+	}
+}
+
+func TestServicePurgeIdempotencyKeys_PassesNow(t *testing.T) {
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	fakeRepo := &fakeRepo{purgeKeysRet: 2}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	purged, err := svc.PurgeIdempotencyKeys(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if purged != 2 {
+		t.Fatalf("expected 2 purged, got %d", purged)
+	}
+	if !fakeRepo.purgeKeysIn.Equal(now) {
+		t.Fatalf("expected now %v, got %v", now, fakeRepo.purgeKeysIn)
+	}
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+func validateTaskMove(move models.TaskMove) error {
+	if (move.BeforeId == 0) == (move.AfterId == 0) {
+		return fmt.Errorf("%w: exactly one of before_id and after_id must be set", ErrInvalidArgument)
+	}
+	if move.BeforeId == move.Id || move.AfterId == move.Id {
+		return fmt.Errorf("%w: task %d cannot be moved relative to itself", ErrInvalidArgument, move.Id)
+	}
+	return nil
+}
+
+// sortTasks orders tasks in place. Ties are broken by manual position and
+// then by id, so the order is stable across cache hits and misses.
+func sortTasks(tasks []models.TaskExportData, order models.TaskSort) error {
+	byPosition := func(a, b models.TaskExportData) int {
+		return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.Id, b.Id))
+	}
+
+	switch order {
+	case models.TaskSortId:
+		slices.SortFunc(tasks, func(a, b models.TaskExportData) int {
+			return cmp.Compare(a.Id, b.Id)
+		})
+	case models.TaskSortPriority:
+		slices.SortFunc(tasks, func(a, b models.TaskExportData) int {
+			return cmp.Or(cmp.Compare(b.Priority, a.Priority), byPosition(a, b))
+		})
+	case models.TaskSortPosition:
+		slices.SortFunc(tasks, byPosition)
+	default:
+		return fmt.Errorf("%w: unknown sort order %d", ErrInvalidArgument, order)
+	}
+	return nil
+}
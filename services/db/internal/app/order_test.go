@@ -0,0 +1,79 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+func TestValidateTaskMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      models.TaskMove
+		wantErr bool
+	}{
+		{name: "before another task", in: models.TaskMove{Id: 1, BeforeId: 2}},
+		{name: "after another task", in: models.TaskMove{Id: 1, AfterId: 2}},
+		{name: "no anchor", in: models.TaskMove{Id: 1}, wantErr: true},
+		{name: "both anchors", in: models.TaskMove{Id: 1, BeforeId: 2, AfterId: 3}, wantErr: true},
+		{name: "relative to itself", in: models.TaskMove{Id: 1, AfterId: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTaskMove(tt.in)
+
+			if tt.wantErr && !errors.Is(err, ErrInvalidArgument) {
+				t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	tasks := func() []models.TaskExportData {
+		return []models.TaskExportData{
+			{Id: 3, Priority: models.PriorityLow, Position: "r"},
+			{Id: 1, Priority: models.PriorityUrgent, Position: "z"},
+			{Id: 4, Priority: models.PriorityUrgent, Position: "i"},
+			{Id: 2, Priority: models.PriorityNone, Position: "a"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		order   models.TaskSort
+		wantIds []int
+	}{
+		{name: "by id", order: models.TaskSortId, wantIds: []int{1, 2, 3, 4}},
+		{name: "by priority", order: models.TaskSortPriority, wantIds: []int{4, 1, 3, 2}},
+		{name: "by position", order: models.TaskSortPosition, wantIds: []int{2, 4, 3, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tasks()
+
+			if err := sortTasks(got, tt.order); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			for i, id := range tt.wantIds {
+				if got[i].Id != id {
+					t.Fatalf("expected ids %v, got %+v", tt.wantIds, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSortTasks_UnknownOrder_ReturnsError(t *testing.T) {
+	err := sortTasks(nil, models.TaskSort(42))
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+}
@@ -0,0 +1,20 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxProjectNameLength = 50
+
+func normalizeProjectName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: empty project name", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(name) > maxProjectNameLength {
+		return "", fmt.Errorf("%w: project name is longer than %d characters", ErrInvalidArgument, maxProjectNameLength)
+	}
+	return name, nil
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeProjectName(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "regular name", in: "Work", want: "Work"},
+		{name: "surrounding spaces", in: "  Дом ", want: "Дом"},
+		{name: "empty name", in: "   ", wantErr: true},
+		{name: "too long name", in: strings.Repeat("я", maxProjectNameLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeProjectName(tt.in)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const maxRecurrenceInterval = 365
+
+// normalizeRecurrence returns nil for a task that doesn't repeat and fills in
+// the defaults otherwise: interval 1, UTC, sorted unique weekdays.
+func normalizeRecurrence(rule *models.Recurrence) (*models.Recurrence, error) {
+	if rule == nil || rule.Frequency == models.RecurrenceNone {
+		return nil, nil
+	}
+	if rule.Frequency < models.RecurrenceNone || rule.Frequency > models.RecurrenceAfterCompletion {
+		return nil, fmt.Errorf("%w: unknown recurrence frequency %d", ErrInvalidArgument, rule.Frequency)
+	}
+
+	out := *rule
+	if out.Interval == 0 {
+		out.Interval = 1
+	}
+	if out.Interval < 1 || out.Interval > maxRecurrenceInterval {
+		return nil, fmt.Errorf("%w: recurrence interval must be between 1 and %d", ErrInvalidArgument, maxRecurrenceInterval)
+	}
+
+	if len(out.Weekdays) > 0 && out.Frequency != models.RecurrenceWeekly {
+		return nil, fmt.Errorf("%w: weekdays apply to weekly recurrence only", ErrInvalidArgument)
+	}
+	for _, day := range out.Weekdays {
+		if day < time.Sunday || day > time.Saturday {
+			return nil, fmt.Errorf("%w: unknown weekday %d", ErrInvalidArgument, day)
+		}
+	}
+	out.Weekdays = slices.Compact(slices.Sorted(slices.Values(out.Weekdays)))
+
+	if out.MonthDay != 0 && out.Frequency != models.RecurrenceMonthly {
+		return nil, fmt.Errorf("%w: month day applies to monthly recurrence only", ErrInvalidArgument)
+	}
+	if out.MonthDay < 0 || out.MonthDay > 31 {
+		return nil, fmt.Errorf("%w: month day must be between 1 and 31", ErrInvalidArgument)
+	}
+
+	if out.TimeZone == "" {
+		out.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(out.TimeZone); err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidArgument, out.TimeZone)
+	}
+
+	return &out, nil
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeRecurrence(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      *models.Recurrence
+		want    *models.Recurrence
+		wantErr bool
+	}{
+		{name: "no recurrence", in: nil, want: nil},
+		{name: "frequency none ends the series", in: &models.Recurrence{Frequency: models.RecurrenceNone, Interval: 3}, want: nil},
+		{
+			name: "defaults",
+			in:   &models.Recurrence{Frequency: models.RecurrenceDaily},
+			want: &models.Recurrence{Frequency: models.RecurrenceDaily, Interval: 1, TimeZone: "UTC"},
+		},
+		{
+			name: "weekdays are sorted and unique",
+			in: &models.Recurrence{
+				Frequency: models.RecurrenceWeekly,
+				Weekdays:  []time.Weekday{time.Friday, time.Monday, time.Friday},
+				TimeZone:  "Europe/Moscow",
+			},
+			want: &models.Recurrence{
+				Frequency: models.RecurrenceWeekly,
+				Interval:  1,
+				Weekdays:  []time.Weekday{time.Monday, time.Friday},
+				TimeZone:  "Europe/Moscow",
+			},
+		},
+		{name: "unknown frequency", in: &models.Recurrence{Frequency: 9}, wantErr: true},
+		{name: "negative interval", in: &models.Recurrence{Frequency: models.RecurrenceDaily, Interval: -1}, wantErr: true},
+		{name: "too long interval", in: &models.Recurrence{Frequency: models.RecurrenceDaily, Interval: maxRecurrenceInterval + 1}, wantErr: true},
+		{name: "weekdays of daily recurrence", in: &models.Recurrence{Frequency: models.RecurrenceDaily, Weekdays: []time.Weekday{time.Monday}}, wantErr: true},
+		{name: "unknown weekday", in: &models.Recurrence{Frequency: models.RecurrenceWeekly, Weekdays: []time.Weekday{7}}, wantErr: true},
+		{name: "month day of weekly recurrence", in: &models.Recurrence{Frequency: models.RecurrenceWeekly, MonthDay: 5}, wantErr: true},
+		{name: "month day out of range", in: &models.Recurrence{Frequency: models.RecurrenceMonthly, MonthDay: 32}, wantErr: true},
+		{name: "unknown time zone", in: &models.Recurrence{Frequency: models.RecurrenceDaily, TimeZone: "Mars/Base"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeRecurrence(tt.in)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchTerms     = 10
+)
+
+// normalizeSearchRequest splits the query into lower-case words, dropping
+// punctuation, and applies the default limit.
+func normalizeSearchRequest(req models.SearchRequest) (models.SearchRequest, error) {
+	req.Query = strings.TrimSpace(req.Query)
+	req.Terms = strings.FieldsFunc(strings.ToLower(req.Query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(req.Terms) == 0 {
+		return req, fmt.Errorf("%w: search query must contain a word", ErrInvalidArgument)
+	}
+	if len(req.Terms) > maxSearchTerms {
+		return req, fmt.Errorf("%w: search query must have at most %d words", ErrInvalidArgument, maxSearchTerms)
+	}
+
+	if req.Limit == 0 {
+		req.Limit = defaultSearchLimit
+	}
+	if req.Limit < 0 || req.Limit > maxSearchLimit {
+		return req, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidArgument, maxSearchLimit)
+	}
+	return req, nil
+}
+
+func (s *Service) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	log.Printf("IN: search tasks: %+v\n", req)
+
+	req, err := normalizeSearchRequest(req)
+	if err != nil {
+		log.Printf("OUT(ERR): search tasks: %v\n", err)
+		return nil, err
+	}
+
+	hits, err := s.dbController.SearchTasks(ctx, req)
+
+	if err != nil {
+		log.Printf("OUT(ERR): search tasks: %v\n", err)
+		return nil, err
+	}
+
+	now := s.now()
+	for i := range hits {
+		hits[i].Task = withOverdue(hits[i].Task, now)
+	}
+	log.Printf("OUT(OK): search tasks: %d hits\n", len(hits))
+
+	return hits, nil
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeSearchRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      models.SearchRequest
+		want    models.SearchRequest
+		wantErr error
+	}{
+		{
+			name: "words with punctuation",
+			in:   models.SearchRequest{Query: "  Купить МОЛОКО, milk-2 ", Limit: 5},
+			want: models.SearchRequest{Query: "Купить МОЛОКО, milk-2", Terms: []string{"купить", "молоко", "milk", "2"}, Limit: 5},
+		},
+		{
+			name: "default limit",
+			in:   models.SearchRequest{Query: "milk"},
+			want: models.SearchRequest{Query: "milk", Terms: []string{"milk"}, Limit: defaultSearchLimit},
+		},
+		{
+			name:    "no words",
+			in:      models.SearchRequest{Query: " !?& "},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "too many words",
+			in:      models.SearchRequest{Query: strings.Repeat("a ", maxSearchTerms+1)},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "limit too large",
+			in:      models.SearchRequest{Query: "milk", Limit: maxSearchLimit + 1},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "negative limit",
+			in:      models.SearchRequest{Query: "milk", Limit: -1},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeSearchRequest(tt.in)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected err %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestServiceSearchTasks_InvalidRequest_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.SearchTasks(context.Background(), models.SearchRequest{Query: "..."})
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.searchCalls != 0 {
+		t.Fatalf("expected SearchTasks not called, got %d calls", fakeRepo.searchCalls)
+	}
+}
+
+func TestServiceSearchTasks_PassesTermsAndComputesOverdue(t *testing.T) {
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	dueAt := now.Add(-time.Hour)
+	fakeRepo := &fakeRepo{searchRet: []models.SearchHit{
+		{Task: models.TaskExportData{Id: 3, DueAt: &dueAt}, Rank: 0.5, TitleSnippet: "Buy <b>milk</b>"},
+	}}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	hits, err := svc.SearchTasks(context.Background(), models.SearchRequest{Query: "Mil"})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"mil"}, fakeRepo.searchIn.Terms); diff != "" {
+		t.Fatal(diff)
+	}
+	if len(hits) != 1 || !hits[0].Task.Overdue || hits[0].TitleSnippet != "Buy <b>milk</b>" {
+		t.Fatalf("unexpected hits %+v", hits)
+	}
+}
@@ -2,24 +2,38 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"slices"
+	"time"
 
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
 )
 
 type Service struct {
 	dbController TaskRepository
+	events       *eventHub
+	now          func() time.Time
 }
 
 func NewService(dbController TaskRepository) *Service {
 	return &Service{
-		dbController: dbController}
+		dbController: dbController,
+		events:       newEventHub(eventReplaySize),
+		now:          time.Now}
 }
 
 func (s *Service) AddTask(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
 	log.Printf("IN: add task: %+v\n", task)
 
+	task, err := s.prepareTask(task)
+	if err != nil {
+		log.Printf("OUT(ERR): add task: %v\n", err)
+		return models.TaskExportData{}, err
+	}
+
 	createdTask, err := s.dbController.AddTask(ctx, task)
+	createdTask = withOverdue(createdTask, s.now())
 
 	if err != nil {
 		log.Printf("OUT(ERR): add task: %v\n", err)
@@ -30,10 +44,30 @@ func (s *Service) AddTask(ctx context.Context, task models.TaskImportData) (mode
 	return createdTask, err
 }
 
+// prepareTask validates and normalizes a task before it is created.
+func (s *Service) prepareTask(task models.TaskImportData) (models.TaskImportData, error) {
+	tags, err := normalizeTags(task.Tags)
+	if err != nil {
+		return task, err
+	}
+	task.Tags = tags
+
+	recurrence, err := normalizeRecurrence(task.Recurrence)
+	if err == nil && recurrence != nil && task.ParentId != 0 {
+		err = ErrRecurringSubtask
+	}
+	if err != nil {
+		return task, err
+	}
+	task.Recurrence = recurrence
+
+	return withIdempotency(task, s.now())
+}
+
 func (s *Service) DeleteTask(ctx context.Context, id int) error {
 	log.Printf("IN: delete task with ID: %v\n", id)
 
-	err := s.dbController.DeleteTask(ctx, id)
+	_, err := s.dbController.DeleteTask(ctx, id)
 
 	if err != nil {
 		log.Printf("OUT(ERR): delete task with ID %v: %v\n", id, err)
@@ -44,24 +78,94 @@ func (s *Service) DeleteTask(ctx context.Context, id int) error {
 	return err
 }
 
-func (s *Service) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
+func (s *Service) GetTaskTree(ctx context.Context, id int) (models.TaskTree, error) {
+	log.Printf("IN: get task tree with ID: %v\n", id)
+
+	tree, err := s.getTaskTree(ctx, id)
+
+	if err != nil {
+		log.Printf("OUT(ERR): get task tree with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): get task tree with ID %v: %+v\n", id, tree)
+	}
+
+	return tree, err
+}
+
+func (s *Service) getTaskTree(ctx context.Context, id int) (models.TaskTree, error) {
+	now := s.now()
+
+	task, err := s.dbController.GetTask(ctx, id)
+	if err != nil {
+		return models.TaskTree{}, err
+	}
+
+	subtasks, err := s.dbController.ListSubtasks(ctx, id)
+	if err != nil {
+		return models.TaskTree{}, err
+	}
+
+	return buildTaskTree(withOverdue(task, now), withOverdueAll(subtasks, now)), nil
+}
+
+// ListAllTasks reports the list as stale when it was served from the cache
+// because the main DB is down.
+func (s *Service) ListAllTasks(ctx context.Context) ([]models.TaskExportData, bool, error) {
 	log.Println("IN: list tasks")
 
-	tasks, err := s.dbController.ListAllTasks(ctx)
+	tasks, stale, err := s.listAllTasks(ctx)
+	tasks = withOverdueAll(tasks, s.now())
 
 	if err != nil {
 		log.Printf("OUT(ERR): list tasks: %v\n", err)
 	} else {
-		log.Printf("OUT(OK): list tasks: %+v\n", tasks)
+		log.Printf("OUT(OK): list tasks (stale: %v): %+v\n", stale, tasks)
 	}
 
-	return tasks, err
+	return tasks, stale, err
+}
+
+func (s *Service) ListTasks(ctx context.Context, filter models.TaskFilter) ([]models.TaskExportData, bool, error) {
+	log.Printf("IN: list tasks with filter: %+v\n", filter)
+
+	now := s.now()
+
+	tasks, stale, err := s.listAllTasks(ctx)
+	if err == nil {
+		tasks, err = filterTasks(withOverdueAll(tasks, now), filter, now)
+	}
+	if err == nil {
+		err = sortTasks(tasks, filter.Sort)
+	}
+
+	if err != nil {
+		log.Printf("OUT(ERR): list tasks with filter: %v\n", err)
+		return nil, false, err
+	}
+
+	log.Printf("OUT(OK): list tasks with filter (stale: %v): %+v\n", stale, tasks)
+	return tasks, stale, nil
+}
+
+// staleLister is a TaskRepository that can serve a stale task list while
+// the main DB is down, like CachedRepository.
+type staleLister interface {
+	ListAllTasksOrStale(ctx context.Context) ([]models.TaskExportData, bool, error)
+}
+
+func (s *Service) listAllTasks(ctx context.Context) ([]models.TaskExportData, bool, error) {
+	if lister, ok := s.dbController.(staleLister); ok {
+		return lister.ListAllTasksOrStale(ctx)
+	}
+	tasks, err := s.dbController.ListAllTasks(ctx)
+	return tasks, false, err
 }
 
 func (s *Service) MarkTaskFinished(ctx context.Context, id int) (models.TaskExportData, error) {
 	log.Printf("IN: finish task with ID: %v\n", id)
 
 	updatedTask, err := s.dbController.MarkTaskFinished(ctx, id)
+	updatedTask = withOverdue(updatedTask, s.now())
 
 	if err != nil {
 		log.Printf("OUT(ERR): finish task with ID %v: %v\n", id, err)
@@ -71,3 +175,335 @@ func (s *Service) MarkTaskFinished(ctx context.Context, id int) (models.TaskExpo
 
 	return updatedTask, err
 }
+
+func (s *Service) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	log.Printf("IN: reopen task with ID: %v\n", id)
+
+	updatedTask, err := s.dbController.ReopenTask(ctx, id)
+	updatedTask = withOverdue(updatedTask, s.now())
+
+	if err != nil {
+		log.Printf("OUT(ERR): reopen task with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): reopen task with ID %v\n", id)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	log.Printf("IN: set priority %v for task with ID: %v\n", priority, id)
+
+	if priority < models.PriorityNone || priority > models.PriorityUrgent {
+		err := fmt.Errorf("%w: unknown priority %d", ErrInvalidArgument, priority)
+		log.Printf("OUT(ERR): set priority for task with ID %v: %v\n", id, err)
+		return models.TaskExportData{}, err
+	}
+
+	updatedTask, err := s.dbController.SetTaskPriority(ctx, id, priority)
+	updatedTask = withOverdue(updatedTask, s.now())
+
+	if err != nil {
+		log.Printf("OUT(ERR): set priority for task with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): set priority for task with ID %v\n", id)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	log.Printf("IN: move task: %+v\n", move)
+
+	if err := validateTaskMove(move); err != nil {
+		log.Printf("OUT(ERR): move task with ID %v: %v\n", move.Id, err)
+		return models.TaskExportData{}, err
+	}
+
+	movedTask, err := s.dbController.MoveTask(ctx, move)
+	movedTask = withOverdue(movedTask, s.now())
+
+	if err != nil {
+		log.Printf("OUT(ERR): move task with ID %v: %v\n", move.Id, err)
+	} else {
+		log.Printf("OUT(OK): move task with ID %v to position %q\n", move.Id, movedTask.Position)
+	}
+
+	return movedTask, err
+}
+
+func (s *Service) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	log.Printf("IN: set recurrence %+v for task with ID: %v\n", rule, id)
+
+	updatedTask, err := s.setTaskRecurrence(ctx, id, rule)
+
+	if err != nil {
+		log.Printf("OUT(ERR): set recurrence for task with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): set recurrence for task with ID %v: %+v\n", id, updatedTask.Recurrence)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) setTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	rule, err := normalizeRecurrence(rule)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	updatedTask, err := s.dbController.SetTaskRecurrence(ctx, id, rule)
+	return withOverdue(updatedTask, s.now()), err
+}
+
+func (s *Service) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	log.Printf("IN: skip occurrence of task with ID: %v\n", id)
+
+	updatedTask, err := s.dbController.SkipOccurrence(ctx, id)
+	updatedTask = withOverdue(updatedTask, s.now())
+
+	if err != nil {
+		log.Printf("OUT(ERR): skip occurrence of task with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): skip occurrence of task with ID %v, next due at %v\n", id, updatedTask.DueAt)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	log.Printf("IN: add tags %v to task with ID: %v\n", tags, id)
+
+	updatedTask, err := s.changeTaskTags(ctx, id, tags, s.dbController.AddTaskTags)
+
+	if err != nil {
+		log.Printf("OUT(ERR): add tags to task with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): add tags to task with ID %v: %v\n", id, updatedTask.Tags)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	log.Printf("IN: remove tags %v from task with ID: %v\n", tags, id)
+
+	updatedTask, err := s.changeTaskTags(ctx, id, tags, s.dbController.RemoveTaskTags)
+
+	if err != nil {
+		log.Printf("OUT(ERR): remove tags from task with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): remove tags from task with ID %v: %v\n", id, updatedTask.Tags)
+	}
+
+	return updatedTask, err
+}
+
+func (s *Service) changeTaskTags(ctx context.Context, id int, tags []string,
+	change func(ctx context.Context, id int, tags []string) (models.TaskExportData, error)) (models.TaskExportData, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	if len(tags) == 0 {
+		return models.TaskExportData{}, fmt.Errorf("%w: no tags given", ErrInvalidArgument)
+	}
+
+	updatedTask, err := change(ctx, id, tags)
+	return withOverdue(updatedTask, s.now()), err
+}
+
+func (s *Service) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	log.Println("IN: list tags")
+
+	tags, err := s.dbController.ListTags(ctx)
+
+	if err != nil {
+		log.Printf("OUT(ERR): list tags: %v\n", err)
+	} else {
+		log.Printf("OUT(OK): list tags: %+v\n", tags)
+	}
+
+	return tags, err
+}
+
+func (s *Service) RenameTag(ctx context.Context, name, newName string) (models.TagChange, error) {
+	log.Printf("IN: rename tag %q to %q\n", name, newName)
+
+	change, err := s.renameTag(ctx, name, newName)
+
+	if err != nil {
+		log.Printf("OUT(ERR): rename tag %q: %v\n", name, err)
+	} else {
+		log.Printf("OUT(OK): rename tag %q: %+v\n", name, change)
+	}
+
+	return change, err
+}
+
+func (s *Service) renameTag(ctx context.Context, name, newName string) (models.TagChange, error) {
+	name, err := normalizeTag(name)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+	newName, err = normalizeTag(newName)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+
+	return s.dbController.RenameTag(ctx, name, newName)
+}
+
+func (s *Service) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	log.Printf("IN: merge tags %v into %q\n", sources, target)
+
+	change, err := s.mergeTags(ctx, sources, target)
+
+	if err != nil {
+		log.Printf("OUT(ERR): merge tags into %q: %v\n", target, err)
+	} else {
+		log.Printf("OUT(OK): merge tags into %q: %+v\n", target, change)
+	}
+
+	return change, err
+}
+
+func (s *Service) mergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	target, err := normalizeTag(target)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+	sources, err = normalizeTags(sources)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+	sources = slices.DeleteFunc(sources, func(tag string) bool { return tag == target })
+	if len(sources) == 0 {
+		return models.TagChange{}, fmt.Errorf("%w: no tags to merge into %q", ErrInvalidArgument, target)
+	}
+
+	return s.dbController.MergeTags(ctx, sources, target)
+}
+
+func (s *Service) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	log.Printf("IN: create project %q\n", name)
+
+	project, err := s.createProject(ctx, name)
+
+	if err != nil {
+		log.Printf("OUT(ERR): create project %q: %v\n", name, err)
+	} else {
+		log.Printf("OUT(OK): create project: %+v\n", project)
+	}
+
+	return project, err
+}
+
+func (s *Service) createProject(ctx context.Context, name string) (models.Project, error) {
+	name, err := normalizeProjectName(name)
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	return s.dbController.CreateProject(ctx, name)
+}
+
+func (s *Service) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	log.Printf("IN: list projects, include archived: %v\n", includeArchived)
+
+	projects, err := s.dbController.ListProjects(ctx, includeArchived)
+
+	if err != nil {
+		log.Printf("OUT(ERR): list projects: %v\n", err)
+	} else {
+		log.Printf("OUT(OK): list projects: %+v\n", projects)
+	}
+
+	return projects, err
+}
+
+func (s *Service) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	log.Printf("IN: rename project with ID %v to %q\n", id, name)
+
+	project, err := s.renameProject(ctx, id, name)
+
+	if err != nil {
+		log.Printf("OUT(ERR): rename project with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): rename project: %+v\n", project)
+	}
+
+	return project, err
+}
+
+func (s *Service) renameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	name, err := normalizeProjectName(name)
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	return s.dbController.RenameProject(ctx, id, name)
+}
+
+func (s *Service) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	log.Printf("IN: set archived=%v for project with ID: %v\n", archived, id)
+
+	project, err := s.dbController.ArchiveProject(ctx, id, archived)
+
+	if err != nil {
+		log.Printf("OUT(ERR): archive project with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): archive project: %+v\n", project)
+	}
+
+	return project, err
+}
+
+func (s *Service) DeleteProject(ctx context.Context, id int) error {
+	log.Printf("IN: delete project with ID: %v\n", id)
+
+	movedIds, err := s.dbController.DeleteProject(ctx, id)
+
+	if err != nil {
+		log.Printf("OUT(ERR): delete project with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): delete project with ID %v, tasks moved to inbox: %v\n", id, movedIds)
+	}
+
+	return err
+}
+
+func (s *Service) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	log.Printf("IN: move task with ID %v to project with ID: %v\n", id, projectId)
+
+	movedTask, err := s.dbController.MoveTaskToProject(ctx, id, projectId)
+	movedTask = withOverdue(movedTask, s.now())
+
+	if err != nil {
+		log.Printf("OUT(ERR): move task with ID %v to project: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): move task with ID %v to project with ID %v\n", id, movedTask.ProjectId)
+	}
+
+	return movedTask, err
+}
+
+func (s *Service) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	log.Printf("IN: get stats: %+v\n", req)
+
+	req, err := normalizeStatsRequest(req, s.now())
+	if err != nil {
+		log.Printf("OUT(ERR): get stats: %v\n", err)
+		return models.Stats{}, err
+	}
+
+	stats, err := s.dbController.GetStats(ctx, req)
+
+	if err != nil {
+		log.Printf("OUT(ERR): get stats: %v\n", err)
+	} else {
+		log.Printf("OUT(OK): get stats: %+v\n", stats)
+	}
+
+	return stats, err
+}
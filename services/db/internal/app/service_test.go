@@ -20,6 +20,7 @@ type fakeRepo struct {
 	deleteTaskCalls int
 	deleteTaskCtx   context.Context
 	deleteTaskIn    int
+	deleteTaskRet   []int
 	deleteTaskErr   error
 
 	listAllTasksCalls int
@@ -33,6 +34,198 @@ type fakeRepo struct {
 	markTaskFinishedRet   models.TaskExportData
 	markTaskFinishedErr   error
 
+	getStatsCalls int
+	getStatsCtx   context.Context
+	getStatsIn    models.StatsRequest
+	getStatsRet   models.Stats
+	getStatsErr   error
+
+	setTaskPriorityCalls int
+	setTaskPriorityCtx   context.Context
+	setTaskPriorityId    int
+	setTaskPriorityIn    models.Priority
+	setTaskPriorityRet   models.TaskExportData
+	setTaskPriorityErr   error
+
+	moveTaskCalls int
+	moveTaskCtx   context.Context
+	moveTaskIn    models.TaskMove
+	moveTaskRet   models.TaskExportData
+	moveTaskErr   error
+
+	addTaskTagsCalls int
+	addTaskTagsCtx   context.Context
+	addTaskTagsId    int
+	addTaskTagsTags  []string
+	addTaskTagsRet   models.TaskExportData
+	addTaskTagsErr   error
+
+	removeTaskTagsCalls int
+	removeTaskTagsCtx   context.Context
+	removeTaskTagsId    int
+	removeTaskTagsTags  []string
+	removeTaskTagsRet   models.TaskExportData
+	removeTaskTagsErr   error
+
+	listTagsCalls int
+	listTagsCtx   context.Context
+	listTagsRet   []models.TagUsage
+	listTagsErr   error
+
+	renameTagCalls   int
+	renameTagCtx     context.Context
+	renameTagName    string
+	renameTagNewName string
+	renameTagRet     models.TagChange
+	renameTagErr     error
+
+	mergeTagsCalls   int
+	mergeTagsCtx     context.Context
+	mergeTagsSources []string
+	mergeTagsTarget  string
+	mergeTagsRet     models.TagChange
+	mergeTagsErr     error
+
+	createProjectCalls int
+	createProjectCtx   context.Context
+	createProjectIn    string
+	createProjectRet   models.Project
+	createProjectErr   error
+
+	listProjectsCalls int
+	listProjectsCtx   context.Context
+	listProjectsIn    bool
+	listProjectsRet   []models.Project
+	listProjectsErr   error
+
+	renameProjectCalls int
+	renameProjectCtx   context.Context
+	renameProjectId    int
+	renameProjectName  string
+	renameProjectRet   models.Project
+	renameProjectErr   error
+
+	archiveProjectCalls    int
+	archiveProjectCtx      context.Context
+	archiveProjectId       int
+	archiveProjectArchived bool
+	archiveProjectRet      models.Project
+	archiveProjectErr      error
+
+	deleteProjectCalls int
+	deleteProjectCtx   context.Context
+	deleteProjectIn    int
+	deleteProjectRet   []int
+	deleteProjectErr   error
+
+	moveTaskToProjectCalls     int
+	moveTaskToProjectCtx       context.Context
+	moveTaskToProjectId        int
+	moveTaskToProjectProjectId int
+	moveTaskToProjectRet       models.TaskExportData
+	moveTaskToProjectErr       error
+
+	getTaskCalls int
+	getTaskCtx   context.Context
+	getTaskIn    int
+	getTaskRet   models.TaskExportData
+	getTaskErr   error
+
+	listSubtasksCalls int
+	listSubtasksCtx   context.Context
+	listSubtasksIn    int
+	listSubtasksRet   []models.TaskExportData
+	listSubtasksErr   error
+
+	setTaskRecurrenceCalls int
+	setTaskRecurrenceCtx   context.Context
+	setTaskRecurrenceId    int
+	setTaskRecurrenceRule  *models.Recurrence
+	setTaskRecurrenceRet   models.TaskExportData
+	setTaskRecurrenceErr   error
+
+	skipOccurrenceCalls int
+	skipOccurrenceCtx   context.Context
+	skipOccurrenceIn    int
+	skipOccurrenceRet   models.TaskExportData
+	skipOccurrenceErr   error
+
+	reopenTaskCalls int
+	reopenTaskCtx   context.Context
+	reopenTaskIn    int
+	reopenTaskRet   models.TaskExportData
+	reopenTaskErr   error
+
+	listTrashCalls int
+	listTrashCtx   context.Context
+	listTrashRet   []models.TaskExportData
+	listTrashErr   error
+
+	restoreTaskCalls int
+	restoreTaskCtx   context.Context
+	restoreTaskIn    int
+	restoreTaskRet   models.TaskExportData
+	restoreTaskErr   error
+
+	purgeTaskCalls int
+	purgeTaskCtx   context.Context
+	purgeTaskIn    int
+	purgeTaskErr   error
+
+	purgeTrashCalls int
+	purgeTrashCtx   context.Context
+	purgeTrashIn    time.Time
+	purgeTrashRet   int
+	purgeTrashErr   error
+
+	getTaskHistoryCalls int
+	getTaskHistoryCtx   context.Context
+	getTaskHistoryIn    models.TaskHistoryRequest
+	getTaskHistoryRet   models.TaskHistoryPage
+	getTaskHistoryErr   error
+
+	purgeKeysCalls int
+	purgeKeysCtx   context.Context
+	purgeKeysIn    time.Time
+	purgeKeysRet   int
+	purgeKeysErr   error
+
+	batchAddCalls int
+	batchAddCtx   context.Context
+	batchAddIn    []models.TaskImportData
+	batchAddRet   []models.BatchItem
+	batchAddErr   error
+
+	batchFinishCalls int
+	batchFinishCtx   context.Context
+	batchFinishIn    []int
+	batchFinishRet   []models.BatchItem
+	batchFinishErr   error
+
+	batchRemoveCalls int
+	batchRemoveCtx   context.Context
+	batchRemoveIn    []int
+	batchRemoveRet   []models.BatchItem
+	batchRemoveErr   error
+
+	searchCalls int
+	searchCtx   context.Context
+	searchIn    models.SearchRequest
+	searchRet   []models.SearchHit
+	searchErr   error
+
+	getChangesCalls int
+	getChangesCtx   context.Context
+	getChangesIn    models.ChangesRequest
+	getChangesRet   models.Changes
+	getChangesErr   error
+
+	getTasksCalls int
+	getTasksCtx   context.Context
+	getTasksIn    []int
+	getTasksRet   []models.TaskExportData
+	getTasksErr   error
+
 	closeCalled int
 	closeErr    error
 }
@@ -44,11 +237,11 @@ func (f *fakeRepo) AddTask(ctx context.Context, task models.TaskImportData) (mod
 	return f.addTaskRet, f.addTaskErr
 }
 
-func (f *fakeRepo) DeleteTask(ctx context.Context, id int) error {
+func (f *fakeRepo) DeleteTask(ctx context.Context, id int) ([]int, error) {
 	f.deleteTaskCalls++
 	f.deleteTaskCtx = ctx
 	f.deleteTaskIn = id
-	return f.deleteTaskErr
+	return f.deleteTaskRet, f.deleteTaskErr
 }
 
 func (f *fakeRepo) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
@@ -64,6 +257,230 @@ func (f *fakeRepo) MarkTaskFinished(ctx context.Context, id int) (models.TaskExp
 	return f.markTaskFinishedRet, f.markTaskFinishedErr
 }
 
+func (f *fakeRepo) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	f.getStatsCalls++
+	f.getStatsCtx = ctx
+	f.getStatsIn = req
+	return f.getStatsRet, f.getStatsErr
+}
+
+func (f *fakeRepo) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	f.setTaskPriorityCalls++
+	f.setTaskPriorityCtx = ctx
+	f.setTaskPriorityId = id
+	f.setTaskPriorityIn = priority
+	return f.setTaskPriorityRet, f.setTaskPriorityErr
+}
+
+func (f *fakeRepo) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	f.moveTaskCalls++
+	f.moveTaskCtx = ctx
+	f.moveTaskIn = move
+	return f.moveTaskRet, f.moveTaskErr
+}
+
+func (f *fakeRepo) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	f.addTaskTagsCalls++
+	f.addTaskTagsCtx = ctx
+	f.addTaskTagsId = id
+	f.addTaskTagsTags = tags
+	return f.addTaskTagsRet, f.addTaskTagsErr
+}
+
+func (f *fakeRepo) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	f.removeTaskTagsCalls++
+	f.removeTaskTagsCtx = ctx
+	f.removeTaskTagsId = id
+	f.removeTaskTagsTags = tags
+	return f.removeTaskTagsRet, f.removeTaskTagsErr
+}
+
+func (f *fakeRepo) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	f.listTagsCalls++
+	f.listTagsCtx = ctx
+	return f.listTagsRet, f.listTagsErr
+}
+
+func (f *fakeRepo) RenameTag(ctx context.Context, name string, newName string) (models.TagChange, error) {
+	f.renameTagCalls++
+	f.renameTagCtx = ctx
+	f.renameTagName = name
+	f.renameTagNewName = newName
+	return f.renameTagRet, f.renameTagErr
+}
+
+func (f *fakeRepo) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	f.mergeTagsCalls++
+	f.mergeTagsCtx = ctx
+	f.mergeTagsSources = sources
+	f.mergeTagsTarget = target
+	return f.mergeTagsRet, f.mergeTagsErr
+}
+
+func (f *fakeRepo) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	f.createProjectCalls++
+	f.createProjectCtx = ctx
+	f.createProjectIn = name
+	return f.createProjectRet, f.createProjectErr
+}
+
+func (f *fakeRepo) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	f.listProjectsCalls++
+	f.listProjectsCtx = ctx
+	f.listProjectsIn = includeArchived
+	return f.listProjectsRet, f.listProjectsErr
+}
+
+func (f *fakeRepo) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	f.renameProjectCalls++
+	f.renameProjectCtx = ctx
+	f.renameProjectId = id
+	f.renameProjectName = name
+	return f.renameProjectRet, f.renameProjectErr
+}
+
+func (f *fakeRepo) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	f.archiveProjectCalls++
+	f.archiveProjectCtx = ctx
+	f.archiveProjectId = id
+	f.archiveProjectArchived = archived
+	return f.archiveProjectRet, f.archiveProjectErr
+}
+
+func (f *fakeRepo) DeleteProject(ctx context.Context, id int) ([]int, error) {
+	f.deleteProjectCalls++
+	f.deleteProjectCtx = ctx
+	f.deleteProjectIn = id
+	return f.deleteProjectRet, f.deleteProjectErr
+}
+
+func (f *fakeRepo) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	f.moveTaskToProjectCalls++
+	f.moveTaskToProjectCtx = ctx
+	f.moveTaskToProjectId = id
+	f.moveTaskToProjectProjectId = projectId
+	return f.moveTaskToProjectRet, f.moveTaskToProjectErr
+}
+
+func (f *fakeRepo) GetTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.getTaskCalls++
+	f.getTaskCtx = ctx
+	f.getTaskIn = id
+	return f.getTaskRet, f.getTaskErr
+}
+
+func (f *fakeRepo) ListSubtasks(ctx context.Context, id int) ([]models.TaskExportData, error) {
+	f.listSubtasksCalls++
+	f.listSubtasksCtx = ctx
+	f.listSubtasksIn = id
+	return f.listSubtasksRet, f.listSubtasksErr
+}
+
+func (f *fakeRepo) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	f.setTaskRecurrenceCalls++
+	f.setTaskRecurrenceCtx = ctx
+	f.setTaskRecurrenceId = id
+	f.setTaskRecurrenceRule = rule
+	return f.setTaskRecurrenceRet, f.setTaskRecurrenceErr
+}
+
+func (f *fakeRepo) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.skipOccurrenceCalls++
+	f.skipOccurrenceCtx = ctx
+	f.skipOccurrenceIn = id
+	return f.skipOccurrenceRet, f.skipOccurrenceErr
+}
+
+func (f *fakeRepo) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.reopenTaskCalls++
+	f.reopenTaskCtx = ctx
+	f.reopenTaskIn = id
+	return f.reopenTaskRet, f.reopenTaskErr
+}
+
+func (f *fakeRepo) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	f.listTrashCalls++
+	f.listTrashCtx = ctx
+	return f.listTrashRet, f.listTrashErr
+}
+
+func (f *fakeRepo) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.restoreTaskCalls++
+	f.restoreTaskCtx = ctx
+	f.restoreTaskIn = id
+	return f.restoreTaskRet, f.restoreTaskErr
+}
+
+func (f *fakeRepo) PurgeTask(ctx context.Context, id int) error {
+	f.purgeTaskCalls++
+	f.purgeTaskCtx = ctx
+	f.purgeTaskIn = id
+	return f.purgeTaskErr
+}
+
+func (f *fakeRepo) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	f.purgeTrashCalls++
+	f.purgeTrashCtx = ctx
+	f.purgeTrashIn = before
+	return f.purgeTrashRet, f.purgeTrashErr
+}
+
+func (f *fakeRepo) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	f.getTaskHistoryCalls++
+	f.getTaskHistoryCtx = ctx
+	f.getTaskHistoryIn = req
+	return f.getTaskHistoryRet, f.getTaskHistoryErr
+}
+
+func (f *fakeRepo) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int, error) {
+	f.purgeKeysCalls++
+	f.purgeKeysCtx = ctx
+	f.purgeKeysIn = now
+	return f.purgeKeysRet, f.purgeKeysErr
+}
+
+func (f *fakeRepo) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	f.batchAddCalls++
+	f.batchAddCtx = ctx
+	f.batchAddIn = tasks
+	return f.batchAddRet, f.batchAddErr
+}
+
+func (f *fakeRepo) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	f.batchFinishCalls++
+	f.batchFinishCtx = ctx
+	f.batchFinishIn = ids
+	return f.batchFinishRet, f.batchFinishErr
+}
+
+func (f *fakeRepo) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	f.batchRemoveCalls++
+	f.batchRemoveCtx = ctx
+	f.batchRemoveIn = ids
+	return f.batchRemoveRet, f.batchRemoveErr
+}
+
+func (f *fakeRepo) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	f.searchCalls++
+	f.searchCtx = ctx
+	f.searchIn = req
+	return f.searchRet, f.searchErr
+}
+
+func (f *fakeRepo) GetChangesSince(ctx context.Context, req models.ChangesRequest) (models.Changes, error) {
+	f.getChangesCalls++
+	f.getChangesCtx = ctx
+	f.getChangesIn = req
+	return f.getChangesRet, f.getChangesErr
+}
+
+func (f *fakeRepo) GetTasks(ctx context.Context, ids []int) ([]models.TaskExportData, error) {
+	f.getTasksCalls++
+	f.getTasksCtx = ctx
+	f.getTasksIn = ids
+	return f.getTasksRet, f.getTasksErr
+}
+
 func (f *fakeRepo) Close() error {
 	f.closeCalled++
 	return f.closeErr
@@ -158,7 +575,7 @@ func TestServiceListAllTasks_DelegatesToTaskRepo(t *testing.T) {
 	}
 	svc := NewService(fakeRepo)
 
-	got, gotErr := svc.ListAllTasks(context.Background())
+	got, _, gotErr := svc.ListAllTasks(context.Background())
 
 	if fakeRepo.listAllTasksCalls != 1 {
 		t.Fatalf("expected ListAllTasks called=1, got %d", fakeRepo.listAllTasksCalls)
@@ -174,6 +591,32 @@ func TestServiceListAllTasks_DelegatesToTaskRepo(t *testing.T) {
 	}
 }
 
+func TestServiceReopenTask_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantTask := models.TaskExportData{Id: 234, Title: "my title"}
+	wantErr := errors.New("boom")
+	fakeRepo := &fakeRepo{
+		reopenTaskRet: wantTask,
+		reopenTaskErr: wantErr,
+	}
+	svc := NewService(fakeRepo)
+
+	gotTask, gotErr := svc.ReopenTask(ctx, 234)
+
+	if fakeRepo.reopenTaskCalls != 1 || fakeRepo.reopenTaskIn != 234 {
+		t.Fatalf("unexpected ReopenTask call: calls=%d id=%d", fakeRepo.reopenTaskCalls, fakeRepo.reopenTaskIn)
+	}
+	if !errors.Is(gotErr, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
+	}
+	if fakeRepo.reopenTaskCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if !reflect.DeepEqual(gotTask, wantTask) {
+		t.Fatalf("mismatch task: want=%+v got=%+v", wantTask, gotTask)
+	}
+}
+
 func TestServiceMarkTaskFinished_DelegatesToTaskRepo(t *testing.T) {
 	ctx := context.Background()
 	wantId := 234
@@ -212,3 +655,615 @@ func TestServiceMarkTaskFinished_DelegatesToTaskRepo(t *testing.T) {
 		t.Fatalf("mismatch task: want %+v got %+v", wantTask, gotTask)
 	}
 }
+
+func TestServiceGetStats_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	fromTS := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	toTS := time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC)
+	wantReq := models.StatsRequest{
+		From:     fromTS,
+		To:       toTS,
+		Bucket:   models.StatsBucketDay,
+		TimeZone: "Europe/Moscow",
+	}
+	wantStats := models.Stats{
+		Points: []models.StatsPoint{
+			{Start: fromTS, Created: 3, Completed: 1},
+		},
+		CreatedTotal:      3,
+		CompletedTotal:    1,
+		AvgTimeToComplete: 2 * time.Hour,
+		OpenCount:         7,
+		CurrentStreak:     1,
+		LongestStreak:     4,
+	}
+	wantErr := errors.New("boom")
+	fakeRepo := &fakeRepo{
+		getStatsRet: wantStats,
+		getStatsErr: wantErr,
+	}
+	svc := NewService(fakeRepo)
+
+	got, gotErr := svc.GetStats(ctx, wantReq)
+
+	if fakeRepo.getStatsCalls != 1 {
+		t.Fatalf("expected GetStats called=1, got %d", fakeRepo.getStatsCalls)
+	}
+	if !errors.Is(gotErr, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
+	}
+	if fakeRepo.getStatsCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if !reflect.DeepEqual(fakeRepo.getStatsIn, wantReq) {
+		t.Fatalf("mismatch stats request: want %+v got %+v", wantReq, fakeRepo.getStatsIn)
+	}
+	if !reflect.DeepEqual(got, wantStats) {
+		t.Fatalf("mismatch stats: want %+v got %+v", wantStats, got)
+	}
+}
+
+func TestServiceGetStats_InvalidRequest_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.GetStats(context.Background(), models.StatsRequest{TimeZone: "Mars/Olympus"})
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.getStatsCalls != 0 {
+		t.Fatalf("expected GetStats not called, got %d", fakeRepo.getStatsCalls)
+	}
+}
+
+func TestServiceListTasks_FiltersTaskRepoList(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 17, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	fakeRepo := &fakeRepo{
+		listAllTasksRet: []models.TaskExportData{
+			{Id: 1, DueAt: &past},
+			{Id: 2, DueAt: &future},
+			{Id: 3},
+		},
+	}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	got, _, err := svc.ListTasks(ctx, models.TaskFilter{Due: models.DueFilterOverdue})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.listAllTasksCalls != 1 {
+		t.Fatalf("expected ListAllTasks called=1, got %d", fakeRepo.listAllTasksCalls)
+	}
+	if fakeRepo.listAllTasksCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if len(got) != 1 || got[0].Id != 1 || !got[0].Overdue {
+		t.Fatalf("expected only overdue task 1, got %+v", got)
+	}
+}
+
+func TestServiceListTasks_TaskRepoError_ReturnsError(t *testing.T) {
+	wantErr := errors.New("boom")
+	svc := NewService(&fakeRepo{listAllTasksErr: wantErr})
+
+	got, _, err := svc.ListTasks(context.Background(), models.TaskFilter{})
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, err)
+	}
+	if got != nil {
+		t.Fatalf("expected nil, got %+v", got)
+	}
+}
+
+func TestServiceListAllTasks_ComputesOverdue(t *testing.T) {
+	now := time.Date(2025, 12, 17, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	svc := NewService(&fakeRepo{
+		listAllTasksRet: []models.TaskExportData{{Id: 1, DueAt: &past}},
+	})
+	svc.now = func() time.Time { return now }
+
+	got, _, _ := svc.ListAllTasks(context.Background())
+
+	if len(got) != 1 || !got[0].Overdue {
+		t.Fatalf("expected overdue task, got %+v", got)
+	}
+}
+
+func TestServiceSetTaskPriority_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantTaskOut := models.TaskExportData{Id: 3, Priority: models.PriorityHigh}
+	wantErr := errors.New("boom")
+	fakeRepo := &fakeRepo{
+		setTaskPriorityRet: wantTaskOut,
+		setTaskPriorityErr: wantErr,
+	}
+	svc := NewService(fakeRepo)
+
+	gotTask, gotErr := svc.SetTaskPriority(ctx, 3, models.PriorityHigh)
+
+	if fakeRepo.setTaskPriorityCalls != 1 {
+		t.Fatalf("expected SetTaskPriority called=1, got %d", fakeRepo.setTaskPriorityCalls)
+	}
+	if fakeRepo.setTaskPriorityCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if fakeRepo.setTaskPriorityId != 3 || fakeRepo.setTaskPriorityIn != models.PriorityHigh {
+		t.Fatalf("unexpected args: id=%d priority=%d", fakeRepo.setTaskPriorityId, fakeRepo.setTaskPriorityIn)
+	}
+	if !errors.Is(gotErr, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
+	}
+	if !reflect.DeepEqual(gotTask, wantTaskOut) {
+		t.Fatalf("expected task %+v, got %+v", wantTaskOut, gotTask)
+	}
+}
+
+func TestServiceSetTaskPriority_UnknownPriority_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.SetTaskPriority(context.Background(), 3, models.Priority(7))
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.setTaskPriorityCalls != 0 {
+		t.Fatalf("expected SetTaskPriority not called, got %d", fakeRepo.setTaskPriorityCalls)
+	}
+}
+
+func TestServiceMoveTask_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantMove := models.TaskMove{Id: 1, BeforeId: 2}
+	wantTaskOut := models.TaskExportData{Id: 1, Position: "ai"}
+	fakeRepo := &fakeRepo{moveTaskRet: wantTaskOut}
+	svc := NewService(fakeRepo)
+
+	gotTask, gotErr := svc.MoveTask(ctx, wantMove)
+
+	if gotErr != nil {
+		t.Fatalf("expected nil, got %v", gotErr)
+	}
+	if fakeRepo.moveTaskCalls != 1 {
+		t.Fatalf("expected MoveTask called=1, got %d", fakeRepo.moveTaskCalls)
+	}
+	if fakeRepo.moveTaskCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	if fakeRepo.moveTaskIn != wantMove {
+		t.Fatalf("expected move %+v, got %+v", wantMove, fakeRepo.moveTaskIn)
+	}
+	if !reflect.DeepEqual(gotTask, wantTaskOut) {
+		t.Fatalf("expected task %+v, got %+v", wantTaskOut, gotTask)
+	}
+}
+
+func TestServiceMoveTask_InvalidMove_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.MoveTask(context.Background(), models.TaskMove{Id: 1})
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.moveTaskCalls != 0 {
+		t.Fatalf("expected MoveTask not called, got %d", fakeRepo.moveTaskCalls)
+	}
+}
+
+func TestServiceListTasks_SortsByPriority(t *testing.T) {
+	fakeRepo := &fakeRepo{
+		listAllTasksRet: []models.TaskExportData{
+			{Id: 1, Priority: models.PriorityLow},
+			{Id: 2, Priority: models.PriorityUrgent},
+		},
+	}
+	svc := NewService(fakeRepo)
+
+	got, _, err := svc.ListTasks(context.Background(), models.TaskFilter{Sort: models.TaskSortPriority})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(got) != 2 || got[0].Id != 2 || got[1].Id != 1 {
+		t.Fatalf("expected tasks sorted by priority, got %+v", got)
+	}
+}
+
+func TestServiceAddTaskTags_NormalizesTagsAndDelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantTaskOut := models.TaskExportData{Id: 2, Tags: []string{"home", "work"}}
+	fakeRepo := &fakeRepo{addTaskTagsRet: wantTaskOut}
+	svc := NewService(fakeRepo)
+
+	got, err := svc.AddTaskTags(ctx, 2, []string{"Work", " home", "work"})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.addTaskTagsCalls != 1 || fakeRepo.addTaskTagsCtx != ctx {
+		t.Fatalf("expected AddTaskTags called once with ctx, got %d calls", fakeRepo.addTaskTagsCalls)
+	}
+	if fakeRepo.addTaskTagsId != 2 || !reflect.DeepEqual(fakeRepo.addTaskTagsTags, []string{"home", "work"}) {
+		t.Fatalf("unexpected args: id=%d tags=%v", fakeRepo.addTaskTagsId, fakeRepo.addTaskTagsTags)
+	}
+	if !reflect.DeepEqual(got, wantTaskOut) {
+		t.Fatalf("expected task %+v, got %+v", wantTaskOut, got)
+	}
+}
+
+func TestServiceRemoveTaskTags_NoTags_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.RemoveTaskTags(context.Background(), 2, nil)
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.removeTaskTagsCalls != 0 {
+		t.Fatalf("expected RemoveTaskTags not called, got %d", fakeRepo.removeTaskTagsCalls)
+	}
+}
+
+func TestServiceListTags_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantTags := []models.TagUsage{{Name: "home", Count: 2}}
+	wantErr := errors.New("boom")
+	fakeRepo := &fakeRepo{listTagsRet: wantTags, listTagsErr: wantErr}
+	svc := NewService(fakeRepo)
+
+	got, err := svc.ListTags(ctx)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, err)
+	}
+	if fakeRepo.listTagsCalls != 1 || fakeRepo.listTagsCtx != ctx {
+		t.Fatalf("expected ListTags called once with ctx, got %d calls", fakeRepo.listTagsCalls)
+	}
+	if !reflect.DeepEqual(got, wantTags) {
+		t.Fatalf("expected tags %+v, got %+v", wantTags, got)
+	}
+}
+
+func TestServiceRenameTag_NormalizesNames(t *testing.T) {
+	fakeRepo := &fakeRepo{renameTagRet: models.TagChange{Name: "job", TaskIds: []int{1}}}
+	svc := NewService(fakeRepo)
+
+	got, err := svc.RenameTag(context.Background(), " Work", "JOB")
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.renameTagName != "work" || fakeRepo.renameTagNewName != "job" {
+		t.Fatalf("unexpected args: %q -> %q", fakeRepo.renameTagName, fakeRepo.renameTagNewName)
+	}
+	if got.Name != "job" {
+		t.Fatalf("unexpected change %+v", got)
+	}
+}
+
+func TestServiceMergeTags_DropsTargetFromSources(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.MergeTags(context.Background(), []string{"job", "Work", "office"}, "work")
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !reflect.DeepEqual(fakeRepo.mergeTagsSources, []string{"job", "office"}) || fakeRepo.mergeTagsTarget != "work" {
+		t.Fatalf("unexpected args: %v -> %q", fakeRepo.mergeTagsSources, fakeRepo.mergeTagsTarget)
+	}
+}
+
+func TestServiceMergeTags_OnlyTarget_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.MergeTags(context.Background(), []string{"work"}, "work")
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.mergeTagsCalls != 0 {
+		t.Fatalf("expected MergeTags not called, got %d", fakeRepo.mergeTagsCalls)
+	}
+}
+
+func TestServiceCreateProject_TrimsNameAndDelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantProject := models.Project{Id: 2, Name: "Work"}
+	fakeRepo := &fakeRepo{createProjectRet: wantProject}
+	svc := NewService(fakeRepo)
+
+	got, err := svc.CreateProject(ctx, " Work ")
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.createProjectCalls != 1 || fakeRepo.createProjectCtx != ctx || fakeRepo.createProjectIn != "Work" {
+		t.Fatalf("unexpected call: calls=%d name=%q", fakeRepo.createProjectCalls, fakeRepo.createProjectIn)
+	}
+	if got != wantProject {
+		t.Fatalf("expected project %+v, got %+v", wantProject, got)
+	}
+}
+
+func TestServiceRenameProject_EmptyName_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.RenameProject(context.Background(), 2, "  ")
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.renameProjectCalls != 0 {
+		t.Fatalf("expected RenameProject not called, got %d", fakeRepo.renameProjectCalls)
+	}
+}
+
+func TestServiceDeleteProject_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantErr := ErrInboxProtected
+	fakeRepo := &fakeRepo{deleteProjectErr: wantErr}
+	svc := NewService(fakeRepo)
+
+	err := svc.DeleteProject(ctx, 1)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, err)
+	}
+	if fakeRepo.deleteProjectCalls != 1 || fakeRepo.deleteProjectCtx != ctx || fakeRepo.deleteProjectIn != 1 {
+		t.Fatalf("unexpected call: calls=%d id=%d", fakeRepo.deleteProjectCalls, fakeRepo.deleteProjectIn)
+	}
+}
+
+func TestServiceMoveTaskToProject_ComputesOverdue(t *testing.T) {
+	now := time.Date(2025, 12, 17, 12, 0, 0, 0, time.UTC)
+	dueAt := now.Add(-time.Hour)
+	fakeRepo := &fakeRepo{moveTaskToProjectRet: models.TaskExportData{Id: 4, ProjectId: 2, DueAt: &dueAt}}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	got, err := svc.MoveTaskToProject(context.Background(), 4, 2)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.moveTaskToProjectId != 4 || fakeRepo.moveTaskToProjectProjectId != 2 {
+		t.Fatalf("unexpected args: id=%d project=%d", fakeRepo.moveTaskToProjectId, fakeRepo.moveTaskToProjectProjectId)
+	}
+	if !got.Overdue || got.ProjectId != 2 {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestServiceGetTaskTree_BuildsTreeAndComputesOverdue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 17, 12, 0, 0, 0, time.UTC)
+	dueAt := now.Add(-time.Hour)
+	fakeRepo := &fakeRepo{
+		getTaskRet:      models.TaskExportData{Id: 1, SubtasksTotal: 1},
+		listSubtasksRet: []models.TaskExportData{{Id: 2, ParentId: 1, DueAt: &dueAt}},
+	}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	got, err := svc.GetTaskTree(ctx, 1)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.getTaskIn != 1 || fakeRepo.getTaskCtx != ctx || fakeRepo.listSubtasksIn != 1 || fakeRepo.listSubtasksCtx != ctx {
+		t.Fatalf("unexpected calls: GetTask(%d) ListSubtasks(%d)", fakeRepo.getTaskIn, fakeRepo.listSubtasksIn)
+	}
+	if got.Task.Id != 1 || len(got.Subtasks) != 1 || !got.Subtasks[0].Task.Overdue {
+		t.Fatalf("unexpected tree %+v", got)
+	}
+}
+
+func TestServiceGetTaskTree_TaskNotFound_DoesNotListSubtasks(t *testing.T) {
+	fakeRepo := &fakeRepo{getTaskErr: ErrTaskNotFound}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.GetTaskTree(context.Background(), 1)
+
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected err %v, got %v", ErrTaskNotFound, err)
+	}
+	if fakeRepo.listSubtasksCalls != 0 {
+		t.Fatalf("expected ListSubtasks not called, got %d calls", fakeRepo.listSubtasksCalls)
+	}
+}
+
+func TestServiceAddTask_RecurringSubtask_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.AddTask(context.Background(), models.TaskImportData{
+		Title:      "step",
+		ParentId:   3,
+		Recurrence: &models.Recurrence{Frequency: models.RecurrenceDaily},
+	})
+
+	if !errors.Is(err, ErrRecurringSubtask) {
+		t.Fatalf("expected err %v, got %v", ErrRecurringSubtask, err)
+	}
+	if fakeRepo.addTaskCalls != 0 {
+		t.Fatalf("expected AddTask not called, got %d calls", fakeRepo.addTaskCalls)
+	}
+}
+
+func TestServiceSetTaskRecurrence_NormalizesRule(t *testing.T) {
+	ctx := context.Background()
+	fakeRepo := &fakeRepo{setTaskRecurrenceRet: models.TaskExportData{Id: 4}}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.SetTaskRecurrence(ctx, 4, &models.Recurrence{Frequency: models.RecurrenceMonthly, MonthDay: 10})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.setTaskRecurrenceCtx != ctx || fakeRepo.setTaskRecurrenceId != 4 {
+		t.Fatalf("unexpected call: id=%d", fakeRepo.setTaskRecurrenceId)
+	}
+	want := &models.Recurrence{Frequency: models.RecurrenceMonthly, Interval: 1, MonthDay: 10, TimeZone: "UTC"}
+	if !reflect.DeepEqual(fakeRepo.setTaskRecurrenceRule, want) {
+		t.Fatalf("rule mismatch: want %+v got %+v", want, fakeRepo.setTaskRecurrenceRule)
+	}
+}
+
+func TestServiceSetTaskRecurrence_InvalidRule_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.SetTaskRecurrence(context.Background(), 4, &models.Recurrence{Frequency: models.RecurrenceDaily, Interval: -2})
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected err %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.setTaskRecurrenceCalls != 0 {
+		t.Fatalf("expected SetTaskRecurrence not called, got %d calls", fakeRepo.setTaskRecurrenceCalls)
+	}
+}
+
+func TestServiceSkipOccurrence_ComputesOverdue(t *testing.T) {
+	now := time.Date(2025, 12, 17, 12, 0, 0, 0, time.UTC)
+	dueAt := now.Add(24 * time.Hour)
+	fakeRepo := &fakeRepo{skipOccurrenceRet: models.TaskExportData{Id: 4, DueAt: &dueAt}}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	got, err := svc.SkipOccurrence(context.Background(), 4)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.skipOccurrenceIn != 4 {
+		t.Fatalf("unexpected id %d", fakeRepo.skipOccurrenceIn)
+	}
+	if got.Overdue {
+		t.Fatalf("unexpected task %+v", got)
+	}
+}
+
+func TestServicePurgeTrash_PassesCutoffByRetention(t *testing.T) {
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	fakeRepo := &fakeRepo{purgeTrashRet: 3}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	purged, err := svc.PurgeTrash(context.Background(), 48*time.Hour)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if purged != 3 {
+		t.Fatalf("expected 3 purged, got %d", purged)
+	}
+	if want := now.Add(-48 * time.Hour); !fakeRepo.purgeTrashIn.Equal(want) {
+		t.Fatalf("expected cutoff %v, got %v", want, fakeRepo.purgeTrashIn)
+	}
+}
+
+// purgeSignalRepo reports every PurgeTrash call of the background job.
+type purgeSignalRepo struct {
+	*fakeRepo
+	purged chan time.Time
+}
+
+func (r purgeSignalRepo) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	select {
+	case r.purged <- before:
+	case <-ctx.Done():
+	}
+	return 0, nil
+}
+
+func TestServiceRunTrashPurge_PurgesUntilContextDone(t *testing.T) {
+	repo := purgeSignalRepo{fakeRepo: &fakeRepo{}, purged: make(chan time.Time)}
+	svc := NewService(repo)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		svc.RunTrashPurge(ctx, time.Hour, time.Millisecond)
+		close(done)
+	}()
+
+	for range 2 {
+		select {
+		case <-repo.purged:
+		case <-time.After(time.Second):
+			t.Fatal("expected purge to run repeatedly")
+		}
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected RunTrashPurge to return after cancel")
+	}
+}
+
+func TestServiceRestoreTask_DelegatesToTaskRepo(t *testing.T) {
+	wantErr := ErrParentInTrash
+	fakeRepo := &fakeRepo{restoreTaskErr: wantErr}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.RestoreTask(context.Background(), 12)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, err)
+	}
+	if fakeRepo.restoreTaskCalls != 1 || fakeRepo.restoreTaskIn != 12 {
+		t.Fatalf("unexpected RestoreTask call: calls=%d id=%d", fakeRepo.restoreTaskCalls, fakeRepo.restoreTaskIn)
+	}
+}
+
+func TestServiceGetTaskHistory_DelegatesToTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	wantPage := models.TaskHistoryPage{
+		Changes: []models.TaskChange{
+			{Id: 12, TaskId: 3, ChangedAt: time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC), ChangedBy: "alice", Field: "title", OldValue: `"a"`, NewValue: `"b"`},
+		},
+		NextCursor: 12,
+	}
+	wantErr := errors.New("boom")
+	fakeRepo := &fakeRepo{
+		getTaskHistoryRet: wantPage,
+		getTaskHistoryErr: wantErr,
+	}
+	svc := NewService(fakeRepo)
+
+	got, gotErr := svc.GetTaskHistory(ctx, models.TaskHistoryRequest{TaskId: 3, Cursor: 20})
+
+	if fakeRepo.getTaskHistoryCalls != 1 {
+		t.Fatalf("expected GetTaskHistory called=1, got %d", fakeRepo.getTaskHistoryCalls)
+	}
+	if !errors.Is(gotErr, wantErr) {
+		t.Fatalf("expected err %v, got %v", wantErr, gotErr)
+	}
+	if fakeRepo.getTaskHistoryCtx != ctx {
+		t.Fatalf("context mismatch")
+	}
+	wantReq := models.TaskHistoryRequest{TaskId: 3, Limit: defaultHistoryLimit, Cursor: 20}
+	if !reflect.DeepEqual(fakeRepo.getTaskHistoryIn, wantReq) {
+		t.Fatalf("mismatch history request: want %+v got %+v", wantReq, fakeRepo.getTaskHistoryIn)
+	}
+	if !reflect.DeepEqual(got, wantPage) {
+		t.Fatalf("mismatch history page: want %+v got %+v", wantPage, got)
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const (
+	defaultStatsDayBuckets  = 30
+	defaultStatsWeekBuckets = 12
+	maxStatsBuckets         = 366
+)
+
+func bucketDuration(bucket models.StatsBucket) time.Duration {
+	if bucket == models.StatsBucketWeek {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// normalizeStatsRequest fills in defaults and rejects requests that would
+// make Postgres generate an unreasonable number of buckets.
+func normalizeStatsRequest(req models.StatsRequest, now time.Time) (models.StatsRequest, error) {
+	if req.Bucket != models.StatsBucketDay && req.Bucket != models.StatsBucketWeek {
+		return req, fmt.Errorf("%w: unknown stats bucket %d", ErrInvalidArgument, req.Bucket)
+	}
+
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(req.TimeZone); err != nil {
+		return req, fmt.Errorf("%w: unknown time zone %q", ErrInvalidArgument, req.TimeZone)
+	}
+
+	if req.To.IsZero() {
+		req.To = now
+	}
+	if req.From.IsZero() {
+		buckets := defaultStatsDayBuckets
+		if req.Bucket == models.StatsBucketWeek {
+			buckets = defaultStatsWeekBuckets
+		}
+		req.From = req.To.Add(-time.Duration(buckets) * bucketDuration(req.Bucket))
+	}
+
+	if !req.From.Before(req.To) {
+		return req, fmt.Errorf("%w: stats range start must be before its end", ErrInvalidArgument)
+	}
+	if req.To.Sub(req.From) > maxStatsBuckets*bucketDuration(req.Bucket) {
+		return req, fmt.Errorf("%w: stats range exceeds %d %s buckets", ErrInvalidArgument, maxStatsBuckets, req.Bucket)
+	}
+
+	return req, nil
+}
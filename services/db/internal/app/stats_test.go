@@ -0,0 +1,95 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeStatsRequest(t *testing.T) {
+	now := time.Date(2025, 12, 20, 15, 0, 0, 0, time.UTC)
+	fromTS := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	toTS := time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		in      models.StatsRequest
+		want    models.StatsRequest
+		wantErr error
+	}{
+		{
+			name: "explicit request",
+			in: models.StatsRequest{
+				From:     fromTS,
+				To:       toTS,
+				Bucket:   models.StatsBucketWeek,
+				TimeZone: "Europe/Moscow",
+			},
+			want: models.StatsRequest{
+				From:     fromTS,
+				To:       toTS,
+				Bucket:   models.StatsBucketWeek,
+				TimeZone: "Europe/Moscow",
+			},
+		},
+		{
+			name: "defaults for day buckets",
+			in:   models.StatsRequest{},
+			want: models.StatsRequest{
+				From:     now.AddDate(0, 0, -defaultStatsDayBuckets),
+				To:       now,
+				Bucket:   models.StatsBucketDay,
+				TimeZone: "UTC",
+			},
+		},
+		{
+			name: "defaults for week buckets",
+			in:   models.StatsRequest{Bucket: models.StatsBucketWeek},
+			want: models.StatsRequest{
+				From:     now.AddDate(0, 0, -7*defaultStatsWeekBuckets),
+				To:       now,
+				Bucket:   models.StatsBucketWeek,
+				TimeZone: "UTC",
+			},
+		},
+		{
+			name:    "unknown bucket",
+			in:      models.StatsRequest{Bucket: models.StatsBucket(42)},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "unknown time zone",
+			in:      models.StatsRequest{TimeZone: "Mars/Olympus"},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "reversed range",
+			in:      models.StatsRequest{From: toTS, To: fromTS},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "too many buckets",
+			in:      models.StatsRequest{From: fromTS.AddDate(-2, 0, 0), To: toTS},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeStatsRequest(tt.in, now)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected err %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package app
+
+import "github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+
+// buildTaskTree nests descendants under root by ParentId, keeping the order
+// they came in for siblings.
+func buildTaskTree(root models.TaskExportData, descendants []models.TaskExportData) models.TaskTree {
+	children := make(map[int][]models.TaskExportData)
+	for _, task := range descendants {
+		children[task.ParentId] = append(children[task.ParentId], task)
+	}
+
+	var build func(task models.TaskExportData) models.TaskTree
+	build = func(task models.TaskExportData) models.TaskTree {
+		tree := models.TaskTree{Task: task, Subtasks: make([]models.TaskTree, 0, len(children[task.Id]))}
+		for _, child := range children[task.Id] {
+			tree.Subtasks = append(tree.Subtasks, build(child))
+		}
+		return tree
+	}
+
+	return build(root)
+}
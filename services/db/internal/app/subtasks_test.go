@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildTaskTree(t *testing.T) {
+	root := models.TaskExportData{Id: 1, SubtasksTotal: 2}
+	descendants := []models.TaskExportData{
+		{Id: 4, ParentId: 1},
+		{Id: 2, ParentId: 1, SubtasksTotal: 1},
+		{Id: 3, ParentId: 2},
+	}
+
+	got := buildTaskTree(root, descendants)
+
+	want := models.TaskTree{
+		Task: root,
+		Subtasks: []models.TaskTree{
+			{Task: descendants[0], Subtasks: []models.TaskTree{}},
+			{Task: descendants[1], Subtasks: []models.TaskTree{
+				{Task: descendants[2], Subtasks: []models.TaskTree{}},
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestBuildTaskTree_NoSubtasks(t *testing.T) {
+	got := buildTaskTree(models.TaskExportData{Id: 1}, nil)
+
+	if got.Task.Id != 1 || got.Subtasks == nil || len(got.Subtasks) != 0 {
+		t.Fatalf("unexpected tree %+v", got)
+	}
+}
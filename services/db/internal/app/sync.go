@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const (
+	defaultChangesLimit = 500
+	maxChangesLimit     = 1000
+)
+
+// GetChangesSince returns the task changes after req.Token and the token to
+// continue from.
+func (s *Service) GetChangesSince(ctx context.Context, req models.ChangesRequest) (models.Changes, error) {
+	log.Printf("IN: get changes since: %+v\n", req)
+
+	req, err := normalizeChangesRequest(req)
+	if err != nil {
+		log.Printf("OUT(ERR): get changes since: %v\n", err)
+		return models.Changes{}, err
+	}
+
+	changes, err := s.dbController.GetChangesSince(ctx, req)
+	if err != nil {
+		log.Printf("OUT(ERR): get changes since: %v\n", err)
+		return models.Changes{}, err
+	}
+
+	now := s.now()
+	for i := range changes.Tasks {
+		changes.Tasks[i] = withOverdue(changes.Tasks[i], now)
+	}
+	changes.Token = encodeSyncToken(changes.Next)
+
+	log.Printf("OUT(OK): get changes since: %d changed, %d deleted, full=%v\n", len(changes.Tasks), len(changes.DeletedIds), changes.Full)
+	return changes, nil
+}
+
+func normalizeChangesRequest(req models.ChangesRequest) (models.ChangesRequest, error) {
+	switch {
+	case req.Limit == 0:
+		req.Limit = defaultChangesLimit
+	case req.Limit < 0 || req.Limit > maxChangesLimit:
+		return req, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidArgument, maxChangesLimit)
+	}
+
+	since, err := decodeSyncToken(req.Token)
+	if err != nil {
+		return req, err
+	}
+	req.Since = since
+
+	return req, nil
+}
+
+// Sync tokens are opaque to clients, so that the cursor format can change.
+func encodeSyncToken(cursor models.SyncCursor) string {
+	if cursor == (models.SyncCursor{}) {
+		return ""
+	}
+	raw := strconv.FormatInt(cursor.Seq, 10) + "." + strconv.Itoa(cursor.TaskId)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSyncToken(token string) (models.SyncCursor, error) {
+	if token == "" {
+		return models.SyncCursor{}, nil
+	}
+
+	errBadToken := fmt.Errorf("%w: malformed sync token", ErrInvalidArgument)
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return models.SyncCursor{}, errBadToken
+	}
+	rawSeq, rawId, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return models.SyncCursor{}, errBadToken
+	}
+	seq, err := strconv.ParseInt(rawSeq, 10, 64)
+	if err != nil || seq <= 0 {
+		return models.SyncCursor{}, errBadToken
+	}
+	id, err := strconv.Atoi(rawId)
+	if err != nil || id < 0 {
+		return models.SyncCursor{}, errBadToken
+	}
+
+	return models.SyncCursor{Seq: seq, TaskId: id}, nil
+}
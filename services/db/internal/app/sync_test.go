@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSyncToken_RoundTrip(t *testing.T) {
+	for _, cursor := range []models.SyncCursor{{}, {Seq: 812, TaskId: 0}, {Seq: 1 << 40, TaskId: 17}} {
+		got, err := decodeSyncToken(encodeSyncToken(cursor))
+		if err != nil {
+			t.Fatalf("%+v: expected nil, got %v", cursor, err)
+		}
+		if got != cursor {
+			t.Fatalf("got %+v want %+v", got, cursor)
+		}
+	}
+}
+
+func TestNormalizeChangesRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      models.ChangesRequest
+		want    models.ChangesRequest
+		wantErr error
+	}{
+		{
+			name: "first sync with default limit",
+			in:   models.ChangesRequest{},
+			want: models.ChangesRequest{Limit: defaultChangesLimit},
+		},
+		{
+			name: "token is decoded",
+			in:   models.ChangesRequest{Token: encodeSyncToken(models.SyncCursor{Seq: 812, TaskId: 3}), Limit: 10},
+			want: models.ChangesRequest{Token: encodeSyncToken(models.SyncCursor{Seq: 812, TaskId: 3}), Since: models.SyncCursor{Seq: 812, TaskId: 3}, Limit: 10},
+		},
+		{
+			name:    "malformed token",
+			in:      models.ChangesRequest{Token: "not a token"},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "token without a task id",
+			in:      models.ChangesRequest{Token: "ODEy"},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "limit too big",
+			in:      models.ChangesRequest{Limit: maxChangesLimit + 1},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeChangesRequest(tt.in)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil {
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Fatalf("request (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceGetChangesSince_PassesCursorAndReturnsNextToken(t *testing.T) {
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	dueAt := now.Add(-time.Hour)
+	next := models.SyncCursor{Seq: 900}
+	fakeRepo := &fakeRepo{getChangesRet: models.Changes{
+		Tasks:      []models.TaskExportData{{Id: 3, DueAt: &dueAt}},
+		DeletedIds: []int{4},
+		Next:       next,
+	}}
+	svc := NewService(fakeRepo)
+	svc.now = func() time.Time { return now }
+
+	changes, err := svc.GetChangesSince(context.Background(), models.ChangesRequest{Token: encodeSyncToken(models.SyncCursor{Seq: 812})})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fakeRepo.getChangesIn.Since != (models.SyncCursor{Seq: 812}) || fakeRepo.getChangesIn.Limit != defaultChangesLimit {
+		t.Fatalf("unexpected request %+v", fakeRepo.getChangesIn)
+	}
+	if len(changes.Tasks) != 1 || !changes.Tasks[0].Overdue || changes.Token != encodeSyncToken(next) {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+}
+
+func TestServiceGetChangesSince_BadToken_DoesNotCallTaskRepo(t *testing.T) {
+	fakeRepo := &fakeRepo{}
+	svc := NewService(fakeRepo)
+
+	_, err := svc.GetChangesSince(context.Background(), models.ChangesRequest{Token: "%%%"})
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+	if fakeRepo.getChangesCalls != 0 {
+		t.Fatalf("expected GetChangesSince not called, got %d calls", fakeRepo.getChangesCalls)
+	}
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const maxTagLength = 50
+
+// normalizeTag makes tag names case-insensitive and free of surrounding spaces.
+func normalizeTag(name string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(name))
+	if tag == "" {
+		return "", fmt.Errorf("%w: empty tag", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(tag) > maxTagLength {
+		return "", fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidArgument, tag, maxTagLength)
+	}
+	return tag, nil
+}
+
+// normalizeTags returns sorted unique normalized tags.
+func normalizeTags(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		tag, err := normalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags), nil
+}
+
+func matchesTags(task models.TaskExportData, tags []string, match models.TagMatch) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	for _, tag := range tags {
+		found := slices.Contains(task.Tags, tag)
+		if match == models.TagMatchAny && found {
+			return true
+		}
+		if match == models.TagMatchAll && !found {
+			return false
+		}
+	}
+	return match == models.TagMatchAll
+}
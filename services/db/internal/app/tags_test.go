@@ -0,0 +1,107 @@
+package app
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no tags", in: nil, want: nil},
+		{name: "case and spaces", in: []string{" Work ", "home", "WORK"}, want: []string{"home", "work"}},
+		{name: "empty tag", in: []string{"work", "  "}, wantErr: true},
+		{name: "too long tag", in: []string{strings.Repeat("я", maxTagLength+1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.in)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterTasks_ByTags(t *testing.T) {
+	now := time.Date(2025, 12, 17, 12, 0, 0, 0, time.UTC)
+	tasks := []models.TaskExportData{
+		{Id: 1, Tags: []string{"home", "work"}},
+		{Id: 2, Tags: []string{"work"}},
+		{Id: 3, Tags: []string{"home"}},
+		{Id: 4},
+	}
+
+	tests := []struct {
+		name    string
+		filter  models.TaskFilter
+		wantIds []int
+	}{
+		{name: "no tags", filter: models.TaskFilter{}, wantIds: []int{1, 2, 3, 4}},
+		{name: "any of", filter: models.TaskFilter{Tags: []string{"Home", "work"}}, wantIds: []int{1, 2, 3}},
+		{name: "all of", filter: models.TaskFilter{Tags: []string{"home", "work"}, TagMatch: models.TagMatchAll}, wantIds: []int{1}},
+		{name: "unknown tag", filter: models.TaskFilter{Tags: []string{"gym"}}, wantIds: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterTasks(tasks, tt.filter, now)
+
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			gotIds := make([]int, 0, len(got))
+			for _, task := range got {
+				gotIds = append(gotIds, task.Id)
+			}
+			if !reflect.DeepEqual(gotIds, tt.wantIds) {
+				t.Fatalf("expected ids %v, got %v", tt.wantIds, gotIds)
+			}
+		})
+	}
+}
+
+func TestFilterTasks_ByProject(t *testing.T) {
+	tasks := []models.TaskExportData{
+		{Id: 1, ProjectId: 1},
+		{Id: 2, ProjectId: 2},
+		{Id: 3, ProjectId: 1},
+	}
+
+	got, err := filterTasks(tasks, models.TaskFilter{ProjectId: 1}, time.Now())
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(got) != 2 || got[0].Id != 1 || got[1].Id != 3 {
+		t.Fatalf("unexpected tasks %+v", got)
+	}
+}
+
+func TestFilterTasks_UnknownTagMatch_ReturnsError(t *testing.T) {
+	_, err := filterTasks(nil, models.TaskFilter{TagMatch: models.TagMatch(5)}, time.Now())
+
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgument, err)
+	}
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// DefaultTrashRetention is how long deleted tasks stay restorable unless
+// configured otherwise.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+func (s *Service) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	log.Println("IN: list trash")
+
+	tasks, err := s.dbController.ListTrash(ctx)
+
+	if err != nil {
+		log.Printf("OUT(ERR): list trash: %v\n", err)
+	} else {
+		log.Printf("OUT(OK): list trash: %+v\n", tasks)
+	}
+
+	return tasks, err
+}
+
+func (s *Service) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	log.Printf("IN: restore task with ID: %v\n", id)
+
+	restoredTask, err := s.dbController.RestoreTask(ctx, id)
+	restoredTask = withOverdue(restoredTask, s.now())
+
+	if err != nil {
+		log.Printf("OUT(ERR): restore task with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): restore task with ID %v\n", id)
+	}
+
+	return restoredTask, err
+}
+
+func (s *Service) PurgeTask(ctx context.Context, id int) error {
+	log.Printf("IN: purge task with ID: %v\n", id)
+
+	err := s.dbController.PurgeTask(ctx, id)
+
+	if err != nil {
+		log.Printf("OUT(ERR): purge task with ID %v: %v\n", id, err)
+	} else {
+		log.Printf("OUT(OK): purge task with ID %v\n", id)
+	}
+
+	return err
+}
+
+// PurgeTrash permanently deletes the tasks that stayed in the trash longer
+// than retention.
+func (s *Service) PurgeTrash(ctx context.Context, retention time.Duration) (int, error) {
+	log.Printf("IN: purge trash older than %v\n", retention)
+
+	purged, err := s.dbController.PurgeTrash(ctx, s.now().Add(-retention))
+
+	if err != nil {
+		log.Printf("OUT(ERR): purge trash: %v\n", err)
+	} else {
+		log.Printf("OUT(OK): purge trash: %d tasks\n", purged)
+	}
+
+	return purged, err
+}
+
+// RunTrashPurge calls PurgeTrash every interval until ctx is done. A failed
+// purge is retried on the next tick.
+func (s *Service) RunTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = s.PurgeTrash(ctx, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package app
+
+import "context"
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion makes the repository reject changes to a task whose
+// version differs from version. Zero disables the check.
+func WithExpectedVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// ExpectedVersionFrom returns the version stored by WithExpectedVersion or 0.
+func ExpectedVersionFrom(ctx context.Context) int {
+	version, _ := ctx.Value(expectedVersionKey{}).(int)
+	return version
+}
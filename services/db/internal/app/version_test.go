@@ -0,0 +1,18 @@
+package app
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExpectedVersionFrom(t *testing.T) {
+	if got := ExpectedVersionFrom(context.Background()); got != 0 {
+		t.Fatalf("expected no version, got %d", got)
+	}
+
+	ctx := WithExpectedVersion(context.Background(), 5)
+
+	if got := ExpectedVersionFrom(ctx); got != 5 {
+		t.Fatalf("expected %d, got %d", 5, got)
+	}
+}
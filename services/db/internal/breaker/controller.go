@@ -0,0 +1,125 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+)
+
+const (
+	DefaultThreshold = 5
+	DefaultCooldown  = 10 * time.Second
+)
+
+// CacheBreaker is a circuit breaker in front of the cache. After threshold
+// failed calls in a row it opens: calls fail with app.ErrCacheUnavailable
+// without reaching the cache, so requests go straight to the main DB.
+// After cooldown the next call probes the cache by flushing it, since the
+// changes made while the breaker was open never reached the cache. The
+// breaker closes once the flush succeeds.
+type CacheBreaker struct {
+	next      app.CacheController
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCacheBreaker opens after threshold failures in a row and probes next
+// every cooldown while open.
+func NewCacheBreaker(next app.CacheController, threshold int, cooldown time.Duration) *CacheBreaker {
+	return &CacheBreaker{
+		next:      next,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Trip opens the breaker, e.g. when the cache is down at startup.
+func (cb *CacheBreaker) Trip() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.trip()
+}
+
+func (cb *CacheBreaker) trip() {
+	cb.open = true
+	cb.openedAt = cb.now()
+}
+
+// call runs fn unless the breaker is open and counts its outcome.
+func (cb *CacheBreaker) call(ctx context.Context, fn func() error) error {
+	if err := cb.acquire(ctx); err != nil {
+		return err
+	}
+	err := fn()
+	cb.record(err)
+	return err
+}
+
+// acquire lets a call through while the breaker is closed. Once the
+// cooldown is over, a single caller probes the cache and the others keep
+// getting app.ErrCacheUnavailable meanwhile.
+func (cb *CacheBreaker) acquire(ctx context.Context) error {
+	cb.mu.Lock()
+	if !cb.open {
+		cb.mu.Unlock()
+		return nil
+	}
+	if cb.probing || cb.now().Before(cb.openedAt.Add(cb.cooldown)) {
+		cb.mu.Unlock()
+		return app.ErrCacheUnavailable
+	}
+	cb.probing = true
+	cb.mu.Unlock()
+
+	err := cb.next.FlushAllData(ctx)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+	if err != nil {
+		log.Printf("cache is still unavailable: %v\n", err)
+		cb.trip()
+		return app.ErrCacheUnavailable
+	}
+	log.Println("cache is available again, flushed it and closed the breaker")
+	cb.open = false
+	cb.failures = 0
+	return nil
+}
+
+func (cb *CacheBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !failed(err) {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if !cb.open && cb.failures >= cb.threshold {
+		log.Printf("cache failed %d times in a row, opened the breaker: %v\n", cb.failures, err)
+		cb.trip()
+	}
+}
+
+// failed tells a cache that is down from answers like a miss or a held lock.
+func failed(err error) bool {
+	return err != nil &&
+		!errors.Is(err, app.ErrTaskNotFound) &&
+		!errors.Is(err, app.ErrLockHeld) &&
+		!errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,92 @@
+package breaker
+
+import (
+	"context"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+func (cb *CacheBreaker) CacheTaskList(ctx context.Context, tasks []models.TaskExportData) error {
+	return cb.call(ctx, func() error {
+		return cb.next.CacheTaskList(ctx, tasks)
+	})
+}
+
+func (cb *CacheBreaker) DeleteTaskList(ctx context.Context) error {
+	return cb.call(ctx, func() error {
+		return cb.next.DeleteTaskList(ctx)
+	})
+}
+
+func (cb *CacheBreaker) GetTaskList(ctx context.Context) (app.CachedTaskList, error) {
+	var list app.CachedTaskList
+	err := cb.call(ctx, func() error {
+		var err error
+		list, err = cb.next.GetTaskList(ctx)
+		return err
+	})
+	return list, err
+}
+
+func (cb *CacheBreaker) CacheTask(ctx context.Context, task models.TaskExportData) error {
+	return cb.call(ctx, func() error {
+		return cb.next.CacheTask(ctx, task)
+	})
+}
+
+func (cb *CacheBreaker) DeleteTaskById(ctx context.Context, id int) error {
+	return cb.call(ctx, func() error {
+		return cb.next.DeleteTaskById(ctx, id)
+	})
+}
+
+func (cb *CacheBreaker) RemoveTasksFromList(ctx context.Context, ids []int) error {
+	return cb.call(ctx, func() error {
+		return cb.next.RemoveTasksFromList(ctx, ids)
+	})
+}
+
+func (cb *CacheBreaker) GetTaskById(ctx context.Context, id int) (models.TaskExportData, bool, error) {
+	var (
+		task  models.TaskExportData
+		stale bool
+	)
+	err := cb.call(ctx, func() error {
+		var err error
+		task, stale, err = cb.next.GetTaskById(ctx, id)
+		return err
+	})
+	return task, stale, err
+}
+
+func (cb *CacheBreaker) FlushAllData(ctx context.Context) error {
+	return cb.call(ctx, func() error {
+		return cb.next.FlushAllData(ctx)
+	})
+}
+
+func (cb *CacheBreaker) Close() error {
+	return cb.next.Close()
+}
+
+// Locker puts the locks taken in the same Redis behind the breaker too.
+func (cb *CacheBreaker) Locker(locker app.CacheLocker) app.CacheLocker {
+	return &guardedLocker{breaker: cb, next: locker}
+}
+
+type guardedLocker struct {
+	breaker *CacheBreaker
+	next    app.CacheLocker
+}
+
+func (gl *guardedLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	var unlock func(context.Context) error
+	err := gl.breaker.call(ctx, func() error {
+		var err error
+		unlock, err = gl.next.TryLock(ctx, key, ttl)
+		return err
+	})
+	return unlock, err
+}
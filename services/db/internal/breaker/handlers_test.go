@@ -0,0 +1,176 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+type fakeCache struct {
+	err      error
+	flushErr error
+
+	calls      int
+	flushCalls int
+}
+
+func (fc *fakeCache) CacheTaskList(ctx context.Context, tasks []models.TaskExportData) error {
+	fc.calls++
+	return fc.err
+}
+
+func (fc *fakeCache) DeleteTaskList(ctx context.Context) error {
+	fc.calls++
+	return fc.err
+}
+
+func (fc *fakeCache) GetTaskList(ctx context.Context) (app.CachedTaskList, error) {
+	fc.calls++
+	return app.CachedTaskList{}, fc.err
+}
+
+func (fc *fakeCache) CacheTask(ctx context.Context, task models.TaskExportData) error {
+	fc.calls++
+	return fc.err
+}
+
+func (fc *fakeCache) DeleteTaskById(ctx context.Context, id int) error {
+	fc.calls++
+	return fc.err
+}
+
+func (fc *fakeCache) RemoveTasksFromList(ctx context.Context, ids []int) error {
+	fc.calls++
+	return fc.err
+}
+
+func (fc *fakeCache) GetTaskById(ctx context.Context, id int) (models.TaskExportData, bool, error) {
+	fc.calls++
+	return models.TaskExportData{Id: id}, false, fc.err
+}
+
+func (fc *fakeCache) FlushAllData(ctx context.Context) error {
+	fc.flushCalls++
+	return fc.flushErr
+}
+
+func (fc *fakeCache) Close() error {
+	return nil
+}
+
+type fakeLocker struct {
+	calls int
+}
+
+func (fl *fakeLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	fl.calls++
+	return func(context.Context) error { return nil }, nil
+}
+
+func newTestBreaker() (*CacheBreaker, *fakeCache, *time.Time) {
+	next := &fakeCache{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := NewCacheBreaker(next, 3, time.Minute)
+	cb.now = func() time.Time { return now }
+	return cb, next, &now
+}
+
+func TestCacheBreaker_FailuresInARow_OpenIt(t *testing.T) {
+	cb, next, _ := newTestBreaker()
+	next.err = errors.New("connection refused")
+	ctx := context.Background()
+
+	for range 3 {
+		_ = cb.CacheTask(ctx, models.TaskExportData{Id: 1})
+	}
+	_, _, err := cb.GetTaskById(ctx, 1)
+
+	if !errors.Is(err, app.ErrCacheUnavailable) {
+		t.Fatalf("expected %v, got %v", app.ErrCacheUnavailable, err)
+	}
+	if next.calls != 3 {
+		t.Fatalf("expected the call after opening skipped, got %d calls", next.calls)
+	}
+}
+
+func TestCacheBreaker_MissesAndSuccess_DontOpenIt(t *testing.T) {
+	cb, next, _ := newTestBreaker()
+	ctx := context.Background()
+
+	for _, err := range []error{errors.New("timeout"), errors.New("timeout"), nil, errors.New("timeout"),
+		app.ErrTaskNotFound, errors.New("timeout"), errors.New("timeout")} {
+		next.err = err
+		_ = cb.DeleteTaskById(ctx, 1)
+	}
+	next.err = nil
+
+	if err := cb.DeleteTaskList(ctx); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if next.calls != 8 {
+		t.Fatalf("expected every call to reach the cache, got %d calls", next.calls)
+	}
+}
+
+func TestCacheBreaker_AfterCooldown_FlushesAndCloses(t *testing.T) {
+	cb, next, now := newTestBreaker()
+	cb.Trip()
+	ctx := context.Background()
+
+	if _, err := cb.GetTaskList(ctx); !errors.Is(err, app.ErrCacheUnavailable) {
+		t.Fatalf("expected %v before the cooldown, got %v", app.ErrCacheUnavailable, err)
+	}
+	*now = now.Add(time.Minute)
+	_, err := cb.GetTaskList(ctx)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if next.flushCalls != 1 || next.calls != 1 {
+		t.Fatalf("expected a flush and the call, got %d flushes and %d calls", next.flushCalls, next.calls)
+	}
+}
+
+func TestCacheBreaker_FailedProbe_StaysOpenForAnotherCooldown(t *testing.T) {
+	cb, next, now := newTestBreaker()
+	cb.Trip()
+	next.flushErr = errors.New("connection refused")
+	ctx := context.Background()
+
+	*now = now.Add(time.Minute)
+	err := cb.CacheTask(ctx, models.TaskExportData{Id: 1})
+	next.flushErr = nil
+	*now = now.Add(time.Second)
+	_ = cb.CacheTask(ctx, models.TaskExportData{Id: 1})
+
+	if !errors.Is(err, app.ErrCacheUnavailable) {
+		t.Fatalf("expected %v, got %v", app.ErrCacheUnavailable, err)
+	}
+	if next.flushCalls != 1 || next.calls != 0 {
+		t.Fatalf("expected one probe and no calls, got %d flushes and %d calls", next.flushCalls, next.calls)
+	}
+}
+
+func TestCacheBreaker_Locker_IsSkippedWhileOpen(t *testing.T) {
+	cb, _, _ := newTestBreaker()
+	locker := &fakeLocker{}
+	guarded := cb.Locker(locker)
+	ctx := context.Background()
+
+	if _, err := guarded.TryLock(ctx, "tasks", time.Second); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	cb.Trip()
+	_, err := guarded.TryLock(ctx, "tasks", time.Second)
+
+	if !errors.Is(err, app.ErrCacheUnavailable) {
+		t.Fatalf("expected %v, got %v", app.ErrCacheUnavailable, err)
+	}
+	if locker.calls != 1 {
+		t.Fatalf("expected one lock taken, got %d", locker.calls)
+	}
+}
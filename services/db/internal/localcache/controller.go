@@ -0,0 +1,166 @@
+package localcache
+
+import (
+	"container/list"
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+)
+
+// InvalidationBus carries invalidation messages between db-service replicas.
+type InvalidationBus interface {
+	PublishInvalidation(ctx context.Context, message string) error
+	// SubscribeInvalidations calls onMessage for every message until ctx is
+	// done. onReset is called whenever messages may have been lost.
+	SubscribeInvalidations(ctx context.Context, onMessage func(string), onReset func())
+}
+
+// LocalCacheController keeps the most recently used values of the next
+// cache in memory, so reads skip the Redis round-trip and JSON decoding.
+// Every change is broadcast over the bus, and all other replicas drop their
+// copy of the changed key. Entries also expire after ttl, in case a message
+// was lost on the way.
+type LocalCacheController struct {
+	next   app.CacheController
+	bus    InvalidationBus
+	origin string
+	size   int
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	order *list.List // of *entry, most recently used first
+	items map[string]*list.Element
+	// generation grows with every invalidation. A value read from the next
+	// cache is kept only if nothing was invalidated while it was read, so an
+	// older value can't overwrite a newer one.
+	generation uint64
+	// listLoad is the generation at the first miss of the list since it was
+	// last cached. The list the caller then reads from the main DB is kept
+	// only if nothing was invalidated after that miss.
+	listLoad        uint64
+	listLoadPending bool
+}
+
+type entry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// NewLocalCacheController keeps up to size values for ttl in front of next.
+func NewLocalCacheController(next app.CacheController, bus InvalidationBus, size int, ttl time.Duration) *LocalCacheController {
+	return &LocalCacheController{
+		next:   next,
+		bus:    bus,
+		origin: strconv.FormatUint(rand.Uint64(), 36),
+		size:   size,
+		ttl:    ttl,
+		now:    time.Now,
+		order:  list.New(),
+		items:  make(map[string]*list.Element),
+	}
+}
+
+// Listen applies the invalidations of other replicas until ctx is done.
+func (lc *LocalCacheController) Listen(ctx context.Context) {
+	lc.bus.SubscribeInvalidations(ctx, lc.applyInvalidation, lc.clear)
+}
+
+func (lc *LocalCacheController) get(key string) (any, uint64, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	elem, ok := lc.items[key]
+	if !ok {
+		return nil, lc.generation, false
+	}
+	e := elem.Value.(*entry)
+	if lc.now().After(e.expiresAt) {
+		lc.order.Remove(elem)
+		delete(lc.items, key)
+		return nil, lc.generation, false
+	}
+	lc.order.MoveToFront(elem)
+	return e.value, lc.generation, true
+}
+
+// put stores the value unless something was invalidated after generation.
+func (lc *LocalCacheController) put(key string, value any, generation uint64) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if generation != lc.generation {
+		return
+	}
+	lc.store(key, value)
+}
+
+// missList remembers the generation of a list miss, unless an earlier miss
+// is still waiting for its list.
+func (lc *LocalCacheController) missList(generation uint64) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if !lc.listLoadPending {
+		lc.listLoad, lc.listLoadPending = generation, true
+	}
+}
+
+// takeListLoad returns the generation of the earliest list miss that is
+// waiting for its list.
+func (lc *LocalCacheController) takeListLoad() (uint64, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	generation, ok := lc.listLoad, lc.listLoadPending
+	lc.listLoadPending = false
+	return generation, ok
+}
+
+func (lc *LocalCacheController) store(key string, value any) {
+	expiresAt := lc.now().Add(lc.ttl)
+	if elem, ok := lc.items[key]; ok {
+		e := elem.Value.(*entry)
+		e.value, e.expiresAt = value, expiresAt
+		lc.order.MoveToFront(elem)
+		return
+	}
+
+	lc.items[key] = lc.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for lc.order.Len() > lc.size {
+		oldest := lc.order.Back()
+		lc.order.Remove(oldest)
+		delete(lc.items, oldest.Value.(*entry).key)
+	}
+}
+
+// replace drops the key, or stores the new value when there is one, and
+// invalidates the values being read meanwhile.
+func (lc *LocalCacheController) replace(key string, value any) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.generation++
+	if value != nil {
+		lc.store(key, value)
+		return
+	}
+	if elem, ok := lc.items[key]; ok {
+		lc.order.Remove(elem)
+		delete(lc.items, key)
+	}
+}
+
+func (lc *LocalCacheController) clear() {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.generation++
+	lc.order.Init()
+	clear(lc.items)
+}
@@ -0,0 +1,130 @@
+package localcache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+const (
+	taskListKey = "tasks"
+	// flushAllKey in a message drops every key.
+	flushAllKey = "*"
+)
+
+func taskKey(id int) string {
+	return "task:" + strconv.Itoa(id)
+}
+
+// invalidate tells the other replicas to drop the key. Messages are
+// "<origin> <key>", so a replica skips its own ones.
+func (lc *LocalCacheController) invalidate(ctx context.Context, key string) error {
+	return lc.bus.PublishInvalidation(ctx, lc.origin+" "+key)
+}
+
+func (lc *LocalCacheController) applyInvalidation(message string) {
+	origin, key, ok := strings.Cut(message, " ")
+	switch {
+	case !ok:
+		log.Printf("bad cache invalidation message %q\n", message)
+	case origin == lc.origin:
+	case key == flushAllKey:
+		lc.clear()
+	default:
+		lc.replace(key, nil)
+		if strings.HasPrefix(key, "task:") {
+			lc.replace(taskListKey, nil)
+		}
+	}
+}
+
+// CacheTaskList isn't broadcast: the list is only cached after it was read
+// from the main DB, while changes of the list are broadcast by the other
+// methods. The list is read after GetTaskList missed it, so it is kept
+// locally only if nothing was invalidated since that miss.
+func (lc *LocalCacheController) CacheTaskList(ctx context.Context, tasks []models.TaskExportData) error {
+	if generation, ok := lc.takeListLoad(); ok {
+		lc.put(taskListKey, slices.Clone(tasks), generation)
+	}
+	return lc.next.CacheTaskList(ctx, tasks)
+}
+
+func (lc *LocalCacheController) DeleteTaskList(ctx context.Context) error {
+	lc.replace(taskListKey, nil)
+	return errors.Join(lc.next.DeleteTaskList(ctx), lc.invalidate(ctx, taskListKey))
+}
+
+// GetTaskList returns a copy of the list, which the caller may change. Only
+// a fresh and complete list is kept locally.
+func (lc *LocalCacheController) GetTaskList(ctx context.Context) (app.CachedTaskList, error) {
+	value, generation, ok := lc.get(taskListKey)
+	if ok {
+		return app.CachedTaskList{Tasks: slices.Clone(value.([]models.TaskExportData))}, nil
+	}
+
+	lc.missList(generation)
+	list, err := lc.next.GetTaskList(ctx)
+	if err == nil && !list.Stale && len(list.MissingIds) == 0 {
+		lc.put(taskListKey, slices.Clone(list.Tasks), generation)
+	}
+
+	return list, err
+}
+
+// The local list is dropped whenever one of its tasks changes; it is
+// rebuilt from Redis, where the list is updated task by task.
+
+func (lc *LocalCacheController) CacheTask(ctx context.Context, task models.TaskExportData) error {
+	key := taskKey(task.Id)
+	lc.replace(key, task)
+	lc.replace(taskListKey, nil)
+	return errors.Join(lc.next.CacheTask(ctx, task), lc.invalidate(ctx, key))
+}
+
+func (lc *LocalCacheController) DeleteTaskById(ctx context.Context, id int) error {
+	key := taskKey(id)
+	lc.replace(key, nil)
+	lc.replace(taskListKey, nil)
+	return errors.Join(lc.next.DeleteTaskById(ctx, id), lc.invalidate(ctx, key))
+}
+
+func (lc *LocalCacheController) RemoveTasksFromList(ctx context.Context, ids []int) error {
+	errs := []error{lc.next.RemoveTasksFromList(ctx, ids)}
+	for _, id := range ids {
+		key := taskKey(id)
+		lc.replace(key, nil)
+		errs = append(errs, lc.invalidate(ctx, key))
+	}
+	lc.replace(taskListKey, nil)
+	return errors.Join(errs...)
+}
+
+func (lc *LocalCacheController) GetTaskById(ctx context.Context, id int) (models.TaskExportData, bool, error) {
+	key := taskKey(id)
+	value, generation, ok := lc.get(key)
+	if ok {
+		return value.(models.TaskExportData), false, nil
+	}
+
+	task, stale, err := lc.next.GetTaskById(ctx, id)
+	if err == nil && !stale {
+		lc.put(key, task, generation)
+	}
+
+	return task, stale, err
+}
+
+func (lc *LocalCacheController) FlushAllData(ctx context.Context) error {
+	lc.clear()
+	return errors.Join(lc.next.FlushAllData(ctx), lc.invalidate(ctx, flushAllKey))
+}
+
+func (lc *LocalCacheController) Close() error {
+	return lc.next.Close()
+}
@@ -0,0 +1,321 @@
+package localcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeCache struct {
+	tasks      []models.TaskExportData
+	tasksStale bool
+	missingIds []int
+	byId       map[int]models.TaskExportData
+
+	getTaskListCalls int
+	getTaskByIdCalls int
+	deleteCalls      int
+	flushCalls       int
+	closeCalls       int
+
+	// onGet runs during a read, like a change made while Redis answers.
+	onGet func()
+}
+
+func (fc *fakeCache) CacheTaskList(ctx context.Context, tasks []models.TaskExportData) error {
+	fc.tasks = tasks
+	return nil
+}
+
+func (fc *fakeCache) DeleteTaskList(ctx context.Context) error {
+	fc.deleteCalls++
+	fc.tasks = nil
+	return nil
+}
+
+func (fc *fakeCache) GetTaskList(ctx context.Context) (app.CachedTaskList, error) {
+	fc.getTaskListCalls++
+	if fc.onGet != nil {
+		fc.onGet()
+	}
+	if fc.tasks == nil {
+		return app.CachedTaskList{}, app.ErrTaskNotFound
+	}
+	return app.CachedTaskList{Tasks: fc.tasks, MissingIds: fc.missingIds, Stale: fc.tasksStale}, nil
+}
+
+func (fc *fakeCache) CacheTask(ctx context.Context, task models.TaskExportData) error {
+	fc.byId[task.Id] = task
+	return nil
+}
+
+func (fc *fakeCache) DeleteTaskById(ctx context.Context, id int) error {
+	fc.deleteCalls++
+	delete(fc.byId, id)
+	return nil
+}
+
+func (fc *fakeCache) RemoveTasksFromList(ctx context.Context, ids []int) error {
+	for _, id := range ids {
+		delete(fc.byId, id)
+	}
+	return nil
+}
+
+func (fc *fakeCache) GetTaskById(ctx context.Context, id int) (models.TaskExportData, bool, error) {
+	fc.getTaskByIdCalls++
+	task, ok := fc.byId[id]
+	if !ok {
+		return models.TaskExportData{}, false, app.ErrTaskNotFound
+	}
+	return task, false, nil
+}
+
+func (fc *fakeCache) FlushAllData(ctx context.Context) error {
+	fc.flushCalls++
+	return nil
+}
+
+func (fc *fakeCache) Close() error {
+	fc.closeCalls++
+	return nil
+}
+
+type fakeBus struct {
+	published  []string
+	publishErr error
+}
+
+func (fb *fakeBus) PublishInvalidation(ctx context.Context, message string) error {
+	fb.published = append(fb.published, message)
+	return fb.publishErr
+}
+
+func (fb *fakeBus) SubscribeInvalidations(ctx context.Context, onMessage func(string), onReset func()) {
+}
+
+func newTestController(size int) (*LocalCacheController, *fakeCache, *fakeBus) {
+	next := &fakeCache{byId: map[int]models.TaskExportData{}}
+	bus := &fakeBus{}
+	return NewLocalCacheController(next, bus, size, time.Minute), next, bus
+}
+
+func TestGetTaskList_SecondRead_SkipsNextCacheAndReturnsCopy(t *testing.T) {
+	lc, next, _ := newTestController(10)
+	next.tasks = []models.TaskExportData{{Id: 1, Title: "a"}}
+	ctx := context.Background()
+
+	first, err := lc.GetTaskList(ctx)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	first.Tasks[0].Title = "changed by caller"
+	second, err := lc.GetTaskList(ctx)
+
+	if err != nil || second.Stale {
+		t.Fatalf("expected fresh list, got stale=%v err=%v", second.Stale, err)
+	}
+	if next.getTaskListCalls != 1 {
+		t.Fatalf("expected next cache read once, got %d", next.getTaskListCalls)
+	}
+	if diff := cmp.Diff([]models.TaskExportData{{Id: 1, Title: "a"}}, second.Tasks); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestGetTaskList_Stale_IsNotKeptLocally(t *testing.T) {
+	lc, next, _ := newTestController(10)
+	next.tasks = []models.TaskExportData{{Id: 1}}
+	next.tasksStale = true
+
+	for range 2 {
+		if list, _ := lc.GetTaskList(context.Background()); !list.Stale {
+			t.Fatal("expected the stale flag passed through")
+		}
+	}
+	if next.getTaskListCalls != 2 {
+		t.Fatalf("expected next cache read twice, got %d", next.getTaskListCalls)
+	}
+}
+
+func TestGetTaskList_Incomplete_IsNotKeptLocally(t *testing.T) {
+	lc, next, _ := newTestController(10)
+	next.tasks = []models.TaskExportData{{Id: 1}}
+	next.missingIds = []int{2}
+
+	_, _ = lc.GetTaskList(context.Background())
+	_, _ = lc.GetTaskList(context.Background())
+
+	if next.getTaskListCalls != 2 {
+		t.Fatalf("expected the list with missing tasks not kept, got %d reads", next.getTaskListCalls)
+	}
+}
+
+func TestGetTaskList_InvalidatedWhileReading_IsNotKept(t *testing.T) {
+	lc, next, _ := newTestController(10)
+	next.tasks = []models.TaskExportData{{Id: 1}}
+	next.onGet = func() {
+		next.onGet = nil
+		lc.applyInvalidation("other-replica tasks")
+	}
+	ctx := context.Background()
+
+	_, _ = lc.GetTaskList(ctx)
+	_, _ = lc.GetTaskList(ctx)
+
+	if next.getTaskListCalls != 2 {
+		t.Fatalf("expected the list read before the invalidation not kept, got %d reads", next.getTaskListCalls)
+	}
+}
+
+func TestCacheTaskList_AfterMiss_IsKeptLocally(t *testing.T) {
+	lc, next, _ := newTestController(10)
+	ctx := context.Background()
+
+	_, _ = lc.GetTaskList(ctx)
+	_ = lc.CacheTaskList(ctx, []models.TaskExportData{{Id: 1}})
+	list, err := lc.GetTaskList(ctx)
+
+	if err != nil || next.getTaskListCalls != 1 {
+		t.Fatalf("expected the loaded list served locally, got err=%v after %d reads", err, next.getTaskListCalls)
+	}
+	if diff := cmp.Diff([]models.TaskExportData{{Id: 1}}, list.Tasks); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCacheTaskList_InvalidatedWhileLoading_IsNotKept(t *testing.T) {
+	lc, next, _ := newTestController(10)
+	ctx := context.Background()
+
+	_, _ = lc.GetTaskList(ctx)
+	lc.applyInvalidation("other-replica task:1")
+	_ = lc.CacheTaskList(ctx, []models.TaskExportData{{Id: 1, Title: "before the change"}})
+	_, _ = lc.GetTaskList(ctx)
+
+	if next.getTaskListCalls != 2 {
+		t.Fatalf("expected the list loaded before the invalidation not kept, got %d reads", next.getTaskListCalls)
+	}
+}
+
+func TestGetTaskById_LeastRecentlyUsed_IsEvicted(t *testing.T) {
+	lc, next, _ := newTestController(2)
+	for id := 1; id <= 3; id++ {
+		next.byId[id] = models.TaskExportData{Id: id}
+	}
+	ctx := context.Background()
+
+	_, _, _ = lc.GetTaskById(ctx, 1)
+	_, _, _ = lc.GetTaskById(ctx, 2)
+	_, _, _ = lc.GetTaskById(ctx, 1)
+	_, _, _ = lc.GetTaskById(ctx, 3)
+	next.getTaskByIdCalls = 0
+
+	_, _, _ = lc.GetTaskById(ctx, 1)
+	_, _, _ = lc.GetTaskById(ctx, 3)
+	if next.getTaskByIdCalls != 0 {
+		t.Fatalf("expected tasks 1 and 3 kept, got %d reads", next.getTaskByIdCalls)
+	}
+	_, _, _ = lc.GetTaskById(ctx, 2)
+	if next.getTaskByIdCalls != 1 {
+		t.Fatalf("expected task 2 evicted, got %d reads", next.getTaskByIdCalls)
+	}
+}
+
+func TestGetTaskById_Expired_ReadsNextCache(t *testing.T) {
+	lc, next, _ := newTestController(10)
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	lc.now = func() time.Time { return now }
+	next.byId[1] = models.TaskExportData{Id: 1}
+	ctx := context.Background()
+
+	_, _, _ = lc.GetTaskById(ctx, 1)
+	now = now.Add(2 * time.Minute)
+	_, _, _ = lc.GetTaskById(ctx, 1)
+
+	if next.getTaskByIdCalls != 2 {
+		t.Fatalf("expected expired task read again, got %d reads", next.getTaskByIdCalls)
+	}
+}
+
+func TestCacheTask_KeepsTaskAndBroadcastsInvalidation(t *testing.T) {
+	lc, next, bus := newTestController(10)
+	ctx := context.Background()
+
+	if err := lc.CacheTask(ctx, models.TaskExportData{Id: 4, Title: "new"}); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	got, _, _ := lc.GetTaskById(ctx, 4)
+
+	if got.Title != "new" || next.getTaskByIdCalls != 0 {
+		t.Fatalf("expected the task served locally, got %+v after %d reads", got, next.getTaskByIdCalls)
+	}
+	if diff := cmp.Diff([]string{lc.origin + " task:4"}, bus.published); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCacheTask_DropsLocalTaskList(t *testing.T) {
+	lc, next, _ := newTestController(10)
+	next.tasks = []models.TaskExportData{{Id: 1}}
+	ctx := context.Background()
+	_, _ = lc.GetTaskList(ctx)
+
+	_ = lc.CacheTask(ctx, models.TaskExportData{Id: 4})
+	_, _ = lc.GetTaskList(ctx)
+
+	if next.getTaskListCalls != 2 {
+		t.Fatalf("expected the list read again from the next cache, got %d reads", next.getTaskListCalls)
+	}
+}
+
+func TestDeleteTaskList_PublishError_IsReturned(t *testing.T) {
+	lc, next, bus := newTestController(10)
+	bus.publishErr = errors.New("redis down")
+
+	err := lc.DeleteTaskList(context.Background())
+
+	if !errors.Is(err, bus.publishErr) {
+		t.Fatalf("expected %v, got %v", bus.publishErr, err)
+	}
+	if next.deleteCalls != 1 {
+		t.Fatalf("expected next cache cleared, got %d calls", next.deleteCalls)
+	}
+}
+
+func TestApplyInvalidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		wantReads int
+	}{
+		{name: "other replica drops the key", message: "other task:1", wantReads: 1},
+		{name: "other replica drops everything", message: "other *", wantReads: 1},
+		{name: "other replica drops another key", message: "other task:2", wantReads: 0},
+		{name: "own message", message: "self task:1", wantReads: 0},
+		{name: "malformed message", message: "task:1", wantReads: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc, next, _ := newTestController(10)
+			lc.origin = "self"
+			next.byId[1] = models.TaskExportData{Id: 1}
+			ctx := context.Background()
+			_, _, _ = lc.GetTaskById(ctx, 1)
+			next.getTaskByIdCalls = 0
+
+			lc.applyInvalidation(tt.message)
+			_, _, _ = lc.GetTaskById(ctx, 1)
+
+			if next.getTaskByIdCalls != tt.wantReads {
+				t.Fatalf("expected %d reads, got %d", tt.wantReads, next.getTaskByIdCalls)
+			}
+		})
+	}
+}
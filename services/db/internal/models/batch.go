@@ -0,0 +1,12 @@
+package models
+
+// BatchItem is the outcome of one item of a batch operation. Id is the task
+// the item created or changed and Task is its new state; both are empty for
+// a failed item, whose Err tells why. Failed items don't stop the batch.
+type BatchItem struct {
+	Id   int
+	Task TaskExportData
+	Err  error
+	// StaleIds lists the other tasks the item changed: subtasks and parents.
+	StaleIds []int
+}
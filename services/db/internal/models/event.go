@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type TaskEventType int
+
+const (
+	TaskEventNone TaskEventType = iota
+	TaskEventCreated
+	TaskEventUpdated
+	TaskEventFinished
+	TaskEventDeleted
+	// TaskEventReset tells a watcher that it missed events and has to reload
+	// the tasks.
+	TaskEventReset
+)
+
+// TaskEvent is a committed change of a task. Moving a task to the trash is
+// reported as deleted and restoring it as created. Version is the version of
+// the task after the change.
+type TaskEvent struct {
+	Id         int
+	Type       TaskEventType
+	TaskId     int
+	ProjectId  int
+	Version    int
+	Actor      string
+	OccurredAt time.Time
+}
+
+// WatchRequest subscribes to the events of the tasks in project ProjectId,
+// or of all tasks when it is 0. A non-zero LastEventId resumes the stream
+// after that event.
+type WatchRequest struct {
+	ProjectId   int
+	LastEventId int
+}
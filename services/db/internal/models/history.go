@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// TaskChange is one changed field of a task. OldValue and NewValue hold JSON
+// and are empty for a missing value; a "created" change carries the whole
+// new task.
+type TaskChange struct {
+	Id        int
+	TaskId    int
+	ChangedAt time.Time
+	ChangedBy string
+	Field     string
+	OldValue  string
+	NewValue  string
+}
+
+// TaskHistoryRequest asks for up to Limit changes older than the change with
+// id Cursor; Cursor 0 starts from the most recent change.
+type TaskHistoryRequest struct {
+	TaskId int
+	Limit  int
+	Cursor int
+}
+
+// TaskHistoryPage lists changes from newest to oldest. NextCursor is 0 on
+// the last page.
+type TaskHistoryPage struct {
+	Changes    []TaskChange
+	NextCursor int
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// IdempotencyKey identifies a create request that clients may repeat.
+// Fingerprint is a hash of the request data: the same key with another
+// fingerprint is a different request. The key can be reused after ExpiresAt.
+type IdempotencyKey struct {
+	Key         string
+	Fingerprint string
+	ExpiresAt   time.Time
+}
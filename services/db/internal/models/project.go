@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Project struct {
+	Id        int
+	Name      string
+	Inbox     bool
+	Archived  bool
+	CreatedAt time.Time
+}
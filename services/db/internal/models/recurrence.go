@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type RecurrenceFrequency int
+
+const (
+	RecurrenceNone RecurrenceFrequency = iota
+	RecurrenceDaily
+	RecurrenceWeekly
+	RecurrenceMonthly
+	// RecurrenceAfterCompletion repeats Interval days after the task is done.
+	RecurrenceAfterCompletion
+)
+
+// Recurrence is the schedule of a recurring task. Interval counts days, weeks
+// or months depending on Frequency. Empty Weekdays and MonthDay fall back to
+// the due date.
+type Recurrence struct {
+	Frequency RecurrenceFrequency
+	Interval  int
+	Weekdays  []time.Weekday
+	MonthDay  int
+	// Until ends the series: no occurrence is due after it.
+	Until    *time.Time
+	TimeZone string
+}
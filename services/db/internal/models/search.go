@@ -0,0 +1,18 @@
+package models
+
+// SearchRequest looks for tasks whose title or text contain words starting
+// with every one of Terms. Query is the text the terms were taken from.
+type SearchRequest struct {
+	Query string
+	Terms []string
+	Limit int
+}
+
+// SearchHit is a found task with its relevance and the title and text
+// fragments around the matched words, marked with <b> and </b>.
+type SearchHit struct {
+	Task         TaskExportData
+	Rank         float64
+	TitleSnippet string
+	TextSnippet  string
+}
@@ -0,0 +1,30 @@
+package models
+
+// SyncCursor is a position in the change sequence: changes are ordered by
+// the sequence number of the change and then by task id. The zero cursor
+// is before all changes.
+type SyncCursor struct {
+	Seq    int64
+	TaskId int
+}
+
+// ChangesRequest asks for up to Limit changes after Since, which is decoded
+// from Token.
+type ChangesRequest struct {
+	Token string
+	Since SyncCursor
+	Limit int
+}
+
+// Changes lists the tasks changed after the requested cursor and the ids of
+// the tasks deleted or moved to the trash since then. Next is where the next
+// sync starts; HasMore means it may start right away. Full means the
+// requested cursor was too old and the changes start from the beginning.
+type Changes struct {
+	Tasks      []TaskExportData
+	DeletedIds []int
+	Next       SyncCursor
+	Token      string
+	HasMore    bool
+	Full       bool
+}
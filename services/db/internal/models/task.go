@@ -2,11 +2,35 @@ package models
 
 import "time"
 
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
 type TaskImportData struct {
-	Title string
-	Text  string
+	Title    string
+	Text     string
+	DueAt    *time.Time
+	Priority Priority
+	Tags     []string
+	// ProjectId 0 puts the task into the inbox. Subtasks always live in
+	// their parent's project.
+	ProjectId int
+	// ParentId 0 creates a top-level task.
+	ParentId   int
+	Recurrence *Recurrence
+	// Idempotency is set when the client may retry the request.
+	Idempotency *IdempotencyKey
 }
 
+// MaxTaskDepth limits nesting: a task, its subtasks and their subtasks.
+const MaxTaskDepth = 3
+
 type TaskExportData struct {
 	Id         int
 	Title      string
@@ -14,4 +38,61 @@ type TaskExportData struct {
 	Finished   bool
 	CreatedAt  time.Time
 	FinishedAt *time.Time
+	DueAt      *time.Time
+	Overdue    bool
+	Priority   Priority
+	Position   string
+	Tags       []string
+	ProjectId  int
+	ParentId   int
+	// SubtasksTotal and SubtasksDone count direct subtasks only.
+	SubtasksTotal int
+	SubtasksDone  int
+	Recurrence    *Recurrence
+	// NextOccurrenceId is set once a recurring task is done.
+	NextOccurrenceId int
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt *time.Time
+	// Version grows with every change of the task or its tags.
+	Version int
+}
+
+// TaskTree is a task with its subtasks, nested down to the deepest level.
+type TaskTree struct {
+	Task     TaskExportData
+	Subtasks []TaskTree
+}
+
+// TaskMove places task Id right before BeforeId or right after AfterId.
+type TaskMove struct {
+	Id       int
+	BeforeId int
+	AfterId  int
+}
+
+type DueFilter int
+
+const (
+	DueFilterAny DueFilter = iota
+	DueFilterOverdue
+	DueFilterToday
+	DueFilterThisWeek
+)
+
+type TaskSort int
+
+const (
+	TaskSortId TaskSort = iota
+	TaskSortPriority
+	TaskSortPosition
+)
+
+type TaskFilter struct {
+	Due      DueFilter
+	TimeZone string
+	Sort     TaskSort
+	Tags     []string
+	TagMatch TagMatch
+	// ProjectId 0 means tasks of all projects.
+	ProjectId int
 }
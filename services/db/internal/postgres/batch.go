@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// BatchAddTasks creates the tasks in one transaction. Every item runs in its
+// own savepoint, so a failed item is rolled back alone and reported in its
+// result.
+func (pc *PostgresController) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	return pc.runBatch(ctx, len(tasks), func(tx *sql.Tx, i int) (models.BatchItem, error) {
+		createdTask, err := addTask(ctx, tx, tasks[i])
+		item := models.BatchItem{Id: createdTask.Id, Task: createdTask}
+		if createdTask.ParentId != 0 {
+			item.StaleIds = []int{createdTask.ParentId}
+		}
+		return item, err
+	})
+}
+
+// BatchMarkFinished finishes the tasks in one transaction, see
+// MarkTaskFinished and BatchAddTasks.
+func (pc *PostgresController) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	return pc.runBatch(ctx, len(ids), func(tx *sql.Tx, i int) (models.BatchItem, error) {
+		item := models.BatchItem{Id: ids[i]}
+		updatedTask, err := markTaskFinished(ctx, tx, ids[i])
+		if err != nil {
+			return item, err
+		}
+		item.Task = updatedTask
+		item.StaleIds, err = relatedIds(ctx, tx, ids[i])
+		return item, err
+	})
+}
+
+// BatchRemoveTasks moves the tasks to the trash in one transaction, see
+// DeleteTask and BatchAddTasks.
+func (pc *PostgresController) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	return pc.runBatch(ctx, len(ids), func(tx *sql.Tx, i int) (models.BatchItem, error) {
+		staleIds, err := deleteTask(ctx, tx, ids[i])
+		return models.BatchItem{Id: ids[i], StaleIds: staleIds}, err
+	})
+}
+
+// runBatch calls apply for n items in one transaction. An item whose apply
+// fails is undone and keeps only its Id and Err; an error of the
+// transaction itself fails the whole batch. An item that lost its rank to
+// another transaction fails the batch too, and the batch is run again.
+func (pc *PostgresController) runBatch(ctx context.Context, n int, apply func(tx *sql.Tx, i int) (models.BatchItem, error)) ([]models.BatchItem, error) {
+	return retryPositionConflict(func() ([]models.BatchItem, error) {
+		return pc.applyBatch(ctx, n, apply)
+	})
+}
+
+func (pc *PostgresController) applyBatch(ctx context.Context, n int, apply func(tx *sql.Tx, i int) (models.BatchItem, error)) ([]models.BatchItem, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	items := make([]models.BatchItem, n)
+	for i := range items {
+		if _, err := tx.ExecContext(ctx, "savepoint batch_item"); err != nil {
+			return nil, err
+		}
+
+		item, itemErr := apply(tx, i)
+		if isPositionConflict(itemErr) {
+			return nil, itemErr
+		}
+		if itemErr != nil {
+			if _, err := tx.ExecContext(ctx, "rollback to savepoint batch_item"); err != nil {
+				return nil, err
+			}
+			item = models.BatchItem{Id: item.Id, Err: itemErr}
+		} else if _, err := tx.ExecContext(ctx, "release savepoint batch_item"); err != nil {
+			return nil, err
+		}
+
+		items[i] = item
+	}
+
+	return items, tx.Commit()
+}
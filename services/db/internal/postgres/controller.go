@@ -4,8 +4,11 @@ import "database/sql"
 
 type PostgresController struct {
 	db *sql.DB
+	// searchConfigs are the text search configurations of the search column.
+	searchConfigs []string
 }
 
 func NewPostgresController() *PostgresController {
-	return &PostgresController{db: initDB()}
+	searchConfigs := searchConfigsFromEnv()
+	return &PostgresController{db: initDB(searchConfigs), searchConfigs: searchConfigs}
 }
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/lib/pq"
+)
+
+const taskEventsChannel = "task_events"
+
+// Task events are sent by a trigger with pg_notify, so listeners get them
+// only once the change is committed and never for rolled back changes,
+// including batch items undone by their savepoint. Event ids come from a
+// sequence that survives restarts. Changes of tasks in the trash are not
+// reported: moving a task there already reported it as deleted.
+const eventTriggersQuery = `create sequence if not exists task_events_id_seq;
+
+    create or replace function notify_task_event() returns trigger as $$
+    declare
+        event_type text;
+        task tasks;
+    begin
+        if tg_op = 'INSERT' then
+            event_type := 'created';
+            task := new;
+        elsif tg_op = 'DELETE' then
+            if old.deleted_at is not null then
+                return null;
+            end if;
+            event_type := 'deleted';
+            task := old;
+        elsif new.deleted_at is not null then
+            if old.deleted_at is not null then
+                return null;
+            end if;
+            event_type := 'deleted';
+            task := new;
+        elsif old.deleted_at is not null then
+            event_type := 'created';
+            task := new;
+        elsif new.version = old.version then
+            return null;
+        elsif new.finished and not coalesce(old.finished, false) then
+            event_type := 'finished';
+            task := new;
+        else
+            event_type := 'updated';
+            task := new;
+        end if;
+
+        perform pg_notify('task_events', json_build_object(
+            'id', nextval('task_events_id_seq'),
+            'type', event_type,
+            'task_id', task.id,
+            'project_id', task.project_id,
+            'version', task.version,
+            'actor', task_history_actor(),
+            'occurred_at', now())::text);
+        return null;
+    end
+    $$ language plpgsql;
+
+    create or replace trigger tasks_events after insert or update or delete on tasks
+        for each row execute function notify_task_event();`
+
+func createEventTriggers(db *sql.DB) {
+	if _, err := db.Exec(eventTriggersQuery); err != nil {
+		log.Fatal(err)
+	}
+}
+
+var taskEventTypes = map[string]models.TaskEventType{
+	"created":  models.TaskEventCreated,
+	"updated":  models.TaskEventUpdated,
+	"finished": models.TaskEventFinished,
+	"deleted":  models.TaskEventDeleted,
+}
+
+type taskEventPayload struct {
+	Id         int       `json:"id"`
+	Type       string    `json:"type"`
+	TaskId     int       `json:"task_id"`
+	ProjectId  int       `json:"project_id"`
+	Version    int       `json:"version"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ListenTaskEvents passes the committed task events to publish until ctx is
+// done. Notifications sent while the listening connection was down are lost,
+// so reset is called once it is back.
+func (pc *PostgresController) ListenTaskEvents(ctx context.Context, publish func(models.TaskEvent), reset func()) {
+	listener := pq.NewListener(connString(), time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("task events listener: %v\n", err)
+		}
+	})
+	defer func() { _ = listener.Close() }()
+
+	if err := listener.Listen(taskEventsChannel); err != nil {
+		log.Printf("failed to listen for task events: %v\n", err)
+		return
+	}
+
+	// pq recommends pinging an idle listener to notice a dead connection.
+	ticker := time.NewTicker(90 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			go func() { _ = listener.Ping() }()
+		case notification := <-listener.Notify:
+			if notification == nil {
+				reset()
+				continue
+			}
+			event, err := taskEventFromPayload(notification.Extra)
+			if err != nil {
+				log.Printf("bad task event %q: %v\n", notification.Extra, err)
+				continue
+			}
+			publish(event)
+		}
+	}
+}
+
+func taskEventFromPayload(payload string) (models.TaskEvent, error) {
+	var p taskEventPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return models.TaskEvent{}, err
+	}
+
+	return models.TaskEvent{
+		Id:         p.Id,
+		Type:       taskEventTypes[p.Type],
+		TaskId:     p.TaskId,
+		ProjectId:  p.ProjectId,
+		Version:    p.Version,
+		Actor:      p.Actor,
+		OccurredAt: p.OccurredAt,
+	}, nil
+}
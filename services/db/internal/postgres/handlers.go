@@ -2,8 +2,13 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/rank"
+	"github.com/lib/pq"
 )
 
 func (pc *PostgresController) Close() error {
@@ -11,46 +16,187 @@ func (pc *PostgresController) Close() error {
 }
 
 func (pc *PostgresController) AddTask(ctx context.Context, task models.TaskImportData) (models.TaskExportData, error) {
-	query := `insert into tasks (title,text) values ($1,$2) returning id, title, text, finished, created_at, finished_at`
+	return retryPositionConflict(func() (models.TaskExportData, error) {
+		tx, err := pc.beginTx(ctx)
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		createdTask, err := addTask(ctx, tx, task)
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
+
+		return createdTask, tx.Commit()
+	})
+}
+
+func addTask(ctx context.Context, tx *sql.Tx, task models.TaskImportData) (models.TaskExportData, error) {
+	if task.Idempotency != nil {
+		savedTask, repeated, err := claimIdempotencyKey(ctx, tx, *task.Idempotency)
+		if err != nil || repeated {
+			return savedTask, err
+		}
+	}
+
+	var err error
+	projectId := task.ProjectId
+	if task.ParentId != 0 {
+		if projectId, err = lockParent(ctx, tx, task.ParentId); err != nil {
+			return models.TaskExportData{}, err
+		}
+	}
+
+	projectId, err = lockProject(ctx, tx, projectId)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
 
-	var createdTask models.TaskExportData
-	if err := pc.db.QueryRowContext(ctx, query, task.Title, task.Text).Scan(
-		&createdTask.Id,
-		&createdTask.Title,
-		&createdTask.Text,
-		&createdTask.Finished,
-		&createdTask.CreatedAt,
-		&createdTask.FinishedAt); err != nil {
+	position, err := appendPosition(ctx, tx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	recurrence, err := recurrenceValue(task.Recurrence)
+	if err != nil {
 		return models.TaskExportData{}, err
 	}
 
+	query := `insert into tasks (title,text,due_at,priority,position,project_id,parent_id,recurrence) values ($1,$2,$3,$4,$5,$6,$7,$8) returning id`
+
+	var id int
+	if err := tx.QueryRowContext(ctx, query,
+		task.Title, task.Text, task.DueAt, task.Priority, position, projectId,
+		sql.NullInt64{Int64: int64(task.ParentId), Valid: task.ParentId != 0}, recurrence).Scan(&id); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	if err := attachTags(ctx, tx, id, task.Tags); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	createdTask, err := scanTask(tx.QueryRowContext(ctx, "select "+taskColumns+" from tasks where id = $1", id))
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	if task.Idempotency != nil {
+		if err := saveIdempotentResponse(ctx, tx, task.Idempotency.Key, createdTask); err != nil {
+			return models.TaskExportData{}, err
+		}
+	}
+
 	return createdTask, nil
 }
 
-func (pc *PostgresController) DeleteTask(ctx context.Context, id int) error {
-	_, err := pc.db.ExecContext(ctx, "delete from tasks where id = $1", id)
+// positionRetries bounds how often a transaction that lost its rank to
+// another one is run again.
+const positionRetries = 3
 
+// lockPositions holds the rank allocation until the transaction ends, so two
+// transactions never read the same neighbours and compute the same rank.
+func lockPositions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "select pg_advisory_xact_lock(hashtext('tasks.position'))")
 	return err
 }
 
+// retryPositionConflict runs fn again when it failed on the unique index of
+// tasks.position: the rank was computed from neighbours that changed, a new
+// transaction reads them again.
+func retryPositionConflict[T any](fn func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		value, err := fn()
+		if attempt < positionRetries && isPositionConflict(err) {
+			continue
+		}
+		return value, err
+	}
+}
+
+func isPositionConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "tasks_position_idx"
+}
+
+// appendPosition returns a rank after every task: new tasks go to the end of
+// the manual order.
+func appendPosition(ctx context.Context, tx *sql.Tx) (string, error) {
+	if err := lockPositions(ctx, tx); err != nil {
+		return "", err
+	}
+
+	var lastPosition string
+	if err := tx.QueryRowContext(ctx, "select coalesce(max(position), '') from tasks").Scan(&lastPosition); err != nil {
+		return "", err
+	}
+
+	return rank.Between(lastPosition, "")
+}
+
+// DeleteTask moves the task with its subtasks to the trash. They all get the
+// same deleted_at, which is how RestoreTask finds what was trashed together.
+func (pc *PostgresController) DeleteTask(ctx context.Context, id int) ([]int, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	staleIds, err := deleteTask(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return staleIds, tx.Commit()
+}
+
+func deleteTask(ctx context.Context, tx *sql.Tx, id int) ([]int, error) {
+	if err := lockTask(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	staleIds, err := relatedIds(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		subtreeIds+`update tasks
+        set deleted_at = NOW()
+        where id in (select id from subtree) and deleted_at is null`,
+		id); err != nil {
+		return nil, err
+	}
+
+	return staleIds, nil
+}
+
+// relatedIds returns the descendants and the parent of the task: the tasks
+// whose cached copies go stale when the task is finished or trashed.
+func relatedIds(ctx context.Context, tx *sql.Tx, id int) ([]int, error) {
+	return queryIds(ctx, tx,
+		subtreeIds+`select id from subtree where id <> $1
+        union all
+        select parent_id from tasks where id = $1 and parent_id is not null`,
+		id)
+}
+
 func (pc *PostgresController) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
 	sliceToReturn := make([]models.TaskExportData, 0)
 
-	rows, err := pc.db.QueryContext(ctx, "select id, title, text, finished, created_at, finished_at from tasks order by id")
+	rows, err := pc.db.QueryContext(ctx, "select "+taskColumns+" from tasks where deleted_at is null order by id")
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = rows.Close() }()
 
 	for rows.Next() {
-		var task models.TaskExportData
-		if err := rows.Scan(
-			&task.Id,
-			&task.Title,
-			&task.Text,
-			&task.Finished,
-			&task.CreatedAt,
-			&task.FinishedAt); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
 		sliceToReturn = append(sliceToReturn, task)
@@ -63,24 +209,210 @@ func (pc *PostgresController) ListAllTasks(ctx context.Context) ([]models.TaskEx
 	return sliceToReturn, nil
 }
 
+func (pc *PostgresController) GetTasks(ctx context.Context, ids []int) ([]models.TaskExportData, error) {
+	tasks := make([]models.TaskExportData, 0, len(ids))
+
+	rows, err := pc.db.QueryContext(ctx,
+		"select "+taskColumns+" from tasks where id = any($1) and deleted_at is null order by id", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// MarkTaskFinished finishes the task with its open subtasks and records a
+// completion for each of them; finishing a task again changes nothing. The
+// next occurrence of a recurring task is created in the same transaction,
+// once per task even if it was reopened and finished again.
 func (pc *PostgresController) MarkTaskFinished(ctx context.Context, id int) (models.TaskExportData, error) {
-	query := `update tasks 
-        set finished = true, 
-        finished_at = NOW() 
-        where id = $1 
-        returning id, title, text, finished, created_at, finished_at`
+	return retryPositionConflict(func() (models.TaskExportData, error) {
+		tx, err := pc.beginTx(ctx)
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		updatedTask, err := markTaskFinished(ctx, tx, id)
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
+
+		return updatedTask, tx.Commit()
+	})
+}
+
+func markTaskFinished(ctx context.Context, tx *sql.Tx, id int) (models.TaskExportData, error) {
+	dueAt, rule, err := lockRecurringTask(ctx, tx, id)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		subtreeIds+`, finished as (
+            update tasks
+            set finished = true,
+            finished_at = NOW()
+            where id in (select id from subtree) and not finished and deleted_at is null
+            returning id, finished_at)
+        insert into task_completions (task_id, finished_at)
+        select id, finished_at from finished`,
+		id); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	if rule != nil {
+		if err := createNextOccurrence(ctx, tx, id, dueAt, *rule); err != nil {
+			return models.TaskExportData{}, err
+		}
+	}
+
+	return scanTask(tx.QueryRowContext(ctx, "select "+taskColumns+" from tasks where id = $1", id))
+}
+
+// ReopenTask also reopens the finished ancestors, since a finished task never
+// has open subtasks. Their completions stay in the history marked as
+// reopened; reopening an open task changes nothing.
+func (pc *PostgresController) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockTask(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		ancestorIds+`, reopened as (
+            update tasks
+            set finished = false,
+            finished_at = NULL
+            where id in (select id from ancestors) and finished
+            returning id)
+        update task_completions
+        set reopened_at = NOW()
+        where task_id in (select id from reopened) and reopened_at is null`,
+		id); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	updatedTask, err := scanTask(tx.QueryRowContext(ctx, "select "+taskColumns+" from tasks where id = $1", id))
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
 
-	var updatedTask models.TaskExportData
+	return updatedTask, tx.Commit()
+}
+
+func (pc *PostgresController) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
 
-	if err := pc.db.QueryRowContext(ctx, query, id).Scan(
-		&updatedTask.Id,
-		&updatedTask.Title,
-		&updatedTask.Text,
-		&updatedTask.Finished,
-		&updatedTask.CreatedAt,
-		&updatedTask.FinishedAt); err != nil {
+	if err := lockTask(ctx, tx, id); err != nil {
 		return models.TaskExportData{}, err
 	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	query := `update tasks
+        set priority = $2
+        where id = $1
+        returning ` + taskColumns
+
+	updatedTask, err := scanTask(tx.QueryRowContext(ctx, query, id, priority))
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	return updatedTask, tx.Commit()
+}
+
+// MoveTask gives the task a rank between the anchor and its neighbour, so
+// only the moved row is rewritten.
+func (pc *PostgresController) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	return retryPositionConflict(func() (models.TaskExportData, error) {
+		tx, err := pc.beginTx(ctx)
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := lockTask(ctx, tx, move.Id); err != nil {
+			return models.TaskExportData{}, err
+		}
+		if err := checkVersion(ctx, tx, move.Id); err != nil {
+			return models.TaskExportData{}, err
+		}
+		if err := lockPositions(ctx, tx); err != nil {
+			return models.TaskExportData{}, err
+		}
+
+		anchorId := move.AfterId
+		if move.BeforeId != 0 {
+			anchorId = move.BeforeId
+		}
+
+		var anchorPosition string
+		err = tx.QueryRowContext(ctx, "select position from tasks where id = $1 and deleted_at is null", anchorId).Scan(&anchorPosition)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TaskExportData{}, app.ErrTaskNotFound
+		}
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
+
+		var lower, upper string
+		if move.BeforeId != 0 {
+			upper = anchorPosition
+			err = tx.QueryRowContext(ctx,
+				"select coalesce(max(position), '') from tasks where position < $1 and id <> $2",
+				anchorPosition, move.Id).Scan(&lower)
+		} else {
+			lower = anchorPosition
+			err = tx.QueryRowContext(ctx,
+				"select coalesce(min(position), '') from tasks where position > $1 and id <> $2",
+				anchorPosition, move.Id).Scan(&upper)
+		}
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
+
+		position, err := rank.Between(lower, upper)
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
+
+		query := `update tasks
+        set position = $2
+        where id = $1
+        returning ` + taskColumns
+
+		movedTask, err := scanTask(tx.QueryRowContext(ctx, query, move.Id, position))
+		if err != nil {
+			return models.TaskExportData{}, err
+		}
 
-	return updatedTask, nil
+		return movedTask, tx.Commit()
+	})
 }
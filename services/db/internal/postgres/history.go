@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// The history is written by triggers, so every mutation, including the ones
+// cascading to subtasks, is recorded in the transaction that made it. The actor comes
+// from the app.actor setting made by beginTx; changes made outside a request
+// are recorded as "system".
+const historyTriggersQuery = `create or replace function task_history_actor() returns text as $$
+        select coalesce(nullif(current_setting('app.actor', true), ''), 'system')
+    $$ language sql stable;
+
+    create or replace function record_task_history() returns trigger as $$
+    declare
+        old_row jsonb;
+        new_row jsonb := to_jsonb(new) - 'id' - 'version' - 'search' - 'change_seq';
+        field text;
+    begin
+        if tg_op = 'INSERT' then
+            insert into task_history (task_id, changed_by, field, new_value)
+            values (new.id, task_history_actor(), 'created', new_row);
+            return new;
+        end if;
+
+        old_row := to_jsonb(old) - 'id' - 'version' - 'search' - 'change_seq';
+        for field in select jsonb_object_keys(new_row) loop
+            if old_row -> field is distinct from new_row -> field then
+                insert into task_history (task_id, changed_by, field, old_value, new_value)
+                values (new.id, task_history_actor(), field, old_row -> field, new_row -> field);
+            end if;
+        end loop;
+        return new;
+    end
+    $$ language plpgsql;
+
+    create or replace function record_task_tag_history() returns trigger as $$
+    begin
+        if tg_op = 'INSERT' then
+            insert into task_history (task_id, changed_by, field, new_value)
+            select new.task_id, task_history_actor(), 'tags', to_jsonb(tg.name)
+            from tags tg where tg.id = new.tag_id;
+            return new;
+        end if;
+
+        -- Rows removed together with their task or tag have nothing to record.
+        insert into task_history (task_id, changed_by, field, old_value)
+        select old.task_id, task_history_actor(), 'tags', to_jsonb(tg.name)
+        from tags tg
+        where tg.id = old.tag_id and exists (select 1 from tasks where id = old.task_id);
+        return old;
+    end
+    $$ language plpgsql;
+
+    create or replace trigger tasks_history after insert or update on tasks
+        for each row execute function record_task_history();
+
+    create or replace trigger task_tags_history after insert or delete on task_tags
+        for each row execute function record_task_tag_history();`
+
+func createHistoryTriggers(db *sql.DB) {
+	if _, err := db.Exec(historyTriggersQuery); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// beginTx starts a transaction that records app.ActorFrom(ctx) as the author
+// of the task changes made in it.
+func (pc *PostgresController) beginTx(ctx context.Context) (*sql.Tx, error) {
+	tx, err := pc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "select set_config('app.actor', $1, true)", app.ActorFrom(ctx)); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (pc *PostgresController) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	var exists bool
+	if err := pc.db.QueryRowContext(ctx, "select exists (select 1 from tasks where id = $1)", req.TaskId).Scan(&exists); err != nil {
+		return models.TaskHistoryPage{}, err
+	}
+	if !exists {
+		return models.TaskHistoryPage{}, app.ErrTaskNotFound
+	}
+
+	// One extra row tells whether there is a next page.
+	rows, err := pc.db.QueryContext(ctx,
+		`select id, task_id, changed_at, changed_by, field,
+            coalesce(old_value::text, ''), coalesce(new_value::text, '')
+        from task_history
+        where task_id = $1 and ($2 = 0 or id < $2)
+        order by id desc
+        limit $3`,
+		req.TaskId, req.Cursor, req.Limit+1)
+	if err != nil {
+		return models.TaskHistoryPage{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	page := models.TaskHistoryPage{Changes: make([]models.TaskChange, 0, req.Limit)}
+	for rows.Next() {
+		var change models.TaskChange
+		if err := rows.Scan(
+			&change.Id,
+			&change.TaskId,
+			&change.ChangedAt,
+			&change.ChangedBy,
+			&change.Field,
+			&change.OldValue,
+			&change.NewValue); err != nil {
+			return models.TaskHistoryPage{}, err
+		}
+		page.Changes = append(page.Changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.TaskHistoryPage{}, err
+	}
+
+	if len(page.Changes) > req.Limit {
+		page.Changes = page.Changes[:req.Limit]
+		page.NextCursor = page.Changes[req.Limit-1].Id
+	}
+
+	return page, nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// claimIdempotencyKey reserves the key for the transaction, taking over an
+// expired one. If the key is taken, it returns the task saved for it instead.
+// A concurrent request with the same key waits here until the first one
+// commits or rolls back.
+func claimIdempotencyKey(ctx context.Context, tx *sql.Tx, key models.IdempotencyKey) (models.TaskExportData, bool, error) {
+	var claimed string
+	err := tx.QueryRowContext(ctx,
+		`insert into idempotency_keys (key, fingerprint, expires_at) values ($1, $2, $3)
+        on conflict (key) do update
+            set fingerprint = excluded.fingerprint, expires_at = excluded.expires_at, response = null
+            where idempotency_keys.expires_at <= now()
+        returning key`,
+		key.Key, key.Fingerprint, key.ExpiresAt).Scan(&claimed)
+	if err == nil {
+		return models.TaskExportData{}, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.TaskExportData{}, false, err
+	}
+
+	var (
+		fingerprint string
+		response    []byte
+	)
+	if err := tx.QueryRowContext(ctx, "select fingerprint, response from idempotency_keys where key = $1", key.Key).
+		Scan(&fingerprint, &response); err != nil {
+		return models.TaskExportData{}, false, err
+	}
+	if fingerprint != key.Fingerprint {
+		return models.TaskExportData{}, false, fmt.Errorf("%w: %q", app.ErrIdempotencyKeyReused, key.Key)
+	}
+
+	var task models.TaskExportData
+	if err := json.Unmarshal(response, &task); err != nil {
+		return models.TaskExportData{}, false, err
+	}
+	return task, true, nil
+}
+
+// saveIdempotentResponse stores the created task for the key claimed by
+// claimIdempotencyKey.
+func saveIdempotentResponse(ctx context.Context, tx *sql.Tx, key string, task models.TaskExportData) error {
+	response, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "update idempotency_keys set response = $2 where key = $1", key, response)
+	return err
+}
+
+func (pc *PostgresController) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int, error) {
+	result, err := pc.db.ExecContext(ctx, "delete from idempotency_keys where expires_at <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/lib/pq"
+)
+
+const projectColumns = `id, name, inbox, archived, created_at`
+
+func scanProject(row rowScanner) (models.Project, error) {
+	var project models.Project
+	err := row.Scan(
+		&project.Id,
+		&project.Name,
+		&project.Inbox,
+		&project.Archived,
+		&project.CreatedAt)
+	return project, err
+}
+
+// lockProject resolves id 0 to the inbox and makes sure the project accepts
+// tasks until the transaction ends.
+func lockProject(ctx context.Context, tx *sql.Tx, id int) (int, error) {
+	query := "select id, archived from projects where id = $1 for share"
+	args := []any{id}
+	if id == 0 {
+		query = "select id, archived from projects where inbox for share"
+		args = nil
+	}
+
+	var archived bool
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&id, &archived)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, app.ErrProjectNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if archived {
+		return 0, app.ErrProjectArchived
+	}
+
+	return id, nil
+}
+
+// lockNonInboxProject makes sure the project exists, is not the inbox and
+// holds its row until the transaction ends.
+func lockNonInboxProject(ctx context.Context, tx *sql.Tx, id int) error {
+	var inbox bool
+	err := tx.QueryRowContext(ctx, "select inbox from projects where id = $1 for update", id).Scan(&inbox)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.ErrProjectNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if inbox {
+		return app.ErrInboxProtected
+	}
+	return nil
+}
+
+func (pc *PostgresController) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	query := `insert into projects (name) values ($1) returning ` + projectColumns
+
+	project, err := scanProject(pc.db.QueryRowContext(ctx, query, name))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return models.Project{}, app.ErrProjectExists
+	}
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	return project, nil
+}
+
+func (pc *PostgresController) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	sliceToReturn := make([]models.Project, 0)
+
+	rows, err := pc.db.QueryContext(ctx,
+		`select `+projectColumns+` from projects
+        where $1 or not archived
+        order by inbox desc, id`,
+		includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		sliceToReturn = append(sliceToReturn, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sliceToReturn, nil
+}
+
+func (pc *PostgresController) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	query := `update projects
+        set name = $2
+        where id = $1
+        returning ` + projectColumns
+
+	project, err := scanProject(pc.db.QueryRowContext(ctx, query, id, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Project{}, app.ErrProjectNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return models.Project{}, app.ErrProjectExists
+	}
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	return project, nil
+}
+
+func (pc *PostgresController) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.Project{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockNonInboxProject(ctx, tx, id); err != nil {
+		return models.Project{}, err
+	}
+
+	query := `update projects
+        set archived = $2
+        where id = $1
+        returning ` + projectColumns
+
+	project, err := scanProject(tx.QueryRowContext(ctx, query, id, archived))
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	return project, tx.Commit()
+}
+
+func (pc *PostgresController) DeleteProject(ctx context.Context, id int) ([]int, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockNonInboxProject(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	movedIds, err := queryIds(ctx, tx,
+		`update tasks
+        set project_id = (select id from projects where inbox)
+        where project_id = $1
+        returning id`,
+		id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "delete from projects where id = $1", id); err != nil {
+		return nil, err
+	}
+
+	return movedIds, tx.Commit()
+}
+
+func (pc *PostgresController) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var parentId sql.NullInt64
+	err = tx.QueryRowContext(ctx, "select parent_id from tasks where id = $1 and deleted_at is null for update", id).Scan(&parentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskExportData{}, app.ErrTaskNotFound
+	}
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+	if parentId.Valid {
+		return models.TaskExportData{}, app.ErrSubtaskMove
+	}
+
+	projectId, err = lockProject(ctx, tx, projectId)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	// Subtasks always follow the task they belong to.
+	if _, err := tx.ExecContext(ctx,
+		subtreeIds+`update tasks
+        set project_id = $2
+        where id in (select id from subtree) and id <> $1`,
+		id, projectId); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	query := `update tasks
+        set project_id = $2
+        where id = $1
+        returning ` + taskColumns
+
+	movedTask, err := scanTask(tx.QueryRowContext(ctx, query, id, projectId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskExportData{}, app.ErrTaskNotFound
+	}
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	return movedTask, tx.Commit()
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/recurrence"
+)
+
+// recurrenceValue stores a schedule as jsonb, a task that doesn't repeat
+// as null.
+func recurrenceValue(rule *models.Recurrence) (any, error) {
+	if rule == nil {
+		return nil, nil
+	}
+	return json.Marshal(rule)
+}
+
+// lockRecurringTask returns the due date and schedule of an open task and
+// holds its row until the transaction ends. The schedule is nil for a task
+// that doesn't repeat, is already done or already has its next occurrence.
+func lockRecurringTask(ctx context.Context, tx *sql.Tx, id int) (*time.Time, *models.Recurrence, error) {
+	var (
+		finished bool
+		dueAt    *time.Time
+		rawRule  []byte
+		nextId   int
+	)
+	err := tx.QueryRowContext(ctx,
+		"select finished, due_at, recurrence, coalesce(next_occurrence_id, 0) from tasks where id = $1 and deleted_at is null for update",
+		id).Scan(&finished, &dueAt, &rawRule, &nextId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, app.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if finished || rawRule == nil || nextId != 0 {
+		return dueAt, nil, nil
+	}
+
+	var rule models.Recurrence
+	if err := json.Unmarshal(rawRule, &rule); err != nil {
+		return nil, nil, err
+	}
+	return dueAt, &rule, nil
+}
+
+// createNextOccurrence copies the task with its tags to the next due date and
+// links the finished task to the copy. Nothing is created once the series is
+// over.
+func createNextOccurrence(ctx context.Context, tx *sql.Tx, id int, dueAt *time.Time, rule models.Recurrence) error {
+	next, ok := recurrence.Next(rule, dueAt, time.Now())
+	if !ok {
+		return nil
+	}
+
+	position, err := appendPosition(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	var nextId int
+	if err := tx.QueryRowContext(ctx,
+		`insert into tasks (title, text, due_at, priority, position, project_id, recurrence)
+        select title, text, $2, priority, $3, project_id, recurrence from tasks where id = $1
+        returning id`,
+		id, next, position).Scan(&nextId); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"insert into task_tags (task_id, tag_id) select $2, tag_id from task_tags where task_id = $1",
+		id, nextId); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "update tasks set next_occurrence_id = $2 where id = $1", id, nextId)
+	return err
+}
+
+func (pc *PostgresController) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var parentId sql.NullInt64
+	err = tx.QueryRowContext(ctx, "select parent_id from tasks where id = $1 and deleted_at is null for update", id).Scan(&parentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskExportData{}, app.ErrTaskNotFound
+	}
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+	if parentId.Valid && rule != nil {
+		return models.TaskExportData{}, app.ErrRecurringSubtask
+	}
+
+	value, err := recurrenceValue(rule)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	query := `update tasks
+        set recurrence = $2
+        where id = $1
+        returning ` + taskColumns
+
+	updatedTask, err := scanTask(tx.QueryRowContext(ctx, query, id, value))
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	return updatedTask, tx.Commit()
+}
+
+func (pc *PostgresController) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	dueAt, rule, err := lockRecurringTask(ctx, tx, id)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+	if rule == nil {
+		return models.TaskExportData{}, app.ErrNotRecurring
+	}
+
+	next, ok := recurrence.Next(*rule, dueAt, time.Now())
+	if !ok {
+		return models.TaskExportData{}, app.ErrSeriesEnded
+	}
+
+	query := `update tasks
+        set due_at = $2
+        where id = $1
+        returning ` + taskColumns
+
+	updatedTask, err := scanTask(tx.QueryRowContext(ctx, query, id, next))
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	return updatedTask, tx.Commit()
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// defaultSearchConfigs index every word twice, so that both Russian and
+// English words are found by any of their forms.
+var defaultSearchConfigs = []string{"russian", "english"}
+
+var searchConfigName = regexp.MustCompile(`^[a-z_]+$`)
+
+// searchConfigsFromEnv reads SEARCH_LANGUAGES, a comma-separated list of
+// Postgres text search configurations.
+func searchConfigsFromEnv() []string {
+	var configs []string
+	for _, name := range strings.Split(os.Getenv("SEARCH_LANGUAGES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			configs = append(configs, name)
+		}
+	}
+	if len(configs) == 0 {
+		return defaultSearchConfigs
+	}
+	return configs
+}
+
+// createSearchColumn adds the generated search vector of the tasks: title
+// words weigh more than text words. The configuration names go into the
+// column expression, so they are checked against pg_ts_config first.
+func createSearchColumn(db *sql.DB, configs []string) {
+	var vectors []string
+	for _, config := range configs {
+		var exists bool
+		if !searchConfigName.MatchString(config) {
+			log.Fatalf("invalid text search configuration %q", config)
+		}
+		if err := db.QueryRow("select exists (select 1 from pg_ts_config where cfgname = $1)", config).Scan(&exists); err != nil {
+			log.Fatal(err)
+		}
+		if !exists {
+			log.Fatalf("unknown text search configuration %q", config)
+		}
+		vectors = append(vectors,
+			fmt.Sprintf("setweight(to_tsvector('%s', title), 'A')", config),
+			fmt.Sprintf("setweight(to_tsvector('%s', coalesce(text, '')), 'B')", config))
+	}
+
+	query := `alter table tasks add column if not exists search tsvector
+            generated always as (` + strings.Join(vectors, " || ") + `) stored;
+
+        create index if not exists tasks_search_idx on tasks using gin (search);`
+	if _, err := db.Exec(query); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// prefixQuery makes every term match the words starting with it, e.g.
+// "'buy':* & 'mil':*". The terms only contain letters and digits.
+func prefixQuery(terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, "'"+term+"':*")
+	}
+	return strings.Join(quoted, " & ")
+}
+
+// searchQuery matches a task if all terms match in one of the configurations.
+func (pc *PostgresController) searchQuery() string {
+	queries := make([]string, 0, len(pc.searchConfigs))
+	for _, config := range pc.searchConfigs {
+		queries = append(queries, fmt.Sprintf("to_tsquery('%s', $1)", config))
+	}
+	return strings.Join(queries, " || ")
+}
+
+// searchScanner scans the rank and the snippets selected after taskColumns.
+type searchScanner struct {
+	rowScanner
+	hit *models.SearchHit
+}
+
+func (s searchScanner) Scan(dest ...any) error {
+	return s.rowScanner.Scan(append(dest, &s.hit.Rank, &s.hit.TitleSnippet, &s.hit.TextSnippet)...)
+}
+
+// SearchTasks ranks by cover density, so tasks where the words stand close
+// together come first. Snippets are built with the first configuration.
+func (pc *PostgresController) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	headlineConfig := pc.searchConfigs[0]
+	query := `with q as (select ` + pc.searchQuery() + ` as query)
+        select ` + taskColumns + `,
+            ts_rank_cd(search, q.query) as search_rank,
+            ts_headline('` + headlineConfig + `', title, q.query, 'HighlightAll=true'),
+            ts_headline('` + headlineConfig + `', coalesce(text, ''), q.query, 'MaxWords=20, MinWords=5, MaxFragments=2')
+        from tasks, q
+        where deleted_at is null and search @@ q.query
+        order by search_rank desc, id
+        limit $2`
+
+	rows, err := pc.db.QueryContext(ctx, query, prefixQuery(req.Terms), req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	hits := make([]models.SearchHit, 0)
+	for rows.Next() {
+		var hit models.SearchHit
+		if hit.Task, err = scanTask(searchScanner{rowScanner: rows, hit: &hit}); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
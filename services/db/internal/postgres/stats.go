@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// Buckets are aligned to the start of a day/week in the requested time zone,
+// so the first bucket may begin slightly before req.From. Completions come
+// from the history, skipping the ones undone by reopening the task.
+const statsPointsQuery = `with buckets as (
+        select generate_series(
+            date_trunc($3, $1::timestamptz, $4),
+            $2::timestamptz - interval '1 microsecond',
+            ('1 ' || $3)::interval,
+            $4) as bucket_start
+    ), created as (
+        select date_trunc($3, created_at, $4) as bucket_start, count(*) as cnt
+        from tasks
+        where created_at >= date_trunc($3, $1::timestamptz, $4) and created_at < $2
+        group by 1
+    ), completed as (
+        select date_trunc($3, finished_at, $4) as bucket_start, count(*) as cnt
+        from task_completions
+        where finished_at >= date_trunc($3, $1::timestamptz, $4) and finished_at < $2
+            and reopened_at is null
+        group by 1
+    )
+    select b.bucket_start, coalesce(c.cnt, 0), coalesce(f.cnt, 0)
+    from buckets b
+    left join created c on c.bucket_start = b.bucket_start
+    left join completed f on f.bucket_start = b.bucket_start
+    order by b.bucket_start`
+
+const statsSummaryQuery = `select
+        (select count(*) from tasks where not finished and deleted_at is null),
+        coalesce(avg(extract(epoch from c.finished_at - t.created_at)), 0)
+    from task_completions c join tasks t on t.id = c.task_id
+    where c.finished_at >= $1 and c.finished_at < $2 and c.reopened_at is null`
+
+// A streak is a run of consecutive days with at least one completed task.
+// The current streak is still alive if its last day is today or yesterday.
+const statsStreaksQuery = `with days as (
+        select distinct (finished_at at time zone $1)::date as day
+        from task_completions
+        where reopened_at is null
+    ), islands as (
+        select count(*) as len, max(day) as last_day
+        from (select day, day - (row_number() over (order by day))::int as grp from days) d
+        group by grp
+    )
+    select
+        coalesce(max(len), 0),
+        coalesce(max(len) filter (where last_day >= (now() at time zone $1)::date - 1), 0)
+    from islands`
+
+func (pc *PostgresController) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	tx, err := pc.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return models.Stats{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stats := models.Stats{Points: make([]models.StatsPoint, 0)}
+
+	rows, err := tx.QueryContext(ctx, statsPointsQuery, req.From, req.To, req.Bucket.String(), req.TimeZone)
+	if err != nil {
+		return models.Stats{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var point models.StatsPoint
+		if err := rows.Scan(&point.Start, &point.Created, &point.Completed); err != nil {
+			return models.Stats{}, err
+		}
+		stats.CreatedTotal += point.Created
+		stats.CompletedTotal += point.Completed
+		stats.Points = append(stats.Points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.Stats{}, err
+	}
+
+	var avgSeconds float64
+	if err := tx.QueryRowContext(ctx, statsSummaryQuery, req.From, req.To).Scan(
+		&stats.OpenCount,
+		&avgSeconds); err != nil {
+		return models.Stats{}, err
+	}
+	stats.AvgTimeToComplete = time.Duration(avgSeconds * float64(time.Second))
+
+	if err := tx.QueryRowContext(ctx, statsStreaksQuery, req.TimeZone).Scan(
+		&stats.LongestStreak,
+		&stats.CurrentStreak); err != nil {
+		return models.Stats{}, err
+	}
+
+	return stats, tx.Commit()
+}
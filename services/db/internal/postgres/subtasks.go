@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// subtreeIds is a CTE prefix selecting task $1 and all of its descendants.
+const subtreeIds = `with recursive subtree as (
+        select id from tasks where id = $1
+        union all
+        select t.id from tasks t join subtree s on t.parent_id = s.id)
+    `
+
+// ancestorIds is a CTE prefix selecting task $1 and all of its ancestors.
+const ancestorIds = `with recursive ancestors as (
+        select id, parent_id from tasks where id = $1
+        union all
+        select t.id, t.parent_id from tasks t join ancestors a on t.id = a.parent_id)
+    `
+
+// taskDepthQuery counts the task itself and all of its ancestors.
+const taskDepthQuery = `with recursive ancestors as (
+        select parent_id from tasks where id = $1
+        union all
+        select t.parent_id from tasks t join ancestors a on t.id = a.parent_id)
+    select count(*) from ancestors`
+
+// lockParent holds the parent row until the transaction ends, checks that
+// one more level fits under it and returns the project subtasks inherit.
+func lockParent(ctx context.Context, tx *sql.Tx, id int) (int, error) {
+	var projectId int
+	err := tx.QueryRowContext(ctx, "select project_id from tasks where id = $1 and deleted_at is null for update", id).Scan(&projectId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: parent %d", app.ErrTaskNotFound, id)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var depth int
+	if err := tx.QueryRowContext(ctx, taskDepthQuery, id).Scan(&depth); err != nil {
+		return 0, err
+	}
+	if depth >= models.MaxTaskDepth {
+		return 0, app.ErrTaskTooDeep
+	}
+
+	return projectId, nil
+}
+
+func (pc *PostgresController) GetTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	task, err := scanTask(pc.db.QueryRowContext(ctx, "select "+taskColumns+" from tasks where id = $1 and deleted_at is null", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskExportData{}, app.ErrTaskNotFound
+	}
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	return task, nil
+}
+
+func (pc *PostgresController) ListSubtasks(ctx context.Context, id int) ([]models.TaskExportData, error) {
+	sliceToReturn := make([]models.TaskExportData, 0)
+
+	rows, err := pc.db.QueryContext(ctx, subtreeIds+`select `+taskColumns+` from tasks
+        where id in (select id from subtree) and id <> $1 and deleted_at is null
+        order by position`,
+		id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		sliceToReturn = append(sliceToReturn, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sliceToReturn, nil
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/lib/pq"
+)
+
+// The change sequence of a task is the id of the last transaction that
+// changed it, and a deleted task leaves a tombstone with the id of the
+// deleting transaction. Transaction ids grow, but transactions commit in any
+// order, so a sync only returns changes below the xmin of its snapshot: all
+// of those are committed, and no change can show up below a cursor later.
+const syncTriggersQuery = `create or replace function set_task_change_seq() returns trigger as $$
+    begin
+        if to_jsonb(new) - 'search' - 'change_seq' is distinct from to_jsonb(old) - 'search' - 'change_seq' then
+            new.change_seq := pg_current_xact_id()::text::bigint;
+        end if;
+        return new;
+    end
+    $$ language plpgsql;
+
+    create or replace function record_task_tombstone() returns trigger as $$
+    begin
+        insert into task_tombstones (task_id, change_seq) values (old.id, pg_current_xact_id()::text::bigint);
+        return old;
+    end
+    $$ language plpgsql;
+
+    create or replace trigger tasks_change_seq before update on tasks
+        for each row execute function set_task_change_seq();
+
+    create or replace trigger tasks_tombstones after delete on tasks
+        for each row execute function record_task_tombstone();`
+
+func createSyncTriggers(db *sql.DB) {
+	if _, err := db.Exec(syncTriggersQuery); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (pc *PostgresController) GetChangesSince(ctx context.Context, req models.ChangesRequest) (models.Changes, error) {
+	// One snapshot for the xmin and the changes below it.
+	tx, err := pc.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return models.Changes{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var horizon, xmin int64
+	err = tx.QueryRowContext(ctx,
+		`select (select change_seq from sync_horizon), pg_snapshot_xmin(pg_current_snapshot())::text::bigint`).
+		Scan(&horizon, &xmin)
+	if err != nil {
+		return models.Changes{}, err
+	}
+
+	var changes models.Changes
+	since := req.Since
+	// Tombstones up to the horizon were purged, so an older cursor may have
+	// missed deletions.
+	if since != (models.SyncCursor{}) && since.Seq <= horizon {
+		since = models.SyncCursor{}
+		changes.Full = true
+	}
+
+	// One extra row tells whether there are more changes.
+	rows, err := tx.QueryContext(ctx,
+		`select id, change_seq, deleted from (
+            select id, change_seq, deleted_at is not null as deleted from tasks
+            where (change_seq, id) > ($1, $2) and change_seq < $3
+            union all
+            select task_id, change_seq, true from task_tombstones
+            where (change_seq, task_id) > ($1, $2) and change_seq < $3) changes
+        order by change_seq, id
+        limit $4`,
+		since.Seq, since.TaskId, xmin, req.Limit+1)
+	if err != nil {
+		return models.Changes{}, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var changedIds []int
+	changes.Next = since
+	for rows.Next() {
+		if len(changedIds)+len(changes.DeletedIds) == req.Limit {
+			changes.HasMore = true
+			break
+		}
+
+		var id int
+		var deleted bool
+		if err := rows.Scan(&id, &changes.Next.Seq, &deleted); err != nil {
+			return models.Changes{}, err
+		}
+		changes.Next.TaskId = id
+
+		if deleted {
+			changes.DeletedIds = append(changes.DeletedIds, id)
+		} else {
+			changedIds = append(changedIds, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return models.Changes{}, err
+	}
+	_ = rows.Close()
+
+	// Everything below xmin has been returned, so the next sync may start
+	// there.
+	if !changes.HasMore && xmin > since.Seq {
+		changes.Next = models.SyncCursor{Seq: xmin}
+	}
+
+	changes.Tasks, err = tasksByIds(ctx, tx, changedIds)
+	if err != nil {
+		return models.Changes{}, err
+	}
+
+	return changes, nil
+}
+
+// tasksByIds loads the tasks in the order of ids.
+func tasksByIds(ctx context.Context, tx *sql.Tx, ids []int) ([]models.TaskExportData, error) {
+	tasks := make([]models.TaskExportData, 0, len(ids))
+	if len(ids) == 0 {
+		return tasks, nil
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		"select "+taskColumns+" from tasks where id = any($1) order by array_position($1, id)", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+// attachTags creates missing tags and links them to the task.
+func attachTags(ctx context.Context, tx *sql.Tx, taskId int, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`insert into tags (name) select unnest($1::text[]) on conflict (name) do nothing`,
+		pq.Array(tags)); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`insert into task_tags (task_id, tag_id)
+        select $1, id from tags where name = any($2)
+        on conflict do nothing`,
+		taskId, pq.Array(tags))
+	return err
+}
+
+// lockTask makes sure the task exists outside the trash and holds its row
+// until the transaction ends.
+func lockTask(ctx context.Context, tx *sql.Tx, id int) error {
+	var lockedId int
+	err := tx.QueryRowContext(ctx, "select id from tasks where id = $1 and deleted_at is null for update", id).Scan(&lockedId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.ErrTaskNotFound
+	}
+	return err
+}
+
+func (pc *PostgresController) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockTask(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	if err := attachTags(ctx, tx, id, tags); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	updatedTask, err := scanTask(tx.QueryRowContext(ctx, "select "+taskColumns+" from tasks where id = $1", id))
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	return updatedTask, tx.Commit()
+}
+
+func (pc *PostgresController) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockTask(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`delete from task_tags
+        where task_id = $1 and tag_id in (select id from tags where name = any($2))`,
+		id, pq.Array(tags)); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	updatedTask, err := scanTask(tx.QueryRowContext(ctx, "select "+taskColumns+" from tasks where id = $1", id))
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	return updatedTask, tx.Commit()
+}
+
+func (pc *PostgresController) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	sliceToReturn := make([]models.TagUsage, 0)
+
+	rows, err := pc.db.QueryContext(ctx,
+		`select tg.name, count(tt.task_id)
+        from tags tg
+        left join task_tags tt on tt.tag_id = tg.id
+            and tt.task_id in (select id from tasks where deleted_at is null)
+        group by tg.id
+        order by tg.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var tag models.TagUsage
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, err
+		}
+		sliceToReturn = append(sliceToReturn, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sliceToReturn, nil
+}
+
+func (pc *PostgresController) RenameTag(ctx context.Context, name, newName string) (models.TagChange, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var tagId int
+	err = tx.QueryRowContext(ctx, "update tags set name = $2 where name = $1 returning id", name, newName).Scan(&tagId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TagChange{}, app.ErrTagNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return models.TagChange{}, app.ErrTagAlreadyExists
+	}
+	if err != nil {
+		return models.TagChange{}, err
+	}
+
+	taskIds, err := queryIds(ctx, tx, "select task_id from task_tags where tag_id = $1 order by task_id", tagId)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+
+	return models.TagChange{Name: newName, TaskIds: taskIds}, tx.Commit()
+}
+
+// MergeTags moves every task from the source tags to the target tag, creating
+// the target if needed, and deletes the sources.
+func (pc *PostgresController) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var targetId int
+	if err := tx.QueryRowContext(ctx,
+		`insert into tags (name) values ($1)
+        on conflict (name) do update set name = excluded.name
+        returning id`,
+		target).Scan(&targetId); err != nil {
+		return models.TagChange{}, err
+	}
+
+	const sourceIds = `select id from tags where name = any($1) and id <> $2`
+
+	taskIds, err := queryIds(ctx, tx,
+		`select distinct task_id from task_tags where tag_id in (`+sourceIds+`) order by task_id`,
+		pq.Array(sources), targetId)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`insert into task_tags (task_id, tag_id)
+        select distinct task_id, $2::bigint from task_tags where tag_id in (`+sourceIds+`)
+        on conflict do nothing`,
+		pq.Array(sources), targetId); err != nil {
+		return models.TagChange{}, err
+	}
+
+	res, err := tx.ExecContext(ctx, `delete from tags where name = any($1) and id <> $2`, pq.Array(sources), targetId)
+	if err != nil {
+		return models.TagChange{}, err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return models.TagChange{}, err
+	}
+	if int(deleted) != len(sources) {
+		return models.TagChange{}, app.ErrTagNotFound
+	}
+
+	return models.TagChange{Name: target, TaskIds: taskIds}, tx.Commit()
+}
+
+func queryIds(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int, error) {
+	ids := make([]int, 0)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
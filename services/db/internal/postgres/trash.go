@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// ListTrash returns the tasks deleted on their own or together with their
+// subtasks, most recently deleted first. Subtasks trashed with a parent are
+// only reachable through it.
+func (pc *PostgresController) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	sliceToReturn := make([]models.TaskExportData, 0)
+
+	rows, err := pc.db.QueryContext(ctx, `select `+taskColumns+` from tasks
+        where deleted_at is not null
+            and not exists (select 1 from tasks p where p.id = tasks.parent_id and p.deleted_at is not null)
+        order by deleted_at desc, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		sliceToReturn = append(sliceToReturn, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sliceToReturn, nil
+}
+
+// RestoreTask brings back the task with the subtasks that were trashed
+// together with it. Subtasks deleted earlier on their own stay in the trash.
+func (pc *PostgresController) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		deletedAt     *time.Time
+		parentTrashed bool
+	)
+	err = tx.QueryRowContext(ctx,
+		`select t.deleted_at, p.deleted_at is not null
+        from tasks t left join tasks p on p.id = t.parent_id
+        where t.id = $1
+        for update of t`,
+		id).Scan(&deletedAt, &parentTrashed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskExportData{}, app.ErrTaskNotFound
+	}
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return models.TaskExportData{}, err
+	}
+	if deletedAt == nil {
+		return models.TaskExportData{}, app.ErrNotInTrash
+	}
+	if parentTrashed {
+		return models.TaskExportData{}, app.ErrParentInTrash
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		subtreeIds+`update tasks
+        set deleted_at = NULL
+        where id in (select id from subtree) and deleted_at = $2`,
+		id, deletedAt); err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	restoredTask, err := scanTask(tx.QueryRowContext(ctx, "select "+taskColumns+" from tasks where id = $1", id))
+	if err != nil {
+		return models.TaskExportData{}, err
+	}
+
+	return restoredTask, tx.Commit()
+}
+
+// PurgeTask permanently deletes a trashed task; the parent_id foreign key
+// removes its subtasks.
+func (pc *PostgresController) PurgeTask(ctx context.Context, id int) error {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var deletedAt *time.Time
+	err = tx.QueryRowContext(ctx, "select deleted_at from tasks where id = $1 for update", id).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.ErrTaskNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(ctx, tx, id); err != nil {
+		return err
+	}
+	if deletedAt == nil {
+		return app.ErrNotInTrash
+	}
+
+	if _, err := tx.ExecContext(ctx, "delete from tasks where id = $1", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PurgeTrash permanently deletes the tasks trashed before the given time and
+// returns how many there were.
+func (pc *PostgresController) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	tx, err := pc.beginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, "delete from tasks where deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+
+	// Tombstones are kept as long as trashed tasks. Sync cursors before the
+	// last purged tombstone can't see all deletions anymore, so the horizon
+	// moves past it.
+	_, err = tx.ExecContext(ctx,
+		`with purged as (delete from task_tombstones where deleted_at < $1 returning change_seq)
+        update sync_horizon set change_seq = greatest(change_seq, (select max(change_seq) from purged))`,
+		before)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(purged), tx.Commit()
+}
@@ -2,19 +2,69 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
 	"log"
 	"os"
 	"time"
 
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
-	_ "github.com/lib/pq"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/rank"
+	"github.com/lib/pq"
 )
 
-func initDB() *sql.DB {
+// taskColumns is the column list every query returning tasks selects,
+// in the order scanTask expects.
+const taskColumns = `id, title, text, finished, created_at, finished_at, due_at, priority, position, project_id,
+    coalesce(parent_id, 0),
+    (select count(*) from tasks st where st.parent_id = tasks.id and st.deleted_at is null) as subtasks_total,
+    (select count(*) from tasks st where st.parent_id = tasks.id and st.deleted_at is null and st.finished) as subtasks_done,
+    recurrence, coalesce(next_occurrence_id, 0), deleted_at, version,
+    array(select tg.name from task_tags tt join tags tg on tg.id = tt.tag_id
+        where tt.task_id = tasks.id order by tg.name) as tags`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (models.TaskExportData, error) {
+	var task models.TaskExportData
+	var rawRecurrence []byte
+	err := row.Scan(
+		&task.Id,
+		&task.Title,
+		&task.Text,
+		&task.Finished,
+		&task.CreatedAt,
+		&task.FinishedAt,
+		&task.DueAt,
+		&task.Priority,
+		&task.Position,
+		&task.ProjectId,
+		&task.ParentId,
+		&task.SubtasksTotal,
+		&task.SubtasksDone,
+		&rawRecurrence,
+		&task.NextOccurrenceId,
+		&task.DeletedAt,
+		&task.Version,
+		pq.Array(&task.Tags))
+	if err == nil && rawRecurrence != nil {
+		task.Recurrence = &models.Recurrence{}
+		err = json.Unmarshal(rawRecurrence, task.Recurrence)
+	}
+	return task, err
+}
+
+// connString builds the Postgres connection string from the environment.
+func connString() string {
 	pUser := os.Getenv("POSTGRES_USER")
 	pPassword := os.Getenv("POSTGRES_PASSWORD")
 	pDb := os.Getenv("POSTGRES_DB")
-	connStr := "postgres://" + pUser + ":" + pPassword + "@postgres:5432/" + pDb + "?sslmode=disable"
+	return "postgres://" + pUser + ":" + pPassword + "@postgres:5432/" + pDb + "?sslmode=disable"
+}
+
+func initDB(searchConfigs []string) *sql.DB {
+	connStr := connString()
 
 	var db *sql.DB
 	var err error
@@ -36,25 +86,116 @@ func initDB() *sql.DB {
 
 	createTasksTable(db)
 
+	createSearchColumn(db, searchConfigs)
+
+	createVersionTriggers(db)
+
+	createHistoryTriggers(db)
+
+	createEventTriggers(db)
+
+	createSyncTriggers(db)
+
+	createInbox(db)
+
 	seedTasks(db)
 
 	return db
 }
 
 func createTasksTable(db *sql.DB) {
-	dropQuery := `drop table if exists tasks`
+	dropQuery := `drop table if exists sync_horizon, task_tombstones, idempotency_keys, task_history, task_completions, task_tags, tags, tasks, projects`
 	if _, err := db.Exec(dropQuery); err != nil {
 		log.Fatal(err)
 		return
 	}
 
-	createQuery := `create table if not exists tasks (
+	createQuery := `create table if not exists projects (
+                id bigserial primary key,
+                name varchar(50) not null unique,
+                inbox bool not null default false,
+                archived bool not null default false,
+                created_at timestamptz not null default NOW());
+
+            create unique index if not exists projects_inbox_idx on projects (inbox) where inbox;
+
+            create table if not exists tasks (
                 id bigserial primary key,
                 title varchar(50) not null,
                 text varchar(200),
                 finished bool default false,
-                created_at timestamp not null default NOW(),
-                finished_at timestamp default NULL);`
+                created_at timestamptz not null default NOW(),
+                finished_at timestamptz default NULL,
+                due_at timestamptz default NULL,
+                priority smallint not null default 0,
+                position text collate "C" not null,
+                project_id bigint not null references projects (id),
+                parent_id bigint references tasks (id) on delete cascade,
+                recurrence jsonb,
+                next_occurrence_id bigint references tasks (id) on delete set null,
+                deleted_at timestamptz default NULL,
+                version bigint not null default 1,
+                change_seq bigint not null default pg_current_xact_id()::text::bigint);
+
+            create index if not exists tasks_project_id_idx on tasks (project_id);
+
+            create index if not exists tasks_parent_id_idx on tasks (parent_id);
+
+            create index if not exists tasks_deleted_at_idx on tasks (deleted_at) where deleted_at is not null;
+
+            create unique index if not exists tasks_position_idx on tasks (position);
+
+            create table if not exists tags (
+                id bigserial primary key,
+                name varchar(50) not null unique);
+
+            create table if not exists task_tags (
+                task_id bigint not null references tasks (id) on delete cascade,
+                tag_id bigint not null references tags (id) on delete cascade,
+                primary key (task_id, tag_id));
+
+            create index if not exists task_tags_tag_id_idx on task_tags (tag_id);
+
+            create table if not exists task_completions (
+                id bigserial primary key,
+                task_id bigint not null references tasks (id) on delete cascade,
+                finished_at timestamptz not null,
+                reopened_at timestamptz default NULL);
+
+            create index if not exists task_completions_finished_at_idx on task_completions (finished_at);
+
+            create table if not exists task_history (
+                id bigserial primary key,
+                task_id bigint not null references tasks (id) on delete cascade,
+                changed_at timestamptz not null default NOW(),
+                changed_by text not null,
+                field text not null,
+                old_value jsonb,
+                new_value jsonb);
+
+            create index if not exists task_history_task_id_idx on task_history (task_id, id);
+
+            create table if not exists idempotency_keys (
+                key varchar(255) primary key,
+                fingerprint text not null,
+                response jsonb,
+                expires_at timestamptz not null);
+
+            create index if not exists idempotency_keys_expires_at_idx on idempotency_keys (expires_at);
+
+            create index if not exists tasks_change_seq_idx on tasks (change_seq, id);
+
+            create table if not exists task_tombstones (
+                task_id bigint primary key,
+                change_seq bigint not null,
+                deleted_at timestamptz not null default NOW());
+
+            create index if not exists task_tombstones_change_seq_idx on task_tombstones (change_seq, task_id);
+
+            create table if not exists sync_horizon (
+                change_seq bigint not null);
+
+            insert into sync_horizon (change_seq) values (pg_current_xact_id()::text::bigint);`
 
 	if _, err := db.Exec(createQuery); err != nil {
 		log.Fatal(err)
@@ -62,6 +203,14 @@ func createTasksTable(db *sql.DB) {
 	}
 }
 
+// createInbox adds the project that receives tasks created without a project.
+// There are no user accounts yet, so the whole instance shares one inbox.
+func createInbox(db *sql.DB) {
+	if _, err := db.Exec(`insert into projects (name, inbox) values ('Inbox', true) on conflict do nothing`); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func seedTasks(db *sql.DB) {
 	tasks := []models.TaskImportData{
 		{Title: "Помыть посуду", Text: "После ужина на кухне"},
@@ -76,10 +225,16 @@ func seedTasks(db *sql.DB) {
 		{Title: "Сериал", Text: "Посмотреть 1 серию вечером"},
 	}
 
+	position := ""
 	for _, t := range tasks {
-		_, err := db.Exec(
-			`insert into tasks (title, text) values ($1, $2)`,
-			t.Title, t.Text,
+		var err error
+		if position, err = rank.Between(position, ""); err != nil {
+			log.Fatal(err)
+		}
+		_, err = db.Exec(
+			`insert into tasks (title, text, position, project_id)
+            values ($1, $2, $3, (select id from projects where inbox))`,
+			t.Title, t.Text, position,
 		)
 		if err != nil {
 			log.Fatal(err)
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+)
+
+// The version of a task grows whenever its row, its tags or the names of its
+// tags change. An update that changes nothing keeps the version. The
+// generated search column isn't computed yet in a before trigger and the
+// change sequence is bookkeeping, so both are left out of the comparison.
+const versionTriggersQuery = `create or replace function bump_task_version() returns trigger as $$
+    begin
+        if to_jsonb(new) - 'search' - 'change_seq' is distinct from to_jsonb(old) - 'search' - 'change_seq' then
+            new.version := old.version + 1;
+        end if;
+        return new;
+    end
+    $$ language plpgsql;
+
+    create or replace function bump_tagged_task_version() returns trigger as $$
+    begin
+        if tg_op = 'INSERT' then
+            update tasks set version = version + 1 where id = new.task_id;
+        else
+            update tasks set version = version + 1 where id = old.task_id;
+        end if;
+        return null;
+    end
+    $$ language plpgsql;
+
+    create or replace function bump_renamed_tag_version() returns trigger as $$
+    begin
+        update tasks set version = version + 1
+        where id in (select task_id from task_tags where tag_id = new.id);
+        return null;
+    end
+    $$ language plpgsql;
+
+    create or replace trigger tasks_version before update on tasks
+        for each row execute function bump_task_version();
+
+    create or replace trigger task_tags_version after insert or delete on task_tags
+        for each row execute function bump_tagged_task_version();
+
+    create or replace trigger tags_version after update of name on tags
+        for each row when (new.name is distinct from old.name) execute function bump_renamed_tag_version();`
+
+func createVersionTriggers(db *sql.DB) {
+	if _, err := db.Exec(versionTriggersQuery); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// checkVersion rejects the change if the caller expects another version of
+// the task, see app.WithExpectedVersion. The transaction must already hold
+// the task row.
+func checkVersion(ctx context.Context, tx *sql.Tx, id int) error {
+	expected := app.ExpectedVersionFrom(ctx)
+	if expected == 0 {
+		return nil
+	}
+
+	var version int
+	err := tx.QueryRowContext(ctx, "select version from tasks where id = $1", id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.ErrTaskNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if version != expected {
+		return fmt.Errorf("%w: task %d is at version %d, expected %d", app.ErrVersionMismatch, id, version, expected)
+	}
+	return nil
+}
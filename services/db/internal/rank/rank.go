@@ -0,0 +1,90 @@
+// Package rank generates lexicographic ranks for manual ordering.
+//
+// A rank is a non-empty string over digits and lowercase latin letters that
+// never ends with '0'. Between two ranks there is always room for another one,
+// so moving an item only requires rewriting that item's rank.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var ErrInvalidRank = errors.New("invalid rank")
+
+// Between returns a rank strictly greater than lower and strictly less than
+// upper. An empty lower means "before everything", an empty upper means
+// "after everything".
+func Between(lower, upper string) (string, error) {
+	if !valid(lower) || !valid(upper) {
+		return "", ErrInvalidRank
+	}
+	if lower != "" && upper != "" && lower >= upper {
+		return "", ErrInvalidRank
+	}
+
+	return midpoint(lower, upper), nil
+}
+
+func valid(r string) bool {
+	if r == "" {
+		return true
+	}
+	if r[len(r)-1] == digits[0] {
+		return false
+	}
+	for i := 0; i < len(r); i++ {
+		if strings.IndexByte(digits, r[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// midpoint expects lower < upper, with an empty upper meaning infinity.
+// A missing digit of lower is treated as '0'.
+func midpoint(lower, upper string) string {
+	if upper != "" {
+		n := 0
+		for n < len(upper) && digitAt(lower, n) == upper[n] {
+			n++
+		}
+		if n > 0 {
+			return upper[:n] + midpoint(lower[min(n, len(lower)):], upper[n:])
+		}
+	}
+
+	lowerDigit := 0
+	if lower != "" {
+		lowerDigit = strings.IndexByte(digits, lower[0])
+	}
+	upperDigit := len(digits)
+	if upper != "" {
+		upperDigit = strings.IndexByte(digits, upper[0])
+	}
+
+	if upperDigit-lowerDigit > 1 {
+		return string(digits[(lowerDigit+upperDigit)/2])
+	}
+
+	// The first digits are adjacent: a prefix of upper fits if upper is longer,
+	// otherwise keep lower's digit and go one level deeper.
+	if len(upper) > 1 {
+		return upper[:1]
+	}
+
+	rest := ""
+	if lower != "" {
+		rest = lower[1:]
+	}
+	return string(digits[lowerDigit]) + midpoint(rest, "")
+}
+
+func digitAt(r string, i int) byte {
+	if i < len(r) {
+		return r[i]
+	}
+	return digits[0]
+}
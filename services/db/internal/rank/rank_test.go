@@ -0,0 +1,93 @@
+package rank
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name  string
+		lower string
+		upper string
+		want  string
+	}{
+		{name: "empty list", lower: "", upper: "", want: "i"},
+		{name: "append", lower: "i", upper: "", want: "r"},
+		{name: "prepend", lower: "", upper: "i", want: "9"},
+		{name: "append after last digit", lower: "z", upper: "", want: "zi"},
+		{name: "prepend before first digit", lower: "", upper: "1", want: "0i"},
+		{name: "adjacent digits", lower: "a", upper: "b", want: "ai"},
+		{name: "upper is longer", lower: "a", upper: "b5", want: "b"},
+		{name: "common prefix", lower: "a1", upper: "a3", want: "a2"},
+		{name: "lower is prefix of upper", lower: "1", upper: "101", want: "100i"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Between(tt.lower, tt.upper)
+
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBetween_InvalidInput_ReturnsError(t *testing.T) {
+	tests := []struct {
+		name  string
+		lower string
+		upper string
+	}{
+		{name: "equal ranks", lower: "a", upper: "a"},
+		{name: "reversed ranks", lower: "b", upper: "a"},
+		{name: "trailing zero", lower: "a0", upper: ""},
+		{name: "unknown digit", lower: "", upper: "A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Between(tt.lower, tt.upper)
+
+			if !errors.Is(err, ErrInvalidRank) {
+				t.Fatalf("expected %v, got %v", ErrInvalidRank, err)
+			}
+		})
+	}
+}
+
+func TestBetween_RandomInsertsKeepOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ranks := []string{}
+
+	for range 1000 {
+		i := rng.Intn(len(ranks) + 1)
+		lower, upper := "", ""
+		if i > 0 {
+			lower = ranks[i-1]
+		}
+		if i < len(ranks) {
+			upper = ranks[i]
+		}
+
+		got, err := Between(lower, upper)
+		if err != nil {
+			t.Fatalf("Between(%q, %q): %v", lower, upper, err)
+		}
+		if (lower != "" && got <= lower) || (upper != "" && got >= upper) {
+			t.Fatalf("Between(%q, %q) = %q is out of range", lower, upper, got)
+		}
+
+		ranks = append(ranks[:i], append([]string{got}, ranks[i:]...)...)
+	}
+
+	if !sort.StringsAreSorted(ranks) {
+		t.Fatal("ranks are not sorted")
+	}
+}
@@ -0,0 +1,108 @@
+// Package recurrence computes due dates of recurring tasks.
+//
+// Schedules are evaluated in the rule's time zone and keep the wall clock
+// time of the due date, so "every day at 9:00" stays at 9:00 across DST.
+package recurrence
+
+import (
+	"slices"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+// maxSteps bounds the search for an occurrence after now when the due date
+// lies far in the past.
+const maxSteps = 100000
+
+// Next returns the due date of the occurrence that follows the one due at
+// due, once that one is done or skipped at now. Schedules step from the due
+// date and pass over occurrences that are already in the past; a task
+// without a due date counts as due now. ok is false when the series is over.
+func Next(rule models.Recurrence, due *time.Time, now time.Time) (time.Time, bool) {
+	loc, err := time.LoadLocation(rule.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	interval := max(rule.Interval, 1)
+
+	anchor := now.In(loc)
+	if due != nil {
+		anchor = due.In(loc)
+	}
+
+	var next time.Time
+	switch rule.Frequency {
+	case models.RecurrenceDaily:
+		next = nextDaily(anchor, now, interval)
+	case models.RecurrenceWeekly:
+		next = nextWeekly(anchor, now, interval, rule.Weekdays)
+	case models.RecurrenceMonthly:
+		next = nextMonthly(anchor, now, interval, rule.MonthDay)
+	case models.RecurrenceAfterCompletion:
+		// The due date only contributes its time of day.
+		next = now.In(loc).AddDate(0, 0, interval)
+		if due != nil {
+			next = time.Date(next.Year(), next.Month(), next.Day(),
+				anchor.Hour(), anchor.Minute(), anchor.Second(), 0, loc)
+		}
+	}
+
+	if next.IsZero() || (rule.Until != nil && next.After(*rule.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func nextDaily(anchor, now time.Time, interval int) time.Time {
+	for i := 1; i <= maxSteps; i++ {
+		if next := anchor.AddDate(0, 0, i*interval); next.After(now) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// nextWeekly counts weeks from Monday of the anchor's week, so with an
+// interval of 2 every other week is skipped as a whole.
+func nextWeekly(anchor, now time.Time, interval int, weekdays []time.Weekday) time.Time {
+	if len(weekdays) == 0 {
+		weekdays = []time.Weekday{anchor.Weekday()}
+	}
+
+	anchorWeek := weekStart(anchor)
+	for i := 1; i <= maxSteps; i++ {
+		next := anchor.AddDate(0, 0, i)
+		weeks := int(weekStart(next).Sub(anchorWeek).Hours()) / (7 * 24)
+		if weeks%interval == 0 && slices.Contains(weekdays, next.Weekday()) && next.After(now) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// nextMonthly moves a month day that doesn't exist in a month to its last
+// day: the 31st falls on April 30th.
+func nextMonthly(anchor, now time.Time, interval, monthDay int) time.Time {
+	if monthDay == 0 {
+		monthDay = anchor.Day()
+	}
+
+	for i := 0; i <= maxSteps; i++ {
+		month := time.Date(anchor.Year(), anchor.Month()+time.Month(i*interval), 1, 0, 0, 0, 0, time.UTC)
+		day := min(monthDay, month.AddDate(0, 1, -1).Day())
+		next := time.Date(month.Year(), month.Month(), day,
+			anchor.Hour(), anchor.Minute(), anchor.Second(), 0, anchor.Location())
+		if next.After(anchor) && next.After(now) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// weekStart returns Monday of t's week as a UTC date, which makes the
+// distance between two weeks an exact number of hours.
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,158 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+func TestNext(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wednesday
+	due := time.Date(2025, 12, 17, 9, 0, 0, 0, moscow)
+	until := time.Date(2025, 12, 31, 0, 0, 0, 0, moscow)
+
+	tests := []struct {
+		name string
+		rule models.Recurrence
+		due  *time.Time
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "daily done on time",
+			rule: models.Recurrence{Frequency: models.RecurrenceDaily, TimeZone: "Europe/Moscow"},
+			due:  &due,
+			now:  due.Add(-time.Hour),
+			want: time.Date(2025, 12, 18, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "every 3 days done late skips past occurrences",
+			rule: models.Recurrence{Frequency: models.RecurrenceDaily, Interval: 3, TimeZone: "Europe/Moscow"},
+			due:  &due,
+			now:  time.Date(2025, 12, 24, 12, 0, 0, 0, moscow),
+			want: time.Date(2025, 12, 26, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "weekly on weekdays skips the weekend",
+			rule: models.Recurrence{
+				Frequency: models.RecurrenceWeekly,
+				Weekdays:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+				TimeZone:  "Europe/Moscow",
+			},
+			due:  ptr(time.Date(2025, 12, 19, 9, 0, 0, 0, moscow)),
+			now:  time.Date(2025, 12, 19, 10, 0, 0, 0, moscow),
+			want: time.Date(2025, 12, 22, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "weekly defaults to the due weekday",
+			rule: models.Recurrence{Frequency: models.RecurrenceWeekly, TimeZone: "Europe/Moscow"},
+			due:  &due,
+			now:  due,
+			want: time.Date(2025, 12, 24, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "every other week skips a whole week",
+			rule: models.Recurrence{
+				Frequency: models.RecurrenceWeekly,
+				Interval:  2,
+				Weekdays:  []time.Weekday{time.Monday, time.Wednesday},
+				TimeZone:  "Europe/Moscow",
+			},
+			due:  &due,
+			now:  due,
+			want: time.Date(2025, 12, 29, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "monthly on a later day of the same month",
+			rule: models.Recurrence{Frequency: models.RecurrenceMonthly, MonthDay: 25, TimeZone: "Europe/Moscow"},
+			due:  &due,
+			now:  due,
+			want: time.Date(2025, 12, 25, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "monthly on the 31st falls on the last day",
+			rule: models.Recurrence{Frequency: models.RecurrenceMonthly, MonthDay: 31},
+			due:  ptr(time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)),
+			now:  time.Date(2026, 1, 31, 13, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly defaults to the due day",
+			rule: models.Recurrence{Frequency: models.RecurrenceMonthly, Interval: 3},
+			due:  ptr(time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)),
+			now:  time.Date(2025, 11, 10, 13, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "after completion keeps the due time of day",
+			rule: models.Recurrence{Frequency: models.RecurrenceAfterCompletion, Interval: 5, TimeZone: "Europe/Moscow"},
+			due:  &due,
+			now:  time.Date(2025, 12, 20, 22, 30, 0, 0, moscow),
+			want: time.Date(2025, 12, 25, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "after completion without due date",
+			rule: models.Recurrence{Frequency: models.RecurrenceAfterCompletion, Interval: 2},
+			due:  nil,
+			now:  time.Date(2025, 12, 20, 22, 30, 0, 0, time.UTC),
+			want: time.Date(2025, 12, 22, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "daily keeps the wall clock across DST",
+			rule: models.Recurrence{Frequency: models.RecurrenceDaily, TimeZone: "Europe/Berlin"},
+			due:  ptr(time.Date(2026, 3, 28, 8, 0, 0, 0, time.UTC)),
+			now:  time.Date(2026, 3, 28, 8, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 3, 29, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "last occurrence before until",
+			rule: models.Recurrence{Frequency: models.RecurrenceWeekly, Until: &until, TimeZone: "Europe/Moscow"},
+			due:  &due,
+			now:  due,
+			want: time.Date(2025, 12, 24, 9, 0, 0, 0, moscow),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Next(tt.rule, tt.due, tt.now)
+
+			if !ok {
+				t.Fatal("expected next occurrence, got end of series")
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNext_SeriesOver(t *testing.T) {
+	due := time.Date(2025, 12, 24, 9, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 12, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		rule models.Recurrence
+	}{
+		{name: "next is after until", rule: models.Recurrence{Frequency: models.RecurrenceWeekly, Until: &until}},
+		{name: "not recurring", rule: models.Recurrence{Frequency: models.RecurrenceNone}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := Next(tt.rule, &due, due); ok {
+				t.Fatalf("expected end of series, got %v", got)
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
@@ -1,26 +1,65 @@
 package redis
 
 import (
-	"context"
-
 	"github.com/redis/go-redis/v9"
 )
 
+// DefaultKeyPrefix namespaces the cache keys when Options.KeyPrefix is empty.
+const DefaultKeyPrefix = "todo"
+
+// cacheSchemaVersion is part of every key. Bump it when the format of
+// cached values changes, so replicas of different versions don't read each
+// other's values.
+const cacheSchemaVersion = 4
+
+// Options configure the Redis connection and the cache.
+type Options struct {
+	Address  string
+	Password string
+	DB       int
+	// TLS turns on TLS; TLSCAFile, when set, is the CA the server certificate
+	// is checked against instead of the system ones.
+	TLS       bool
+	TLSCAFile string
+	// KeyPrefix namespaces all keys of the cache, so it can share a Redis
+	// instance with other data.
+	KeyPrefix  string
+	TTLSeconds int
+	// StaleSeconds is how long an expired value is still kept to be served
+	// while it is reloaded.
+	StaleSeconds int
+}
+
 type RedisController struct {
 	redisClient *redis.Client
-	taskListKey string
-	ttlSeconds  int
+	// keyPrefix is "<prefix>:v<schema version>:".
+	keyPrefix       string
+	prefix          string
+	taskListMetaKey string
+	taskListIdsKey  string
+	ttlSeconds      int
+	staleSeconds    int
 }
 
-func NewRedisController(ctx context.Context, address string, ttlSeconds int) (*RedisController, error) {
-	redisClient, err := initRedis(ctx, address)
+func NewRedisController(opts Options) (*RedisController, error) {
+	redisClient, err := initRedis(opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return &RedisController{
-		redisClient: redisClient,
-		taskListKey: "tasks",
-		ttlSeconds:  ttlSeconds,
-	}, nil
+	prefix := opts.KeyPrefix
+	if prefix == "" {
+		prefix = DefaultKeyPrefix
+	}
+
+	rc := &RedisController{
+		redisClient:  redisClient,
+		prefix:       prefix,
+		keyPrefix:    cacheKeyPrefix(prefix, cacheSchemaVersion),
+		ttlSeconds:   opts.TTLSeconds,
+		staleSeconds: opts.StaleSeconds,
+	}
+	rc.taskListMetaKey = rc.key("tasks")
+	rc.taskListIdsKey = rc.key("tasks:ids")
+	return rc, nil
 }
@@ -2,8 +2,14 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
 	"strconv"
 	"time"
 
@@ -12,89 +18,338 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func initRedis(ctx context.Context, address string) (*redis.Client, error) {
-	redisClient := redis.NewClient(
-		&redis.Options{
-			Addr:     address,
-			Password: "",
-			DB:       0,
-		},
-	)
+func initRedis(opts Options) (*redis.Client, error) {
+	redisOptions := &redis.Options{
+		Addr:     opts.Address,
+		Password: opts.Password,
+		DB:       opts.DB,
+	}
+	if opts.TLS {
+		tlsConfig, err := tlsConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		redisOptions.TLSConfig = tlsConfig
+	}
 
-	if _, err := redisClient.Ping(ctx).Result(); err != nil {
-		return nil, err
+	// The client connects lazily, so a Redis that is down doesn't stop the
+	// service from starting; see Ping.
+	return redis.NewClient(redisOptions), nil
+}
+
+// Ping checks that Redis can be reached.
+func (rc *RedisController) Ping(ctx context.Context) error {
+	return rc.redisClient.Ping(ctx).Err()
+}
+
+func tlsConfig(opts Options) (*tls.Config, error) {
+	host, _, err := net.SplitHostPort(opts.Address)
+	if err != nil {
+		return nil, fmt.Errorf("redis address: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: host,
+	}
+
+	if opts.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(opts.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("redis CA file: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("redis CA file %s has no certificates", opts.TLSCAFile)
+		}
 	}
 
-	return redisClient, nil
+	return config, nil
 }
 
 func (rc *RedisController) Close() error {
 	return rc.redisClient.Close()
 }
 
+// ttlJitter spreads the expiry of keys cached together by up to ±10%, so
+// they don't all go to Postgres at the same moment.
+const ttlJitter = 0.1
+
+// The task list is a sorted set of task IDs, scored by ID like the list from
+// Postgres, next to a hash per task with its JSON, the time it stops being
+// fresh and its version, so an older read never replaces a newer one. A
+// change of one task touches only its hash and the set, so the list stays
+// cached under write load. The list meta key holds the time the list stops
+// being fresh and marks the set as complete; the set expires together with
+// it. Redis keeps every key staleSeconds longer than it is fresh, so it can
+// be served while it is reloaded.
+const (
+	dataField       = "data"
+	freshUntilField = "fresh_until"
+)
+
+func (rc *RedisController) ttl() time.Duration {
+	ttl := time.Duration(rc.ttlSeconds) * time.Second
+	spread := time.Duration(float64(ttl) * ttlJitter)
+	if spread <= 0 {
+		return ttl
+	}
+	return ttl - spread + rand.N(2*spread+1)
+}
+
+// expiry is the Redis TTL of a key that is fresh for ttl.
+func (rc *RedisController) expiry(ttl time.Duration) time.Duration {
+	return ttl + time.Duration(rc.staleSeconds)*time.Second
+}
+
+// cacheTaskListScript replaces the cached list. A task hash is written only
+// if it doesn't hold a newer version of the task: the list may have been
+// read from Postgres before a change whose task was cached meanwhile.
+// KEYS: list meta, list IDs, then a hash per task.
+// ARGV: fresh until in ms, TTL in ms, then ID, version and JSON per task.
+var cacheTaskListScript = redis.NewScript(`
+redis.call("UNLINK", KEYS[2])
+for i = 3, #KEYS do
+	local arg = 3 + (i - 3) * 3
+	local cached = tonumber(redis.call("HGET", KEYS[i], "version"))
+	if not cached or cached <= tonumber(ARGV[arg + 1]) then
+		redis.call("HSET", KEYS[i], "data", ARGV[arg + 2], "fresh_until", ARGV[1], "version", ARGV[arg + 1])
+		redis.call("PEXPIRE", KEYS[i], ARGV[2])
+	end
+	redis.call("ZADD", KEYS[2], ARGV[arg], ARGV[arg])
+end
+if #KEYS > 2 then
+	redis.call("PEXPIRE", KEYS[2], ARGV[2])
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+// CacheTaskList replaces the cached list and its tasks in one script.
 func (rc *RedisController) CacheTaskList(ctx context.Context, tasks []models.TaskExportData) error {
-	taskList, err := json.Marshal(tasks)
+	ttl := rc.ttl()
+	keys, args, err := rc.taskListScriptArgs(tasks, time.Now().Add(ttl).UnixMilli(), rc.expiry(ttl).Milliseconds())
 	if err != nil {
 		return err
 	}
+	return cacheTaskListScript.Run(ctx, rc.redisClient, keys, args...).Err()
+}
 
-	return rc.redisClient.Set(ctx, rc.taskListKey, taskList, time.Duration(rc.ttlSeconds)*time.Second).Err()
+func (rc *RedisController) taskListScriptArgs(tasks []models.TaskExportData, freshUntil, expiry int64) ([]string, []any, error) {
+	keys := make([]string, 0, len(tasks)+2)
+	keys = append(keys, rc.taskListMetaKey, rc.taskListIdsKey)
+	args := make([]any, 0, 3*len(tasks)+2)
+	args = append(args, freshUntil, expiry)
+	for _, task := range tasks {
+		data, err := json.Marshal(task)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, rc.taskKey(task.Id))
+		args = append(args, task.Id, task.Version, data)
+	}
+	return keys, args, nil
 }
 
 func (rc *RedisController) DeleteTaskList(ctx context.Context) error {
-	return rc.redisClient.Del(ctx, rc.taskListKey).Err()
+	return rc.redisClient.Unlink(ctx, rc.taskListMetaKey, rc.taskListIdsKey).Err()
 }
 
-func (rc *RedisController) GetTaskList(ctx context.Context) ([]models.TaskExportData, error) {
-	tasksStr, err := rc.redisClient.Get(ctx, rc.taskListKey).Result()
+// GetTaskList reads the listed IDs in one transaction and then their tasks
+// in one pipeline.
+func (rc *RedisController) GetTaskList(ctx context.Context) (app.CachedTaskList, error) {
+	var meta *redis.StringCmd
+	var ids *redis.StringSliceCmd
+	_, err := rc.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		meta = pipe.Get(ctx, rc.taskListMetaKey)
+		ids = pipe.ZRange(ctx, rc.taskListIdsKey, 0, -1)
+		return nil
+	})
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return nil, app.ErrTaskNotFound
+			return app.CachedTaskList{}, app.ErrTaskNotFound
 		}
-		return nil, err
+		return app.CachedTaskList{}, err
 	}
 
-	var tasksToReturn []models.TaskExportData
-	if err := json.Unmarshal([]byte(tasksStr), &tasksToReturn); err != nil {
-		return nil, err
+	freshUntil, err := meta.Int64()
+	if err != nil {
+		return app.CachedTaskList{}, err
+	}
+
+	datas := make([]*redis.StringCmd, 0, len(ids.Val()))
+	_, err = rc.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids.Val() {
+			datas = append(datas, pipe.HGet(ctx, rc.key("task:"+id), dataField))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return app.CachedTaskList{}, err
+	}
+
+	list := app.CachedTaskList{
+		Tasks: make([]models.TaskExportData, 0, len(datas)),
+		Stale: time.Now().UnixMilli() > freshUntil,
+	}
+	for i, data := range datas {
+		taskData, err := data.Bytes()
+		if errors.Is(err, redis.Nil) {
+			id, err := strconv.Atoi(ids.Val()[i])
+			if err != nil {
+				return app.CachedTaskList{}, err
+			}
+			list.MissingIds = append(list.MissingIds, id)
+			continue
+		}
+		if err != nil {
+			return app.CachedTaskList{}, err
+		}
+
+		var task models.TaskExportData
+		if err := json.Unmarshal(taskData, &task); err != nil {
+			return app.CachedTaskList{}, err
+		}
+		list.Tasks = append(list.Tasks, task)
 	}
 
-	return tasksToReturn, nil
+	return list, nil
 }
 
+// cacheTaskScript stores the task hash unless it holds a newer version and,
+// when the list is cached, adds the task to it; the set keeps expiring with
+// the list meta.
+// KEYS: task hash, list meta, list IDs.
+// ARGV: task JSON, fresh until in ms, TTL in ms, task ID, task version.
+var cacheTaskScript = redis.NewScript(`
+local cached = tonumber(redis.call("HGET", KEYS[1], "version"))
+if not cached or cached <= tonumber(ARGV[5]) then
+	redis.call("HSET", KEYS[1], "data", ARGV[1], "fresh_until", ARGV[2], "version", ARGV[5])
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+end
+local listTTL = redis.call("PTTL", KEYS[2])
+if listTTL > 0 then
+	redis.call("ZADD", KEYS[3], ARGV[4], ARGV[4])
+	redis.call("PEXPIRE", KEYS[3], listTTL)
+end
+return 1
+`)
+
+// CacheTask stores the task and adds it to the cached list.
 func (rc *RedisController) CacheTask(ctx context.Context, task models.TaskExportData) error {
-	key := "task:" + strconv.Itoa(task.Id)
-	taskStr, err := json.Marshal(task)
+	data, err := json.Marshal(task)
 	if err != nil {
 		return err
 	}
 
-	return rc.redisClient.Set(ctx, key, taskStr, time.Duration(rc.ttlSeconds)*time.Second).Err()
+	ttl := rc.ttl()
+	keys := []string{rc.taskKey(task.Id), rc.taskListMetaKey, rc.taskListIdsKey}
+	return cacheTaskScript.Run(ctx, rc.redisClient, keys,
+		data, time.Now().Add(ttl).UnixMilli(), rc.expiry(ttl).Milliseconds(), task.Id, task.Version).Err()
 }
 
+// DeleteTaskById drops the cached task. A listed task stays in the list and
+// is read from the main DB with it.
 func (rc *RedisController) DeleteTaskById(ctx context.Context, id int) error {
-	key := "task:" + strconv.Itoa(id)
-	return rc.redisClient.Del(ctx, key).Err()
+	return rc.redisClient.Unlink(ctx, rc.taskKey(id)).Err()
+}
+
+func (rc *RedisController) RemoveTasksFromList(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	members := make([]any, 0, len(ids))
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, id)
+		keys = append(keys, rc.taskKey(id))
+	}
+
+	_, err := rc.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, rc.taskListIdsKey, members...)
+		pipe.Unlink(ctx, keys...)
+		return nil
+	})
+	return err
 }
 
-func (rc *RedisController) GetTaskById(ctx context.Context, id int) (models.TaskExportData, error) {
-	key := "task:" + strconv.Itoa(id)
-	taskStr, err := rc.redisClient.Get(ctx, key).Result()
+func (rc *RedisController) GetTaskById(ctx context.Context, id int) (models.TaskExportData, bool, error) {
+	values, err := rc.redisClient.HMGet(ctx, rc.taskKey(id), dataField, freshUntilField).Result()
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return models.TaskExportData{}, app.ErrTaskNotFound
-		}
-		return models.TaskExportData{}, err
+		return models.TaskExportData{}, false, err
+	}
+
+	data, ok := values[0].(string)
+	if !ok {
+		return models.TaskExportData{}, false, app.ErrTaskNotFound
+	}
+	freshUntil, _ := values[1].(string)
+	freshUntilMs, err := strconv.ParseInt(freshUntil, 10, 64)
+	if err != nil {
+		return models.TaskExportData{}, false, err
 	}
 
 	var taskToReturn models.TaskExportData
-	if err := json.Unmarshal([]byte(taskStr), &taskToReturn); err != nil {
-		return models.TaskExportData{}, err
+	if err := json.Unmarshal([]byte(data), &taskToReturn); err != nil {
+		return models.TaskExportData{}, false, err
 	}
 
-	return taskToReturn, nil
+	return taskToReturn, time.Now().UnixMilli() > freshUntilMs, nil
 }
 
+// unlockScript deletes the lock only if it still holds our token, so an
+// expired lock taken over by another replica isn't released by mistake.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// TryLock takes a lock shared by all db-service replicas. The lock expires
+// after ttl in case its holder dies.
+func (rc *RedisController) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	lockKey := rc.key("lock:" + key)
+	token := strconv.FormatUint(rand.Uint64(), 36)
+
+	ok, err := rc.redisClient.SetNX(ctx, lockKey, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, app.ErrLockHeld
+	}
+
+	return func(ctx context.Context) error {
+		return unlockScript.Run(ctx, rc.redisClient, []string{lockKey}, token).Err()
+	}, nil
+}
+
+// flushBatch is how many keys FlushAllData asks SCAN for and unlinks at once.
+const flushBatch = 500
+
+// FlushAllData removes the keys of all cache schema versions under the key
+// prefix and leaves the rest of the Redis instance alone. UNLINK frees the
+// memory in the background, so a large cache doesn't block Redis.
 func (rc *RedisController) FlushAllData(ctx context.Context) error {
-	return rc.redisClient.FlushAll(ctx).Err()
+	iter := rc.redisClient.Scan(ctx, 0, flushPattern(rc.prefix), flushBatch).Iterator()
+
+	keys := make([]string, 0, flushBatch)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == flushBatch {
+			if err := rc.redisClient.Unlink(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return rc.redisClient.Unlink(ctx, keys...).Err()
 }
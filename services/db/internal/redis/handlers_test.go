@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+)
+
+func TestTaskListScriptArgs_PassVersionOfEveryTask(t *testing.T) {
+	rc := &RedisController{keyPrefix: "todo:v4:", taskListMetaKey: "todo:v4:tasks", taskListIdsKey: "todo:v4:tasks:ids"}
+	tasks := []models.TaskExportData{{Id: 3, Version: 7}, {Id: 5, Version: 1}}
+
+	keys, args, err := rc.taskListScriptArgs(tasks, 1000, 2000)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	wantKeys := []string{"todo:v4:tasks", "todo:v4:tasks:ids", "todo:v4:task:3", "todo:v4:task:5"}
+	if len(keys) != len(wantKeys) {
+		t.Fatalf("expected keys %v, got %v", wantKeys, keys)
+	}
+	for i := range wantKeys {
+		if keys[i] != wantKeys[i] {
+			t.Fatalf("expected keys %v, got %v", wantKeys, keys)
+		}
+	}
+	if len(args) != 2+3*len(tasks) || args[0] != int64(1000) || args[1] != int64(2000) {
+		t.Fatalf("unexpected args %v", args)
+	}
+	for i, task := range tasks {
+		if args[2+3*i] != task.Id || args[3+3*i] != task.Version {
+			t.Fatalf("task %d: expected ID %d and version %d, got %v and %v",
+				i, task.Id, task.Version, args[2+3*i], args[3+3*i])
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+)
+
+func cacheKeyPrefix(prefix string, schemaVersion int) string {
+	return prefix + ":v" + strconv.Itoa(schemaVersion) + ":"
+}
+
+func (rc *RedisController) key(name string) string {
+	return rc.keyPrefix + name
+}
+
+func (rc *RedisController) taskKey(id int) string {
+	return rc.key("task:" + strconv.Itoa(id))
+}
+
+// escapeGlob makes s match itself in a SCAN MATCH pattern.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '^', '-', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// flushPattern matches the keys of all schema versions under the prefix.
+func flushPattern(prefix string) string {
+	return escapeGlob(prefix) + ":*"
+}
@@ -0,0 +1,26 @@
+package redis
+
+import "testing"
+
+func TestCacheKeyPrefix(t *testing.T) {
+	if got := cacheKeyPrefix("todo", 2); got != "todo:v2:" {
+		t.Fatalf("expected todo:v2:, got %q", got)
+	}
+}
+
+func TestFlushPattern_EscapesGlobCharacters(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{prefix: "todo", want: `todo:*`},
+		{prefix: "team*[a]", want: `team\*\[a\]:*`},
+		{prefix: `a?b\c`, want: `a\?b\\c:*`},
+	}
+
+	for _, tt := range tests {
+		if got := flushPattern(tt.prefix); got != tt.want {
+			t.Fatalf("%q: expected %q, got %q", tt.prefix, tt.want, got)
+		}
+	}
+}
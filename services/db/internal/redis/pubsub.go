@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	invalidationChannel = "cache-invalidation"
+	// invalidationPingInterval is how often a quiet subscription checks that
+	// its connection is still alive.
+	invalidationPingInterval = 30 * time.Second
+	invalidationRetryDelay   = time.Second
+)
+
+func (rc *RedisController) PublishInvalidation(ctx context.Context, message string) error {
+	return rc.redisClient.Publish(ctx, rc.key(invalidationChannel), message).Err()
+}
+
+// SubscribeInvalidations calls onMessage for every invalidation message until
+// ctx is done. Redis doesn't keep messages for a disconnected subscriber, so
+// onReset is called on every (re)subscription and connection error.
+func (rc *RedisController) SubscribeInvalidations(ctx context.Context, onMessage func(string), onReset func()) {
+	pubsub := rc.redisClient.Subscribe(ctx, rc.key(invalidationChannel))
+	// Receive doesn't stop on ctx, closing the subscription does.
+	stop := context.AfterFunc(ctx, func() { _ = pubsub.Close() })
+	defer func() {
+		if stop() {
+			_ = pubsub.Close()
+		}
+	}()
+
+	for {
+		msg, err := pubsub.ReceiveTimeout(ctx, invalidationPingInterval)
+		if ctx.Err() != nil {
+			return
+		}
+
+		var netErr net.Error
+		switch {
+		case errors.As(err, &netErr) && netErr.Timeout():
+			if err := pubsub.Ping(ctx); err != nil {
+				log.Printf("cache invalidation ping err: %v\n", err)
+			}
+			continue
+		case err != nil:
+			log.Printf("cache invalidation subscription err: %v\n", err)
+			onReset()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(invalidationRetryDelay):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			onReset()
+		case *redis.Message:
+			onMessage(msg.Payload)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package grpc
 
 import (
+	"time"
+
 	"github.com/dodocheck/go-pet-project-1/pkg/pb"
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -11,18 +15,45 @@ func taskImportDataFromPB(task *pb.TaskImportData) models.TaskImportData {
 		return models.TaskImportData{}
 	}
 
-	return models.TaskImportData{
-		Title: task.GetTitle(),
-		Text:  task.GetText(),
+	out := models.TaskImportData{
+		Title:      task.GetTitle(),
+		Text:       task.GetText(),
+		Priority:   models.Priority(task.GetPriority()),
+		Tags:       task.GetTags(),
+		ProjectId:  int(task.GetProjectId()),
+		ParentId:   int(task.GetParentId()),
+		Recurrence: recurrenceFromPB(task.GetRecurrence()),
+	}
+
+	if task.GetDueAt() != nil {
+		dueAt := task.GetDueAt().AsTime()
+		out.DueAt = &dueAt
+	}
+
+	if key := task.GetIdempotencyKey(); key != "" {
+		out.Idempotency = &models.IdempotencyKey{Key: key}
 	}
+
+	return out
 }
 
 func taskExportDataToPB(task models.TaskExportData) *pb.TaskExportData {
 	out := &pb.TaskExportData{
-		Id:       int64(task.Id),
-		Title:    task.Title,
-		Text:     task.Text,
-		Finished: task.Finished,
+		Id:               int64(task.Id),
+		Title:            task.Title,
+		Text:             task.Text,
+		Finished:         task.Finished,
+		Overdue:          task.Overdue,
+		Priority:         pb.Priority(task.Priority),
+		Position:         task.Position,
+		Tags:             task.Tags,
+		ProjectId:        int64(task.ProjectId),
+		ParentId:         int64(task.ParentId),
+		SubtasksTotal:    int64(task.SubtasksTotal),
+		SubtasksDone:     int64(task.SubtasksDone),
+		Recurrence:       recurrenceToPB(task.Recurrence),
+		NextOccurrenceId: int64(task.NextOccurrenceId),
+		Version:          int64(task.Version),
 	}
 
 	if !task.CreatedAt.IsZero() {
@@ -31,10 +62,67 @@ func taskExportDataToPB(task models.TaskExportData) *pb.TaskExportData {
 	if task.FinishedAt != nil && !task.FinishedAt.IsZero() {
 		out.FinishedAt = timestamppb.New(*task.FinishedAt)
 	}
+	if task.DueAt != nil && !task.DueAt.IsZero() {
+		out.DueAt = timestamppb.New(*task.DueAt)
+	}
+	if task.DeletedAt != nil {
+		out.DeletedAt = timestamppb.New(*task.DeletedAt)
+	}
+
+	return out
+}
+
+func recurrenceFromPB(rule *pb.Recurrence) *models.Recurrence {
+	if rule == nil {
+		return nil
+	}
+
+	out := &models.Recurrence{
+		Frequency: models.RecurrenceFrequency(rule.GetFrequency()),
+		Interval:  int(rule.GetInterval()),
+		MonthDay:  int(rule.GetMonthDay()),
+		TimeZone:  rule.GetTimeZone(),
+	}
+	for _, day := range rule.GetWeekdays() {
+		out.Weekdays = append(out.Weekdays, time.Weekday(day))
+	}
+	if rule.GetUntil() != nil {
+		until := rule.GetUntil().AsTime()
+		out.Until = &until
+	}
+
+	return out
+}
+
+func recurrenceToPB(rule *models.Recurrence) *pb.Recurrence {
+	if rule == nil {
+		return nil
+	}
+
+	out := &pb.Recurrence{
+		Frequency: pb.RecurrenceFrequency(rule.Frequency),
+		Interval:  int32(rule.Interval),
+		MonthDay:  int32(rule.MonthDay),
+		TimeZone:  rule.TimeZone,
+	}
+	for _, day := range rule.Weekdays {
+		out.Weekdays = append(out.Weekdays, int32(day))
+	}
+	if rule.Until != nil {
+		out.Until = timestamppb.New(*rule.Until)
+	}
 
 	return out
 }
 
+func taskTreeToPB(tree models.TaskTree) *pb.TaskTree {
+	out := &pb.TaskTree{Task: taskExportDataToPB(tree.Task)}
+	for _, subtask := range tree.Subtasks {
+		out.Subtasks = append(out.Subtasks, taskTreeToPB(subtask))
+	}
+	return out
+}
+
 func taskSliceToPB(tasks []models.TaskExportData) *pb.TaskList {
 	if tasks == nil {
 		return nil
@@ -47,6 +135,88 @@ func taskSliceToPB(tasks []models.TaskExportData) *pb.TaskList {
 	return taskList
 }
 
+// staleTaskListToPB flags a list served from the cache while the main DB is
+// down.
+func staleTaskListToPB(tasks []models.TaskExportData, stale bool) *pb.TaskList {
+	taskList := taskSliceToPB(tasks)
+	if stale {
+		if taskList == nil {
+			taskList = &pb.TaskList{}
+		}
+		taskList.Stale = true
+	}
+	return taskList
+}
+
+func taskFilterFromPB(filter *pb.TaskFilter) models.TaskFilter {
+	if filter == nil {
+		return models.TaskFilter{}
+	}
+
+	return models.TaskFilter{
+		Due:       models.DueFilter(filter.GetDue()),
+		TimeZone:  filter.GetTimeZone(),
+		Sort:      models.TaskSort(filter.GetSort()),
+		Tags:      filter.GetTags(),
+		TagMatch:  models.TagMatch(filter.GetTagMatch()),
+		ProjectId: int(filter.GetProjectId()),
+	}
+}
+
+func taskMoveFromPB(move *pb.MoveTaskRequest) models.TaskMove {
+	if move == nil {
+		return models.TaskMove{}
+	}
+
+	return models.TaskMove{
+		Id:       int(move.GetId()),
+		BeforeId: int(move.GetBeforeId()),
+		AfterId:  int(move.GetAfterId()),
+	}
+}
+
+func tagListToPB(tags []models.TagUsage) *pb.TagList {
+	tagList := &pb.TagList{}
+	for _, v := range tags {
+		tagList.Tags = append(tagList.Tags, &pb.TagUsage{
+			Name:  v.Name,
+			Count: int64(v.Count),
+		})
+	}
+	return tagList
+}
+
+func tagChangeToPB(change models.TagChange) *pb.TagChange {
+	out := &pb.TagChange{Name: change.Name}
+	for _, id := range change.TaskIds {
+		out.TaskIds = append(out.TaskIds, int64(id))
+	}
+	return out
+}
+
+func projectToPB(project models.Project) *pb.Project {
+	out := &pb.Project{
+		Id:       int64(project.Id),
+		Name:     project.Name,
+		Inbox:    project.Inbox,
+		Archived: project.Archived,
+	}
+
+	if !project.CreatedAt.IsZero() {
+		out.CreatedAt = timestamppb.New(project.CreatedAt)
+	}
+
+	return out
+}
+
+func projectListToPB(projects []models.Project) *pb.ProjectList {
+	projectList := &pb.ProjectList{}
+	for _, v := range projects {
+		projectList.Projects = append(projectList.Projects, projectToPB(v))
+	}
+	return projectList
+}
+
 func taskIdFromPB(id *pb.TaskId) int {
 	if id == nil {
 		return -1
@@ -54,3 +224,167 @@ func taskIdFromPB(id *pb.TaskId) int {
 
 	return int(id.GetId())
 }
+
+func statsRequestFromPB(req *pb.StatsRequest) models.StatsRequest {
+	if req == nil {
+		return models.StatsRequest{}
+	}
+
+	out := models.StatsRequest{
+		Bucket:   models.StatsBucket(req.GetBucket()),
+		TimeZone: req.GetTimeZone(),
+	}
+
+	if req.GetFrom() != nil {
+		out.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		out.To = req.GetTo().AsTime()
+	}
+
+	return out
+}
+
+func statsToPB(stats models.Stats) *pb.Stats {
+	out := &pb.Stats{
+		CreatedTotal:      int64(stats.CreatedTotal),
+		CompletedTotal:    int64(stats.CompletedTotal),
+		AvgTimeToComplete: durationpb.New(stats.AvgTimeToComplete),
+		OpenCount:         int64(stats.OpenCount),
+		CurrentStreak:     int64(stats.CurrentStreak),
+		LongestStreak:     int64(stats.LongestStreak),
+	}
+
+	for _, v := range stats.Points {
+		out.Points = append(out.Points, &pb.StatsPoint{
+			Start:     timestamppb.New(v.Start),
+			Created:   int64(v.Created),
+			Completed: int64(v.Completed),
+		})
+	}
+
+	return out
+}
+
+func taskHistoryRequestFromPB(req *pb.TaskHistoryRequest) models.TaskHistoryRequest {
+	return models.TaskHistoryRequest{
+		TaskId: int(req.GetTaskId()),
+		Limit:  int(req.GetLimit()),
+		Cursor: int(req.GetCursor()),
+	}
+}
+
+func taskHistoryPageToPB(page models.TaskHistoryPage) *pb.TaskHistoryPage {
+	out := &pb.TaskHistoryPage{NextCursor: int64(page.NextCursor)}
+
+	for _, change := range page.Changes {
+		out.Changes = append(out.Changes, &pb.TaskChange{
+			Id:        int64(change.Id),
+			TaskId:    int64(change.TaskId),
+			ChangedAt: timestamppb.New(change.ChangedAt),
+			ChangedBy: change.ChangedBy,
+			Field:     change.Field,
+			OldValue:  change.OldValue,
+			NewValue:  change.NewValue,
+		})
+	}
+
+	return out
+}
+
+func taskIdsFromPB(req *pb.TaskIds) []int {
+	ids := make([]int, 0, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		ids = append(ids, int(id))
+	}
+	return ids
+}
+
+// batchResultToPB reports failed items with the status code a single request
+// would have got.
+func batchResultToPB(items []models.BatchItem, operation string) *pb.BatchResult {
+	out := &pb.BatchResult{Items: make([]*pb.BatchItemResult, 0, len(items))}
+
+	for _, item := range items {
+		result := &pb.BatchItemResult{Id: int64(item.Id)}
+		switch {
+		case item.Err != nil:
+			st := status.Convert(statusError(operation, item.Err))
+			result.Code = int32(st.Code())
+			result.Error = st.Message()
+		case item.Task.Id != 0:
+			result.Task = taskExportDataToPB(item.Task)
+		}
+		out.Items = append(out.Items, result)
+	}
+
+	return out
+}
+
+func searchRequestFromPB(req *pb.SearchRequest) models.SearchRequest {
+	return models.SearchRequest{
+		Query: req.GetQuery(),
+		Limit: int(req.GetLimit()),
+	}
+}
+
+func searchResultToPB(hits []models.SearchHit) *pb.SearchResult {
+	out := &pb.SearchResult{Hits: make([]*pb.SearchHit, 0, len(hits))}
+
+	for _, hit := range hits {
+		out.Hits = append(out.Hits, &pb.SearchHit{
+			Task:         taskExportDataToPB(hit.Task),
+			Rank:         hit.Rank,
+			TitleSnippet: hit.TitleSnippet,
+			TextSnippet:  hit.TextSnippet,
+		})
+	}
+
+	return out
+}
+
+func watchRequestFromPB(req *pb.WatchTasksRequest) models.WatchRequest {
+	return models.WatchRequest{
+		ProjectId:   int(req.GetProjectId()),
+		LastEventId: int(req.GetLastEventId()),
+	}
+}
+
+func taskEventToPB(event models.TaskEvent) *pb.TaskEvent {
+	out := &pb.TaskEvent{
+		Id:        int64(event.Id),
+		Type:      pb.TaskEventType(event.Type),
+		TaskId:    int64(event.TaskId),
+		ProjectId: int64(event.ProjectId),
+		Version:   int64(event.Version),
+		Actor:     event.Actor,
+	}
+	if !event.OccurredAt.IsZero() {
+		out.OccurredAt = timestamppb.New(event.OccurredAt)
+	}
+	return out
+}
+
+func changesRequestFromPB(req *pb.ChangesRequest) models.ChangesRequest {
+	return models.ChangesRequest{
+		Token: req.GetToken(),
+		Limit: int(req.GetLimit()),
+	}
+}
+
+func changesToPB(changes models.Changes) *pb.Changes {
+	out := &pb.Changes{
+		Tasks:      make([]*pb.TaskExportData, 0, len(changes.Tasks)),
+		DeletedIds: make([]int64, 0, len(changes.DeletedIds)),
+		Token:      changes.Token,
+		HasMore:    changes.HasMore,
+		Full:       changes.Full,
+	}
+	for _, task := range changes.Tasks {
+		out.Tasks = append(out.Tasks, taskExportDataToPB(task))
+	}
+	for _, id := range changes.DeletedIds {
+		out.DeletedIds = append(out.DeletedIds, int64(id))
+	}
+	return out
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -28,6 +29,44 @@ func TestTaskImportDataFromPB(t *testing.T) {
 				Text:  "my text",
 			},
 		},
+		{
+			name: "task with idempotency key",
+			in: &pb.TaskImportData{
+				Title:          "my title",
+				IdempotencyKey: "abc",
+			},
+			want: models.TaskImportData{
+				Title:       "my title",
+				Idempotency: &models.IdempotencyKey{Key: "abc"},
+			},
+		},
+		{
+			name: "task with due date",
+			in: &pb.TaskImportData{
+				Title: "my title",
+				DueAt: timestamppb.New(time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC)),
+			},
+			want: models.TaskImportData{
+				Title: "my title",
+				DueAt: func() *time.Time {
+					ts := time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC)
+					return &ts
+				}(),
+			},
+		},
+		{
+			name: "task with priority",
+			in: &pb.TaskImportData{
+				Title:    "my title",
+				Priority: pb.Priority_PRIORITY_URGENT,
+				Tags:     []string{"home"},
+			},
+			want: models.TaskImportData{
+				Title:    "my title",
+				Priority: models.PriorityUrgent,
+				Tags:     []string{"home"},
+			},
+		},
 		{
 			name: "nil task",
 			in:   nil,
@@ -92,6 +131,118 @@ func TestTaskExportDataToPB(t *testing.T) {
 				FinishedAt: timestamppb.New(finishedAtTS),
 			},
 		},
+		{
+			name: "overdue task",
+			in: models.TaskExportData{
+				Id:        679,
+				Title:     "some title3",
+				CreatedAt: createdAtTS,
+				DueAt:     &finishedAtTS,
+				Overdue:   true,
+			},
+			want: &pb.TaskExportData{
+				Id:        679,
+				Title:     "some title3",
+				CreatedAt: timestamppb.New(createdAtTS),
+				DueAt:     timestamppb.New(finishedAtTS),
+				Overdue:   true,
+			},
+		},
+		{
+			name: "ranked task",
+			in: models.TaskExportData{
+				Id:        680,
+				Title:     "some title4",
+				CreatedAt: createdAtTS,
+				Priority:  models.PriorityMedium,
+				Position:  "ai",
+				Tags:      []string{"home", "work"},
+				ProjectId: 2,
+			},
+			want: &pb.TaskExportData{
+				Id:        680,
+				Title:     "some title4",
+				CreatedAt: timestamppb.New(createdAtTS),
+				Priority:  pb.Priority_PRIORITY_MEDIUM,
+				Position:  "ai",
+				Tags:      []string{"home", "work"},
+				ProjectId: 2,
+			},
+		},
+		{
+			name: "subtask",
+			in: models.TaskExportData{
+				Id:            681,
+				Title:         "some title5",
+				CreatedAt:     createdAtTS,
+				ProjectId:     2,
+				ParentId:      680,
+				SubtasksTotal: 5,
+				SubtasksDone:  3,
+			},
+			want: &pb.TaskExportData{
+				Id:            681,
+				Title:         "some title5",
+				CreatedAt:     timestamppb.New(createdAtTS),
+				ProjectId:     2,
+				ParentId:      680,
+				SubtasksTotal: 5,
+				SubtasksDone:  3,
+			},
+		},
+		{
+			name: "recurring task",
+			in: models.TaskExportData{
+				Id:    682,
+				Title: "some title6",
+				Recurrence: &models.Recurrence{
+					Frequency: models.RecurrenceWeekly,
+					Interval:  2,
+					Weekdays:  []time.Weekday{time.Monday, time.Thursday},
+					Until:     &finishedAtTS,
+					TimeZone:  "Europe/Moscow",
+				},
+				NextOccurrenceId: 690,
+			},
+			want: &pb.TaskExportData{
+				Id:    682,
+				Title: "some title6",
+				Recurrence: &pb.Recurrence{
+					Frequency: pb.RecurrenceFrequency_RECURRENCE_FREQUENCY_WEEKLY,
+					Interval:  2,
+					Weekdays:  []int32{1, 4},
+					Until:     timestamppb.New(finishedAtTS),
+					TimeZone:  "Europe/Moscow",
+				},
+				NextOccurrenceId: 690,
+			},
+		},
+		{
+			name: "task with version",
+			in: models.TaskExportData{
+				Id:      684,
+				Title:   "some title8",
+				Version: 7,
+			},
+			want: &pb.TaskExportData{
+				Id:      684,
+				Title:   "some title8",
+				Version: 7,
+			},
+		},
+		{
+			name: "trashed task",
+			in: models.TaskExportData{
+				Id:        683,
+				Title:     "some title7",
+				DeletedAt: &finishedAtTS,
+			},
+			want: &pb.TaskExportData{
+				Id:        683,
+				Title:     "some title7",
+				DeletedAt: timestamppb.New(finishedAtTS),
+			},
+		},
 		{
 			name: "empty task",
 			in:   models.TaskExportData{},
@@ -176,6 +327,140 @@ func TestTaskSliceToPB(t *testing.T) {
 	}
 }
 
+func TestStaleTaskListToPB(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    []models.TaskExportData
+		stale bool
+		want  *pb.TaskList
+	}{
+		{
+			name: "fresh list",
+			in:   []models.TaskExportData{{Id: 1}},
+			want: &pb.TaskList{Tasks: []*pb.TaskExportData{{Id: 1}}},
+		},
+		{
+			name:  "stale list",
+			in:    []models.TaskExportData{{Id: 1}},
+			stale: true,
+			want:  &pb.TaskList{Tasks: []*pb.TaskExportData{{Id: 1}}, Stale: true},
+		},
+		{
+			name:  "stale empty list",
+			in:    nil,
+			stale: true,
+			want:  &pb.TaskList{Stale: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := staleTaskListToPB(tt.in, tt.stale)
+
+			if diff := cmp.Diff(got, tt.want, protocmp.Transform()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestTaskFilterFromPB(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *pb.TaskFilter
+		want models.TaskFilter
+	}{
+		{
+			name: "regular filter",
+			in: &pb.TaskFilter{
+				Due:       pb.DueFilter_DUE_FILTER_THIS_WEEK,
+				TimeZone:  "Europe/Moscow",
+				Sort:      pb.TaskSort_TASK_SORT_POSITION,
+				Tags:      []string{"home"},
+				TagMatch:  pb.TagMatch_TAG_MATCH_ALL,
+				ProjectId: 3,
+			},
+			want: models.TaskFilter{
+				Due:       models.DueFilterThisWeek,
+				TimeZone:  "Europe/Moscow",
+				Sort:      models.TaskSortPosition,
+				Tags:      []string{"home"},
+				TagMatch:  models.TagMatchAll,
+				ProjectId: 3,
+			},
+		},
+		{
+			name: "nil filter",
+			in:   nil,
+			want: models.TaskFilter{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := taskFilterFromPB(tt.in)
+
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestTaskMoveFromPB(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *pb.MoveTaskRequest
+		want models.TaskMove
+	}{
+		{
+			name: "move before",
+			in:   &pb.MoveTaskRequest{Id: 5, BeforeId: 7},
+			want: models.TaskMove{Id: 5, BeforeId: 7},
+		},
+		{
+			name: "move after",
+			in:   &pb.MoveTaskRequest{Id: 5, AfterId: 8},
+			want: models.TaskMove{Id: 5, AfterId: 8},
+		},
+		{
+			name: "nil request",
+			in:   nil,
+			want: models.TaskMove{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := taskMoveFromPB(tt.in)
+
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestProjectToPB(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	got := projectToPB(models.Project{Id: 2, Name: "Work", Archived: true, CreatedAt: createdAt})
+
+	want := &pb.Project{Id: 2, Name: "Work", Archived: true, CreatedAt: timestamppb.New(createdAt)}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestTagChangeToPB(t *testing.T) {
+	got := tagChangeToPB(models.TagChange{Name: "work", TaskIds: []int{2, 3}})
+
+	want := &pb.TagChange{Name: "work", TaskIds: []int64{2, 3}}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
 func TestTaskIdFromPB(t *testing.T) {
 	tests := []struct {
 		name string
@@ -204,3 +489,143 @@ func TestTaskIdFromPB(t *testing.T) {
 		})
 	}
 }
+
+func TestStatsRequestFromPB(t *testing.T) {
+	fromTS := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	toTS := time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		in   *pb.StatsRequest
+		want models.StatsRequest
+	}{
+		{
+			name: "regular request",
+			in: &pb.StatsRequest{
+				From:     timestamppb.New(fromTS),
+				To:       timestamppb.New(toTS),
+				Bucket:   pb.StatsBucket_STATS_BUCKET_WEEK,
+				TimeZone: "Europe/Moscow",
+			},
+			want: models.StatsRequest{
+				From:     fromTS,
+				To:       toTS,
+				Bucket:   models.StatsBucketWeek,
+				TimeZone: "Europe/Moscow",
+			},
+		},
+		{
+			name: "request without range",
+			in:   &pb.StatsRequest{},
+			want: models.StatsRequest{Bucket: models.StatsBucketDay},
+		},
+		{
+			name: "nil request",
+			in:   nil,
+			want: models.StatsRequest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statsRequestFromPB(tt.in)
+
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestStatsToPB(t *testing.T) {
+	startTS := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		in   models.Stats
+		want *pb.Stats
+	}{
+		{
+			name: "regular stats",
+			in: models.Stats{
+				Points: []models.StatsPoint{
+					{Start: startTS, Created: 4, Completed: 2},
+					{Start: startTS.AddDate(0, 0, 1), Created: 0, Completed: 1},
+				},
+				CreatedTotal:      4,
+				CompletedTotal:    3,
+				AvgTimeToComplete: 90 * time.Minute,
+				OpenCount:         6,
+				CurrentStreak:     2,
+				LongestStreak:     5,
+			},
+			want: &pb.Stats{
+				Points: []*pb.StatsPoint{
+					{Start: timestamppb.New(startTS), Created: 4, Completed: 2},
+					{Start: timestamppb.New(startTS.AddDate(0, 0, 1)), Created: 0, Completed: 1},
+				},
+				CreatedTotal:      4,
+				CompletedTotal:    3,
+				AvgTimeToComplete: durationpb.New(90 * time.Minute),
+				OpenCount:         6,
+				CurrentStreak:     2,
+				LongestStreak:     5,
+			},
+		},
+		{
+			name: "empty stats",
+			in:   models.Stats{},
+			want: &pb.Stats{AvgTimeToComplete: durationpb.New(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statsToPB(tt.in)
+
+			if diff := cmp.Diff(got, tt.want, protocmp.Transform()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestTaskHistoryPageToPB(t *testing.T) {
+	changedAt := time.Date(2025, 12, 1, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		in   models.TaskHistoryPage
+		want *pb.TaskHistoryPage
+	}{
+		{
+			name: "page with changes",
+			in: models.TaskHistoryPage{
+				Changes: []models.TaskChange{
+					{Id: 3, TaskId: 1, ChangedAt: changedAt, ChangedBy: "alice", Field: "title", OldValue: `"old"`, NewValue: `"new"`},
+					{Id: 2, TaskId: 1, ChangedAt: changedAt, ChangedBy: "system", Field: "finished", OldValue: "false", NewValue: "true"},
+				},
+				NextCursor: 2,
+			},
+			want: &pb.TaskHistoryPage{
+				Changes: []*pb.TaskChange{
+					{Id: 3, TaskId: 1, ChangedAt: timestamppb.New(changedAt), ChangedBy: "alice", Field: "title", OldValue: `"old"`, NewValue: `"new"`},
+					{Id: 2, TaskId: 1, ChangedAt: timestamppb.New(changedAt), ChangedBy: "system", Field: "finished", OldValue: "false", NewValue: "true"},
+				},
+				NextCursor: 2,
+			},
+		},
+		{
+			name: "empty page",
+			in:   models.TaskHistoryPage{},
+			want: &pb.TaskHistoryPage{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := taskHistoryPageToPB(tt.in)
+
+			if diff := cmp.Diff(got, tt.want, protocmp.Transform()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
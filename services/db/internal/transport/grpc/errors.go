@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError maps domain errors to gRPC status codes. Anything unexpected is
+// reported as Internal, prefixed with the failed operation.
+func statusError(operation string, err error) error {
+	switch {
+	case errors.Is(err, app.ErrInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrTaskNotFound), errors.Is(err, app.ErrTagNotFound), errors.Is(err, app.ErrProjectNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, app.ErrTagAlreadyExists), errors.Is(err, app.ErrProjectExists), errors.Is(err, app.ErrIdempotencyKeyReused):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, app.ErrProjectArchived), errors.Is(err, app.ErrInboxProtected),
+		errors.Is(err, app.ErrTaskTooDeep), errors.Is(err, app.ErrSubtaskMove),
+		errors.Is(err, app.ErrRecurringSubtask), errors.Is(err, app.ErrNotRecurring), errors.Is(err, app.ErrSeriesEnded),
+		errors.Is(err, app.ErrNotInTrash), errors.Is(err, app.ErrParentInTrash):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, app.ErrVersionMismatch):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, app.ErrWatcherTooSlow):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case app.MainDBUnavailable(err):
+		return status.Errorf(codes.Unavailable, "%s error: %v", operation, err)
+	default:
+		return status.Errorf(codes.Internal, "%s error: %v\n", operation, err)
+	}
+}
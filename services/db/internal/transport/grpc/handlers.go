@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/dodocheck/go-pet-project-1/pkg/pb"
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -18,7 +19,7 @@ func (s *Server) AddTask(ctx context.Context, task *pb.TaskImportData) (*pb.Task
 
 	createdTask, err := s.service.AddTask(ctx, taskFromPB)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "add task error: %v\n", err)
+		return nil, statusError("add task", err)
 	}
 
 	taskToPB := taskExportDataToPB(createdTask)
@@ -32,19 +33,118 @@ func (s *Server) RemoveTask(ctx context.Context, id *pb.TaskId) (*emptypb.Empty,
 	}
 
 	if err := s.service.DeleteTask(ctx, taskIdFromPB(id)); err != nil {
-		return nil, status.Errorf(codes.Internal, "remove task error: %v\n", err)
+		return nil, statusError("remove task", err)
 	}
 
-	return nil, nil
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListTrash(ctx context.Context, _ *emptypb.Empty) (*pb.TaskList, error) {
+	tasks, err := s.service.ListTrash(ctx)
+	if err != nil {
+		return nil, statusError("list trash", err)
+	}
+
+	return taskSliceToPB(tasks), nil
+}
+
+func (s *Server) RestoreTask(ctx context.Context, id *pb.TaskId) (*pb.TaskExportData, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty id")
+	}
+
+	restoredTask, err := s.service.RestoreTask(ctx, taskIdFromPB(id))
+	if err != nil {
+		return nil, statusError("restore task", err)
+	}
+
+	return taskExportDataToPB(restoredTask), nil
+}
+
+func (s *Server) PurgeTask(ctx context.Context, id *pb.TaskId) (*emptypb.Empty, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty id")
+	}
+
+	if err := s.service.PurgeTask(ctx, taskIdFromPB(id)); err != nil {
+		return nil, statusError("purge task", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) GetTaskTree(ctx context.Context, id *pb.TaskId) (*pb.TaskTree, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty id")
+	}
+
+	tree, err := s.service.GetTaskTree(ctx, taskIdFromPB(id))
+	if err != nil {
+		return nil, statusError("get task tree", err)
+	}
+
+	return taskTreeToPB(tree), nil
 }
 
 func (s *Server) ListAllTasks(ctx context.Context, _ *emptypb.Empty) (*pb.TaskList, error) {
-	allTasks, err := s.service.ListAllTasks(ctx)
+	allTasks, stale, err := s.service.ListAllTasks(ctx)
+	if err != nil {
+		return nil, statusError("list tasks", err)
+	}
+
+	return staleTaskListToPB(allTasks, stale), nil
+}
+
+func (s *Server) ListTasks(ctx context.Context, filter *pb.TaskFilter) (*pb.TaskList, error) {
+	tasks, stale, err := s.service.ListTasks(ctx, taskFilterFromPB(filter))
+	if err != nil {
+		return nil, statusError("list tasks", err)
+	}
+
+	return staleTaskListToPB(tasks, stale), nil
+}
+
+func (s *Server) SearchTasks(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResult, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty search request")
+	}
+
+	hits, err := s.service.SearchTasks(ctx, searchRequestFromPB(req))
+	if err != nil {
+		return nil, statusError("search tasks", err)
+	}
+
+	return searchResultToPB(hits), nil
+}
+
+func (s *Server) GetChangesSince(ctx context.Context, req *pb.ChangesRequest) (*pb.Changes, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty changes request")
+	}
+
+	changes, err := s.service.GetChangesSince(ctx, changesRequestFromPB(req))
+	if err != nil {
+		return nil, statusError("get changes", err)
+	}
+
+	return changesToPB(changes), nil
+}
+
+// WatchTasks streams task events until the client goes away. A client that
+// falls behind gets ResourceExhausted and may resume from its last event.
+func (s *Server) WatchTasks(req *pb.WatchTasksRequest, stream pb.TasksService_WatchTasksServer) error {
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "received empty watch request")
+	}
+
+	err := s.service.WatchTasks(stream.Context(), watchRequestFromPB(req), func(event models.TaskEvent) error {
+		return stream.Send(taskEventToPB(event))
+	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "list tasks error: %v\n", err)
+		return statusError("watch tasks", err)
 	}
 
-	return taskSliceToPB(allTasks), nil
+	return nil
 }
 
 func (s *Server) MarkTaskFinished(ctx context.Context, id *pb.TaskId) (*pb.TaskExportData, error) {
@@ -54,8 +154,277 @@ func (s *Server) MarkTaskFinished(ctx context.Context, id *pb.TaskId) (*pb.TaskE
 
 	updatedTask, err := s.service.MarkTaskFinished(ctx, taskIdFromPB(id))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "finish task error: %v\n", err)
+		return nil, statusError("finish task", err)
+	}
+
+	return taskExportDataToPB(updatedTask), nil
+}
+
+func (s *Server) ReopenTask(ctx context.Context, id *pb.TaskId) (*pb.TaskExportData, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty id")
+	}
+
+	updatedTask, err := s.service.ReopenTask(ctx, taskIdFromPB(id))
+	if err != nil {
+		return nil, statusError("reopen task", err)
+	}
+
+	return taskExportDataToPB(updatedTask), nil
+}
+
+func (s *Server) SetTaskPriority(ctx context.Context, req *pb.TaskPriority) (*pb.TaskExportData, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty priority request")
+	}
+
+	updatedTask, err := s.service.SetTaskPriority(ctx, int(req.GetId()), models.Priority(req.GetPriority()))
+	if err != nil {
+		return nil, statusError("set task priority", err)
+	}
+
+	return taskExportDataToPB(updatedTask), nil
+}
+
+func (s *Server) SetTaskRecurrence(ctx context.Context, req *pb.TaskRecurrence) (*pb.TaskExportData, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty recurrence request")
+	}
+
+	updatedTask, err := s.service.SetTaskRecurrence(ctx, int(req.GetId()), recurrenceFromPB(req.GetRecurrence()))
+	if err != nil {
+		return nil, statusError("set task recurrence", err)
+	}
+
+	return taskExportDataToPB(updatedTask), nil
+}
+
+func (s *Server) SkipOccurrence(ctx context.Context, id *pb.TaskId) (*pb.TaskExportData, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty id")
+	}
+
+	updatedTask, err := s.service.SkipOccurrence(ctx, taskIdFromPB(id))
+	if err != nil {
+		return nil, statusError("skip occurrence", err)
+	}
+
+	return taskExportDataToPB(updatedTask), nil
+}
+
+func (s *Server) MoveTask(ctx context.Context, req *pb.MoveTaskRequest) (*pb.TaskExportData, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty move request")
+	}
+
+	movedTask, err := s.service.MoveTask(ctx, taskMoveFromPB(req))
+	if err != nil {
+		return nil, statusError("move task", err)
+	}
+
+	return taskExportDataToPB(movedTask), nil
+}
+
+func (s *Server) AddTaskTags(ctx context.Context, req *pb.TaskTags) (*pb.TaskExportData, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty tags request")
+	}
+
+	updatedTask, err := s.service.AddTaskTags(ctx, int(req.GetId()), req.GetTags())
+	if err != nil {
+		return nil, statusError("add task tags", err)
+	}
+
+	return taskExportDataToPB(updatedTask), nil
+}
+
+func (s *Server) RemoveTaskTags(ctx context.Context, req *pb.TaskTags) (*pb.TaskExportData, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty tags request")
+	}
+
+	updatedTask, err := s.service.RemoveTaskTags(ctx, int(req.GetId()), req.GetTags())
+	if err != nil {
+		return nil, statusError("remove task tags", err)
 	}
 
 	return taskExportDataToPB(updatedTask), nil
 }
+
+func (s *Server) ListTags(ctx context.Context, _ *emptypb.Empty) (*pb.TagList, error) {
+	tags, err := s.service.ListTags(ctx)
+	if err != nil {
+		return nil, statusError("list tags", err)
+	}
+
+	return tagListToPB(tags), nil
+}
+
+func (s *Server) RenameTag(ctx context.Context, req *pb.RenameTagRequest) (*pb.TagChange, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty rename request")
+	}
+
+	change, err := s.service.RenameTag(ctx, req.GetName(), req.GetNewName())
+	if err != nil {
+		return nil, statusError("rename tag", err)
+	}
+
+	return tagChangeToPB(change), nil
+}
+
+func (s *Server) MergeTags(ctx context.Context, req *pb.MergeTagsRequest) (*pb.TagChange, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty merge request")
+	}
+
+	change, err := s.service.MergeTags(ctx, req.GetSources(), req.GetTarget())
+	if err != nil {
+		return nil, statusError("merge tags", err)
+	}
+
+	return tagChangeToPB(change), nil
+}
+
+func (s *Server) CreateProject(ctx context.Context, req *pb.CreateProjectRequest) (*pb.Project, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty project")
+	}
+
+	project, err := s.service.CreateProject(ctx, req.GetName())
+	if err != nil {
+		return nil, statusError("create project", err)
+	}
+
+	return projectToPB(project), nil
+}
+
+func (s *Server) ListProjects(ctx context.Context, req *pb.ListProjectsRequest) (*pb.ProjectList, error) {
+	projects, err := s.service.ListProjects(ctx, req.GetIncludeArchived())
+	if err != nil {
+		return nil, statusError("list projects", err)
+	}
+
+	return projectListToPB(projects), nil
+}
+
+func (s *Server) RenameProject(ctx context.Context, req *pb.RenameProjectRequest) (*pb.Project, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty rename request")
+	}
+
+	project, err := s.service.RenameProject(ctx, int(req.GetId()), req.GetName())
+	if err != nil {
+		return nil, statusError("rename project", err)
+	}
+
+	return projectToPB(project), nil
+}
+
+func (s *Server) ArchiveProject(ctx context.Context, req *pb.ArchiveProjectRequest) (*pb.Project, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty archive request")
+	}
+
+	project, err := s.service.ArchiveProject(ctx, int(req.GetId()), req.GetArchived())
+	if err != nil {
+		return nil, statusError("archive project", err)
+	}
+
+	return projectToPB(project), nil
+}
+
+func (s *Server) DeleteProject(ctx context.Context, id *pb.ProjectId) (*emptypb.Empty, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty project id")
+	}
+
+	if err := s.service.DeleteProject(ctx, int(id.GetId())); err != nil {
+		return nil, statusError("delete project", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) MoveTaskToProject(ctx context.Context, req *pb.TaskProject) (*pb.TaskExportData, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty task project")
+	}
+
+	movedTask, err := s.service.MoveTaskToProject(ctx, int(req.GetId()), int(req.GetProjectId()))
+	if err != nil {
+		return nil, statusError("move task to project", err)
+	}
+
+	return taskExportDataToPB(movedTask), nil
+}
+
+func (s *Server) GetStats(ctx context.Context, req *pb.StatsRequest) (*pb.Stats, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty stats request")
+	}
+
+	stats, err := s.service.GetStats(ctx, statsRequestFromPB(req))
+	if err != nil {
+		return nil, statusError("get stats", err)
+	}
+
+	return statsToPB(stats), nil
+}
+
+func (s *Server) GetTaskHistory(ctx context.Context, req *pb.TaskHistoryRequest) (*pb.TaskHistoryPage, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty history request")
+	}
+
+	page, err := s.service.GetTaskHistory(ctx, taskHistoryRequestFromPB(req))
+	if err != nil {
+		return nil, statusError("get task history", err)
+	}
+
+	return taskHistoryPageToPB(page), nil
+}
+
+func (s *Server) BatchAddTasks(ctx context.Context, req *pb.BatchAddTasksRequest) (*pb.BatchResult, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty batch")
+	}
+
+	tasks := make([]models.TaskImportData, 0, len(req.GetTasks()))
+	for _, task := range req.GetTasks() {
+		tasks = append(tasks, taskImportDataFromPB(task))
+	}
+
+	items, err := s.service.BatchAddTasks(ctx, tasks)
+	if err != nil {
+		return nil, statusError("batch add tasks", err)
+	}
+
+	return batchResultToPB(items, "add task"), nil
+}
+
+func (s *Server) BatchMarkFinished(ctx context.Context, req *pb.TaskIds) (*pb.BatchResult, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty batch")
+	}
+
+	items, err := s.service.BatchMarkFinished(ctx, taskIdsFromPB(req))
+	if err != nil {
+		return nil, statusError("batch finish tasks", err)
+	}
+
+	return batchResultToPB(items, "finish task"), nil
+}
+
+func (s *Server) BatchRemoveTasks(ctx context.Context, req *pb.TaskIds) (*pb.BatchResult, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "received empty batch")
+	}
+
+	items, err := s.service.BatchRemoveTasks(ctx, taskIdsFromPB(req))
+	if err != nil {
+		return nil, statusError("batch remove tasks", err)
+	}
+
+	return batchResultToPB(items, "remove task"), nil
+}
@@ -1,8 +1,12 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,9 +14,13 @@ import (
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type fakeRepo struct {
@@ -25,6 +33,7 @@ type fakeRepo struct {
 	deleteTaskCalls int
 	deleteTaskCtx   context.Context
 	deleteTaskIn    int
+	deleteTaskRet   []int
 	deleteTaskErr   error
 
 	listAllTasksCalls int
@@ -38,6 +47,198 @@ type fakeRepo struct {
 	markTaskFinishedRet   models.TaskExportData
 	markTaskFinishedErr   error
 
+	getStatsCalls int
+	getStatsCtx   context.Context
+	getStatsIn    models.StatsRequest
+	getStatsRet   models.Stats
+	getStatsErr   error
+
+	setTaskPriorityCalls int
+	setTaskPriorityCtx   context.Context
+	setTaskPriorityId    int
+	setTaskPriorityIn    models.Priority
+	setTaskPriorityRet   models.TaskExportData
+	setTaskPriorityErr   error
+
+	moveTaskCalls int
+	moveTaskCtx   context.Context
+	moveTaskIn    models.TaskMove
+	moveTaskRet   models.TaskExportData
+	moveTaskErr   error
+
+	addTaskTagsCalls int
+	addTaskTagsCtx   context.Context
+	addTaskTagsId    int
+	addTaskTagsTags  []string
+	addTaskTagsRet   models.TaskExportData
+	addTaskTagsErr   error
+
+	removeTaskTagsCalls int
+	removeTaskTagsCtx   context.Context
+	removeTaskTagsId    int
+	removeTaskTagsTags  []string
+	removeTaskTagsRet   models.TaskExportData
+	removeTaskTagsErr   error
+
+	listTagsCalls int
+	listTagsCtx   context.Context
+	listTagsRet   []models.TagUsage
+	listTagsErr   error
+
+	renameTagCalls   int
+	renameTagCtx     context.Context
+	renameTagName    string
+	renameTagNewName string
+	renameTagRet     models.TagChange
+	renameTagErr     error
+
+	mergeTagsCalls   int
+	mergeTagsCtx     context.Context
+	mergeTagsSources []string
+	mergeTagsTarget  string
+	mergeTagsRet     models.TagChange
+	mergeTagsErr     error
+
+	createProjectCalls int
+	createProjectCtx   context.Context
+	createProjectIn    string
+	createProjectRet   models.Project
+	createProjectErr   error
+
+	listProjectsCalls int
+	listProjectsCtx   context.Context
+	listProjectsIn    bool
+	listProjectsRet   []models.Project
+	listProjectsErr   error
+
+	renameProjectCalls int
+	renameProjectCtx   context.Context
+	renameProjectId    int
+	renameProjectName  string
+	renameProjectRet   models.Project
+	renameProjectErr   error
+
+	archiveProjectCalls    int
+	archiveProjectCtx      context.Context
+	archiveProjectId       int
+	archiveProjectArchived bool
+	archiveProjectRet      models.Project
+	archiveProjectErr      error
+
+	deleteProjectCalls int
+	deleteProjectCtx   context.Context
+	deleteProjectIn    int
+	deleteProjectRet   []int
+	deleteProjectErr   error
+
+	moveTaskToProjectCalls     int
+	moveTaskToProjectCtx       context.Context
+	moveTaskToProjectId        int
+	moveTaskToProjectProjectId int
+	moveTaskToProjectRet       models.TaskExportData
+	moveTaskToProjectErr       error
+
+	getTaskCalls int
+	getTaskCtx   context.Context
+	getTaskIn    int
+	getTaskRet   models.TaskExportData
+	getTaskErr   error
+
+	listSubtasksCalls int
+	listSubtasksCtx   context.Context
+	listSubtasksIn    int
+	listSubtasksRet   []models.TaskExportData
+	listSubtasksErr   error
+
+	setTaskRecurrenceCalls int
+	setTaskRecurrenceCtx   context.Context
+	setTaskRecurrenceId    int
+	setTaskRecurrenceRule  *models.Recurrence
+	setTaskRecurrenceRet   models.TaskExportData
+	setTaskRecurrenceErr   error
+
+	skipOccurrenceCalls int
+	skipOccurrenceCtx   context.Context
+	skipOccurrenceIn    int
+	skipOccurrenceRet   models.TaskExportData
+	skipOccurrenceErr   error
+
+	reopenTaskCalls int
+	reopenTaskCtx   context.Context
+	reopenTaskIn    int
+	reopenTaskRet   models.TaskExportData
+	reopenTaskErr   error
+
+	listTrashCalls int
+	listTrashCtx   context.Context
+	listTrashRet   []models.TaskExportData
+	listTrashErr   error
+
+	restoreTaskCalls int
+	restoreTaskCtx   context.Context
+	restoreTaskIn    int
+	restoreTaskRet   models.TaskExportData
+	restoreTaskErr   error
+
+	purgeTaskCalls int
+	purgeTaskCtx   context.Context
+	purgeTaskIn    int
+	purgeTaskErr   error
+
+	purgeTrashCalls int
+	purgeTrashCtx   context.Context
+	purgeTrashIn    time.Time
+	purgeTrashRet   int
+	purgeTrashErr   error
+
+	getTaskHistoryCalls int
+	getTaskHistoryCtx   context.Context
+	getTaskHistoryIn    models.TaskHistoryRequest
+	getTaskHistoryRet   models.TaskHistoryPage
+	getTaskHistoryErr   error
+
+	purgeKeysCalls int
+	purgeKeysCtx   context.Context
+	purgeKeysIn    time.Time
+	purgeKeysRet   int
+	purgeKeysErr   error
+
+	batchAddCalls int
+	batchAddCtx   context.Context
+	batchAddIn    []models.TaskImportData
+	batchAddRet   []models.BatchItem
+	batchAddErr   error
+
+	batchFinishCalls int
+	batchFinishCtx   context.Context
+	batchFinishIn    []int
+	batchFinishRet   []models.BatchItem
+	batchFinishErr   error
+
+	batchRemoveCalls int
+	batchRemoveCtx   context.Context
+	batchRemoveIn    []int
+	batchRemoveRet   []models.BatchItem
+	batchRemoveErr   error
+
+	searchCalls int
+	searchCtx   context.Context
+	searchIn    models.SearchRequest
+	searchRet   []models.SearchHit
+	searchErr   error
+
+	getChangesCalls int
+	getChangesCtx   context.Context
+	getChangesIn    models.ChangesRequest
+	getChangesRet   models.Changes
+	getChangesErr   error
+
+	getTasksCalls int
+	getTasksCtx   context.Context
+	getTasksIn    []int
+	getTasksRet   []models.TaskExportData
+	getTasksErr   error
+
 	closeCalled int
 	closeErr    error
 }
@@ -49,11 +250,11 @@ func (f *fakeRepo) AddTask(ctx context.Context, task models.TaskImportData) (mod
 	return f.addTaskRet, f.addTaskErr
 }
 
-func (f *fakeRepo) DeleteTask(ctx context.Context, id int) error {
+func (f *fakeRepo) DeleteTask(ctx context.Context, id int) ([]int, error) {
 	f.deleteTaskCalls++
 	f.deleteTaskCtx = ctx
 	f.deleteTaskIn = id
-	return f.deleteTaskErr
+	return f.deleteTaskRet, f.deleteTaskErr
 }
 
 func (f *fakeRepo) ListAllTasks(ctx context.Context) ([]models.TaskExportData, error) {
@@ -69,6 +270,230 @@ func (f *fakeRepo) MarkTaskFinished(ctx context.Context, id int) (models.TaskExp
 	return f.markTaskFinishedRet, f.markTaskFinishedErr
 }
 
+func (f *fakeRepo) GetStats(ctx context.Context, req models.StatsRequest) (models.Stats, error) {
+	f.getStatsCalls++
+	f.getStatsCtx = ctx
+	f.getStatsIn = req
+	return f.getStatsRet, f.getStatsErr
+}
+
+func (f *fakeRepo) SetTaskPriority(ctx context.Context, id int, priority models.Priority) (models.TaskExportData, error) {
+	f.setTaskPriorityCalls++
+	f.setTaskPriorityCtx = ctx
+	f.setTaskPriorityId = id
+	f.setTaskPriorityIn = priority
+	return f.setTaskPriorityRet, f.setTaskPriorityErr
+}
+
+func (f *fakeRepo) MoveTask(ctx context.Context, move models.TaskMove) (models.TaskExportData, error) {
+	f.moveTaskCalls++
+	f.moveTaskCtx = ctx
+	f.moveTaskIn = move
+	return f.moveTaskRet, f.moveTaskErr
+}
+
+func (f *fakeRepo) AddTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	f.addTaskTagsCalls++
+	f.addTaskTagsCtx = ctx
+	f.addTaskTagsId = id
+	f.addTaskTagsTags = tags
+	return f.addTaskTagsRet, f.addTaskTagsErr
+}
+
+func (f *fakeRepo) RemoveTaskTags(ctx context.Context, id int, tags []string) (models.TaskExportData, error) {
+	f.removeTaskTagsCalls++
+	f.removeTaskTagsCtx = ctx
+	f.removeTaskTagsId = id
+	f.removeTaskTagsTags = tags
+	return f.removeTaskTagsRet, f.removeTaskTagsErr
+}
+
+func (f *fakeRepo) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	f.listTagsCalls++
+	f.listTagsCtx = ctx
+	return f.listTagsRet, f.listTagsErr
+}
+
+func (f *fakeRepo) RenameTag(ctx context.Context, name string, newName string) (models.TagChange, error) {
+	f.renameTagCalls++
+	f.renameTagCtx = ctx
+	f.renameTagName = name
+	f.renameTagNewName = newName
+	return f.renameTagRet, f.renameTagErr
+}
+
+func (f *fakeRepo) MergeTags(ctx context.Context, sources []string, target string) (models.TagChange, error) {
+	f.mergeTagsCalls++
+	f.mergeTagsCtx = ctx
+	f.mergeTagsSources = sources
+	f.mergeTagsTarget = target
+	return f.mergeTagsRet, f.mergeTagsErr
+}
+
+func (f *fakeRepo) CreateProject(ctx context.Context, name string) (models.Project, error) {
+	f.createProjectCalls++
+	f.createProjectCtx = ctx
+	f.createProjectIn = name
+	return f.createProjectRet, f.createProjectErr
+}
+
+func (f *fakeRepo) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	f.listProjectsCalls++
+	f.listProjectsCtx = ctx
+	f.listProjectsIn = includeArchived
+	return f.listProjectsRet, f.listProjectsErr
+}
+
+func (f *fakeRepo) RenameProject(ctx context.Context, id int, name string) (models.Project, error) {
+	f.renameProjectCalls++
+	f.renameProjectCtx = ctx
+	f.renameProjectId = id
+	f.renameProjectName = name
+	return f.renameProjectRet, f.renameProjectErr
+}
+
+func (f *fakeRepo) ArchiveProject(ctx context.Context, id int, archived bool) (models.Project, error) {
+	f.archiveProjectCalls++
+	f.archiveProjectCtx = ctx
+	f.archiveProjectId = id
+	f.archiveProjectArchived = archived
+	return f.archiveProjectRet, f.archiveProjectErr
+}
+
+func (f *fakeRepo) DeleteProject(ctx context.Context, id int) ([]int, error) {
+	f.deleteProjectCalls++
+	f.deleteProjectCtx = ctx
+	f.deleteProjectIn = id
+	return f.deleteProjectRet, f.deleteProjectErr
+}
+
+func (f *fakeRepo) MoveTaskToProject(ctx context.Context, id, projectId int) (models.TaskExportData, error) {
+	f.moveTaskToProjectCalls++
+	f.moveTaskToProjectCtx = ctx
+	f.moveTaskToProjectId = id
+	f.moveTaskToProjectProjectId = projectId
+	return f.moveTaskToProjectRet, f.moveTaskToProjectErr
+}
+
+func (f *fakeRepo) GetTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.getTaskCalls++
+	f.getTaskCtx = ctx
+	f.getTaskIn = id
+	return f.getTaskRet, f.getTaskErr
+}
+
+func (f *fakeRepo) ListSubtasks(ctx context.Context, id int) ([]models.TaskExportData, error) {
+	f.listSubtasksCalls++
+	f.listSubtasksCtx = ctx
+	f.listSubtasksIn = id
+	return f.listSubtasksRet, f.listSubtasksErr
+}
+
+func (f *fakeRepo) SetTaskRecurrence(ctx context.Context, id int, rule *models.Recurrence) (models.TaskExportData, error) {
+	f.setTaskRecurrenceCalls++
+	f.setTaskRecurrenceCtx = ctx
+	f.setTaskRecurrenceId = id
+	f.setTaskRecurrenceRule = rule
+	return f.setTaskRecurrenceRet, f.setTaskRecurrenceErr
+}
+
+func (f *fakeRepo) SkipOccurrence(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.skipOccurrenceCalls++
+	f.skipOccurrenceCtx = ctx
+	f.skipOccurrenceIn = id
+	return f.skipOccurrenceRet, f.skipOccurrenceErr
+}
+
+func (f *fakeRepo) ReopenTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.reopenTaskCalls++
+	f.reopenTaskCtx = ctx
+	f.reopenTaskIn = id
+	return f.reopenTaskRet, f.reopenTaskErr
+}
+
+func (f *fakeRepo) ListTrash(ctx context.Context) ([]models.TaskExportData, error) {
+	f.listTrashCalls++
+	f.listTrashCtx = ctx
+	return f.listTrashRet, f.listTrashErr
+}
+
+func (f *fakeRepo) RestoreTask(ctx context.Context, id int) (models.TaskExportData, error) {
+	f.restoreTaskCalls++
+	f.restoreTaskCtx = ctx
+	f.restoreTaskIn = id
+	return f.restoreTaskRet, f.restoreTaskErr
+}
+
+func (f *fakeRepo) PurgeTask(ctx context.Context, id int) error {
+	f.purgeTaskCalls++
+	f.purgeTaskCtx = ctx
+	f.purgeTaskIn = id
+	return f.purgeTaskErr
+}
+
+func (f *fakeRepo) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	f.purgeTrashCalls++
+	f.purgeTrashCtx = ctx
+	f.purgeTrashIn = before
+	return f.purgeTrashRet, f.purgeTrashErr
+}
+
+func (f *fakeRepo) GetTaskHistory(ctx context.Context, req models.TaskHistoryRequest) (models.TaskHistoryPage, error) {
+	f.getTaskHistoryCalls++
+	f.getTaskHistoryCtx = ctx
+	f.getTaskHistoryIn = req
+	return f.getTaskHistoryRet, f.getTaskHistoryErr
+}
+
+func (f *fakeRepo) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int, error) {
+	f.purgeKeysCalls++
+	f.purgeKeysCtx = ctx
+	f.purgeKeysIn = now
+	return f.purgeKeysRet, f.purgeKeysErr
+}
+
+func (f *fakeRepo) BatchAddTasks(ctx context.Context, tasks []models.TaskImportData) ([]models.BatchItem, error) {
+	f.batchAddCalls++
+	f.batchAddCtx = ctx
+	f.batchAddIn = tasks
+	return f.batchAddRet, f.batchAddErr
+}
+
+func (f *fakeRepo) BatchMarkFinished(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	f.batchFinishCalls++
+	f.batchFinishCtx = ctx
+	f.batchFinishIn = ids
+	return f.batchFinishRet, f.batchFinishErr
+}
+
+func (f *fakeRepo) BatchRemoveTasks(ctx context.Context, ids []int) ([]models.BatchItem, error) {
+	f.batchRemoveCalls++
+	f.batchRemoveCtx = ctx
+	f.batchRemoveIn = ids
+	return f.batchRemoveRet, f.batchRemoveErr
+}
+
+func (f *fakeRepo) SearchTasks(ctx context.Context, req models.SearchRequest) ([]models.SearchHit, error) {
+	f.searchCalls++
+	f.searchCtx = ctx
+	f.searchIn = req
+	return f.searchRet, f.searchErr
+}
+
+func (f *fakeRepo) GetChangesSince(ctx context.Context, req models.ChangesRequest) (models.Changes, error) {
+	f.getChangesCalls++
+	f.getChangesCtx = ctx
+	f.getChangesIn = req
+	return f.getChangesRet, f.getChangesErr
+}
+
+func (f *fakeRepo) GetTasks(ctx context.Context, ids []int) ([]models.TaskExportData, error) {
+	f.getTasksCalls++
+	f.getTasksCtx = ctx
+	f.getTasksIn = ids
+	return f.getTasksRet, f.getTasksErr
+}
+
 func (f *fakeRepo) Close() error {
 	f.closeCalled++
 	return f.closeErr
@@ -170,13 +595,13 @@ func TestRemoveTask_ServiceError_ReturnsInternalError(t *testing.T) {
 	}
 }
 
-func TestRemoveTask_OK_ReturnsNil(t *testing.T) {
+func TestRemoveTask_OK_ReturnsEmpty(t *testing.T) {
 	srv := NewServer(app.NewService(&fakeRepo{}))
 
 	got, err := srv.RemoveTask(context.Background(), &pb.TaskId{Id: int64(2)})
 
-	if got != nil {
-		t.Fatalf("expected nil, got %v", got)
+	if got == nil {
+		t.Fatalf("expected an empty response, got nil")
 	}
 	if err != nil {
 		t.Fatalf("expected nil, got %v", err)
@@ -224,6 +649,17 @@ func TestListAllTasks_OK_ReturnsTaskListAndNil(t *testing.T) {
 	}
 }
 
+func TestListAllTasks_MainDBDown_ReturnsUnavailable(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	srv := NewServer(app.NewService(&fakeRepo{listAllTasksErr: dialErr}))
+
+	_, err := srv.ListAllTasks(context.Background(), nil)
+
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Unavailable, err)
+	}
+}
+
 func TestListAllTasks_ServiceError_ReturnsInternalErrorAndNilTaskList(t *testing.T) {
 	wantErr := errors.New("boom")
 	srv := NewServer(app.NewService(&fakeRepo{listAllTasksErr: wantErr}))
@@ -294,3 +730,1053 @@ func TestMarkTaskFinished_ServiceError_ReturnsInternal(t *testing.T) {
 		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Internal, err)
 	}
 }
+
+func TestGetStats_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.GetStats(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestGetStats_BadRequest_ReturnsInvalidArgument(t *testing.T) {
+	fr := &fakeRepo{}
+	srv := NewServer(app.NewService(fr))
+
+	_, err := srv.GetStats(context.Background(), &pb.StatsRequest{TimeZone: "Mars/Olympus"})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+	if fr.getStatsCalls != 0 {
+		t.Fatalf("expected GetStats not called, got=%d", fr.getStatsCalls)
+	}
+}
+
+func TestGetStats_OK_DelegatesToService(t *testing.T) {
+	ctx := context.Background()
+	fromTS := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	toTS := time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC)
+	wantStats := models.Stats{
+		Points: []models.StatsPoint{
+			{Start: fromTS, Created: 2, Completed: 1},
+			{Start: fromTS.AddDate(0, 0, 1), Created: 1, Completed: 0},
+		},
+		CreatedTotal:   3,
+		CompletedTotal: 1,
+		OpenCount:      2,
+	}
+	fr := &fakeRepo{getStatsRet: wantStats}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.GetStats(ctx, &pb.StatsRequest{
+		From:     timestamppb.New(fromTS),
+		To:       timestamppb.New(toTS),
+		Bucket:   pb.StatsBucket_STATS_BUCKET_DAY,
+		TimeZone: "UTC",
+	})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.getStatsCalls != 1 {
+		t.Fatalf("expected GetStats calls=1, got=%d", fr.getStatsCalls)
+	}
+	if fr.getStatsCtx != ctx {
+		t.Fatal("context mismatch")
+	}
+	if !fr.getStatsIn.From.Equal(fromTS) || !fr.getStatsIn.To.Equal(toTS) {
+		t.Fatalf("unexpected stats range %v - %v", fr.getStatsIn.From, fr.getStatsIn.To)
+	}
+	if diff := cmp.Diff(got, statsToPB(wantStats), protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestGetStats_ServiceError_ReturnsInternal(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{getStatsErr: errors.New("boom")}))
+
+	got, err := srv.GetStats(context.Background(), &pb.StatsRequest{})
+
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Internal, err)
+	}
+}
+
+func TestListTasks_OK_ReturnsFilteredTaskList(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	fr := &fakeRepo{listAllTasksRet: []models.TaskExportData{
+		{Id: 1, Title: "overdue", DueAt: &past},
+		{Id: 2, Title: "no due date"},
+	}}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.ListTasks(ctx, &pb.TaskFilter{Due: pb.DueFilter_DUE_FILTER_OVERDUE})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.listAllTasksCtx != ctx {
+		t.Fatal("context mismatch")
+	}
+	if len(got.GetTasks()) != 1 || got.GetTasks()[0].GetId() != 1 || !got.GetTasks()[0].GetOverdue() {
+		t.Fatalf("expected only overdue task 1, got %v", got)
+	}
+}
+
+func TestListTasks_BadTimeZone_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.ListTasks(context.Background(), &pb.TaskFilter{
+		Due:      pb.DueFilter_DUE_FILTER_TODAY,
+		TimeZone: "Mars/Olympus",
+	})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestListTasks_ServiceError_ReturnsInternal(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{listAllTasksErr: errors.New("boom")}))
+
+	got, err := srv.ListTasks(context.Background(), nil)
+
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Internal, err)
+	}
+}
+
+func TestSetTaskPriority_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.SetTaskPriority(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestSetTaskPriority_OK_DelegatesToService(t *testing.T) {
+	ctx := context.Background()
+	wantTaskOut := models.TaskExportData{Id: 12, Title: "my title", Priority: models.PriorityHigh}
+	fr := &fakeRepo{setTaskPriorityRet: wantTaskOut}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.SetTaskPriority(ctx, &pb.TaskPriority{Id: 12, Priority: pb.Priority_PRIORITY_HIGH})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.setTaskPriorityCtx != ctx {
+		t.Fatal("context mismatch")
+	}
+	if fr.setTaskPriorityId != 12 || fr.setTaskPriorityIn != models.PriorityHigh {
+		t.Fatalf("unexpected args: id=%d priority=%d", fr.setTaskPriorityId, fr.setTaskPriorityIn)
+	}
+	if diff := cmp.Diff(got, taskExportDataToPB(wantTaskOut), protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestSetTaskPriority_TaskNotFound_ReturnsNotFound(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{setTaskPriorityErr: app.ErrTaskNotFound}))
+
+	_, err := srv.SetTaskPriority(context.Background(), &pb.TaskPriority{Id: 12})
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.NotFound, err)
+	}
+}
+
+func TestSetTaskPriority_VersionMismatch_ReturnsAborted(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{setTaskPriorityErr: app.ErrVersionMismatch}))
+
+	_, err := srv.SetTaskPriority(context.Background(), &pb.TaskPriority{Id: 12, ExpectedVersion: 3})
+
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Aborted, err)
+	}
+}
+
+func TestAddTask_IdempotencyKeyReused_ReturnsAlreadyExists(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{addTaskErr: app.ErrIdempotencyKeyReused}))
+
+	_, err := srv.AddTask(context.Background(), &pb.TaskImportData{Title: "my title", IdempotencyKey: "abc"})
+
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.AlreadyExists, err)
+	}
+}
+
+func TestMoveTask_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.MoveTask(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestMoveTask_BothAnchors_ReturnsInvalidArgument(t *testing.T) {
+	fr := &fakeRepo{}
+	srv := NewServer(app.NewService(fr))
+
+	_, err := srv.MoveTask(context.Background(), &pb.MoveTaskRequest{Id: 1, BeforeId: 2, AfterId: 3})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+	if fr.moveTaskCalls != 0 {
+		t.Fatalf("expected MoveTask not called, got %d", fr.moveTaskCalls)
+	}
+}
+
+func TestMoveTask_OK_DelegatesToService(t *testing.T) {
+	ctx := context.Background()
+	wantTaskOut := models.TaskExportData{Id: 1, Title: "my title", Position: "ai"}
+	fr := &fakeRepo{moveTaskRet: wantTaskOut}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.MoveTask(ctx, &pb.MoveTaskRequest{Id: 1, AfterId: 2})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.moveTaskCtx != ctx {
+		t.Fatal("context mismatch")
+	}
+	if fr.moveTaskIn != (models.TaskMove{Id: 1, AfterId: 2}) {
+		t.Fatalf("unexpected move %+v", fr.moveTaskIn)
+	}
+	if diff := cmp.Diff(got, taskExportDataToPB(wantTaskOut), protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestAddTaskTags_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.AddTaskTags(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestAddTaskTags_OK_DelegatesToService(t *testing.T) {
+	ctx := context.Background()
+	wantTaskOut := models.TaskExportData{Id: 5, Title: "my title", Tags: []string{"home"}}
+	fr := &fakeRepo{addTaskTagsRet: wantTaskOut}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.AddTaskTags(ctx, &pb.TaskTags{Id: 5, Tags: []string{"Home"}})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.addTaskTagsCtx != ctx || fr.addTaskTagsId != 5 {
+		t.Fatalf("unexpected call: id=%d", fr.addTaskTagsId)
+	}
+	if diff := cmp.Diff(got, taskExportDataToPB(wantTaskOut), protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestRemoveTaskTags_TaskNotFound_ReturnsNotFound(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{removeTaskTagsErr: app.ErrTaskNotFound}))
+
+	_, err := srv.RemoveTaskTags(context.Background(), &pb.TaskTags{Id: 5, Tags: []string{"home"}})
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.NotFound, err)
+	}
+}
+
+func TestListTags_OK_ReturnsTagList(t *testing.T) {
+	fr := &fakeRepo{listTagsRet: []models.TagUsage{{Name: "home", Count: 2}, {Name: "work", Count: 0}}}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.ListTags(context.Background(), &emptypb.Empty{})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	want := &pb.TagList{Tags: []*pb.TagUsage{{Name: "home", Count: 2}, {Name: "work", Count: 0}}}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestRenameTag_NameTaken_ReturnsAlreadyExists(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{renameTagErr: app.ErrTagAlreadyExists}))
+
+	_, err := srv.RenameTag(context.Background(), &pb.RenameTagRequest{Name: "work", NewName: "job"})
+
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.AlreadyExists, err)
+	}
+}
+
+func TestMergeTags_OK_ReturnsTagChange(t *testing.T) {
+	fr := &fakeRepo{mergeTagsRet: models.TagChange{Name: "work", TaskIds: []int{1, 4}}}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.MergeTags(context.Background(), &pb.MergeTagsRequest{Sources: []string{"job"}, Target: "work"})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	want := &pb.TagChange{Name: "work", TaskIds: []int64{1, 4}}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestMergeTags_TagNotFound_ReturnsNotFound(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{mergeTagsErr: app.ErrTagNotFound}))
+
+	_, err := srv.MergeTags(context.Background(), &pb.MergeTagsRequest{Sources: []string{"job"}, Target: "work"})
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.NotFound, err)
+	}
+}
+
+func TestCreateProject_NameTaken_ReturnsAlreadyExists(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{createProjectErr: app.ErrProjectExists}))
+
+	_, err := srv.CreateProject(context.Background(), &pb.CreateProjectRequest{Name: "Work"})
+
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.AlreadyExists, err)
+	}
+}
+
+func TestListProjects_OK_ReturnsProjectList(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	fr := &fakeRepo{listProjectsRet: []models.Project{
+		{Id: 1, Name: "Inbox", Inbox: true, CreatedAt: createdAt},
+		{Id: 2, Name: "Old", Archived: true, CreatedAt: createdAt},
+	}}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.ListProjects(context.Background(), &pb.ListProjectsRequest{IncludeArchived: true})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !fr.listProjectsIn {
+		t.Fatalf("expected archived projects to be requested")
+	}
+	want := &pb.ProjectList{Projects: []*pb.Project{
+		{Id: 1, Name: "Inbox", Inbox: true, CreatedAt: timestamppb.New(createdAt)},
+		{Id: 2, Name: "Old", Archived: true, CreatedAt: timestamppb.New(createdAt)},
+	}}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestArchiveProject_Inbox_ReturnsFailedPrecondition(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{archiveProjectErr: app.ErrInboxProtected}))
+
+	_, err := srv.ArchiveProject(context.Background(), &pb.ArchiveProjectRequest{Id: 1, Archived: true})
+
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.FailedPrecondition, err)
+	}
+}
+
+func TestDeleteProject_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.DeleteProject(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestMoveTaskToProject_ProjectNotFound_ReturnsNotFound(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{moveTaskToProjectErr: app.ErrProjectNotFound}))
+
+	_, err := srv.MoveTaskToProject(context.Background(), &pb.TaskProject{Id: 1, ProjectId: 7})
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.NotFound, err)
+	}
+}
+
+func TestGetTaskTree_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.GetTaskTree(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestGetTaskTree_OK_ReturnsNestedSubtasks(t *testing.T) {
+	fr := &fakeRepo{
+		getTaskRet:      models.TaskExportData{Id: 1, SubtasksTotal: 1, SubtasksDone: 1},
+		listSubtasksRet: []models.TaskExportData{{Id: 2, ParentId: 1, Finished: true}},
+	}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.GetTaskTree(context.Background(), &pb.TaskId{Id: 1})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	want := &pb.TaskTree{
+		Task: &pb.TaskExportData{Id: 1, SubtasksTotal: 1, SubtasksDone: 1},
+		Subtasks: []*pb.TaskTree{
+			{Task: &pb.TaskExportData{Id: 2, ParentId: 1, Finished: true}},
+		},
+	}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestAddTask_TooDeep_ReturnsFailedPrecondition(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{addTaskErr: app.ErrTaskTooDeep}))
+
+	_, err := srv.AddTask(context.Background(), &pb.TaskImportData{Title: "step", ParentId: 3})
+
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.FailedPrecondition, err)
+	}
+}
+
+func TestSetTaskRecurrence_OK_PassesRule(t *testing.T) {
+	fr := &fakeRepo{setTaskRecurrenceRet: models.TaskExportData{Id: 4}}
+	srv := NewServer(app.NewService(fr))
+
+	_, err := srv.SetTaskRecurrence(context.Background(), &pb.TaskRecurrence{
+		Id: 4,
+		Recurrence: &pb.Recurrence{
+			Frequency: pb.RecurrenceFrequency_RECURRENCE_FREQUENCY_WEEKLY,
+			Weekdays:  []int32{5, 1},
+			TimeZone:  "Europe/Moscow",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	want := &models.Recurrence{
+		Frequency: models.RecurrenceWeekly,
+		Interval:  1,
+		Weekdays:  []time.Weekday{time.Monday, time.Friday},
+		TimeZone:  "Europe/Moscow",
+	}
+	if diff := cmp.Diff(want, fr.setTaskRecurrenceRule); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestSkipOccurrence_SeriesEnded_ReturnsFailedPrecondition(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{skipOccurrenceErr: app.ErrSeriesEnded}))
+
+	_, err := srv.SkipOccurrence(context.Background(), &pb.TaskId{Id: 4})
+
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.FailedPrecondition, err)
+	}
+}
+
+func TestReopenTask_NilId_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.ReopenTask(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestReopenTask_OK_DelegatesToService(t *testing.T) {
+	wantTaskOut := models.TaskExportData{Id: 34, Title: "my title", CreatedAt: time.Date(2025, 12, 10, 4, 6, 3, 2, time.UTC)}
+	fr := &fakeRepo{reopenTaskRet: wantTaskOut}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.ReopenTask(context.Background(), &pb.TaskId{Id: 34})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.reopenTaskCalls != 1 || fr.reopenTaskIn != 34 {
+		t.Fatalf("unexpected ReopenTask call: calls=%d id=%d", fr.reopenTaskCalls, fr.reopenTaskIn)
+	}
+	if diff := cmp.Diff(got, taskExportDataToPB(wantTaskOut), protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestReopenTask_NotFound_ReturnsNotFound(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{reopenTaskErr: app.ErrTaskNotFound}))
+
+	_, err := srv.ReopenTask(context.Background(), &pb.TaskId{Id: 4})
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.NotFound, err)
+	}
+}
+
+func TestListTrash_OK_ReturnsTrashedTasks(t *testing.T) {
+	deletedAtTS := time.Date(2025, 12, 10, 4, 6, 3, 0, time.UTC)
+	fr := &fakeRepo{listTrashRet: []models.TaskExportData{{Id: 3, DeletedAt: &deletedAtTS}}}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.ListTrash(context.Background(), &emptypb.Empty{})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if diff := cmp.Diff(got, taskSliceToPB(fr.listTrashRet), protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestRestoreTask_ParentInTrash_ReturnsFailedPrecondition(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{restoreTaskErr: app.ErrParentInTrash}))
+
+	_, err := srv.RestoreTask(context.Background(), &pb.TaskId{Id: 4})
+
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.FailedPrecondition, err)
+	}
+}
+
+func TestPurgeTask_OK_ReturnsEmpty(t *testing.T) {
+	fr := &fakeRepo{}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.PurgeTask(context.Background(), &pb.TaskId{Id: 4})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected empty response, got nil")
+	}
+	if fr.purgeTaskCalls != 1 || fr.purgeTaskIn != 4 {
+		t.Fatalf("unexpected PurgeTask call: calls=%d id=%d", fr.purgeTaskCalls, fr.purgeTaskIn)
+	}
+}
+
+func TestPurgeTask_NotInTrash_ReturnsFailedPrecondition(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{purgeTaskErr: app.ErrNotInTrash}))
+
+	_, err := srv.PurgeTask(context.Background(), &pb.TaskId{Id: 4})
+
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.FailedPrecondition, err)
+	}
+}
+
+func TestGetTaskHistory_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.GetTaskHistory(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestGetTaskHistory_BadRequest_ReturnsInvalidArgument(t *testing.T) {
+	fr := &fakeRepo{}
+	srv := NewServer(app.NewService(fr))
+
+	_, err := srv.GetTaskHistory(context.Background(), &pb.TaskHistoryRequest{TaskId: 1, Limit: 1000})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+	if fr.getTaskHistoryCalls != 0 {
+		t.Fatalf("expected GetTaskHistory not called, got=%d", fr.getTaskHistoryCalls)
+	}
+}
+
+func TestGetTaskHistory_OK_DelegatesToService(t *testing.T) {
+	ctx := context.Background()
+	wantPage := models.TaskHistoryPage{
+		Changes: []models.TaskChange{
+			{Id: 9, TaskId: 4, ChangedAt: time.Date(2025, 12, 2, 10, 0, 0, 0, time.UTC), ChangedBy: "bob", Field: "priority", OldValue: "0", NewValue: "2"},
+		},
+		NextCursor: 9,
+	}
+	fr := &fakeRepo{getTaskHistoryRet: wantPage}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.GetTaskHistory(ctx, &pb.TaskHistoryRequest{TaskId: 4, Limit: 1, Cursor: 15})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.getTaskHistoryCalls != 1 {
+		t.Fatalf("expected GetTaskHistory calls=1, got=%d", fr.getTaskHistoryCalls)
+	}
+	if fr.getTaskHistoryCtx != ctx {
+		t.Fatal("context mismatch")
+	}
+	wantReq := models.TaskHistoryRequest{TaskId: 4, Limit: 1, Cursor: 15}
+	if diff := cmp.Diff(fr.getTaskHistoryIn, wantReq); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := cmp.Diff(got, taskHistoryPageToPB(wantPage), protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestGetTaskHistory_NotFound_ReturnsNotFound(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{getTaskHistoryErr: app.ErrTaskNotFound}))
+
+	_, err := srv.GetTaskHistory(context.Background(), &pb.TaskHistoryRequest{TaskId: 4})
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.NotFound, err)
+	}
+}
+
+func TestActorInterceptor_PutsActorIntoContext(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "actor in metadata",
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs(actorMetadataKey, "alice")),
+			want: "alice",
+		},
+		{
+			name: "no metadata",
+			ctx:  context.Background(),
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := func(ctx context.Context, req any) (any, error) {
+				got = app.ActorFrom(ctx)
+				return nil, nil
+			}
+
+			if _, err := actorInterceptor(tt.ctx, nil, nil, handler); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected actor %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExpectedVersionInterceptor_PutsVersionIntoContext(t *testing.T) {
+	tests := []struct {
+		name string
+		req  any
+		want int
+	}{
+		{name: "versioned request", req: &pb.TaskPriority{Id: 1, ExpectedVersion: 4}, want: 4},
+		{name: "version not set", req: &pb.TaskId{Id: 1}, want: 0},
+		{name: "request without version", req: &pb.TaskHistoryRequest{TaskId: 1}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int
+			handler := func(ctx context.Context, req any) (any, error) {
+				got = app.ExpectedVersionFrom(ctx)
+				return nil, nil
+			}
+
+			if _, err := expectedVersionInterceptor(context.Background(), tt.req, nil, handler); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected version %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBatchRemoveTasks_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.BatchRemoveTasks(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestBatchMarkFinished_ReportsItemCodes(t *testing.T) {
+	fakeRepo := &fakeRepo{batchFinishRet: []models.BatchItem{
+		{Id: 1, Task: models.TaskExportData{Id: 1, Finished: true}},
+		{Id: 2, Err: app.ErrTaskNotFound},
+	}}
+	srv := NewServer(app.NewService(fakeRepo))
+
+	got, err := srv.BatchMarkFinished(context.Background(), &pb.TaskIds{Ids: []int64{1, 2}})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if diff := cmp.Diff([]int{1, 2}, fakeRepo.batchFinishIn); diff != "" {
+		t.Fatal(diff)
+	}
+	items := got.GetItems()
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	if items[0].GetCode() != int32(codes.OK) || !items[0].GetTask().GetFinished() {
+		t.Fatalf("unexpected first item %+v", items[0])
+	}
+	if items[1].GetId() != 2 || items[1].GetCode() != int32(codes.NotFound) || items[1].GetError() == "" || items[1].GetTask() != nil {
+		t.Fatalf("unexpected second item %+v", items[1])
+	}
+}
+
+func TestBatchAddTasks_EmptyBatch_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.BatchAddTasks(context.Background(), &pb.BatchAddTasksRequest{})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestSearchTasks_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.SearchTasks(context.Background(), nil)
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestSearchTasks_ReturnsHits(t *testing.T) {
+	fr := &fakeRepo{searchRet: []models.SearchHit{
+		{Task: models.TaskExportData{Id: 2, Title: "Buy milk"}, Rank: 0.1, TitleSnippet: "Buy <b>milk</b>", TextSnippet: "2 liters"},
+	}}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.SearchTasks(context.Background(), &pb.SearchRequest{Query: "milk", Limit: 5})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.searchIn.Query != "milk" || fr.searchIn.Limit != 5 {
+		t.Fatalf("unexpected request %+v", fr.searchIn)
+	}
+	hits := got.GetHits()
+	if len(hits) != 1 || hits[0].GetTask().GetId() != 2 || hits[0].GetRank() != 0.1 ||
+		hits[0].GetTitleSnippet() != "Buy <b>milk</b>" || hits[0].GetTextSnippet() != "2 liters" {
+		t.Fatalf("unexpected hits %+v", hits)
+	}
+}
+
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	sent   []*pb.TaskEvent
+}
+
+func (f *fakeWatchStream) Context() context.Context {
+	return f.ctx
+}
+
+// Send stops the watch after the first event.
+func (f *fakeWatchStream) Send(event *pb.TaskEvent) error {
+	f.sent = append(f.sent, event)
+	f.cancel()
+	return nil
+}
+
+func TestWatchTasks_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	err := srv.WatchTasks(nil, &fakeWatchStream{ctx: context.Background()})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v want=%v got=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestWatchTasks_OK_StreamsEventsAfterLastEventId(t *testing.T) {
+	svc := app.NewService(&fakeRepo{})
+	srv := NewServer(svc)
+	occurredAt := time.Date(2025, 12, 24, 10, 0, 0, 0, time.UTC)
+	svc.PublishTaskEvent(models.TaskEvent{Id: 7, Type: models.TaskEventCreated, TaskId: 1, ProjectId: 1, Version: 1})
+	svc.PublishTaskEvent(models.TaskEvent{Id: 8, Type: models.TaskEventFinished, TaskId: 1, ProjectId: 1, Version: 2, Actor: "alice", OccurredAt: occurredAt})
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeWatchStream{ctx: ctx, cancel: cancel}
+
+	err := srv.WatchTasks(&pb.WatchTasksRequest{ProjectId: 1, LastEventId: 7}, stream)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	want := []*pb.TaskEvent{{
+		Id:         8,
+		Type:       pb.TaskEventType_TASK_EVENT_TYPE_FINISHED,
+		TaskId:     1,
+		ProjectId:  1,
+		Version:    2,
+		Actor:      "alice",
+		OccurredAt: timestamppb.New(occurredAt),
+	}}
+	if diff := cmp.Diff(want, stream.sent, protocmp.Transform()); diff != "" {
+		t.Fatalf("sent events (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetChangesSince_NilRequest_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	got, err := srv.GetChangesSince(context.Background(), nil)
+
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v want=%v got=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestGetChangesSince_BadToken_ReturnsInvalidArgument(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+
+	_, err := srv.GetChangesSince(context.Background(), &pb.ChangesRequest{Token: "!"})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%v want=%v got=%v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
+
+func TestGetChangesSince_OK_ReturnsChanges(t *testing.T) {
+	fr := &fakeRepo{getChangesRet: models.Changes{
+		Tasks:      []models.TaskExportData{{Id: 3, Title: "Buy milk"}},
+		DeletedIds: []int{4},
+		Next:       models.SyncCursor{Seq: 900, TaskId: 3},
+		HasMore:    true,
+	}}
+	srv := NewServer(app.NewService(fr))
+
+	got, err := srv.GetChangesSince(context.Background(), &pb.ChangesRequest{Limit: 2})
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if fr.getChangesIn.Limit != 2 {
+		t.Fatalf("unexpected request %+v", fr.getChangesIn)
+	}
+	if len(got.GetTasks()) != 1 || got.GetTasks()[0].GetTitle() != "Buy milk" || !got.GetHasMore() || got.GetFull() {
+		t.Fatalf("unexpected changes %v", got)
+	}
+	if diff := cmp.Diff([]int64{4}, got.GetDeletedIds()); diff != "" {
+		t.Fatalf("deleted ids (-want +got):\n%s", diff)
+	}
+	if got.GetToken() == "" {
+		t.Fatalf("expected a token to continue from")
+	}
+}
+
+func TestRecoveryInterceptor_Panic_ReturnsInternal(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.TasksService/AddTask"}
+
+	_, err := recoveryInterceptor(context.Background(), &pb.TaskId{Id: 1}, info, handler)
+
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Internal, err)
+	}
+}
+
+func TestRecoveryStreamInterceptor_Panic_ReturnsInternal(t *testing.T) {
+	handler := func(srv any, ss grpc.ServerStream) error {
+		panic("boom")
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/pb.TasksService/WatchTasks"}
+
+	err := recoveryStreamInterceptor(nil, &fakeWatchStream{ctx: context.Background()}, info, handler)
+
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Internal, err)
+	}
+}
+
+func TestValidationInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      any
+		wantCode codes.Code
+	}{
+		{name: "valid id", req: &pb.TaskId{Id: 1}, wantCode: codes.OK},
+		{name: "empty message", req: &emptypb.Empty{}, wantCode: codes.OK},
+		{name: "nil request", req: (*pb.TaskId)(nil), wantCode: codes.InvalidArgument},
+		{name: "zero id", req: &pb.TaskId{}, wantCode: codes.InvalidArgument},
+		{name: "negative project id", req: &pb.RenameProjectRequest{Id: -1, Name: "home"}, wantCode: codes.InvalidArgument},
+		{name: "zero history task id", req: &pb.TaskHistoryRequest{Limit: 10}, wantCode: codes.InvalidArgument},
+		{name: "negative batch id", req: &pb.TaskIds{Ids: []int64{1, -2}}, wantCode: codes.InvalidArgument},
+		{name: "all projects", req: &pb.TaskFilter{}, wantCode: codes.OK},
+		{name: "negative filter project", req: &pb.TaskFilter{ProjectId: -3}, wantCode: codes.InvalidArgument},
+		{name: "negative limit", req: &pb.ChangesRequest{Limit: -1}, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+				return req, nil
+			}
+
+			_, err := validationInterceptor(context.Background(), tt.req, nil, handler)
+
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), tt.wantCode, err)
+			}
+			if called != (tt.wantCode == codes.OK) {
+				t.Fatalf("expected handler called=%v, got %v", tt.wantCode == codes.OK, called)
+			}
+		})
+	}
+}
+
+func TestParseCallerTokens(t *testing.T) {
+	got, err := ParseCallerTokens(" api-service:secret1, worker:secret2,")
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(got) != 2 || got["secret1"] != "api-service" || got["secret2"] != "worker" {
+		t.Fatalf("unexpected tokens %v", got)
+	}
+
+	if _, err := ParseCallerTokens("api-service"); err == nil {
+		t.Fatalf("expected an error for a pair without a token")
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	withToken := func(value string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationMetadataKey, value))
+	}
+	tests := []struct {
+		name       string
+		tokens     map[string]string
+		ctx        context.Context
+		wantCode   codes.Code
+		wantCaller string
+	}{
+		{name: "authentication off", ctx: context.Background(), wantCode: codes.OK},
+		{name: "known token", tokens: map[string]string{"secret": "api-service"}, ctx: withToken("Bearer secret"),
+			wantCode: codes.OK, wantCaller: "api-service"},
+		{name: "unknown token", tokens: map[string]string{"secret": "api-service"}, ctx: withToken("Bearer other"),
+			wantCode: codes.Unauthenticated},
+		{name: "no token", tokens: map[string]string{"secret": "api-service"}, ctx: context.Background(),
+			wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(app.NewService(&fakeRepo{}))
+			srv.SetCallerTokens(tt.tokens)
+			var gotCaller string
+			handler := func(ctx context.Context, req any) (any, error) {
+				gotCaller = callerFrom(ctx)
+				return nil, nil
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: "/pb.TasksService/ListAllTasks"}
+
+			_, err := srv.authInterceptor(tt.ctx, &emptypb.Empty{}, info, handler)
+
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), tt.wantCode, err)
+			}
+			if gotCaller != tt.wantCaller {
+				t.Fatalf("expected caller %q, got %q", tt.wantCaller, gotCaller)
+			}
+		})
+	}
+}
+
+func TestAuthStreamInterceptor_PassesCallerToHandler(t *testing.T) {
+	srv := NewServer(app.NewService(&fakeRepo{}))
+	srv.SetCallerTokens(map[string]string{"secret": "api-service"})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationMetadataKey, "Bearer secret"))
+	var gotCaller string
+	handler := func(srv any, ss grpc.ServerStream) error {
+		gotCaller = callerFrom(ss.Context())
+		return nil
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/pb.TasksService/WatchTasks"}
+
+	if err := srv.authStreamInterceptor(nil, &fakeWatchStream{ctx: ctx}, info, handler); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if gotCaller != "api-service" {
+		t.Fatalf("expected caller %q, got %q", "api-service", gotCaller)
+	}
+}
+
+// chainUnary runs handler behind interceptors the way grpc.ChainUnaryInterceptor
+// does.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler
+}
+
+func TestLoggingInterceptor_LogsActorAndCodeOfPanickingHandler(t *testing.T) {
+	var logged bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logged, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	srv := NewServer(app.NewService(&fakeRepo{}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.TasksService/MarkTaskFinished"}
+	handler := chainUnary(srv.unaryInterceptors(), info, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(actorMetadataKey, "alice"))
+
+	_, err := handler(ctx, &pb.TaskId{Id: 1})
+
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("code=%v, want=%v, err=%v", status.Code(err), codes.Internal, err)
+	}
+	line := logged.String()
+	for _, want := range []string{"method=/pb.TasksService/MarkTaskFinished", "code=Internal", "actor=alice"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %q in the access log, got %q", want, line)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// actorMetadataKey carries the name of whoever made the request through to
+// the task history.
+const actorMetadataKey = "x-actor"
+
+func actorInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if values := metadata.ValueFromIncomingContext(ctx, actorMetadataKey); len(values) > 0 {
+		ctx = app.WithActor(ctx, values[0])
+	}
+	return handler(ctx, req)
+}
+
+// versionedRequest is implemented by every request that changes a task.
+type versionedRequest interface {
+	GetExpectedVersion() int64
+}
+
+// expectedVersionInterceptor hands the expected_version of a request over to
+// the repository, which compares it with the task it locks.
+func expectedVersionInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if versioned, ok := req.(versionedRequest); ok && versioned.GetExpectedVersion() != 0 {
+		ctx = app.WithExpectedVersion(ctx, int(versioned.GetExpectedVersion()))
+	}
+	return handler(ctx, req)
+}
+
+// recoveryInterceptor turns a panic in a handler into an Internal error, so
+// one bad request doesn't take the service down.
+func recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoveryStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func recovered(method string, r any) error {
+	log.Printf("panic in %s: %v\n%s", method, r, debug.Stack())
+	return status.Errorf(codes.Internal, "%s panicked", method)
+}
+
+// loggingInterceptor writes an access log line for every call: method,
+// caller, actor, resulting code and how long it took.
+func loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logAccess(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+func loggingStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logAccess(ss.Context(), info.FullMethod, start, err)
+	return err
+}
+
+func logAccess(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		level = slog.LevelError
+	}
+
+	attrs := []any{
+		"method", method,
+		"code", code.String(),
+		"duration", time.Since(start),
+		"caller", callerFrom(ctx),
+		"actor", app.ActorFrom(ctx),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		attrs = append(attrs, "peer", p.Addr.String())
+	}
+	if err != nil {
+		attrs = append(attrs, "error", status.Convert(err).Message())
+	}
+	slog.Log(ctx, level, "grpc call", attrs...)
+}
+
+// identifiedRequest is implemented by every request about one task or
+// project.
+type identifiedRequest interface {
+	GetId() int64
+}
+
+type taskHistoryRequest interface {
+	GetTaskId() int64
+}
+
+type batchRequest interface {
+	GetIds() []int64
+}
+
+type projectScopedRequest interface {
+	GetProjectId() int64
+}
+
+type pagedRequest interface {
+	GetLimit() int32
+}
+
+// validationInterceptor rejects requests that can't be right whatever the
+// data: a missing request, an id that isn't positive, a negative limit.
+func validationInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func validationStreamInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &validatedStream{ServerStream: ss})
+}
+
+// validatedStream validates the messages the client sends on a stream.
+type validatedStream struct {
+	grpc.ServerStream
+}
+
+func (vs *validatedStream) RecvMsg(m any) error {
+	if err := vs.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validateRequest(m)
+}
+
+func validateRequest(req any) error {
+	if v := reflect.ValueOf(req); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return status.Error(codes.InvalidArgument, "received empty request")
+	}
+	if r, ok := req.(identifiedRequest); ok && r.GetId() <= 0 {
+		return status.Errorf(codes.InvalidArgument, "id must be positive, got %d", r.GetId())
+	}
+	if r, ok := req.(taskHistoryRequest); ok && r.GetTaskId() <= 0 {
+		return status.Errorf(codes.InvalidArgument, "task_id must be positive, got %d", r.GetTaskId())
+	}
+	if r, ok := req.(batchRequest); ok {
+		for _, id := range r.GetIds() {
+			if id <= 0 {
+				return status.Errorf(codes.InvalidArgument, "ids must be positive, got %d", id)
+			}
+		}
+	}
+	if r, ok := req.(projectScopedRequest); ok && r.GetProjectId() < 0 {
+		return status.Errorf(codes.InvalidArgument, "project_id can't be negative, got %d", r.GetProjectId())
+	}
+	if r, ok := req.(pagedRequest); ok && r.GetLimit() < 0 {
+		return status.Errorf(codes.InvalidArgument, "limit can't be negative, got %d", r.GetLimit())
+	}
+	return nil
+}
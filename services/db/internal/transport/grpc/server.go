@@ -1,31 +1,83 @@
 package grpc
 
 import (
+	"fmt"
 	"log"
 	"net"
+	"time"
 
 	"github.com/dodocheck/go-pet-project-1/pkg/pb"
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/app"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
+// keepaliveMinTime is how often clients may ping; more frequent pings close
+// the connection.
+const keepaliveMinTime = 20 * time.Second
+
 type Server struct {
 	pb.UnimplementedTasksServiceServer
 	service *app.Service
+	// callerTokens maps the token of every service allowed to call
+	// db-service to its name.
+	callerTokens map[string]string
+	tls          TLSOptions
 }
 
 func NewServer(service *app.Service) *Server {
 	return &Server{service: service}
 }
 
+// SetCallerTokens turns on caller authentication; see ParseCallerTokens.
+func (s *Server) SetCallerTokens(tokens map[string]string) {
+	s.callerTokens = tokens
+}
+
+// SetTLS turns on TLS, and mutual TLS when opts has a client CA.
+func (s *Server) SetTLS(opts TLSOptions) {
+	s.tls = opts
+}
+
+// unaryInterceptors run in order. The actor is read before the access log
+// so it is recorded, and a panic is recovered inside it so the call is
+// logged as Internal.
+func (s *Server) unaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{s.authInterceptor, actorInterceptor, loggingInterceptor,
+		recoveryInterceptor, validationInterceptor, expectedVersionInterceptor}
+}
+
+func (s *Server) streamInterceptors() []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{s.authStreamInterceptor, loggingStreamInterceptor,
+		recoveryStreamInterceptor, validationStreamInterceptor}
+}
+
 func (s *Server) StartServer(serverAddress string) error {
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unaryInterceptors()...),
+		grpc.ChainStreamInterceptor(s.streamInterceptors()...),
+		// api-service pings idle connections every 30 seconds.
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             keepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+	if s.tls.CertFile != "" {
+		tlsConfig, err := serverTLSConfig(s.tls)
+		if err != nil {
+			return fmt.Errorf("TLS: %w", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
 	lis, err := net.Listen("tcp", serverAddress)
 	if err != nil {
 		log.Fatalf("listen %s: %v\n", serverAddress, err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(serverOpts...)
 	pb.RegisterTasksServiceServer(grpcServer, s)
 	reflection.Register(grpcServer)
 
@@ -0,0 +1,207 @@
+package grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testCA{cert: cert, key: key}
+}
+
+func (ca testCA) writeCert(t *testing.T, path string) {
+	t.Helper()
+	writeTestFile(t, path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+}
+
+// issue writes a certificate for name signed by the CA and its key.
+func (ca testCA) issue(t *testing.T, certPath, keyPath, name string, serial int64, usage x509.ExtKeyUsage) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeTestFile(t, keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// touch moves the modification time of paths forward, so a rewrite within
+// the same clock tick still looks changed.
+func touch(t *testing.T, paths ...string) {
+	t.Helper()
+	modTime := time.Now().Add(time.Minute)
+	for _, path := range paths {
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// serveTLS accepts connections with config until the test ends, completing
+// the handshake on each.
+func serveTLS(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func dialTLS(address string, config *tls.Config) (*tls.ConnectionState, error) {
+	conn, err := tls.Dial("tcp", address, config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	// A rejected client certificate only shows on the first read in TLS 1.3.
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	state := conn.ConnectionState()
+	return &state, nil
+}
+
+func TestServerTLSConfig_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	ca.writeCert(t, filepath.Join(dir, "ca.pem"))
+	ca.issue(t, filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), "db-service", 2, x509.ExtKeyUsageServerAuth)
+	ca.issue(t, filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem"), "api-service", 3, x509.ExtKeyUsageClientAuth)
+
+	config, err := serverTLSConfig(TLSOptions{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	address := serveTLS(t, config)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientCert, err := tls.LoadX509KeyPair(filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dialTLS(address, &tls.Config{RootCAs: roots, ServerName: "db-service", Certificates: []tls.Certificate{clientCert}}); err != nil {
+		t.Fatalf("expected a client with a certificate to connect, got %v", err)
+	}
+	if _, err := dialTLS(address, &tls.Config{RootCAs: roots, ServerName: "db-service"}); err == nil {
+		t.Fatalf("expected a client without a certificate to be rejected")
+	}
+}
+
+func TestServerTLSConfig_ReloadsRenewedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	ca := newTestCA(t)
+	ca.issue(t, certPath, keyPath, "db-service", 2, x509.ExtKeyUsageServerAuth)
+
+	config, err := serverTLSConfig(TLSOptions{CertFile: certPath, KeyFile: keyPath})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	address := serveTLS(t, config)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "db-service"}
+
+	ca.issue(t, certPath, keyPath, "db-service", 7, x509.ExtKeyUsageServerAuth)
+	touch(t, certPath, keyPath)
+	state, err := dialTLS(address, clientConfig)
+
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if serial := state.PeerCertificates[0].SerialNumber.Int64(); serial != 7 {
+		t.Fatalf("expected the renewed certificate, got serial %d", serial)
+	}
+}
+
+func TestServerTLSConfig_MissingFiles_ReturnsError(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := serverTLSConfig(TLSOptions{CertFile: filepath.Join(dir, "server.pem"), KeyFile: filepath.Join(dir, "server-key.pem")})
+
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
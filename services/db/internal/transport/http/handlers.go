@@ -9,6 +9,10 @@ import (
 	"github.com/dodocheck/go-pet-project-1/services/db/internal/models"
 )
 
+// staleWarning marks a response served from the cache while the main DB is
+// down.
+const staleWarning = `110 - "Response is Stale"`
+
 type HttpHandlers struct {
 	service     *app.Service
 	closeServer func() error
@@ -80,20 +84,30 @@ info: -
 success:
   - status code: 200 Ok
   - response body: JSON represented found data
+  - header Warning: 110 - "Response is Stale", if the list was served from
+    the cache because the main DB is down
 
 failure:
-  - status code: 500
+  - status code: 500, 503 if the main DB is down and the cache has no list
   - response body: JSON with error + time
 */
 func (h *HttpHandlers) handleListAllTasks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	tasks, err := h.service.ListAllTasks(ctx)
+	tasks, stale, err := h.service.ListAllTasks(ctx)
 	if err != nil {
 		errorDTO := NewErrorDTO(err.Error())
-		http.Error(w, errorDTO.ToString(), http.StatusInternalServerError)
+		statusCode := http.StatusInternalServerError
+		if app.MainDBUnavailable(err) {
+			statusCode = http.StatusServiceUnavailable
+		}
+		http.Error(w, errorDTO.ToString(), statusCode)
 		return
 	}
 
+	if stale {
+		w.Header().Set("Warning", staleWarning)
+	}
+
 	b, err := json.MarshalIndent(tasks, "", "    ")
 	if err != nil {
 		errorDTO := NewErrorDTO(err.Error())
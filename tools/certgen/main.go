@@ -0,0 +1,186 @@
+// certgen issues a local CA and the certificates api-service and db-service
+// use for the gRPC link, for Docker Compose and tests. An existing CA in the
+// output directory is reused, so certificates issued again are trusted by
+// running services, which pick them up without a restart.
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	caFile    = "ca.pem"
+	caKeyFile = "ca-key.pem"
+)
+
+func main() {
+	out := flag.String("out", "deployment/certs", "directory to write the certificates to")
+	server := flag.String("server", "db-service", "name of the server certificate")
+	hosts := flag.String("hosts", "db-service,localhost,127.0.0.1", "comma-separated DNS names and IPs of the server")
+	client := flag.String("client", "api-service", "name of the client certificate, empty for none")
+	days := flag.Int("days", 365, "validity of the issued certificates in days")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	ca, caKey, err := loadOrCreateCA(*out)
+	if err != nil {
+		log.Fatalf("CA: %v", err)
+	}
+	validity := time.Duration(*days) * 24 * time.Hour
+
+	serverTemplate := leafTemplate(*server, validity, x509.ExtKeyUsageServerAuth)
+	for host := range strings.SplitSeq(*hosts, ",") {
+		if host = strings.TrimSpace(host); host == "" {
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+	if err := issue(*out, *server, serverTemplate, ca, caKey); err != nil {
+		log.Fatalf("server certificate: %v", err)
+	}
+
+	if *client != "" {
+		clientTemplate := leafTemplate(*client, validity, x509.ExtKeyUsageClientAuth)
+		if err := issue(*out, *client, clientTemplate, ca, caKey); err != nil {
+			log.Fatalf("client certificate: %v", err)
+		}
+	}
+
+	log.Printf("certificates are in %s\n", *out)
+}
+
+func loadOrCreateCA(dir string) (*x509.Certificate, crypto.Signer, error) {
+	certPEM, certErr := os.ReadFile(filepath.Join(dir, caFile))
+	keyPEM, keyErr := os.ReadFile(filepath.Join(dir, caKeyFile))
+	if certErr == nil && keyErr == nil {
+		return parseCA(certPEM, keyPEM)
+	}
+	if !errors.Is(certErr, os.ErrNotExist) && certErr != nil {
+		return nil, nil, certErr
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber(),
+		Subject:               pkix.Name{CommonName: "todo-list dev CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writePEM(dir, caFile, der, key); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("created a CA in %s\n", dir)
+
+	ca, err := x509.ParseCertificate(der)
+	return ca, key, err
+}
+
+func parseCA(certPEM, keyPEM []byte) (*x509.Certificate, crypto.Signer, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, fmt.Errorf("%s or %s is not PEM", caFile, caKeyFile)
+	}
+	ca, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s can't sign", caKeyFile)
+	}
+	return ca, signer, nil
+}
+
+func leafTemplate(name string, validity time.Duration, usage x509.ExtKeyUsage) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: serialNumber(),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+}
+
+// issue signs template with the CA and writes name.pem and name-key.pem.
+func issue(dir, name string, template, ca *x509.Certificate, caKey crypto.Signer) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	if err != nil {
+		return err
+	}
+	return writePEM(dir, name+".pem", der, key)
+}
+
+// writePEM writes the certificate and its key next to it. Each file is
+// written to a temporary one and renamed, so a service reloading them never
+// reads half a file.
+func writePEM(dir, certName string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	keyName := strings.TrimSuffix(certName, ".pem") + "-key.pem"
+
+	if err := writeFile(filepath.Join(dir, keyName), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, certName), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+}
+
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func serialNumber() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return serial
+}